@@ -11,6 +11,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 )
@@ -59,6 +60,13 @@ type FinalizationFetcher interface {
 	PreviousJustifiedCheckpt() *ethpb.Checkpoint
 }
 
+// ForkChoiceFetcher retrieves the fork choice store of the node, and the snapshots of it kept on disk.
+type ForkChoiceFetcher interface {
+	ForkChoiceDump() *rpcpb.ForkChoiceDump
+	ForkChoiceSnapshots() ([]*rpcpb.ForkChoiceSnapshotInfo, error)
+	ForkChoiceSnapshot(timestamp uint64) (*rpcpb.ForkChoiceDump, error)
+}
+
 // ParticipationFetcher defines a common interface for methods in blockchain service which
 // directly retrieves validator participation related data.
 type ParticipationFetcher interface {
@@ -0,0 +1,189 @@
+package blockchain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/pkg/errors"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+// forkChoiceSnapshotExt is the extension of the fork choice snapshot files. Snapshots are gzip
+// compressed JSON files named after the time they were taken and the head slot at that time.
+const forkChoiceSnapshotExt = ".json.gz"
+
+// ErrNoForkChoiceSnapshot is returned when no fork choice snapshot was taken at or before a given time.
+var ErrNoForkChoiceSnapshot = errors.New("no fork choice snapshot found")
+
+// ForkChoiceDump returns a copy of the fork choice store, along with the current head of the node.
+func (s *Service) ForkChoiceDump() *rpcpb.ForkChoiceDump {
+	dump := s.forkChoiceStore.Dump()
+	dump.Timestamp = uint64(roughtime.Now().Unix())
+	if s.hasHeadState() {
+		headRoot := s.headRoot()
+		dump.HeadRoot = headRoot[:]
+		dump.HeadSlot = s.headSlot()
+	}
+	return dump
+}
+
+// ForkChoiceSnapshots returns the fork choice snapshots kept on disk, ordered by time.
+func (s *Service) ForkChoiceSnapshots() ([]*rpcpb.ForkChoiceSnapshotInfo, error) {
+	if s.snapshotDir == "" {
+		return []*rpcpb.ForkChoiceSnapshotInfo{}, nil
+	}
+	files, err := ioutil.ReadDir(s.snapshotDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*rpcpb.ForkChoiceSnapshotInfo{}, nil
+		}
+		return nil, err
+	}
+	snapshots := make([]*rpcpb.ForkChoiceSnapshotInfo, 0, len(files))
+	for _, f := range files {
+		info, ok := parseForkChoiceSnapshotName(f.Name())
+		if !ok {
+			continue
+		}
+		snapshots = append(snapshots, info)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp < snapshots[j].Timestamp
+	})
+	return snapshots, nil
+}
+
+// ForkChoiceSnapshot returns the latest fork choice snapshot taken at or before the given unix time.
+func (s *Service) ForkChoiceSnapshot(timestamp uint64) (*rpcpb.ForkChoiceDump, error) {
+	snapshots, err := s.ForkChoiceSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	var found *rpcpb.ForkChoiceSnapshotInfo
+	for _, info := range snapshots {
+		if info.Timestamp > timestamp {
+			break
+		}
+		found = info
+	}
+	if found == nil {
+		return nil, ErrNoForkChoiceSnapshot
+	}
+
+	f, err := os.Open(filepath.Join(s.snapshotDir, forkChoiceSnapshotName(found)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close fork choice snapshot")
+		}
+	}()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress fork choice snapshot")
+	}
+	dump := &rpcpb.ForkChoiceDump{}
+	if err := jsonpb.Unmarshal(r, dump); err != nil {
+		return nil, errors.Wrap(err, "could not decode fork choice snapshot")
+	}
+	return dump, nil
+}
+
+// This saves a snapshot of the fork choice store at every snapshot interval, and deletes the
+// oldest snapshots beyond the retention count. All snapshots are kept if the count is not set.
+func (s *Service) snapshotForkChoiceRoutine() {
+	if err := os.MkdirAll(s.snapshotDir, 0700); err != nil {
+		log.WithError(err).Error("Could not create fork choice snapshot directory")
+		return
+	}
+	ticker := time.NewTicker(s.snapshotInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !s.hasHeadState() {
+				continue
+			}
+			if err := s.saveForkChoiceSnapshot(); err != nil {
+				log.WithError(err).Error("Could not save fork choice snapshot")
+				continue
+			}
+			if err := s.pruneForkChoiceSnapshots(); err != nil {
+				log.WithError(err).Error("Could not prune fork choice snapshots")
+			}
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting fork choice snapshot routine")
+			return
+		}
+	}
+}
+
+func (s *Service) saveForkChoiceSnapshot() error {
+	dump := s.ForkChoiceDump()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := (&jsonpb.Marshaler{}).Marshal(w, dump); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a partially written snapshot is never listed.
+	name := forkChoiceSnapshotName(&rpcpb.ForkChoiceSnapshotInfo{Timestamp: dump.Timestamp, HeadSlot: dump.HeadSlot})
+	path := filepath.Join(s.snapshotDir, name)
+	if err := ioutil.WriteFile(path+".tmp", buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (s *Service) pruneForkChoiceSnapshots() error {
+	snapshots, err := s.ForkChoiceSnapshots()
+	if err != nil {
+		return err
+	}
+	if s.snapshotRetention <= 0 || len(snapshots) <= s.snapshotRetention {
+		return nil
+	}
+	for _, info := range snapshots[:len(snapshots)-s.snapshotRetention] {
+		if err := os.Remove(filepath.Join(s.snapshotDir, forkChoiceSnapshotName(info))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func forkChoiceSnapshotName(info *rpcpb.ForkChoiceSnapshotInfo) string {
+	return fmt.Sprintf("%d-%d%s", info.Timestamp, info.HeadSlot, forkChoiceSnapshotExt)
+}
+
+func parseForkChoiceSnapshotName(name string) (*rpcpb.ForkChoiceSnapshotInfo, bool) {
+	if !strings.HasSuffix(name, forkChoiceSnapshotExt) {
+		return nil, false
+	}
+	parts := strings.Split(strings.TrimSuffix(name, forkChoiceSnapshotExt), "-")
+	if len(parts) != 2 {
+		return nil, false
+	}
+	timestamp, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	slot, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &rpcpb.ForkChoiceSnapshotInfo{Timestamp: timestamp, HeadSlot: slot}, true
+}
@@ -0,0 +1,82 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice/protoarray"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestForkChoiceSnapshots_SaveAndRetrieve(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(), "forkchoice-snapshots")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	store := protoarray.New(0, 0, params.BeaconConfig().ZeroHash)
+	root := [32]byte{'A'}
+	if err := store.ProcessBlock(context.Background(), 0, root, params.BeaconConfig().ZeroHash, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	s := &Service{forkChoiceStore: store, snapshotDir: dir, snapshotRetention: 2}
+
+	if err := s.saveForkChoiceSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := s.ForkChoiceSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("Wanted 1 snapshot, received %d", len(snapshots))
+	}
+	dump, err := s.ForkChoiceSnapshot(snapshots[0].Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dump.Nodes) != 1 || !bytes.Equal(dump.Nodes[0].Root, root[:]) {
+		t.Errorf("Unexpected snapshot nodes %v", dump.Nodes)
+	}
+	if _, err := s.ForkChoiceSnapshot(snapshots[0].Timestamp - 1); err != ErrNoForkChoiceSnapshot {
+		t.Errorf("Wanted %v, received %v", ErrNoForkChoiceSnapshot, err)
+	}
+}
+
+func TestForkChoiceSnapshots_Prune(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(), "forkchoice-snapshots-prune")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	for _, name := range []string{"100-1.json.gz", "200-2.json.gz", "300-3.json.gz", "unrelated.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte{}, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := &Service{snapshotDir: dir, snapshotRetention: 2}
+
+	if err := s.pruneForkChoiceSnapshots(); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := s.ForkChoiceSnapshots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Timestamp != 200 || snapshots[1].HeadSlot != 3 {
+		t.Errorf("Expected the oldest snapshot to be pruned, received %v", snapshots)
+	}
+}
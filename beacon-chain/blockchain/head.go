@@ -2,14 +2,21 @@ package blockchain
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
 
@@ -100,6 +107,13 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 		return errors.New("cannot save nil head state")
 	}
 
+	// Remember the previous head to detect reorgs.
+	oldHeadRoot := s.headRoot()
+	var oldHeadSlot uint64
+	if oldHeadRoot != params.BeaconConfig().ZeroHash {
+		oldHeadSlot = s.headSlot()
+	}
+
 	// Cache the new head info.
 	s.setHead(headRoot, newHeadBlock, newHeadState)
 
@@ -108,9 +122,134 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 		return errors.Wrap(err, "could not save head root in DB")
 	}
 
+	s.notifyNewHead(ctx, oldHeadRoot, oldHeadSlot, headRoot, newHeadBlock)
+
 	return nil
 }
 
+// This sends the new head to the state feed. If the new head does not descend from
+// the previous head, the reorg is recorded and a reorg event is sent first.
+func (s *Service) notifyNewHead(ctx context.Context, oldRoot [32]byte, oldSlot uint64, newRoot [32]byte, newBlock *ethpb.SignedBeaconBlock) {
+	newSlot := newBlock.Block.Slot
+	if reorg := s.detectReorg(ctx, oldRoot, oldSlot, newRoot, newBlock); reorg != nil {
+		s.recordReorg(ctx, reorg)
+		s.stateNotifier.StateFeed().Send(&feed.Event{
+			Type: statefeed.Reorg,
+			Data: reorg,
+		})
+	}
+
+	s.stateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.NewHead,
+		Data: &statefeed.NewHeadData{
+			Slot:            newSlot,
+			BlockRoot:       newRoot,
+			StateRoot:       newBlock.Block.StateRoot,
+			EpochTransition: helpers.SlotToEpoch(newSlot) > helpers.SlotToEpoch(oldSlot),
+		},
+	})
+}
+
+// This returns the reorg from the previous head to the new head, or nil if the new head
+// descends from the previous head.
+func (s *Service) detectReorg(ctx context.Context, oldRoot [32]byte, oldSlot uint64, newRoot [32]byte, newBlock *ethpb.SignedBeaconBlock) *statefeed.ReorgData {
+	if oldRoot == params.BeaconConfig().ZeroHash || bytesutil.ToBytes32(newBlock.Block.ParentRoot) == oldRoot {
+		return nil
+	}
+	ancestorRoot, ancestorSlot, err := s.commonAncestor(ctx, oldRoot, newRoot)
+	if err != nil {
+		log.WithError(err).Warn("Could not determine common ancestor of previous and new head")
+		return nil
+	}
+	if ancestorRoot == oldRoot {
+		return nil
+	}
+	return &statefeed.ReorgData{
+		NewSlot:            newBlock.Block.Slot,
+		OldSlot:            oldSlot,
+		Depth:              oldSlot - ancestorSlot,
+		NewHeadRoot:        newRoot,
+		OldHeadRoot:        oldRoot,
+		CommonAncestorRoot: ancestorRoot,
+		CommonAncestorSlot: ancestorSlot,
+	}
+}
+
+// This logs a reorg, observes its depth and saves it to the reorg history of the database.
+func (s *Service) recordReorg(ctx context.Context, reorg *statefeed.ReorgData) {
+	log.WithFields(logrus.Fields{
+		"oldSlot":        reorg.OldSlot,
+		"newSlot":        reorg.NewSlot,
+		"depth":          reorg.Depth,
+		"oldRoot":        fmt.Sprintf("%#x", bytesutil.Trunc(reorg.OldHeadRoot[:])),
+		"newRoot":        fmt.Sprintf("%#x", bytesutil.Trunc(reorg.NewHeadRoot[:])),
+		"commonAncestor": fmt.Sprintf("%#x", bytesutil.Trunc(reorg.CommonAncestorRoot[:])),
+	}).Info("Chain reorg occurred")
+	reorgDepth.Observe(float64(reorg.Depth))
+	if err := s.beaconDB.SaveReorg(ctx, &rpcpb.Reorg{
+		Timestamp:          uint64(roughtime.Now().Unix()),
+		OldHeadRoot:        reorg.OldHeadRoot[:],
+		OldHeadSlot:        reorg.OldSlot,
+		NewHeadRoot:        reorg.NewHeadRoot[:],
+		NewHeadSlot:        reorg.NewSlot,
+		CommonAncestorRoot: reorg.CommonAncestorRoot[:],
+		CommonAncestorSlot: reorg.CommonAncestorSlot,
+		Depth:              reorg.Depth,
+	}); err != nil {
+		log.WithError(err).Error("Could not save reorg to history")
+	}
+}
+
+// This returns the root and slot of the latest block which both given blocks descend from.
+// It walks back the parent links of whichever block has the higher slot until both meet.
+func (s *Service) commonAncestor(ctx context.Context, root1 [32]byte, root2 [32]byte) ([32]byte, uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "blockchain.commonAncestor")
+	defer span.End()
+
+	b1, err := s.blockByRoot(ctx, root1)
+	if err != nil {
+		return [32]byte{}, 0, err
+	}
+	b2, err := s.blockByRoot(ctx, root2)
+	if err != nil {
+		return [32]byte{}, 0, err
+	}
+	for root1 != root2 {
+		if ctx.Err() != nil {
+			return [32]byte{}, 0, ctx.Err()
+		}
+		if b1.Block.Slot >= b2.Block.Slot {
+			if b1.Block.Slot == 0 {
+				return [32]byte{}, 0, errors.New("blocks do not share a common ancestor")
+			}
+			root1 = bytesutil.ToBytes32(b1.Block.ParentRoot)
+			b1, err = s.blockByRoot(ctx, root1)
+		} else {
+			root2 = bytesutil.ToBytes32(b2.Block.ParentRoot)
+			b2, err = s.blockByRoot(ctx, root2)
+		}
+		if err != nil {
+			return [32]byte{}, 0, err
+		}
+	}
+	return root1, b1.Block.Slot, nil
+}
+
+// This retrieves a block from the initial sync cache or the DB.
+func (s *Service) blockByRoot(ctx context.Context, root [32]byte) (*ethpb.SignedBeaconBlock, error) {
+	if !featureconfig.Get().NoInitSyncBatchSaveBlocks && s.hasInitSyncBlock(root) {
+		return s.getInitSyncBlock(root), nil
+	}
+	b, err := s.beaconDB.Block(ctx, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get block")
+	}
+	if b == nil || b.Block == nil {
+		return nil, fmt.Errorf("could not find block %#x", root)
+	}
+	return b, nil
+}
+
 // This gets called to update canonical root mapping. It does not save head block
 // root in DB. With the inception of inital-sync-cache-state flag, it uses finalized
 // check point as anchors to resume sync therefore head is no longer needed to be saved on per slot basis.
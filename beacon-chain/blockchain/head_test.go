@@ -8,6 +8,8 @@ import (
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
@@ -84,3 +86,112 @@ func TestSaveHead_Different(t *testing.T) {
 		t.Error("Head did not change")
 	}
 }
+
+func TestSaveHead_Reorg(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := setupBeaconChain(t, db)
+	ctx := context.Background()
+
+	saveBlock := func(b *ethpb.BeaconBlock) [32]byte {
+		if err := service.beaconDB.SaveBlock(ctx, &ethpb.SignedBeaconBlock{Block: b}); err != nil {
+			t.Fatal(err)
+		}
+		r, err := ssz.HashTreeRoot(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	ancestorRoot := saveBlock(&ethpb.BeaconBlock{Slot: 1})
+	oldBlock := &ethpb.BeaconBlock{Slot: 3, ParentRoot: ancestorRoot[:]}
+	oldRoot := saveBlock(oldBlock)
+	newRoot := saveBlock(&ethpb.BeaconBlock{Slot: 4, ParentRoot: ancestorRoot[:], StateRoot: []byte{'S'}})
+	service.head = &head{slot: 3, root: oldRoot, block: &ethpb.SignedBeaconBlock{Block: oldBlock}}
+
+	headState := testutil.NewBeaconState()
+	if err := headState.SetSlot(4); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveStateSummary(ctx, &pb.StateSummary{Slot: 4, Root: newRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveState(ctx, headState, newRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan *feed.Event, 2)
+	sub := service.stateNotifier.StateFeed().Subscribe(events)
+	defer sub.Unsubscribe()
+	if err := service.saveHead(ctx, newRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	e := <-events
+	if e.Type != statefeed.Reorg {
+		t.Fatalf("Expected reorg event, received %d", e.Type)
+	}
+	wantReorg := &statefeed.ReorgData{
+		NewSlot:            4,
+		OldSlot:            3,
+		Depth:              2,
+		NewHeadRoot:        newRoot,
+		OldHeadRoot:        oldRoot,
+		CommonAncestorRoot: ancestorRoot,
+		CommonAncestorSlot: 1,
+	}
+	if !reflect.DeepEqual(e.Data, wantReorg) {
+		t.Errorf("Wanted %v, received %v", wantReorg, e.Data)
+	}
+	e = <-events
+	if e.Type != statefeed.NewHead {
+		t.Fatalf("Expected new head event, received %d", e.Type)
+	}
+	data, ok := e.Data.(*statefeed.NewHeadData)
+	if !ok {
+		t.Fatal("Unexpected new head event data")
+	}
+	if data.Slot != 4 || data.BlockRoot != newRoot || !bytes.Equal(data.StateRoot, []byte{'S'}) {
+		t.Errorf("Unexpected new head event data %v", data)
+	}
+
+	reorgs, err := service.beaconDB.Reorgs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reorgs) != 1 {
+		t.Fatalf("Expected the reorg to be saved to history, received %v", reorgs)
+	}
+	reorg := reorgs[0]
+	if reorg.Depth != 2 || reorg.CommonAncestorSlot != 1 || !bytes.Equal(reorg.CommonAncestorRoot, ancestorRoot[:]) {
+		t.Errorf("Unexpected common ancestor in reorg history %v", reorg)
+	}
+	if reorg.OldHeadSlot != 3 || !bytes.Equal(reorg.OldHeadRoot, oldRoot[:]) ||
+		reorg.NewHeadSlot != 4 || !bytes.Equal(reorg.NewHeadRoot, newRoot[:]) {
+		t.Errorf("Unexpected heads in reorg history %v", reorg)
+	}
+}
+
+func TestCommonAncestor_NoAncestor(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	service := setupBeaconChain(t, db)
+	ctx := context.Background()
+
+	b1 := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 0, ParentRoot: []byte{'A'}}}
+	b2 := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 0, ParentRoot: []byte{'B'}}}
+	if err := service.beaconDB.SaveBlocks(ctx, []*ethpb.SignedBeaconBlock{b1, b2}); err != nil {
+		t.Fatal(err)
+	}
+	r1, err := ssz.HashTreeRoot(b1.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := ssz.HashTreeRoot(b2.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.commonAncestor(ctx, r1, r2); err == nil {
+		t.Error("Expected error for blocks without common ancestor")
+	}
+}
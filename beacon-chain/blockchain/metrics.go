@@ -27,6 +27,11 @@ var (
 		Name: "competing_blocks",
 		Help: "The # of blocks received and processed from a competing chain",
 	})
+	reorgDepth = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "beacon_reorg_depth",
+		Help:    "The number of slots from the common ancestor to the previous head of each chain reorg",
+		Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128},
+	})
 	headFinalizedEpoch = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "head_finalized_epoch",
 		Help: "Last finalized epoch of the head state",
@@ -83,6 +88,14 @@ var (
 		Name: "total_voted_target_balances",
 		Help: "The total amount of ether, in gwei, that is eligible for voting of previous epoch",
 	})
+	prunedDBObjects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "beacon_db_pruned_objects_total",
+		Help: "The number of objects deleted from the database by pruning, by kind",
+	}, []string{"kind"})
+	dbFileSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "beacon_db_file_size_bytes",
+		Help: "The size of the database file after the last compaction",
+	})
 )
 
 // reportSlotMetrics reports slot related metrics.
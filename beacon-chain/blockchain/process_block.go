@@ -157,6 +157,8 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 				return nil, errors.Wrap(err, "could not migrate to cold")
 			}
 		}
+
+		s.notifyPruner()
 	}
 
 	// Epoch boundary bookkeeping such as logging epoch summaries.
@@ -311,6 +313,8 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 				return errors.Wrap(err, "could not migrate to cold")
 			}
 		}
+
+		s.notifyPruner()
 	}
 
 	if !featureconfig.Get().NewStateMgmt {
@@ -0,0 +1,88 @@
+package blockchain
+
+import (
+	"context"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+)
+
+// notifyPruner requests a database pruning pass after a new finalized checkpoint. The request
+// is dropped if a pass is already pending, as a pass prunes up to the latest finalized checkpoint.
+func (s *Service) notifyPruner() {
+	if !s.pruneDB {
+		return
+	}
+	select {
+	case s.pruneCh <- struct{}{}:
+	default:
+	}
+}
+
+// pruneRoutine runs the requested database pruning passes in the background, and compacts the
+// database file after a pass at most once per compaction interval.
+func (s *Service) pruneRoutine() {
+	lastCompaction := roughtime.Now()
+	for {
+		select {
+		case <-s.pruneCh:
+			report, err := s.beaconDB.Prune(s.ctx, &db.PruneOptions{
+				RetentionSlots: s.pruneRetention,
+				DryRun:         s.pruneDryRun,
+			})
+			if err != nil {
+				log.WithError(err).Error("Could not prune database")
+				continue
+			}
+			logPruneReport(report)
+			if s.pruneDryRun || s.compactionInterval == 0 || roughtime.Since(lastCompaction) < s.compactionInterval {
+				continue
+			}
+			lastCompaction = roughtime.Now()
+			if err := s.compactDB(s.ctx); err != nil {
+				log.WithError(err).Error("Could not compact database")
+			}
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting database pruning routine")
+			return
+		}
+	}
+}
+
+func (s *Service) compactDB(ctx context.Context) error {
+	start := time.Now()
+	report, err := s.beaconDB.Compact(ctx)
+	if err != nil {
+		return err
+	}
+	dbFileSize.Set(float64(report.SizeAfter))
+	log.WithFields(logrus.Fields{
+		"sizeBefore": report.SizeBefore,
+		"sizeAfter":  report.SizeAfter,
+		"duration":   time.Since(start),
+	}).Info("Compacted database")
+	return nil
+}
+
+func logPruneReport(report *db.PruneReport) {
+	fields := logrus.Fields{
+		"finalizedSlot":      report.FinalizedSlot,
+		"nonCanonicalBlocks": report.NonCanonicalBlocks,
+		"canonicalBlocks":    report.CanonicalBlocks,
+		"states":             report.States,
+		"stateSummaries":     report.StateSummaries,
+		"attestations":       report.Attestations,
+	}
+	if report.DryRun {
+		log.WithFields(fields).Info("Dry run, database pruning would delete")
+		return
+	}
+	prunedDBObjects.WithLabelValues("non_canonical_block").Add(float64(report.NonCanonicalBlocks))
+	prunedDBObjects.WithLabelValues("canonical_block").Add(float64(report.CanonicalBlocks))
+	prunedDBObjects.WithLabelValues("state").Add(float64(report.States))
+	prunedDBObjects.WithLabelValues("state_summary").Add(float64(report.StateSummaries))
+	prunedDBObjects.WithLabelValues("attestation").Add(float64(report.Attestations))
+	log.WithFields(fields).Debug("Pruned database")
+}
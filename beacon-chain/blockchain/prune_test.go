@@ -0,0 +1,83 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestNotifyPruner_CoalescesRequests(t *testing.T) {
+	s := &Service{pruneCh: make(chan struct{}, 1)}
+	s.notifyPruner()
+	if len(s.pruneCh) != 0 {
+		t.Error("Expected no pruning request with pruning disabled")
+	}
+
+	s.pruneDB = true
+	s.notifyPruner()
+	s.notifyPruner()
+	if len(s.pruneCh) != 1 {
+		t.Errorf("Wanted %d pending pruning request, received %d", 1, len(s.pruneCh))
+	}
+}
+
+func TestPruneRoutine_PrunesForks(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The chain genesis <- 1 <- 2 is finalized at block 2, with a fork block at slot 1.
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{ParentRoot: make([]byte, 32)}}
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1 := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: genesisRoot[:]}}
+	b1Root, err := stateutil.BlockRoot(b1.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2 := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 2, ParentRoot: b1Root[:]}}
+	b2Root, err := stateutil.BlockRoot(b2.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fork := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, ProposerIndex: 1, ParentRoot: genesisRoot[:]}}
+	forkRoot, err := stateutil.BlockRoot(fork.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlocks(ctx, []*ethpb.SignedBeaconBlock{genesis, b1, b2, fork}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), b2Root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: b2Root[:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{ctx: ctx, beaconDB: db, pruneDB: true, pruneCh: make(chan struct{}, 1)}
+	go s.pruneRoutine()
+	s.notifyPruner()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for db.HasBlock(ctx, forkRoot) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the fork block to be pruned")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !db.HasBlock(ctx, b1Root) {
+		t.Error("Expected the canonical block to be kept")
+	}
+}
@@ -77,6 +77,14 @@ type Service struct {
 	opsService             *attestations.Service
 	initSyncBlocks         map[[32]byte]*ethpb.SignedBeaconBlock
 	initSyncBlocksLock     sync.RWMutex
+	snapshotDir            string
+	snapshotInterval       time.Duration
+	snapshotRetention      int
+	pruneDB                bool
+	pruneRetention         uint64
+	pruneDryRun            bool
+	compactionInterval     time.Duration
+	pruneCh                chan struct{}
 }
 
 // Config options for the service.
@@ -94,6 +102,18 @@ type Config struct {
 	ForkChoiceStore   f.ForkChoicer
 	OpsService        *attestations.Service
 	StateGen          *stategen.State
+	// Snapshots of the fork choice store are saved in the snapshot directory at every snapshot
+	// interval, keeping the latest snapshots up to the retention count. No snapshot is saved if
+	// the directory or the interval is not set.
+	ForkChoiceSnapshotDir       string
+	ForkChoiceSnapshotInterval  time.Duration
+	ForkChoiceSnapshotRetention int
+	// The database is pruned on every new finalized checkpoint if pruning is enabled, and compacted
+	// after pruning at most once per compaction interval. No compaction runs if the interval is not set.
+	PruneDB              bool
+	PruneRetentionSlots  uint64
+	PruneDryRun          bool
+	DBCompactionInterval time.Duration
 }
 
 // NewService instantiates a new block service instance that will
@@ -120,6 +140,14 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 		opsService:         cfg.OpsService,
 		stateGen:           cfg.StateGen,
 		initSyncBlocks:     make(map[[32]byte]*ethpb.SignedBeaconBlock),
+		snapshotDir:        cfg.ForkChoiceSnapshotDir,
+		snapshotInterval:   cfg.ForkChoiceSnapshotInterval,
+		snapshotRetention:  cfg.ForkChoiceSnapshotRetention,
+		pruneDB:            cfg.PruneDB,
+		pruneRetention:     cfg.PruneRetentionSlots,
+		pruneDryRun:        cfg.PruneDryRun,
+		compactionInterval: cfg.DBCompactionInterval,
+		pruneCh:            make(chan struct{}, 1),
 	}, nil
 }
 
@@ -232,6 +260,14 @@ func (s *Service) Start() {
 	}
 
 	go s.processAttestation(attestationProcessorSubscribed)
+
+	if s.snapshotDir != "" && s.snapshotInterval > 0 {
+		go s.snapshotForkChoiceRoutine()
+	}
+
+	if s.pruneDB {
+		go s.pruneRoutine()
+	}
 }
 
 // processChainStartTime initializes a series of deposits from the ChainStart deposits in the eth1
@@ -378,14 +414,22 @@ func (s *Service) initializeChainInfo(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "could not get genesis block from db")
 	}
-	if genesisBlock == nil {
-		return errors.New("no genesis block in db")
-	}
-	genesisBlkRoot, err := ssz.HashTreeRoot(genesisBlock.Block)
-	if err != nil {
-		return errors.Wrap(err, "could not get signing root of genesis block")
+	if genesisBlock != nil {
+		genesisBlkRoot, err := ssz.HashTreeRoot(genesisBlock.Block)
+		if err != nil {
+			return errors.Wrap(err, "could not get signing root of genesis block")
+		}
+		s.genesisRoot = genesisBlkRoot
+	} else {
+		// A node started from a checkpoint has no genesis block until it is backfilled.
+		anchorRoot, err := s.beaconDB.AnchorBlockRoot(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get anchor block root from db")
+		}
+		if anchorRoot == params.BeaconConfig().ZeroHash {
+			return errors.New("no genesis block in db")
+		}
 	}
-	s.genesisRoot = genesisBlkRoot
 
 	if flags.Get().UnsafeSync {
 		headBlock, err := s.beaconDB.HeadBlock(ctx)
@@ -451,7 +495,18 @@ func (s *Service) pruneGarbageState(ctx context.Context, slot uint64) error {
 		return nil
 	}
 
-	filter := filters.NewFilter().SetStartSlot(1).SetEndSlot(slot)
+	// Blocks before the block history start slot of a node started from a checkpoint are not available.
+	startSlot, err := s.beaconDB.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		return err
+	}
+	if startSlot < 1 {
+		startSlot = 1
+	}
+	if startSlot > slot {
+		return nil
+	}
+	filter := filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(slot)
 	roots, err := s.beaconDB.BlockRoots(ctx, filter)
 	if err != nil {
 		return err
@@ -16,6 +16,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/sirupsen/logrus"
@@ -39,6 +40,7 @@ type ChainService struct {
 	blockNotifier               blockfeed.Notifier
 	opNotifier                  opfeed.Notifier
 	ValidAttestation            bool
+	ForkChoice                  *rpcpb.ForkChoiceDump
 }
 
 // StateNotifier mocks the same method in the chain service.
@@ -250,3 +252,24 @@ func (ms *ChainService) HasInitSyncBlock(root [32]byte) bool {
 func (ms *ChainService) HeadGenesisValidatorRoot() [32]byte {
 	return [32]byte{}
 }
+
+// ForkChoiceDump mocks the same method in the chain service.
+func (ms *ChainService) ForkChoiceDump() *rpcpb.ForkChoiceDump {
+	return ms.ForkChoice
+}
+
+// ForkChoiceSnapshots mocks the same method in the chain service.
+func (ms *ChainService) ForkChoiceSnapshots() ([]*rpcpb.ForkChoiceSnapshotInfo, error) {
+	if ms.ForkChoice == nil {
+		return []*rpcpb.ForkChoiceSnapshotInfo{}, nil
+	}
+	return []*rpcpb.ForkChoiceSnapshotInfo{{Timestamp: ms.ForkChoice.Timestamp, HeadSlot: ms.ForkChoice.HeadSlot}}, nil
+}
+
+// ForkChoiceSnapshot mocks the same method in the chain service.
+func (ms *ChainService) ForkChoiceSnapshot(timestamp uint64) (*rpcpb.ForkChoiceDump, error) {
+	if ms.ForkChoice == nil || ms.ForkChoice.Timestamp > timestamp {
+		return nil, errors.New("no fork choice snapshot found")
+	}
+	return ms.ForkChoice, nil
+}
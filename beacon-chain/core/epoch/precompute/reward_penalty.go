@@ -31,7 +31,7 @@ func ProcessRewardsAndPenaltiesPrecompute(
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get attestation delta")
 	}
-	proposerRewards, err := proposerDeltaPrecompute(state, bp, vp)
+	proposerRewards, err := ProposersDelta(state, bp, vp)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not get attestation delta")
 	}
@@ -123,9 +123,9 @@ func attestationDelta(state *stateTrie.BeaconState, bp *Balance, v *Validator) (
 	return r, p
 }
 
-// This computes the rewards and penalties differences for individual validators based on the
-// proposer inclusion records.
-func proposerDeltaPrecompute(state *stateTrie.BeaconState, bp *Balance, vp []*Validator) ([]uint64, error) {
+// ProposersDelta computes the rewards of individual validators for proposing blocks which include
+// attestations of the previous epoch, based on the proposer inclusion records.
+func ProposersDelta(state *stateTrie.BeaconState, bp *Balance, vp []*Validator) ([]uint64, error) {
 	numofVals := state.NumValidators()
 	rewards := make([]uint64, numofVals)
 
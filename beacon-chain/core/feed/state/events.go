@@ -11,6 +11,10 @@ const (
 	Initialized
 	// Synced is sent when the beacon node has completed syncing and is ready to participate in the network.
 	Synced
+	// NewHead is sent when the head of the chain changes.
+	NewHead
+	// Reorg is sent when the new head of the chain does not descend from the previous head.
+	Reorg
 )
 
 // BlockProcessedData is the data sent with BlockProcessed events.
@@ -42,3 +46,33 @@ type InitializedData struct {
 	// GenesisValidatorsRoot represents ssz.HashTreeRoot(state.validators).
 	GenesisValidatorsRoot []byte
 }
+
+// NewHeadData is the data sent with NewHead events.
+type NewHeadData struct {
+	// Slot is the slot of the new head block.
+	Slot uint64
+	// BlockRoot is the root of the new head block.
+	BlockRoot [32]byte
+	// StateRoot is the root of the state of the new head block.
+	StateRoot []byte
+	// EpochTransition is true if the new head is in a later epoch than the previous head.
+	EpochTransition bool
+}
+
+// ReorgData is the data sent with Reorg events.
+type ReorgData struct {
+	// NewSlot is the slot of the new head block.
+	NewSlot uint64
+	// OldSlot is the slot of the previous head block.
+	OldSlot uint64
+	// Depth is the number of slots from the common ancestor of both heads to the previous head.
+	Depth uint64
+	// NewHeadRoot is the root of the new head block.
+	NewHeadRoot [32]byte
+	// OldHeadRoot is the root of the previous head block.
+	OldHeadRoot [32]byte
+	// CommonAncestorRoot is the root of the latest block both heads descend from.
+	CommonAncestorRoot [32]byte
+	// CommonAncestorSlot is the slot of the latest block both heads descend from.
+	CommonAncestorSlot uint64
+}
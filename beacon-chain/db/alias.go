@@ -22,3 +22,12 @@ type HeadAccessDatabase = iface.HeadAccessDatabase
 // key-value or relational database in practice. This is the full database interface which should
 // not be used often. Prefer a more restrictive interface in this package.
 type Database = iface.Database
+
+// PruneOptions configures a pass deleting the data no longer needed after finalization.
+type PruneOptions = iface.PruneOptions
+
+// PruneReport summarizes the data deleted by a pruning pass.
+type PruneReport = iface.PruneReport
+
+// CompactionReport gives the size of the database file before and after a compaction.
+type CompactionReport = iface.CompactionReport
@@ -0,0 +1,7 @@
+package db
+
+import "github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+
+// ErrBlockHistoryUnavailable is returned by block queries over slots below the block history
+// start slot, which a node started from a checkpoint has not backfilled yet.
+var ErrBlockHistoryUnavailable = kv.ErrBlockHistoryUnavailable
@@ -12,6 +12,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/proto/beacon/db"
 	ethereum_beacon_p2p_v1 "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 )
 
 // ReadOnlyDatabase -- See github.com/prysmaticlabs/prysm/beacon-chain/db.ReadOnlyDatabase
@@ -45,6 +46,15 @@ type ReadOnlyDatabase interface {
 	// Block operations.
 	VoluntaryExit(ctx context.Context, exitRoot [32]byte) (*eth.VoluntaryExit, error)
 	HasVoluntaryExit(ctx context.Context, exitRoot [32]byte) bool
+	// Operation pool persistence.
+	PoolAttestations(ctx context.Context) ([]*eth.Attestation, error)
+	PoolProposerSlashings(ctx context.Context) ([]*eth.ProposerSlashing, error)
+	PoolAttesterSlashings(ctx context.Context) ([]*eth.AttesterSlashing, error)
+	PoolVoluntaryExits(ctx context.Context) ([]*eth.SignedVoluntaryExit, error)
+	// Reorg history.
+	Reorgs(ctx context.Context) ([]*rpcpb.Reorg, error)
+	// Validator performance.
+	ValidatorPerformance(ctx context.Context, validatorIdx uint64, startEpoch uint64, endEpoch uint64) ([]*rpcpb.ValidatorEpochPerformance, error)
 	// Checkpoint operations.
 	JustifiedCheckpoint(ctx context.Context) (*eth.Checkpoint, error)
 	FinalizedCheckpoint(ctx context.Context) (*eth.Checkpoint, error)
@@ -57,6 +67,10 @@ type ReadOnlyDatabase interface {
 	HasArchivedPoint(ctx context.Context, index uint64) bool
 	LastArchivedIndexRoot(ctx context.Context) [32]byte
 	LastArchivedIndex(ctx context.Context) (uint64, error)
+	// Checkpoint sync anchor and block history boundary.
+	AnchorBlockRoot(ctx context.Context) ([32]byte, error)
+	BlockHistoryStartSlot(ctx context.Context) (uint64, error)
+	BlockHistoryStartRoot(ctx context.Context) ([32]byte, error)
 	// Deposit contract related handlers.
 	DepositContractAddress(ctx context.Context) ([]byte, error)
 	// Powchain operations.
@@ -93,6 +107,18 @@ type NoHeadAccessDatabase interface {
 	// Block operations.
 	SaveVoluntaryExit(ctx context.Context, exit *eth.VoluntaryExit) error
 	DeleteVoluntaryExit(ctx context.Context, exitRoot [32]byte) error
+	// Operation pool persistence.
+	SavePoolAttestations(ctx context.Context, atts []*eth.Attestation) error
+	SavePoolProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error
+	DeletePoolProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error
+	SavePoolAttesterSlashing(ctx context.Context, slashing *eth.AttesterSlashing) error
+	DeletePoolAttesterSlashing(ctx context.Context, slashing *eth.AttesterSlashing) error
+	SavePoolVoluntaryExit(ctx context.Context, exit *eth.SignedVoluntaryExit) error
+	DeletePoolVoluntaryExit(ctx context.Context, exit *eth.SignedVoluntaryExit) error
+	// Reorg history.
+	SaveReorg(ctx context.Context, reorg *rpcpb.Reorg) error
+	// Validator performance.
+	SaveValidatorPerformance(ctx context.Context, records []*rpcpb.ValidatorEpochPerformance) error
 	// Checkpoint operations.
 	SaveJustifiedCheckpoint(ctx context.Context, checkpoint *eth.Checkpoint) error
 	SaveFinalizedCheckpoint(ctx context.Context, checkpoint *eth.Checkpoint) error
@@ -103,6 +129,10 @@ type NoHeadAccessDatabase interface {
 	SaveArchivedValidatorParticipation(ctx context.Context, epoch uint64, part *eth.ValidatorParticipation) error
 	SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte, index uint64) error
 	SaveLastArchivedIndex(ctx context.Context, index uint64) error
+	// Checkpoint sync anchor and block history boundary.
+	SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error
+	SaveBlockHistoryStartSlot(ctx context.Context, slot uint64) error
+	SaveBackfilledBlocks(ctx context.Context, blocks []*eth.SignedBeaconBlock) error
 	// Deposit contract related handlers.
 	SaveDepositContractAddress(ctx context.Context, addr common.Address) error
 	// Powchain operations.
@@ -118,6 +148,9 @@ type HeadAccessDatabase interface {
 	SaveHeadBlockRoot(ctx context.Context, blockRoot [32]byte) error
 	// State related methods.
 	HeadState(ctx context.Context) (*state.BeaconState, error)
+	// Pruning of finalized data, which protects the head.
+	Prune(ctx context.Context, opts *PruneOptions) (*PruneReport, error)
+	Compact(ctx context.Context) (*CompactionReport, error)
 }
 
 // Database -- See github.com/prysmaticlabs/prysm/beacon-chain/db.Database
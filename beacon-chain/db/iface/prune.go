@@ -0,0 +1,27 @@
+package iface
+
+// PruneOptions configures a pass deleting data which the node no longer needs after finalization.
+type PruneOptions struct {
+	// RetentionSlots is the number of slots before the finalized block for which canonical blocks
+	// are kept. Older canonical blocks are deleted as well if it is set, zero keeps all of them.
+	RetentionSlots uint64
+	// DryRun only reports the data a pass would delete, without deleting it.
+	DryRun bool
+}
+
+// PruneReport summarizes the data deleted by a pruning pass, or which would be deleted in a dry run.
+type PruneReport struct {
+	FinalizedSlot      uint64
+	NonCanonicalBlocks int
+	CanonicalBlocks    int
+	States             int
+	StateSummaries     int
+	Attestations       int
+	DryRun             bool
+}
+
+// CompactionReport gives the size of the database file before and after a compaction.
+type CompactionReport struct {
+	SizeBefore int64
+	SizeAfter  int64
+}
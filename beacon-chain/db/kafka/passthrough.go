@@ -7,10 +7,12 @@ import (
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/proto/beacon/db"
 	ethereum_beacon_p2p_v1 "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 )
 
 // DatabasePath -- passthrough.
@@ -103,6 +105,16 @@ func (e Exporter) HeadState(ctx context.Context) (*state.BeaconState, error) {
 	return e.db.HeadState(ctx)
 }
 
+// Prune -- passthrough.
+func (e Exporter) Prune(ctx context.Context, opts *iface.PruneOptions) (*iface.PruneReport, error) {
+	return e.db.Prune(ctx, opts)
+}
+
+// Compact -- passthrough.
+func (e Exporter) Compact(ctx context.Context) (*iface.CompactionReport, error) {
+	return e.db.Compact(ctx)
+}
+
 // GenesisState -- passthrough.
 func (e Exporter) GenesisState(ctx context.Context) (*state.BeaconState, error) {
 	return e.db.GenesisState(ctx)
@@ -153,6 +165,41 @@ func (e Exporter) DeleteVoluntaryExit(ctx context.Context, exitRoot [32]byte) er
 	return e.db.DeleteVoluntaryExit(ctx, exitRoot)
 }
 
+// Reorgs -- passthrough.
+func (e Exporter) Reorgs(ctx context.Context) ([]*rpcpb.Reorg, error) {
+	return e.db.Reorgs(ctx)
+}
+
+// ValidatorPerformance -- passthrough.
+func (e Exporter) ValidatorPerformance(
+	ctx context.Context,
+	validatorIdx uint64,
+	startEpoch uint64,
+	endEpoch uint64,
+) ([]*rpcpb.ValidatorEpochPerformance, error) {
+	return e.db.ValidatorPerformance(ctx, validatorIdx, startEpoch, endEpoch)
+}
+
+// PoolAttestations -- passthrough.
+func (e Exporter) PoolAttestations(ctx context.Context) ([]*eth.Attestation, error) {
+	return e.db.PoolAttestations(ctx)
+}
+
+// PoolProposerSlashings -- passthrough.
+func (e Exporter) PoolProposerSlashings(ctx context.Context) ([]*eth.ProposerSlashing, error) {
+	return e.db.PoolProposerSlashings(ctx)
+}
+
+// PoolAttesterSlashings -- passthrough.
+func (e Exporter) PoolAttesterSlashings(ctx context.Context) ([]*eth.AttesterSlashing, error) {
+	return e.db.PoolAttesterSlashings(ctx)
+}
+
+// PoolVoluntaryExits -- passthrough.
+func (e Exporter) PoolVoluntaryExits(ctx context.Context) ([]*eth.SignedVoluntaryExit, error) {
+	return e.db.PoolVoluntaryExits(ctx)
+}
+
 // JustifiedCheckpoint -- passthrough.
 func (e Exporter) JustifiedCheckpoint(ctx context.Context) (*eth.Checkpoint, error) {
 	return e.db.JustifiedCheckpoint(ctx)
@@ -238,6 +285,51 @@ func (e Exporter) SaveVoluntaryExit(ctx context.Context, exit *eth.VoluntaryExit
 	return e.db.SaveVoluntaryExit(ctx, exit)
 }
 
+// SaveReorg -- passthrough.
+func (e Exporter) SaveReorg(ctx context.Context, reorg *rpcpb.Reorg) error {
+	return e.db.SaveReorg(ctx, reorg)
+}
+
+// SaveValidatorPerformance -- passthrough.
+func (e Exporter) SaveValidatorPerformance(ctx context.Context, records []*rpcpb.ValidatorEpochPerformance) error {
+	return e.db.SaveValidatorPerformance(ctx, records)
+}
+
+// SavePoolAttestations -- passthrough.
+func (e Exporter) SavePoolAttestations(ctx context.Context, atts []*eth.Attestation) error {
+	return e.db.SavePoolAttestations(ctx, atts)
+}
+
+// SavePoolProposerSlashing -- passthrough.
+func (e Exporter) SavePoolProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error {
+	return e.db.SavePoolProposerSlashing(ctx, slashing)
+}
+
+// DeletePoolProposerSlashing -- passthrough.
+func (e Exporter) DeletePoolProposerSlashing(ctx context.Context, slashing *eth.ProposerSlashing) error {
+	return e.db.DeletePoolProposerSlashing(ctx, slashing)
+}
+
+// SavePoolAttesterSlashing -- passthrough.
+func (e Exporter) SavePoolAttesterSlashing(ctx context.Context, slashing *eth.AttesterSlashing) error {
+	return e.db.SavePoolAttesterSlashing(ctx, slashing)
+}
+
+// DeletePoolAttesterSlashing -- passthrough.
+func (e Exporter) DeletePoolAttesterSlashing(ctx context.Context, slashing *eth.AttesterSlashing) error {
+	return e.db.DeletePoolAttesterSlashing(ctx, slashing)
+}
+
+// SavePoolVoluntaryExit -- passthrough.
+func (e Exporter) SavePoolVoluntaryExit(ctx context.Context, exit *eth.SignedVoluntaryExit) error {
+	return e.db.SavePoolVoluntaryExit(ctx, exit)
+}
+
+// DeletePoolVoluntaryExit -- passthrough.
+func (e Exporter) DeletePoolVoluntaryExit(ctx context.Context, exit *eth.SignedVoluntaryExit) error {
+	return e.db.DeletePoolVoluntaryExit(ctx, exit)
+}
+
 // SaveJustifiedCheckpoint -- passthrough.
 func (e Exporter) SaveJustifiedCheckpoint(ctx context.Context, checkpoint *eth.Checkpoint) error {
 	return e.db.SaveJustifiedCheckpoint(ctx, checkpoint)
@@ -358,7 +450,37 @@ func (e Exporter) LastArchivedIndex(ctx context.Context) (uint64, error) {
 	return e.db.LastArchivedIndex(ctx)
 }
 
+// AnchorBlockRoot -- passthrough
+func (e Exporter) AnchorBlockRoot(ctx context.Context) ([32]byte, error) {
+	return e.db.AnchorBlockRoot(ctx)
+}
+
+// SaveAnchorBlockRoot -- passthrough
+func (e Exporter) SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	return e.db.SaveAnchorBlockRoot(ctx, blockRoot)
+}
+
+// BlockHistoryStartSlot -- passthrough
+func (e Exporter) BlockHistoryStartSlot(ctx context.Context) (uint64, error) {
+	return e.db.BlockHistoryStartSlot(ctx)
+}
+
+// SaveBlockHistoryStartSlot -- passthrough
+func (e Exporter) SaveBlockHistoryStartSlot(ctx context.Context, slot uint64) error {
+	return e.db.SaveBlockHistoryStartSlot(ctx, slot)
+}
+
 // HistoricalStatesDeleted -- passthrough
 func (e Exporter) HistoricalStatesDeleted(ctx context.Context) error {
 	return e.db.HistoricalStatesDeleted(ctx)
 }
+
+// BlockHistoryStartRoot -- passthrough
+func (e Exporter) BlockHistoryStartRoot(ctx context.Context) ([32]byte, error) {
+	return e.db.BlockHistoryStartRoot(ctx)
+}
+
+// SaveBackfilledBlocks -- passthrough
+func (e Exporter) SaveBackfilledBlocks(ctx context.Context, blocks []*eth.SignedBeaconBlock) error {
+	return e.db.SaveBackfilledBlocks(ctx, blocks)
+}
@@ -0,0 +1,128 @@
+package kv
+
+import (
+	"context"
+	"errors"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// ErrBlockHistoryUnavailable is returned by block queries over a slot range starting below the
+// block history start slot. A node started from a checkpoint has no blocks before its anchor
+// until they have been backfilled, and an empty result would wrongly suggest those slots were skipped.
+var ErrBlockHistoryUnavailable = errors.New("blocks below the block history start slot are not available")
+
+// AnchorBlockRoot returns the root of the finalized block the node was started from with
+// checkpoint sync, or the zero hash if the node was started from genesis.
+func (k *Store) AnchorBlockRoot(ctx context.Context) ([32]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.AnchorBlockRoot")
+	defer span.End()
+	var root [32]byte
+	err := k.view(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(chainMetadataBucket).Get(anchorBlockRootKey)
+		if enc != nil {
+			root = bytesutil.ToBytes32(enc)
+		}
+		return nil
+	})
+	return root, err
+}
+
+// SaveAnchorBlockRoot saves the root of the block the node was started from with checkpoint sync.
+func (k *Store) SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveAnchorBlockRoot")
+	defer span.End()
+	return k.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(chainMetadataBucket).Put(anchorBlockRootKey, blockRoot[:])
+	})
+}
+
+// BlockHistoryStartSlot returns the slot from which the node holds every block up to its head.
+// It is 0 for a node started from genesis, and the slot of the anchor block for a node started
+// from a checkpoint, until older blocks are backfilled.
+func (k *Store) BlockHistoryStartSlot(ctx context.Context) (uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.BlockHistoryStartSlot")
+	defer span.End()
+	var slot uint64
+	err := k.view(func(tx *bolt.Tx) error {
+		slot = blockHistoryStartSlot(tx)
+		return nil
+	})
+	return slot, err
+}
+
+// SaveBlockHistoryStartSlot saves the slot from which the node holds every block up to its head.
+func (k *Store) SaveBlockHistoryStartSlot(ctx context.Context, slot uint64) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBlockHistoryStartSlot")
+	defer span.End()
+	return k.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(chainMetadataBucket).Put(blockHistoryStartSlotKey, bytesutil.Uint64ToBytes(slot))
+	})
+}
+
+// BlockHistoryStartRoot returns the root of the oldest block of the block history, which is the
+// anchor block root until older blocks are backfilled.
+func (k *Store) BlockHistoryStartRoot(ctx context.Context) ([32]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.BlockHistoryStartRoot")
+	defer span.End()
+	var root [32]byte
+	err := k.view(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(chainMetadataBucket)
+		enc := bkt.Get(blockHistoryStartRootKey)
+		if enc == nil {
+			enc = bkt.Get(anchorBlockRootKey)
+		}
+		if enc != nil {
+			root = bytesutil.ToBytes32(enc)
+		}
+		return nil
+	})
+	return root, err
+}
+
+// SaveBackfilledBlocks saves a batch of blocks preceding the block history, sorted by increasing
+// slot, and moves the start of the block history to the first of them in the same transaction.
+// The caller is expected to have verified that the blocks link up to the current history start.
+func (k *Store) SaveBackfilledBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBlock) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBackfilledBlocks")
+	defer span.End()
+	if len(blocks) == 0 {
+		return nil
+	}
+	oldest := blocks[0].Block
+	oldestRoot, err := stateutil.BlockRoot(oldest)
+	if err != nil {
+		return err
+	}
+	return k.update(func(tx *bolt.Tx) error {
+		if oldest.Slot >= blockHistoryStartSlot(tx) {
+			return errors.New("backfilled blocks must precede the block history start slot")
+		}
+		if err := k.saveBlocks(ctx, tx, blocks); err != nil {
+			return err
+		}
+		bkt := tx.Bucket(chainMetadataBucket)
+		if err := bkt.Put(blockHistoryStartSlotKey, bytesutil.Uint64ToBytes(oldest.Slot)); err != nil {
+			return err
+		}
+		if err := bkt.Put(blockHistoryStartRootKey, oldestRoot[:]); err != nil {
+			return err
+		}
+		if oldest.Slot == 0 {
+			return tx.Bucket(blocksBucket).Put(genesisBlockRootKey, oldestRoot[:])
+		}
+		return nil
+	})
+}
+
+func blockHistoryStartSlot(tx *bolt.Tx) uint64 {
+	enc := tx.Bucket(chainMetadataBucket).Get(blockHistoryStartSlotKey)
+	if enc == nil {
+		return 0
+	}
+	return bytesutil.FromBytes8(enc)
+}
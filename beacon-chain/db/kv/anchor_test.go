@@ -0,0 +1,213 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStore_AnchorBlockRoot_CanSaveRetrieve(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	root, err := db.AnchorBlockRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != [32]byte{} {
+		t.Errorf("Expected no anchor root, received %#x", root)
+	}
+	want := [32]byte{'A'}
+	if err := db.SaveAnchorBlockRoot(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	root, err = db.AnchorBlockRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != want {
+		t.Errorf("Wanted %#x, received %#x", want, root)
+	}
+}
+
+func TestStore_Blocks_BelowBlockHistoryStart(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	b := make([]*ethpb.SignedBeaconBlock, 0, 20)
+	for i := uint64(100); i < 120; i++ {
+		b = append(b, &ethpb.SignedBeaconBlock{
+			Block: &ethpb.BeaconBlock{
+				ParentRoot: []byte("parent"),
+				Slot:       i,
+			},
+		})
+	}
+	if err := db.SaveBlocks(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlockHistoryStartSlot(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	start, err := db.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 100 {
+		t.Errorf("Wanted block history start slot 100, received %d", start)
+	}
+
+	retrieved, err := db.Blocks(ctx, filters.NewFilter().SetStartSlot(100).SetEndSlot(109))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retrieved) != 10 {
+		t.Errorf("Wanted 10 blocks, received %d", len(retrieved))
+	}
+
+	if _, err := db.Blocks(ctx, filters.NewFilter().SetStartSlot(50).SetEndSlot(109)); err != ErrBlockHistoryUnavailable {
+		t.Errorf("Wanted %v, received %v", ErrBlockHistoryUnavailable, err)
+	}
+	if _, err := db.BlockRoots(ctx, filters.NewFilter().SetStartSlot(0).SetEndSlot(10)); errors.Cause(err) != ErrBlockHistoryUnavailable {
+		t.Errorf("Wanted %v, received %v", ErrBlockHistoryUnavailable, err)
+	}
+	// Queries without a slot range are not affected.
+	if _, err := db.Blocks(ctx, filters.NewFilter().SetParentRoot([]byte("parent"))); err != nil {
+		t.Errorf("Unexpected error for a query without a slot range: %v", err)
+	}
+}
+
+func TestStore_SaveFinalizedCheckpoint_FromAnchor(t *testing.T) {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	// The anchor block's parent is not in the database.
+	anchor := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 2 * slotsPerEpoch, ParentRoot: []byte("missing")}}
+	anchorRoot, err := ssz.HashTreeRoot(anchor.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, anchor); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlockHistoryStartSlot(ctx, anchor.Block.Slot); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 2, Root: anchorRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	if !db.IsFinalizedBlock(ctx, anchorRoot) {
+		t.Error("Expected anchor block to be finalized")
+	}
+
+	// Later checkpoints are indexed back to the anchor.
+	blks := makeBlocks(t, int(2*slotsPerEpoch), int(slotsPerEpoch), anchorRoot)
+	if err := db.SaveBlocks(ctx, blks); err != nil {
+		t.Fatal(err)
+	}
+	root, err := ssz.HashTreeRoot(blks[len(blks)-1].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 3, Root: root[:]}); err != nil {
+		t.Fatal(err)
+	}
+	for i, b := range blks {
+		root, err := ssz.HashTreeRoot(b.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !db.IsFinalizedBlock(ctx, root) {
+			t.Errorf("Block at index %d was not considered finalized in the index", i)
+		}
+	}
+}
+
+func TestStore_SaveBackfilledBlocks(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	anchorRoot := [32]byte{'A'}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlockHistoryStartSlot(ctx, 64); err != nil {
+		t.Fatal(err)
+	}
+	root, err := db.BlockHistoryStartRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != anchorRoot {
+		t.Errorf("Wanted history start root %#x, received %#x", anchorRoot, root)
+	}
+
+	blks := makeBlocks(t, 31, 10, [32]byte{})
+	if err := db.SaveBackfilledBlocks(ctx, blks); err != nil {
+		t.Fatal(err)
+	}
+	wantRoot, err := ssz.HashTreeRoot(blks[0].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err = db.BlockHistoryStartRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != wantRoot {
+		t.Errorf("Wanted history start root %#x, received %#x", wantRoot, root)
+	}
+	start, err := db.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 32 {
+		t.Errorf("Wanted block history start slot 32, received %d", start)
+	}
+	retrieved, err := db.Blocks(ctx, filters.NewFilter().SetStartSlot(32).SetEndSlot(63))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retrieved) != len(blks) {
+		t.Errorf("Wanted %d blocks, received %d", len(blks), len(retrieved))
+	}
+
+	// Blocks which do not precede the history are rejected.
+	if err := db.SaveBackfilledBlocks(ctx, makeBlocks(t, 40, 1, [32]byte{})); err == nil {
+		t.Error("Expected an error when saving blocks after the history start")
+	}
+
+	// Backfilling the genesis block completes the history.
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 0, ParentRoot: make([]byte, 32)}}
+	if err := db.SaveBackfilledBlocks(ctx, []*ethpb.SignedBeaconBlock{genesis}); err != nil {
+		t.Fatal(err)
+	}
+	genesisBlock, err := db.GenesisBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if genesisBlock == nil || genesisBlock.Block.Slot != 0 {
+		t.Errorf("Expected genesis block to be saved, received %v", genesisBlock)
+	}
+}
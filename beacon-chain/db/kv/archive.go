@@ -18,7 +18,7 @@ func (k *Store) ArchivedActiveValidatorChanges(ctx context.Context, epoch uint64
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target *pb.ArchivedActiveSetChanges
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(archivedValidatorSetChangesBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -39,7 +39,7 @@ func (k *Store) SaveArchivedActiveValidatorChanges(ctx context.Context, epoch ui
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedValidatorSetChangesBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -52,7 +52,7 @@ func (k *Store) ArchivedCommitteeInfo(ctx context.Context, epoch uint64) (*pb.Ar
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target *pb.ArchivedCommitteeInfo
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(archivedCommitteeInfoBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -73,7 +73,7 @@ func (k *Store) SaveArchivedCommitteeInfo(ctx context.Context, epoch uint64, inf
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedCommitteeInfoBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -86,7 +86,7 @@ func (k *Store) ArchivedBalances(ctx context.Context, epoch uint64) ([]uint64, e
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target []uint64
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(archivedBalancesBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -104,7 +104,7 @@ func (k *Store) SaveArchivedBalances(ctx context.Context, epoch uint64, balances
 	defer span.End()
 	buf := bytesutil.Uint64ToBytes(epoch)
 	enc := marshalBalances(balances)
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedBalancesBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -117,7 +117,7 @@ func (k *Store) ArchivedValidatorParticipation(ctx context.Context, epoch uint64
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target *ethpb.ValidatorParticipation
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(archivedValidatorParticipationBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -138,7 +138,7 @@ func (k *Store) SaveArchivedValidatorParticipation(ctx context.Context, epoch ui
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedValidatorParticipationBucket)
 		return bucket.Put(buf, enc)
 	})
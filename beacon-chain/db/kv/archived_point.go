@@ -14,7 +14,7 @@ func (k *Store) SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte, i
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveArchivedPointRoot")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		return bucket.Put(bytesutil.Uint64ToBytes(index), blockRoot[:])
 	})
@@ -24,7 +24,7 @@ func (k *Store) SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte, i
 func (k *Store) SaveLastArchivedIndex(ctx context.Context, index uint64) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveHeadBlockRoot")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		return bucket.Put(lastArchivedIndexKey, bytesutil.Uint64ToBytes(index))
 	})
@@ -35,7 +35,7 @@ func (k *Store) LastArchivedIndex(ctx context.Context) (uint64, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.LastArchivedIndex")
 	defer span.End()
 	var index uint64
-	err := k.db.Update(func(tx *bolt.Tx) error {
+	err := k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		b := bucket.Get(lastArchivedIndexKey)
 		if b == nil {
@@ -54,7 +54,7 @@ func (k *Store) LastArchivedIndexRoot(ctx context.Context) [32]byte {
 	defer span.End()
 
 	var blockRoot []byte
-	if err := k.db.View(func(tx *bolt.Tx) error {
+	if err := k.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		lastArchivedIndex := bucket.Get(lastArchivedIndexKey)
 		if lastArchivedIndex == nil {
@@ -76,7 +76,7 @@ func (k *Store) ArchivedPointRoot(ctx context.Context, index uint64) [32]byte {
 	defer span.End()
 
 	var blockRoot []byte
-	if err := k.db.View(func(tx *bolt.Tx) error {
+	if err := k.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		blockRoot = bucket.Get(bytesutil.Uint64ToBytes(index))
 		return nil
@@ -92,7 +92,7 @@ func (k *Store) HasArchivedPoint(ctx context.Context, index uint64) bool {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HasArchivedPoint")
 	defer span.End()
 	var exists bool
-	if err := k.db.View(func(tx *bolt.Tx) error {
+	if err := k.view(func(tx *bolt.Tx) error {
 		iBucket := tx.Bucket(archivedIndexRootBucket)
 		exists = iBucket.Get(bytesutil.Uint64ToBytes(index)) != nil
 		return nil
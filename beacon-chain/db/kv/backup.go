@@ -45,7 +45,7 @@ func (k *Store) Backup(ctx context.Context) error {
 		}
 	}()
 
-	return k.db.View(func(tx *bolt.Tx) error {
+	return k.view(func(tx *bolt.Tx) error {
 		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
 			logrus.Debugf("Copying bucket %s\n", name)
 			return copyDB.Update(func(tx2 *bolt.Tx) error {
@@ -31,7 +31,7 @@ func (k *Store) Block(ctx context.Context, blockRoot [32]byte) (*ethpb.SignedBea
 		return v.(*ethpb.SignedBeaconBlock), nil
 	}
 	var block *ethpb.SignedBeaconBlock
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		enc := bkt.Get(blockRoot[:])
 		if enc == nil {
@@ -48,7 +48,7 @@ func (k *Store) HeadBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error)
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HeadBlock")
 	defer span.End()
 	var headBlock *ethpb.SignedBeaconBlock
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		headRoot := bkt.Get(headBlockRootKey)
 		if headRoot == nil {
@@ -69,7 +69,7 @@ func (k *Store) Blocks(ctx context.Context, f *filters.QueryFilter) ([]*ethpb.Si
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Blocks")
 	defer span.End()
 	blocks := make([]*ethpb.SignedBeaconBlock, 0)
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 
 		keys, err := getBlockRootsByFilter(ctx, tx, f)
@@ -95,7 +95,7 @@ func (k *Store) BlockRoots(ctx context.Context, f *filters.QueryFilter) ([][32]b
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.BlockRoots")
 	defer span.End()
 	blockRoots := make([][32]byte, 0)
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		keys, err := getBlockRootsByFilter(ctx, tx, f)
 		if err != nil {
 			return err
@@ -120,7 +120,7 @@ func (k *Store) HasBlock(ctx context.Context, blockRoot [32]byte) bool {
 		return true
 	}
 	exists := false
-	if err := k.db.View(func(tx *bolt.Tx) error {
+	if err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		exists = bkt.Get(blockRoot[:]) != nil
 		return nil
@@ -134,7 +134,7 @@ func (k *Store) HasBlock(ctx context.Context, blockRoot [32]byte) bool {
 func (k *Store) DeleteBlock(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteBlock")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		enc := bkt.Get(blockRoot[:])
 		if enc == nil {
@@ -161,7 +161,7 @@ func (k *Store) DeleteBlocks(ctx context.Context, blockRoots [][32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteBlocks")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		for _, blockRoot := range blockRoots {
 			enc := bkt.Get(blockRoot[:])
@@ -199,7 +199,7 @@ func (k *Store) SaveBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 	if v, ok := k.blockCache.Get(string(blockRoot[:])); v != nil && ok {
 		return nil
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		if err := k.setBlockSlotBitField(ctx, tx, signed.Block.Slot); err != nil {
 			return err
 		}
@@ -226,43 +226,47 @@ func (k *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBloc
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBlocks")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
-		bkt := tx.Bucket(blocksBucket)
-		for _, block := range blocks {
-			if err := k.setBlockSlotBitField(ctx, tx, block.Block.Slot); err != nil {
-				return err
-			}
-			blockRoot, err := stateutil.BlockRoot(block.Block)
-			if err != nil {
-				return err
-			}
+	return k.update(func(tx *bolt.Tx) error {
+		return k.saveBlocks(ctx, tx, blocks)
+	})
+}
 
-			if existingBlock := bkt.Get(blockRoot[:]); existingBlock != nil {
-				continue
-			}
-			enc, err := encode(block)
-			if err != nil {
-				return err
-			}
-			indicesByBucket := createBlockIndicesFromBlock(block.Block)
-			if err := updateValueForIndices(indicesByBucket, blockRoot[:], tx); err != nil {
-				return errors.Wrap(err, "could not update DB indices")
-			}
-			k.blockCache.Set(string(blockRoot[:]), block, int64(len(enc)))
+func (k *Store) saveBlocks(ctx context.Context, tx *bolt.Tx, blocks []*ethpb.SignedBeaconBlock) error {
+	bkt := tx.Bucket(blocksBucket)
+	for _, block := range blocks {
+		if err := k.setBlockSlotBitField(ctx, tx, block.Block.Slot); err != nil {
+			return err
+		}
+		blockRoot, err := stateutil.BlockRoot(block.Block)
+		if err != nil {
+			return err
+		}
 
-			if err := bkt.Put(blockRoot[:], enc); err != nil {
-				return err
-			}
+		if existingBlock := bkt.Get(blockRoot[:]); existingBlock != nil {
+			continue
 		}
-		return nil
-	})
+		enc, err := encode(block)
+		if err != nil {
+			return err
+		}
+		indicesByBucket := createBlockIndicesFromBlock(block.Block)
+		if err := updateValueForIndices(indicesByBucket, blockRoot[:], tx); err != nil {
+			return errors.Wrap(err, "could not update DB indices")
+		}
+		k.blockCache.Set(string(blockRoot[:]), block, int64(len(enc)))
+
+		if err := bkt.Put(blockRoot[:], enc); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SaveHeadBlockRoot to the db.
 func (k *Store) SaveHeadBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveHeadBlockRoot")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		if featureconfig.Get().NewStateMgmt {
 			hasStateSummaryInCache := k.stateSummaryCache.Has(blockRoot)
 			hasStateSummaryInDB := tx.Bucket(stateSummaryBucket).Get(blockRoot[:]) != nil
@@ -286,7 +290,7 @@ func (k *Store) GenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, err
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisBlock")
 	defer span.End()
 	var block *ethpb.SignedBeaconBlock
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		root := bkt.Get(genesisBlockRootKey)
 		enc := bkt.Get(root)
@@ -303,7 +307,7 @@ func (k *Store) GenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, err
 func (k *Store) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveGenesisBlockRoot")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(blocksBucket)
 		return bucket.Put(genesisBlockRootKey, blockRoot[:])
 	})
@@ -315,7 +319,7 @@ func (k *Store) HighestSlotBlocks(ctx context.Context) ([]*ethpb.SignedBeaconBlo
 	defer span.End()
 
 	blocks := make([]*ethpb.SignedBeaconBlock, 0)
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		sBkt := tx.Bucket(slotsHasObjectBucket)
 		savedSlots := sBkt.Get(savedBlockSlotsKey)
 		highestIndex, err := bytesutil.HighestBitIndex(savedSlots)
@@ -339,7 +343,7 @@ func (k *Store) HighestSlotBlocksBelow(ctx context.Context, slot uint64) ([]*eth
 	defer span.End()
 
 	blocks := make([]*ethpb.SignedBeaconBlock, 0)
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		sBkt := tx.Bucket(slotsHasObjectBucket)
 		savedSlots := sBkt.Get(savedBlockSlotsKey)
 		if len(savedSlots) == 0 {
@@ -459,6 +463,9 @@ func getBlockRootsByFilter(ctx context.Context, tx *bolt.Tx, f *filters.QueryFil
 
 	// We retrieve block roots that match a filter criteria of slot ranges, if specified.
 	filtersMap := f.Filters()
+	if startSlot, ok := filterStartSlot(filtersMap); ok && startSlot < blockHistoryStartSlot(tx) {
+		return nil, ErrBlockHistoryUnavailable
+	}
 	rootsBySlotRange := fetchBlockRootsBySlotRange(
 		tx.Bucket(blockSlotIndicesBucket),
 		filtersMap[filters.StartSlot],
@@ -492,6 +499,17 @@ func getBlockRootsByFilter(ctx context.Context, tx *bolt.Tx, f *filters.QueryFil
 	return keys, nil
 }
 
+// filterStartSlot returns the first slot of the slot range requested by a filter, if any.
+func filterStartSlot(filtersMap map[filters.FilterType]interface{}) (uint64, bool) {
+	if startSlot, ok := filtersMap[filters.StartSlot].(uint64); ok {
+		return startSlot, true
+	}
+	if startEpoch, ok := filtersMap[filters.StartEpoch].(uint64); ok {
+		return helpers.StartSlot(startEpoch), true
+	}
+	return 0, false
+}
+
 // fetchBlockRootsBySlotRange looks into a boltDB bucket and performs a binary search
 // range scan using sorted left-padded byte keys using a start slot and an end slot.
 // If both the start and end slot are the same, and are 0, the function returns nil.
@@ -14,14 +14,14 @@ var historicalStateDeletedKey = []byte("historical-states-deleted")
 // HistoricalStatesDeleted verifies historical states exist in DB.
 func (kv *Store) HistoricalStatesDeleted(ctx context.Context) error {
 	if !featureconfig.Get().NewStateMgmt {
-		return kv.db.Update(func(tx *bolt.Tx) error {
+		return kv.update(func(tx *bolt.Tx) error {
 			bkt := tx.Bucket(newStateServiceCompatibleBucket)
 			return bkt.Put(historicalStateDeletedKey, []byte{0x01})
 		})
 	}
 
 	var historicalStateDeleted bool
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(newStateServiceCompatibleBucket)
 		v := bkt.Get(historicalStateDeletedKey)
 		historicalStateDeleted = len(v) == 1 && v[0] == 0x01
@@ -37,7 +37,7 @@ func (kv *Store) HistoricalStatesDeleted(ctx context.Context) error {
 		}
 	}
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(newStateServiceCompatibleBucket)
 		return bkt.Put(historicalStateDeletedKey, []byte{0x00})
 	})
@@ -0,0 +1,167 @@
+package kv
+
+import (
+	"context"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+const (
+	compactionFileSuffix = ".compact"
+	// compactionTxMaxSize is the number of bytes copied to the new file before a commit, which
+	// bounds the memory held by a single write transaction.
+	compactionTxMaxSize = 64 * 1024 * 1024
+)
+
+// Compact copies the database into a new file and swaps it with the current one. Bolt never
+// returns the pages freed by deleted data to the file system, so the file only shrinks this way.
+// Transactions are held back while the database is copied and swapped.
+func (k *Store) Compact(ctx context.Context) (*iface.CompactionReport, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Compact")
+	defer span.End()
+
+	datafile := path.Join(k.databasePath, databaseFileName)
+	compactFile := datafile + compactionFileSuffix
+	report := &iface.CompactionReport{}
+
+	k.lockForSwap()
+	defer k.unlockForSwap()
+
+	info, err := os.Stat(datafile)
+	if err != nil {
+		return nil, err
+	}
+	report.SizeBefore = info.Size()
+
+	if err := os.Remove(compactFile); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	dst, err := bolt.Open(compactFile, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := copyDB(dst, k.db); err != nil {
+		if closeErr := dst.Close(); closeErr != nil {
+			logrus.WithError(closeErr).Error("Failed to close compacted database")
+		}
+		if rmErr := os.Remove(compactFile); rmErr != nil {
+			logrus.WithError(rmErr).Error("Failed to remove compacted database")
+		}
+		return nil, errors.Wrap(err, "could not copy database")
+	}
+	if err := dst.Close(); err != nil {
+		return nil, err
+	}
+
+	prometheus.Unregister(createBoltCollector(k.db))
+	if err := k.db.Close(); err != nil {
+		return nil, err
+	}
+	// The rename atomically replaces the database file, so either file is complete if it fails.
+	renameErr := os.Rename(compactFile, datafile)
+	boltDB, err := openBoltDB(datafile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not reopen database")
+	}
+	k.db = boltDB
+	if err := prometheus.Register(createBoltCollector(k.db)); err != nil {
+		logrus.WithError(err).Error("Failed to register database metrics")
+	}
+	if renameErr != nil {
+		return nil, errors.Wrap(renameErr, "could not replace database file")
+	}
+
+	info, err = os.Stat(datafile)
+	if err != nil {
+		return nil, err
+	}
+	report.SizeAfter = info.Size()
+	return report, nil
+}
+
+// copyDB copies every bucket of the source database into the destination, along with the nested
+// buckets and the sequence of each bucket, committing the destination transaction whenever it
+// reaches the maximum size.
+func copyDB(dst *bolt.DB, src *bolt.DB) error {
+	tx, err := dst.Begin(true)
+	if err != nil {
+		return err
+	}
+	c := &dbCopier{dst: dst, tx: tx}
+	defer func() {
+		// Rolling back after a commit does nothing.
+		_ = c.tx.Rollback()
+	}()
+
+	return src.View(func(srcTx *bolt.Tx) error {
+		if err := srcTx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return c.copyBucket([][]byte{name}, b)
+		}); err != nil {
+			return err
+		}
+		return c.tx.Commit()
+	})
+}
+
+// dbCopier holds the destination transaction of a database copy, which is replaced whenever it
+// is committed. Buckets are looked up by their path, as they belong to a single transaction.
+type dbCopier struct {
+	dst  *bolt.DB
+	tx   *bolt.Tx
+	size int64
+}
+
+func (c *dbCopier) copyBucket(path [][]byte, src *bolt.Bucket) error {
+	dstBkt, err := c.bucket(path)
+	if err != nil {
+		return err
+	}
+	// Keys such as the reorg history are taken from the bucket sequence, which must not restart.
+	if err := dstBkt.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(key []byte, value []byte) error {
+		if value == nil {
+			return c.copyBucket(append(path[:len(path):len(path)], key), src.Bucket(key))
+		}
+		if c.size+int64(len(key)+len(value)) > compactionTxMaxSize {
+			if err := c.tx.Commit(); err != nil {
+				return err
+			}
+			tx, err := c.dst.Begin(true)
+			if err != nil {
+				return err
+			}
+			c.tx = tx
+			c.size = 0
+		}
+		c.size += int64(len(key) + len(value))
+		b, err := c.bucket(path)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, value)
+	})
+}
+
+// bucket returns the destination bucket at the given path, creating it if needed.
+func (c *dbCopier) bucket(path [][]byte) (*bolt.Bucket, error) {
+	b, err := c.tx.CreateBucketIfNotExists(path[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range path[1:] {
+		if b, err = b.CreateBucketIfNotExists(name); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,116 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore_Compact(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	blocks := make([]*ethpb.SignedBeaconBlock, 1000)
+	roots := make([][32]byte, len(blocks))
+	for i := range blocks {
+		blocks[i] = &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{
+			Slot:       uint64(i),
+			ParentRoot: make([]byte, 32),
+			Body:       &ethpb.BeaconBlockBody{Graffiti: make([]byte, 32)},
+		}}
+		root, err := stateutil.BlockRoot(blocks[i].Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots[i] = root
+	}
+	if err := db.SaveBlocks(ctx, blocks); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteBlocks(ctx, roots[1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := db.Compact(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SizeAfter > report.SizeBefore {
+		t.Errorf("Wanted the database to shrink from %d bytes, received %d bytes", report.SizeBefore, report.SizeAfter)
+	}
+
+	// The compacted database is used from then on.
+	if !db.HasBlock(ctx, roots[0]) {
+		t.Error("Expected the block to be kept by the compaction")
+	}
+	if err := db.SaveBlock(ctx, blocks[1]); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := db.Block(ctx, roots[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blk == nil || blk.Block.Slot != 1 {
+		t.Errorf("Wanted the block saved after compaction, received %v", blk)
+	}
+}
+
+func TestStore_Compact_KeepsBucketSequences(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	for slot := uint64(1); slot <= 3; slot++ {
+		if err := db.SaveReorg(ctx, &rpcpb.Reorg{NewHeadSlot: slot}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	nested := []byte("nested")
+	if err := db.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.Bucket(reorgsBucket).CreateBucket(nested)
+		if err != nil {
+			return err
+		}
+		if err := b.SetSequence(7); err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte("value"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Compact(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(reorgsBucket).Bucket(nested)
+		if b == nil || b.Sequence() != 7 || string(b.Get([]byte("key"))) != "value" {
+			t.Errorf("Expected the nested bucket to be copied with its sequence, received %v", b)
+		}
+		return tx.Bucket(reorgsBucket).DeleteBucket(nested)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reorg saved after the compaction follows the reorgs saved before it.
+	if err := db.SaveReorg(ctx, &rpcpb.Reorg{NewHeadSlot: 4}); err != nil {
+		t.Fatal(err)
+	}
+	reorgs, err := db.Reorgs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reorgs) != 4 {
+		t.Fatalf("Wanted 4 reorgs, received %d", len(reorgs))
+	}
+	for i, reorg := range reorgs {
+		if reorg.NewHeadSlot != uint64(i+1) {
+			t.Errorf("Wanted reorg %d at slot %d, received %d", i, i+1, reorg.NewHeadSlot)
+		}
+	}
+}
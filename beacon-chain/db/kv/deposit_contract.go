@@ -15,7 +15,7 @@ func (k *Store) DepositContractAddress(ctx context.Context) ([]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DepositContractAddress")
 	defer span.End()
 	var addr []byte
-	if err := k.db.View(func(tx *bolt.Tx) error {
+	if err := k.view(func(tx *bolt.Tx) error {
 		chainInfo := tx.Bucket(chainMetadataBucket)
 		addr = chainInfo.Get(depositContractAddressKey)
 		return nil
@@ -30,7 +30,7 @@ func (k *Store) SaveDepositContractAddress(ctx context.Context, addr common.Addr
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.VerifyContractAddress")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		chainInfo := tx.Bucket(chainMetadataBucket)
 		expectedAddress := chainInfo.Get(depositContractAddressKey)
 		if expectedAddress != nil {
@@ -6,9 +6,11 @@ import (
 	"fmt"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
 	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
@@ -46,6 +48,7 @@ func (k *Store) updateFinalizedBlockRoots(ctx context.Context, tx *bolt.Tx, chec
 	root := checkpoint.Root
 	var previousRoot []byte
 	genesisRoot := tx.Bucket(blocksBucket).Get(genesisBlockRootKey)
+	anchorRoot := tx.Bucket(chainMetadataBucket).Get(anchorBlockRootKey)
 
 	// De-index recent finalized block roots, to be re-indexed.
 	previousFinalizedCheckpoint := &ethpb.Checkpoint{}
@@ -56,8 +59,13 @@ func (k *Store) updateFinalizedBlockRoots(ctx context.Context, tx *bolt.Tx, chec
 		}
 	}
 
+	// A node started from a checkpoint holds no blocks before its block history start slot.
+	startEpoch := previousFinalizedCheckpoint.Epoch
+	if historyStart := blockHistoryStartSlot(tx); helpers.StartSlot(startEpoch) < historyStart {
+		startEpoch = (historyStart + params.BeaconConfig().SlotsPerEpoch - 1) / params.BeaconConfig().SlotsPerEpoch
+	}
 	blockRoots, err := k.BlockRoots(ctx, filters.NewFilter().
-		SetStartEpoch(previousFinalizedCheckpoint.Epoch).
+		SetStartEpoch(startEpoch).
 		SetEndEpoch(checkpoint.Epoch+1),
 	)
 	if err != nil {
@@ -72,7 +80,7 @@ func (k *Store) updateFinalizedBlockRoots(ctx context.Context, tx *bolt.Tx, chec
 	}
 
 	// Walk up the ancestry chain until we reach a block root present in the finalized block roots
-	// index bucket or genesis block root.
+	// index bucket, the genesis block root or the checkpoint sync anchor block root.
 	for {
 		if bytes.Equal(root, genesisRoot) {
 			break
@@ -105,6 +113,11 @@ func (k *Store) updateFinalizedBlockRoots(ctx context.Context, tx *bolt.Tx, chec
 			return err
 		}
 
+		// The ancestors of the anchor block may not have been backfilled yet.
+		if anchorRoot != nil && bytes.Equal(root, anchorRoot) {
+			break
+		}
+
 		// Found parent, loop exit condition.
 		if parentBytes := bkt.Get(block.ParentRoot); parentBytes != nil {
 			parent := &dbpb.FinalizedBlockRootContainer{}
@@ -164,7 +177,7 @@ func (k *Store) IsFinalizedBlock(ctx context.Context, blockRoot [32]byte) bool {
 	defer span.End()
 
 	var exists bool
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		exists = tx.Bucket(finalizedBlockRootsIndexBucket).Get(blockRoot[:]) != nil
 		return nil
 	})
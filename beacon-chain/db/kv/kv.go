@@ -40,6 +40,11 @@ type Store struct {
 	stateSlotBitLock    sync.Mutex
 	blockSlotBitLock    sync.Mutex
 	stateSummaryCache   *cache.StateSummaryCache
+	// Transactions are counted so that compaction can swap the database file once none is open.
+	txLock    sync.Mutex
+	txCond    *sync.Cond
+	activeTxs int
+	swapping  bool
 }
 
 // NewKVStore initializes a new boltDB key-value store at the directory
@@ -49,15 +54,10 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 	if err := os.MkdirAll(dirPath, 0700); err != nil {
 		return nil, err
 	}
-	datafile := path.Join(dirPath, databaseFileName)
-	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, InitialMmapSize: 10e6})
+	boltDB, err := openBoltDB(path.Join(dirPath, databaseFileName))
 	if err != nil {
-		if err == bolt.ErrTimeout {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
-		}
 		return nil, err
 	}
-	boltDB.AllocSize = boltAllocSize
 	blockCache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1000,           // number of keys to track frequency of (1000).
 		MaxCost:     BlockCacheSize, // maximum cost of cache (1000 Blocks).
@@ -83,8 +83,9 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 		validatorIndexCache: validatorCache,
 		stateSummaryCache:   stateSummaryCache,
 	}
+	kv.txCond = sync.NewCond(&kv.txLock)
 
-	if err := kv.db.Update(func(tx *bolt.Tx) error {
+	if err := kv.update(func(tx *bolt.Tx) error {
 		return createBuckets(
 			tx,
 			attestationsBucket,
@@ -103,6 +104,13 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 			stateSummaryBucket,
 			archivedIndexRootBucket,
 			slotsHasObjectBucket,
+			// Operation pool buckets.
+			poolAttestationsBucket,
+			poolProposerSlashingsBucket,
+			poolAttesterSlashingsBucket,
+			poolVoluntaryExitsBucket,
+			reorgsBucket,
+			validatorPerformanceBucket,
 			// Indices buckets.
 			attestationHeadBlockRootBucket,
 			attestationSourceRootIndicesBucket,
@@ -144,6 +152,78 @@ func (k *Store) DatabasePath() string {
 	return k.databasePath
 }
 
+// openBoltDB opens the bolt database file with the options used by the store.
+func openBoltDB(datafile string) (*bolt.DB, error) {
+	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, InitialMmapSize: 10e6})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return nil, err
+	}
+	boltDB.AllocSize = boltAllocSize
+	return boltDB, nil
+}
+
+// view runs a read-only transaction on the database.
+func (k *Store) view(fn func(*bolt.Tx) error) error {
+	k.beginTx()
+	defer k.endTx()
+	return k.db.View(fn)
+}
+
+// update runs a read-write transaction on the database.
+func (k *Store) update(fn func(*bolt.Tx) error) error {
+	k.beginTx()
+	defer k.endTx()
+	return k.db.Update(fn)
+}
+
+// batch runs a read-write transaction on the database, which may be combined with other
+// concurrent batch calls.
+func (k *Store) batch(fn func(*bolt.Tx) error) error {
+	k.beginTx()
+	defer k.endTx()
+	return k.db.Batch(fn)
+}
+
+// beginTx waits for a database file swap in progress to complete. Transactions may be nested,
+// so a pending swap does not hold back new transactions: it waits until none is open instead.
+func (k *Store) beginTx() {
+	k.txLock.Lock()
+	for k.swapping {
+		k.txCond.Wait()
+	}
+	k.activeTxs++
+	k.txLock.Unlock()
+}
+
+func (k *Store) endTx() {
+	k.txLock.Lock()
+	k.activeTxs--
+	if k.activeTxs == 0 {
+		k.txCond.Broadcast()
+	}
+	k.txLock.Unlock()
+}
+
+// lockForSwap waits until no transaction is open and holds back new ones until unlockForSwap.
+func (k *Store) lockForSwap() {
+	k.txLock.Lock()
+	for k.swapping || k.activeTxs > 0 {
+		k.txCond.Wait()
+	}
+	k.swapping = true
+	k.txLock.Unlock()
+}
+
+func (k *Store) unlockForSwap() {
+	k.txLock.Lock()
+	k.swapping = false
+	k.txCond.Broadcast()
+	k.txLock.Unlock()
+}
+
 func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
 	for _, bucket := range buckets {
 		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
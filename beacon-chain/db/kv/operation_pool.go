@@ -0,0 +1,186 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// PoolAttestations returns the attestations persisted from the operation pool.
+func (k *Store) PoolAttestations(ctx context.Context) ([]*ethpb.Attestation, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PoolAttestations")
+	defer span.End()
+	atts := make([]*ethpb.Attestation, 0)
+	err := k.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(poolAttestationsBucket).ForEach(func(_, v []byte) error {
+			att := &ethpb.Attestation{}
+			if err := decode(v, att); err != nil {
+				return err
+			}
+			atts = append(atts, att)
+			return nil
+		})
+	})
+	return atts, err
+}
+
+// SavePoolAttestations replaces the persisted attestations of the operation pool with the given
+// ones. The whole pool is saved at once, periodically and at shutdown, rather than every single
+// attestation received from the network.
+func (k *Store) SavePoolAttestations(ctx context.Context, atts []*ethpb.Attestation) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SavePoolAttestations")
+	defer span.End()
+	keys := make([][]byte, len(atts))
+	encs := make([][]byte, len(atts))
+	for i, att := range atts {
+		root, err := ssz.HashTreeRoot(att)
+		if err != nil {
+			return err
+		}
+		enc, err := encode(att)
+		if err != nil {
+			return err
+		}
+		keys[i] = root[:]
+		encs[i] = enc
+	}
+	return k.update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(poolAttestationsBucket); err != nil {
+			return err
+		}
+		bkt, err := tx.CreateBucket(poolAttestationsBucket)
+		if err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if err := bkt.Put(key, encs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PoolProposerSlashings returns the proposer slashings persisted from the operation pool.
+func (k *Store) PoolProposerSlashings(ctx context.Context) ([]*ethpb.ProposerSlashing, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PoolProposerSlashings")
+	defer span.End()
+	slashings := make([]*ethpb.ProposerSlashing, 0)
+	err := k.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(poolProposerSlashingsBucket).ForEach(func(_, v []byte) error {
+			slashing := &ethpb.ProposerSlashing{}
+			if err := decode(v, slashing); err != nil {
+				return err
+			}
+			slashings = append(slashings, slashing)
+			return nil
+		})
+	})
+	return slashings, err
+}
+
+// SavePoolProposerSlashing persists a proposer slashing of the operation pool by its hash tree root.
+func (k *Store) SavePoolProposerSlashing(ctx context.Context, slashing *ethpb.ProposerSlashing) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SavePoolProposerSlashing")
+	defer span.End()
+	return k.savePoolOperation(poolProposerSlashingsBucket, slashing)
+}
+
+// DeletePoolProposerSlashing deletes a persisted proposer slashing of the operation pool.
+func (k *Store) DeletePoolProposerSlashing(ctx context.Context, slashing *ethpb.ProposerSlashing) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeletePoolProposerSlashing")
+	defer span.End()
+	return k.deletePoolOperation(poolProposerSlashingsBucket, slashing)
+}
+
+// PoolAttesterSlashings returns the attester slashings persisted from the operation pool.
+func (k *Store) PoolAttesterSlashings(ctx context.Context) ([]*ethpb.AttesterSlashing, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PoolAttesterSlashings")
+	defer span.End()
+	slashings := make([]*ethpb.AttesterSlashing, 0)
+	err := k.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(poolAttesterSlashingsBucket).ForEach(func(_, v []byte) error {
+			slashing := &ethpb.AttesterSlashing{}
+			if err := decode(v, slashing); err != nil {
+				return err
+			}
+			slashings = append(slashings, slashing)
+			return nil
+		})
+	})
+	return slashings, err
+}
+
+// SavePoolAttesterSlashing persists an attester slashing of the operation pool by its hash tree root.
+func (k *Store) SavePoolAttesterSlashing(ctx context.Context, slashing *ethpb.AttesterSlashing) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SavePoolAttesterSlashing")
+	defer span.End()
+	return k.savePoolOperation(poolAttesterSlashingsBucket, slashing)
+}
+
+// DeletePoolAttesterSlashing deletes a persisted attester slashing of the operation pool.
+func (k *Store) DeletePoolAttesterSlashing(ctx context.Context, slashing *ethpb.AttesterSlashing) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeletePoolAttesterSlashing")
+	defer span.End()
+	return k.deletePoolOperation(poolAttesterSlashingsBucket, slashing)
+}
+
+// PoolVoluntaryExits returns the voluntary exits persisted from the operation pool.
+func (k *Store) PoolVoluntaryExits(ctx context.Context) ([]*ethpb.SignedVoluntaryExit, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PoolVoluntaryExits")
+	defer span.End()
+	exits := make([]*ethpb.SignedVoluntaryExit, 0)
+	err := k.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(poolVoluntaryExitsBucket).ForEach(func(_, v []byte) error {
+			exit := &ethpb.SignedVoluntaryExit{}
+			if err := decode(v, exit); err != nil {
+				return err
+			}
+			exits = append(exits, exit)
+			return nil
+		})
+	})
+	return exits, err
+}
+
+// SavePoolVoluntaryExit persists a voluntary exit of the operation pool by its hash tree root.
+func (k *Store) SavePoolVoluntaryExit(ctx context.Context, exit *ethpb.SignedVoluntaryExit) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SavePoolVoluntaryExit")
+	defer span.End()
+	return k.savePoolOperation(poolVoluntaryExitsBucket, exit)
+}
+
+// DeletePoolVoluntaryExit deletes a persisted voluntary exit of the operation pool.
+func (k *Store) DeletePoolVoluntaryExit(ctx context.Context, exit *ethpb.SignedVoluntaryExit) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeletePoolVoluntaryExit")
+	defer span.End()
+	return k.deletePoolOperation(poolVoluntaryExitsBucket, exit)
+}
+
+func (k *Store) savePoolOperation(bucket []byte, op proto.Message) error {
+	root, err := ssz.HashTreeRoot(op)
+	if err != nil {
+		return err
+	}
+	enc, err := encode(op)
+	if err != nil {
+		return err
+	}
+	return k.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(root[:], enc)
+	})
+}
+
+func (k *Store) deletePoolOperation(bucket []byte, op proto.Message) error {
+	root, err := ssz.HashTreeRoot(op)
+	if err != nil {
+		return err
+	}
+	return k.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete(root[:])
+	})
+}
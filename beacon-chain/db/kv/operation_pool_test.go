@@ -0,0 +1,108 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestStore_SavePoolAttestations(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	data := &ethpb.AttestationData{
+		Slot:            1,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+	}
+	att1 := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b1001}, Signature: make([]byte, 96)}
+	att2 := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b1010}, Signature: make([]byte, 96)}
+	if err := db.SavePoolAttestations(ctx, []*ethpb.Attestation{att1, att2}); err != nil {
+		t.Fatal(err)
+	}
+	atts, err := db.PoolAttestations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atts) != 2 {
+		t.Fatalf("Wanted 2 attestations, received %d", len(atts))
+	}
+
+	// Saving the pool again replaces the attestations saved before.
+	aggregate := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b1011}, Signature: make([]byte, 96)}
+	if err := db.SavePoolAttestations(ctx, []*ethpb.Attestation{aggregate}); err != nil {
+		t.Fatal(err)
+	}
+	atts, err = db.PoolAttestations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atts) != 1 || !proto.Equal(atts[0], aggregate) {
+		t.Errorf("Expected only the aggregate to be saved, received %v", atts)
+	}
+}
+
+func TestStore_PoolSlashingsAndExits_CRUD(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	proposerSlashing := &ethpb.ProposerSlashing{
+		Header_1: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{ProposerIndex: 1, Slot: 5},
+			Signature: make([]byte, 96),
+		},
+		Header_2: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{ProposerIndex: 1, Slot: 5, StateRoot: []byte{'A'}},
+			Signature: make([]byte, 96),
+		},
+	}
+	exit := &ethpb.SignedVoluntaryExit{
+		Exit:      &ethpb.VoluntaryExit{Epoch: 5, ValidatorIndex: 2},
+		Signature: make([]byte, 96),
+	}
+	if err := db.SavePoolProposerSlashing(ctx, proposerSlashing); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SavePoolVoluntaryExit(ctx, exit); err != nil {
+		t.Fatal(err)
+	}
+	slashings, err := db.PoolProposerSlashings(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slashings) != 1 || !proto.Equal(slashings[0], proposerSlashing) {
+		t.Errorf("Wanted %v, received %v", proposerSlashing, slashings)
+	}
+	exits, err := db.PoolVoluntaryExits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exits) != 1 || !proto.Equal(exits[0], exit) {
+		t.Errorf("Wanted %v, received %v", exit, exits)
+	}
+
+	if err := db.DeletePoolProposerSlashing(ctx, proposerSlashing); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeletePoolVoluntaryExit(ctx, exit); err != nil {
+		t.Fatal(err)
+	}
+	slashings, err = db.PoolProposerSlashings(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slashings) != 0 {
+		t.Errorf("Expected proposer slashing to have been deleted, received %v", slashings)
+	}
+	exits, err = db.PoolVoluntaryExits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exits) != 0 {
+		t.Errorf("Expected voluntary exit to have been deleted, received %v", exits)
+	}
+}
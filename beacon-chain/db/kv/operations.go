@@ -14,7 +14,7 @@ func (k *Store) VoluntaryExit(ctx context.Context, exitRoot [32]byte) (*ethpb.Vo
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.VoluntaryExit")
 	defer span.End()
 	var exit *ethpb.VoluntaryExit
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(voluntaryExitsBucket)
 		enc := bkt.Get(exitRoot[:])
 		if enc == nil {
@@ -31,7 +31,7 @@ func (k *Store) HasVoluntaryExit(ctx context.Context, exitRoot [32]byte) bool {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HasVoluntaryExit")
 	defer span.End()
 	exists := false
-	if err := k.db.View(func(tx *bolt.Tx) error {
+	if err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(voluntaryExitsBucket)
 		exists = bkt.Get(exitRoot[:]) != nil
 		return nil
@@ -53,7 +53,7 @@ func (k *Store) SaveVoluntaryExit(ctx context.Context, exit *ethpb.VoluntaryExit
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(voluntaryExitsBucket)
 		return bucket.Put(exitRoot[:], enc)
 	})
@@ -63,7 +63,7 @@ func (k *Store) SaveVoluntaryExit(ctx context.Context, exit *ethpb.VoluntaryExit
 func (k *Store) DeleteVoluntaryExit(ctx context.Context, exitRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteVoluntaryExit")
 	defer span.End()
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(voluntaryExitsBucket)
 		return bucket.Delete(exitRoot[:])
 	})
@@ -14,7 +14,7 @@ func (k *Store) SavePowchainData(ctx context.Context, data *db.ETH1ChainData) er
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SavePowchainData")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(powchainBucket)
 		enc, err := proto.Marshal(data)
 		if err != nil {
@@ -30,7 +30,7 @@ func (k *Store) PowchainData(ctx context.Context) (*db.ETH1ChainData, error) {
 	defer span.End()
 
 	var data *db.ETH1ChainData
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(powchainBucket)
 		enc := bkt.Get(powchainDataKey)
 		if len(enc) == 0 {
@@ -0,0 +1,397 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// pruneBatchSlots is the number of slots pruned within a single database transaction, so a pass
+// neither holds back other writers nor buffers its changes for long.
+const pruneBatchSlots = 256
+
+// Prune deletes the blocks older than the finalized block which are not its ancestors, along with
+// their states, state summaries and the attestations voting for them. If a retention window is
+// set, canonical blocks older than the window are deleted as well and the block history start
+// moves up to the window. Each pass resumes from the slot reached by the previous one.
+//
+// The genesis, head, justified and finalized blocks, as well as the blocks of archived points,
+// are never deleted. Nodes started from a checkpoint keep their canonical blocks, as the
+// backfill service would fetch them again.
+//
+// The pass commits every pruneBatchSlots slots and stops between batches once the context is
+// canceled, keeping the progress made so far.
+func (k *Store) Prune(ctx context.Context, opts *iface.PruneOptions) (*iface.PruneReport, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Prune")
+	defer span.End()
+	if opts == nil {
+		opts = &iface.PruneOptions{}
+	}
+	p := &pruner{
+		k:      k,
+		ctx:    ctx,
+		dryRun: opts.DryRun,
+		report: &iface.PruneReport{DryRun: opts.DryRun},
+		pruned: make(map[[32]byte]bool),
+	}
+	var plan *prunePlan
+	if err := k.view(func(tx *bolt.Tx) error {
+		var err error
+		plan, err = p.plan(tx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return p.report, nil
+	}
+
+	for start := plan.floor; start < plan.finalizedSlot; start += pruneBatchSlots {
+		end := start + pruneBatchSlots
+		if end > plan.finalizedSlot {
+			end = plan.finalizedSlot
+		}
+		if err := p.batch(func() error {
+			return p.pruneForks(start, end, plan.canonical)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.RetentionSlots > 0 && plan.finalizedSlot > opts.RetentionSlots && !plan.anchored {
+		cutoff := plan.finalizedSlot - opts.RetentionSlots
+		for start := plan.historyStart; start < cutoff; start += pruneBatchSlots {
+			end := start + pruneBatchSlots
+			if end > cutoff {
+				end = cutoff
+			}
+			if err := p.batch(func() error {
+				return p.pruneHistory(start, end)
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.report, nil
+}
+
+// pruner holds the state of a pruning pass, whose batches each run in their own transaction.
+type pruner struct {
+	k         *Store
+	ctx       context.Context
+	tx        *bolt.Tx
+	dryRun    bool
+	report    *iface.PruneReport
+	protected map[[32]byte]bool
+	pruned    map[[32]byte]bool
+}
+
+// prunePlan is what a pruning pass deletes, read before its first batch.
+type prunePlan struct {
+	finalizedSlot uint64
+	floor         uint64
+	historyStart  uint64
+	anchored      bool
+	canonical     map[[32]byte]bool
+}
+
+// plan reads the finalized checkpoint and the blocks to keep. It returns nil if there is nothing
+// to prune yet.
+func (p *pruner) plan(tx *bolt.Tx) (*prunePlan, error) {
+	enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey)
+	if enc == nil {
+		return nil, nil
+	}
+	checkpoint := &ethpb.Checkpoint{}
+	if err := decode(enc, checkpoint); err != nil {
+		return nil, err
+	}
+	if checkpoint.Epoch == 0 {
+		return nil, nil
+	}
+	finalized, err := blockByRoot(tx, checkpoint.Root)
+	if err != nil {
+		return nil, err
+	}
+	if finalized == nil {
+		return nil, fmt.Errorf("missing finalized block in database: block root=%#x", checkpoint.Root)
+	}
+	p.report.FinalizedSlot = finalized.Block.Slot
+	p.protected, err = protectedRoots(tx, checkpoint.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocks are canonical if they are ancestors of the finalized block. The walk stops at the
+	// previously pruned slot, or at the oldest block it can reach if a parent is missing.
+	plan := &prunePlan{
+		finalizedSlot: finalized.Block.Slot,
+		historyStart:  blockHistoryStartSlot(tx),
+		anchored:      tx.Bucket(chainMetadataBucket).Get(anchorBlockRootKey) != nil,
+		canonical:     make(map[[32]byte]bool),
+	}
+	plan.floor = plan.historyStart
+	if enc := tx.Bucket(chainMetadataBucket).Get(lastPrunedSlotKey); enc != nil && bytesutil.FromBytes8(enc) > plan.floor {
+		plan.floor = bytesutil.FromBytes8(enc)
+	}
+	blk := finalized
+	root := bytesutil.ToBytes32(checkpoint.Root)
+	for {
+		plan.canonical[root] = true
+		if blk.Block.Slot <= plan.floor {
+			break
+		}
+		parent, err := blockByRoot(tx, blk.Block.ParentRoot)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			plan.floor = blk.Block.Slot
+			break
+		}
+		root = bytesutil.ToBytes32(blk.Block.ParentRoot)
+		blk = parent
+	}
+	return plan, nil
+}
+
+// batch runs fn in its own transaction, which is read-only in a dry run. It fails without
+// running fn once the context of the pass is canceled.
+func (p *pruner) batch(fn func() error) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	run := func(tx *bolt.Tx) error {
+		p.tx = tx
+		defer func() {
+			p.tx = nil
+		}()
+		return fn()
+	}
+	if p.dryRun {
+		return p.k.view(run)
+	}
+	return p.k.update(run)
+}
+
+// pruneForks deletes the blocks at slots in [start, end) which are neither canonical nor
+// protected, and records end as the slot the next pass resumes from.
+func (p *pruner) pruneForks(start uint64, end uint64, canonical map[[32]byte]bool) error {
+	for _, root := range indexedBlockRoots(p.tx, start, end) {
+		if canonical[root] || p.protected[root] {
+			continue
+		}
+		if err := p.pruneBlock(root); err != nil {
+			return err
+		}
+		p.report.NonCanonicalBlocks++
+	}
+	if p.dryRun {
+		return nil
+	}
+	return p.tx.Bucket(chainMetadataBucket).Put(lastPrunedSlotKey, bytesutil.Uint64ToBytes(end))
+}
+
+// pruneHistory deletes the blocks at slots in [start, end) which are not protected, and moves the
+// block history start up to end.
+func (p *pruner) pruneHistory(start uint64, end uint64) error {
+	for _, root := range indexedBlockRoots(p.tx, start, end) {
+		if p.protected[root] || p.pruned[root] {
+			continue
+		}
+		if err := p.pruneBlock(root); err != nil {
+			return err
+		}
+		p.report.CanonicalBlocks++
+	}
+	if p.dryRun {
+		return nil
+	}
+
+	// The oldest block left above the new start begins the block history.
+	bkt := p.tx.Bucket(chainMetadataBucket)
+	if err := bkt.Put(blockHistoryStartSlotKey, bytesutil.Uint64ToBytes(end)); err != nil {
+		return err
+	}
+	roots := indexedBlockRoots(p.tx, end, p.report.FinalizedSlot+1)
+	if len(roots) == 0 {
+		return nil
+	}
+	return bkt.Put(blockHistoryStartRootKey, roots[0][:])
+}
+
+// pruneBlock deletes a block along with its state, state summary and the attestations voting
+// for it. In a dry run, it only counts them.
+func (p *pruner) pruneBlock(root [32]byte) error {
+	blocks := p.tx.Bucket(blocksBucket)
+	signed, err := blockByRoot(p.tx, root[:])
+	if err != nil {
+		return err
+	}
+	if signed == nil {
+		return nil
+	}
+	p.pruned[root] = true
+	if err := p.pruneAttestations(root); err != nil {
+		return err
+	}
+
+	states := p.tx.Bucket(stateBucket)
+	hasState := states.Get(root[:]) != nil
+	if hasState {
+		p.report.States++
+	}
+	summaries := p.tx.Bucket(stateSummaryBucket)
+	hasSummary := summaries.Get(root[:]) != nil
+	if hasSummary {
+		p.report.StateSummaries++
+	}
+	if p.dryRun {
+		return nil
+	}
+
+	slot := signed.Block.Slot
+	if err := deleteValueForIndices(createBlockIndicesFromBlock(signed.Block), root[:], p.tx); err != nil {
+		return errors.Wrap(err, "could not delete root for DB indices")
+	}
+	p.k.blockCache.Del(string(root[:]))
+	if err := blocks.Delete(root[:]); err != nil {
+		return err
+	}
+	// Other blocks and states may remain at the same slot, whose bits must stay set.
+	remaining := indexedBlockRoots(p.tx, slot, slot+1)
+	if len(remaining) == 0 {
+		if err := p.k.clearBlockSlotBitField(p.ctx, p.tx, slot); err != nil {
+			return err
+		}
+	}
+	if hasState {
+		if err := states.Delete(root[:]); err != nil {
+			return err
+		}
+		stateRemains := false
+		for _, r := range remaining {
+			if states.Get(r[:]) != nil {
+				stateRemains = true
+				break
+			}
+		}
+		if !stateRemains {
+			if err := p.k.clearStateSlotBitField(p.ctx, p.tx, slot); err != nil {
+				return err
+			}
+		}
+	}
+	if hasSummary {
+		if err := summaries.Delete(root[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneAttestations deletes the attestations whose head vote is the block root.
+func (p *pruner) pruneAttestations(root [32]byte) error {
+	// The index value is copied, as deleting attestations updates it.
+	indexed := p.tx.Bucket(attestationHeadBlockRootBucket).Get(root[:])
+	attDataRoots := make([]byte, len(indexed))
+	copy(attDataRoots, indexed)
+
+	bkt := p.tx.Bucket(attestationsBucket)
+	for i := 0; i+32 <= len(attDataRoots); i += 32 {
+		attDataRoot := attDataRoots[i : i+32]
+		enc := bkt.Get(attDataRoot)
+		if enc == nil {
+			continue
+		}
+		p.report.Attestations++
+		if p.dryRun {
+			continue
+		}
+		ac := &dbpb.AttestationContainer{}
+		if err := decode(enc, ac); err != nil {
+			return err
+		}
+		if err := deleteValueForIndices(createAttestationIndicesFromData(ac.Data), attDataRoot, p.tx); err != nil {
+			return errors.Wrap(err, "could not delete root for DB indices")
+		}
+		if err := bkt.Delete(attDataRoot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protectedRoots returns the block roots which pruning must keep: the genesis, head, justified
+// and finalized roots, the checkpoint sync anchor and the roots of archived points.
+func protectedRoots(tx *bolt.Tx, finalizedRoot []byte) (map[[32]byte]bool, error) {
+	roots := [][]byte{
+		finalizedRoot,
+		tx.Bucket(blocksBucket).Get(genesisBlockRootKey),
+		tx.Bucket(blocksBucket).Get(headBlockRootKey),
+		tx.Bucket(chainMetadataBucket).Get(anchorBlockRootKey),
+	}
+	if enc := tx.Bucket(checkpointBucket).Get(justifiedCheckpointKey); enc != nil {
+		justified := &ethpb.Checkpoint{}
+		if err := decode(enc, justified); err != nil {
+			return nil, errors.Wrap(err, "could not decode justified checkpoint")
+		}
+		roots = append(roots, justified.Root)
+	}
+	if err := tx.Bucket(archivedIndexRootBucket).ForEach(func(k, v []byte) error {
+		if !bytes.Equal(k, lastArchivedIndexKey) {
+			roots = append(roots, v)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	protected := make(map[[32]byte]bool, len(roots))
+	for _, root := range roots {
+		if len(root) == 32 {
+			protected[bytesutil.ToBytes32(root)] = true
+		}
+	}
+	return protected, nil
+}
+
+// indexedBlockRoots returns the roots in the block slot index at slots in [start, end).
+func indexedBlockRoots(tx *bolt.Tx, start uint64, end uint64) [][32]byte {
+	roots := make([][32]byte, 0)
+	if end <= start {
+		return roots
+	}
+	max := []byte(fmt.Sprintf("%07d", end))
+	c := tx.Bucket(blockSlotIndicesBucket).Cursor()
+	for k, v := c.Seek([]byte(fmt.Sprintf("%07d", start))); k != nil && bytes.Compare(k, max) < 0; k, v = c.Next() {
+		for i := 0; i+32 <= len(v); i += 32 {
+			roots = append(roots, bytesutil.ToBytes32(v[i:i+32]))
+		}
+	}
+	return roots
+}
+
+// blockByRoot decodes the block saved with the root, or returns nil if there is none.
+func blockByRoot(tx *bolt.Tx, root []byte) (*ethpb.SignedBeaconBlock, error) {
+	enc := tx.Bucket(blocksBucket).Get(root)
+	if enc == nil {
+		return nil, nil
+	}
+	signed := &ethpb.SignedBeaconBlock{}
+	if err := decode(enc, signed); err != nil {
+		return nil, err
+	}
+	if signed.Block == nil {
+		return nil, nil
+	}
+	return signed, nil
+}
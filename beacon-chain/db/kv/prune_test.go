@@ -0,0 +1,200 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// This saves the canonical chain genesis <- 1 <- 2 <- 3 with block 3 finalized and head, and a
+// fork block at slot 2 with a state, a state summary and an attestation voting for it. It returns
+// the canonical roots by slot and the fork root.
+func setupPrunedChain(t *testing.T, db *Store) ([][32]byte, [32]byte, [32]byte) {
+	ctx := context.Background()
+	canonical := make([][32]byte, 4)
+	parent := make([]byte, 32)
+	for slot := uint64(0); slot < 4; slot++ {
+		blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot, ParentRoot: parent}}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		canonical[slot] = root
+		parent = root[:]
+	}
+	fork := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 2, ProposerIndex: 1, ParentRoot: canonical[1][:]}}
+	forkRoot, err := stateutil.BlockRoot(fork.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, fork); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.SaveGenesisBlockRoot(ctx, canonical[0]); err != nil {
+		t.Fatal(err)
+	}
+	for _, root := range [][32]byte{canonical[0], canonical[3], forkRoot} {
+		if err := db.SaveState(ctx, testutil.NewBeaconState(), root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SaveStateSummary(ctx, &pb.StateSummary{Slot: 2, Root: forkRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: canonical[3][:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, canonical[3]); err != nil {
+		t.Fatal(err)
+	}
+
+	att := &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			Slot:            2,
+			BeaconBlockRoot: forkRoot[:],
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		AggregationBits: bitfield.Bitlist{0b00000001, 0b1},
+	}
+	if err := db.SaveAttestation(ctx, att); err != nil {
+		t.Fatal(err)
+	}
+	attDataRoot, err := ssz.HashTreeRoot(att.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return canonical, forkRoot, attDataRoot
+}
+
+func TestStore_Prune_DryRun(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	_, forkRoot, attDataRoot := setupPrunedChain(t, db)
+
+	report, err := db.Prune(ctx, &iface.PruneOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &iface.PruneReport{
+		FinalizedSlot:      3,
+		NonCanonicalBlocks: 1,
+		States:             1,
+		StateSummaries:     1,
+		Attestations:       1,
+		DryRun:             true,
+	}
+	if *report != *want {
+		t.Errorf("Wanted report %+v, received %+v", want, report)
+	}
+	if !db.HasBlock(ctx, forkRoot) || !db.HasState(ctx, forkRoot) || !db.HasStateSummary(ctx, forkRoot) {
+		t.Error("Expected the fork block data to be kept in a dry run")
+	}
+	if !db.HasAttestation(ctx, attDataRoot) {
+		t.Error("Expected the fork attestation to be kept in a dry run")
+	}
+}
+
+func TestStore_Prune_NonCanonical(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	canonical, forkRoot, attDataRoot := setupPrunedChain(t, db)
+
+	report, err := db.Prune(ctx, &iface.PruneOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NonCanonicalBlocks != 1 || report.CanonicalBlocks != 0 || report.Attestations != 1 {
+		t.Errorf("Unexpected prune report %+v", report)
+	}
+	if db.HasBlock(ctx, forkRoot) || db.HasState(ctx, forkRoot) || db.HasStateSummary(ctx, forkRoot) {
+		t.Error("Expected the fork block data to be deleted")
+	}
+	if db.HasAttestation(ctx, attDataRoot) {
+		t.Error("Expected the fork attestation to be deleted")
+	}
+	for _, root := range canonical {
+		if !db.HasBlock(ctx, root) {
+			t.Errorf("Expected canonical block %#x to be kept", root)
+		}
+	}
+	highest, err := db.HighestSlotBlocksBelow(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(highest) != 1 || highest[0].Block.ProposerIndex != 0 {
+		t.Errorf("Wanted the canonical block at slot 2, received %v", highest)
+	}
+
+	// A second pass resumes from the finalized slot and has nothing left to prune.
+	report, err = db.Prune(ctx, &iface.PruneOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.NonCanonicalBlocks != 0 {
+		t.Errorf("Wanted no blocks pruned on the second pass, received %d", report.NonCanonicalBlocks)
+	}
+}
+
+func TestStore_Prune_Canceled(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	_, forkRoot, _ := setupPrunedChain(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := db.Prune(ctx, &iface.PruneOptions{}); err != context.Canceled {
+		t.Errorf("Wanted error %v, received %v", context.Canceled, err)
+	}
+	if !db.HasBlock(context.Background(), forkRoot) {
+		t.Error("Expected no block pruned once the context is canceled")
+	}
+}
+
+func TestStore_Prune_Retention(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	canonical, _, _ := setupPrunedChain(t, db)
+
+	report, err := db.Prune(ctx, &iface.PruneOptions{RetentionSlots: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.CanonicalBlocks != 1 {
+		t.Errorf("Wanted %d canonical block pruned, received %d", 1, report.CanonicalBlocks)
+	}
+	if db.HasBlock(ctx, canonical[1]) {
+		t.Error("Expected the canonical block below the retention window to be deleted")
+	}
+	if !db.HasBlock(ctx, canonical[0]) || !db.HasBlock(ctx, canonical[2]) {
+		t.Error("Expected the genesis block and the blocks in the retention window to be kept")
+	}
+	startSlot, err := db.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if startSlot != 2 {
+		t.Errorf("Wanted block history start slot %d, received %d", 2, startSlot)
+	}
+	startRoot, err := db.BlockHistoryStartRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if startRoot != canonical[2] {
+		t.Errorf("Wanted block history start root %#x, received %#x", canonical[2], startRoot)
+	}
+}
@@ -0,0 +1,71 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// maxReorgHistory is the number of reorgs kept in the database. The oldest reorgs are deleted
+// as new ones are saved.
+const maxReorgHistory = 1024
+
+// Reorgs returns the reorgs saved in the database, in the order they happened.
+func (k *Store) Reorgs(ctx context.Context) ([]*rpcpb.Reorg, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.Reorgs")
+	defer span.End()
+	reorgs := make([]*rpcpb.Reorg, 0)
+	err := k.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(reorgsBucket).ForEach(func(_, v []byte) error {
+			reorg := &rpcpb.Reorg{}
+			if err := decode(v, reorg); err != nil {
+				return err
+			}
+			reorgs = append(reorgs, reorg)
+			return nil
+		})
+	})
+	return reorgs, err
+}
+
+// SaveReorg saves a reorg to the database, deleting the oldest reorgs beyond the history limit.
+// Reorgs are keyed by a big endian sequence number, so iterating the bucket follows the order
+// in which they were saved.
+func (k *Store) SaveReorg(ctx context.Context, reorg *rpcpb.Reorg) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveReorg")
+	defer span.End()
+	enc, err := encode(reorg)
+	if err != nil {
+		return err
+	}
+	return k.update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(reorgsBucket)
+		seq, err := bkt.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		if err := bkt.Put(key, enc); err != nil {
+			return err
+		}
+		if seq <= maxReorgHistory {
+			return nil
+		}
+		// Every reorg takes the next sequence number, so the reorgs beyond the history limit
+		// are those with a sequence lower than the latest one minus the limit.
+		oldest := make([]byte, 8)
+		binary.BigEndian.PutUint64(oldest, seq-maxReorgHistory+1)
+		c := bkt.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, oldest) < 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
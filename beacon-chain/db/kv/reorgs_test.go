@@ -0,0 +1,32 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+func TestStore_Reorgs(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	for i := uint64(0); i < maxReorgHistory+10; i++ {
+		if err := db.SaveReorg(ctx, &rpcpb.Reorg{Timestamp: i, NewHeadSlot: i, Depth: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reorgs, err := db.Reorgs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reorgs) != maxReorgHistory {
+		t.Fatalf("Wanted %d reorgs, received %d", maxReorgHistory, len(reorgs))
+	}
+	for i, reorg := range reorgs {
+		if reorg.Timestamp != uint64(i)+10 {
+			t.Fatalf("Expected the oldest reorgs to be deleted, received reorg at %d in position %d", reorg.Timestamp, i)
+		}
+	}
+}
@@ -23,6 +23,14 @@ var (
 	powchainBucket                       = []byte("powchain")
 	archivedIndexRootBucket              = []byte("archived-index-root")
 	slotsHasObjectBucket                 = []byte("slots-has-objects")
+	reorgsBucket                         = []byte("reorgs")
+	validatorPerformanceBucket           = []byte("validator-performance")
+
+	// Operation pool buckets, persisting pending operations across restarts.
+	poolAttestationsBucket      = []byte("pool-attestations")
+	poolProposerSlashingsBucket = []byte("pool-proposer-slashings")
+	poolAttesterSlashingsBucket = []byte("pool-attester-slashings")
+	poolVoluntaryExitsBucket    = []byte("pool-voluntary-exits")
 
 	// Key indices buckets.
 	blockParentRootIndicesBucket        = []byte("block-parent-root-indices")
@@ -44,6 +52,10 @@ var (
 	lastArchivedIndexKey      = []byte("last-archived")
 	savedBlockSlotsKey        = []byte("saved-block-slots")
 	savedStateSlotsKey        = []byte("saved-state-slots")
+	anchorBlockRootKey        = []byte("anchor-block-root")
+	blockHistoryStartSlotKey  = []byte("block-history-start-slot")
+	blockHistoryStartRootKey  = []byte("block-history-start-root")
+	lastPrunedSlotKey         = []byte("last-pruned-slot")
 
 	// New state management service compatibility bucket.
 	newStateServiceCompatibleBucket = []byte("new-state-compatible")
@@ -17,7 +17,7 @@ func (k *Store) SaveStateSummary(ctx context.Context, summary *pb.StateSummary)
 	if err != nil {
 		return err
 	}
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		return bucket.Put(summary.Root, enc)
 	})
@@ -28,7 +28,7 @@ func (k *Store) SaveStateSummaries(ctx context.Context, summaries []*pb.StateSum
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveStateSummaries")
 	defer span.End()
 
-	return k.db.Update(func(tx *bolt.Tx) error {
+	return k.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		for _, summary := range summaries {
 			enc, err := encode(summary)
@@ -49,7 +49,7 @@ func (k *Store) StateSummary(ctx context.Context, blockRoot [32]byte) (*pb.State
 	defer span.End()
 
 	var summary *pb.StateSummary
-	err := k.db.View(func(tx *bolt.Tx) error {
+	err := k.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		enc := bucket.Get(blockRoot[:])
 		if enc == nil {
@@ -67,7 +67,7 @@ func (k *Store) HasStateSummary(ctx context.Context, blockRoot [32]byte) bool {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HasStateSummary")
 	defer span.End()
 	var exists bool
-	if err := k.db.View(func(tx *bolt.Tx) error {
+	if err := k.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(stateSummaryBucket)
 		exists = bucket.Get(blockRoot[:]) != nil
 		return nil
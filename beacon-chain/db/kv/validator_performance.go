@@ -0,0 +1,65 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// ValidatorPerformance returns the per-epoch performance of a validator saved in the database,
+// for the epochs in the given inclusive range, ordered by epoch.
+func (k *Store) ValidatorPerformance(
+	ctx context.Context,
+	validatorIdx uint64,
+	startEpoch uint64,
+	endEpoch uint64,
+) ([]*rpcpb.ValidatorEpochPerformance, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.ValidatorPerformance")
+	defer span.End()
+	records := make([]*rpcpb.ValidatorEpochPerformance, 0)
+	err := k.view(func(tx *bolt.Tx) error {
+		c := tx.Bucket(validatorPerformanceBucket).Cursor()
+		end := validatorPerformanceKey(validatorIdx, endEpoch)
+		for k, v := c.Seek(validatorPerformanceKey(validatorIdx, startEpoch)); k != nil && bytes.Compare(k, end) <= 0; k, v = c.Next() {
+			record := &rpcpb.ValidatorEpochPerformance{}
+			if err := decode(v, record); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	return records, err
+}
+
+// SaveValidatorPerformance saves the per-epoch performance of validators to the database. The
+// records are keyed by validator index followed by epoch, so the performance of a validator over
+// a range of epochs is a single range scan.
+func (k *Store) SaveValidatorPerformance(ctx context.Context, records []*rpcpb.ValidatorEpochPerformance) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveValidatorPerformance")
+	defer span.End()
+	return k.update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(validatorPerformanceBucket)
+		for _, record := range records {
+			enc, err := encode(record)
+			if err != nil {
+				return err
+			}
+			if err := bkt.Put(validatorPerformanceKey(record.ValidatorIndex, record.Epoch), enc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func validatorPerformanceKey(validatorIdx uint64, epoch uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], validatorIdx)
+	binary.BigEndian.PutUint64(key[8:], epoch)
+	return key
+}
@@ -0,0 +1,50 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+func TestStore_ValidatorPerformance(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	records := make([]*rpcpb.ValidatorEpochPerformance, 0)
+	for epoch := uint64(0); epoch < 10; epoch++ {
+		for idx := uint64(0); idx < 3; idx++ {
+			records = append(records, &rpcpb.ValidatorEpochPerformance{
+				Epoch:          epoch,
+				ValidatorIndex: idx,
+				BalanceDelta:   int64(epoch) - int64(idx),
+			})
+		}
+	}
+	if err := db.SaveValidatorPerformance(ctx, records); err != nil {
+		t.Fatal(err)
+	}
+
+	received, err := db.ValidatorPerformance(ctx, 1, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 3 {
+		t.Fatalf("Wanted 3 epochs, received %d", len(received))
+	}
+	for i, record := range received {
+		want := &rpcpb.ValidatorEpochPerformance{Epoch: uint64(i) + 3, ValidatorIndex: 1, BalanceDelta: int64(i) + 2}
+		if !proto.Equal(record, want) {
+			t.Errorf("Wanted %v, received %v", want, record)
+		}
+	}
+	received, err = db.ValidatorPerformance(ctx, 4, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 0 {
+		t.Errorf("Expected no performance for an unknown validator, received %v", received)
+	}
+}
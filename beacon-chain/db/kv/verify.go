@@ -0,0 +1,504 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// Kinds of integrity issues found by VerifyIntegrity.
+const (
+	CorruptBlockIssue          = "corrupt-block"
+	MissingParentIssue         = "missing-parent"
+	StaleBlockIndexIssue       = "stale-block-index"
+	MissingBlockIndexIssue     = "missing-block-index"
+	MissingBlockSlotBitIssue   = "missing-block-slot-bit"
+	OrphanStateSummaryIssue    = "orphan-state-summary"
+	MissingArchivedStateIssue  = "missing-archived-state"
+	InvalidLastArchivedIssue   = "invalid-last-archived-index"
+	StaleFinalizedIndexIssue   = "stale-finalized-index"
+	MissingGenesisBlockIssue   = "missing-genesis-block"
+	MissingHeadBlockIssue      = "missing-head-block"
+	MissingFinalizedBlockIssue = "missing-finalized-block"
+	MissingJustifiedBlockIssue = "missing-justified-block"
+)
+
+// Only the first unindexed blocks are detailed, as a lost index leaves every block unindexed.
+const missingBlockIndexIssueLimit = 1000
+
+// IntegrityIssue is an inconsistency between the linked buckets and indices of the database.
+type IntegrityIssue struct {
+	Kind     string
+	Root     [32]byte
+	Detail   string
+	Repaired bool
+}
+
+func (i *IntegrityIssue) String() string {
+	return fmt.Sprintf("%s %#x: %s", i.Kind, i.Root, i.Detail)
+}
+
+// IntegrityReport summarizes a verification of the database.
+type IntegrityReport struct {
+	Blocks         int
+	StateSummaries int
+	ArchivedPoints int
+	Issues         []*IntegrityIssue
+}
+
+// Repaired returns the number of issues fixed by the verification.
+func (r *IntegrityReport) Repaired() int {
+	var repaired int
+	for _, issue := range r.Issues {
+		if issue.Repaired {
+			repaired++
+		}
+	}
+	return repaired
+}
+
+type verifiedBlock struct {
+	slot       uint64
+	parentRoot []byte
+}
+
+// This returns the keys of the block in the slot and parent root index buckets.
+func (b *verifiedBlock) indexKeys() map[string]string {
+	keys := map[string]string{
+		string(blockSlotIndicesBucket): fmt.Sprintf("%07d", b.slot),
+	}
+	if len(b.parentRoot) > 0 {
+		keys[string(blockParentRootIndicesBucket)] = string(b.parentRoot)
+	}
+	return keys
+}
+
+// VerifyIntegrity walks the whole database and checks that the blocks, the block indices, the
+// state summaries, the archived points, the finalized block roots index and the genesis, head and
+// checkpoint roots agree with each other. With repair set, the derived data is rebuilt from the
+// blocks and states: the block indices and slot bitfield, the finalized block roots index, and the
+// state summaries and archived points pointing to missing objects are removed. Missing blocks and
+// states cannot be recovered and are only reported.
+//
+// This is meant to run offline, as the repair is done in a single write transaction.
+func (k *Store) VerifyIntegrity(ctx context.Context, repair bool) (*IntegrityReport, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.VerifyIntegrity")
+	defer span.End()
+
+	report := &IntegrityReport{}
+	verify := func(tx *bolt.Tx) error {
+		blocks, err := verifyBlocks(tx, report)
+		if err != nil {
+			return err
+		}
+		if err := verifyBlockIndices(tx, blocks, report, repair); err != nil {
+			return err
+		}
+		if err := verifyStateSummaries(tx, blocks, report, repair); err != nil {
+			return err
+		}
+		if err := verifyArchivedPoints(tx, report, repair); err != nil {
+			return err
+		}
+		return k.verifyChainRoots(ctx, tx, blocks, report, repair)
+	}
+	var err error
+	if repair {
+		err = k.update(verify)
+	} else {
+		err = k.view(verify)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// This decodes every block and reports the blocks whose parent is missing, except for the
+// genesis block and the oldest block of a node started from a checkpoint.
+func verifyBlocks(tx *bolt.Tx, report *IntegrityReport) (map[[32]byte]*verifiedBlock, error) {
+	blocks := make(map[[32]byte]*verifiedBlock)
+	if err := tx.Bucket(blocksBucket).ForEach(func(key []byte, enc []byte) error {
+		if len(key) != 32 {
+			return nil
+		}
+		root := bytesutil.ToBytes32(key)
+		blk := &ethpb.SignedBeaconBlock{}
+		if err := decode(enc, blk); err != nil || blk.Block == nil {
+			report.Issues = append(report.Issues, &IntegrityIssue{
+				Kind:   CorruptBlockIssue,
+				Root:   root,
+				Detail: "block could not be decoded",
+			})
+			return nil
+		}
+		blocks[root] = &verifiedBlock{slot: blk.Block.Slot, parentRoot: blk.Block.ParentRoot}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	report.Blocks = len(blocks)
+
+	historyStart := map[[32]byte]bool{}
+	for _, enc := range [][]byte{
+		tx.Bucket(blocksBucket).Get(genesisBlockRootKey),
+		tx.Bucket(chainMetadataBucket).Get(anchorBlockRootKey),
+		tx.Bucket(chainMetadataBucket).Get(blockHistoryStartRootKey),
+	} {
+		if enc != nil {
+			historyStart[bytesutil.ToBytes32(enc)] = true
+		}
+	}
+	// Blocks below the block history start, such as the archived points kept by retention
+	// pruning, may have lost their parents to pruning.
+	historyStartSlot := blockHistoryStartSlot(tx)
+	for _, root := range sortedRoots(blocks) {
+		blk := blocks[root]
+		if blk.slot == 0 || blk.slot < historyStartSlot || historyStart[root] {
+			continue
+		}
+		if _, ok := blocks[bytesutil.ToBytes32(blk.parentRoot)]; !ok {
+			report.Issues = append(report.Issues, &IntegrityIssue{
+				Kind:   MissingParentIssue,
+				Root:   root,
+				Detail: fmt.Sprintf("parent %#x of block at slot %d is not in the database", blk.parentRoot, blk.slot),
+			})
+		}
+	}
+	return blocks, nil
+}
+
+// This checks the block slot and parent root indices and the block slot bitfield against the
+// blocks, and rebuilds them from the blocks if repair is set.
+func verifyBlockIndices(tx *bolt.Tx, blocks map[[32]byte]*verifiedBlock, report *IntegrityReport, repair bool) error {
+	expected := map[string]map[string][][32]byte{
+		string(blockSlotIndicesBucket):       {},
+		string(blockParentRootIndicesBucket): {},
+	}
+	roots := sortedRoots(blocks)
+	var bitfield []byte
+	for _, root := range roots {
+		for bucket, key := range blocks[root].indexKeys() {
+			expected[bucket][key] = append(expected[bucket][key], root)
+		}
+		bitfield = bytesutil.SetBit(bitfield, int(blocks[root].slot))
+	}
+
+	for _, bucket := range [][]byte{blockSlotIndicesBucket, blockParentRootIndicesBucket} {
+		var issues []*IntegrityIssue
+		indexed := make(map[string]map[[32]byte]bool)
+		if err := tx.Bucket(bucket).ForEach(func(key []byte, value []byte) error {
+			indexed[string(key)] = make(map[[32]byte]bool)
+			for i := 0; i+32 <= len(value); i += 32 {
+				root := bytesutil.ToBytes32(value[i : i+32])
+				indexed[string(key)][root] = true
+				if !containsRoot(expected[string(bucket)][string(key)], root) {
+					issues = append(issues, &IntegrityIssue{
+						Kind:   StaleBlockIndexIssue,
+						Root:   root,
+						Detail: fmt.Sprintf("entry of the %s bucket does not match a block", bucket),
+					})
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		var missing int
+		for _, root := range roots {
+			key, ok := blocks[root].indexKeys()[string(bucket)]
+			if !ok || indexed[key][root] {
+				continue
+			}
+			missing++
+			if missing <= missingBlockIndexIssueLimit {
+				issues = append(issues, &IntegrityIssue{
+					Kind:   MissingBlockIndexIssue,
+					Root:   root,
+					Detail: fmt.Sprintf("block is not indexed in the %s bucket", bucket),
+				})
+			}
+		}
+		if missing > missingBlockIndexIssueLimit {
+			issues = append(issues, &IntegrityIssue{
+				Kind:   MissingBlockIndexIssue,
+				Detail: fmt.Sprintf("%d more blocks are not indexed in the %s bucket", missing-missingBlockIndexIssueLimit, bucket),
+			})
+		}
+		if repair && len(issues) > 0 {
+			if err := rebuildIndex(tx, bucket, expected[string(bucket)]); err != nil {
+				return err
+			}
+			markRepaired(issues)
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
+
+	bkt := tx.Bucket(slotsHasObjectBucket)
+	saved := bkt.Get(savedBlockSlotsKey)
+	var issues []*IntegrityIssue
+	for _, root := range roots {
+		slot := blocks[root].slot
+		if slot/8 >= uint64(len(saved)) || saved[slot/8]&(1<<(slot%8)) == 0 {
+			issues = append(issues, &IntegrityIssue{
+				Kind:   MissingBlockSlotBitIssue,
+				Root:   root,
+				Detail: fmt.Sprintf("slot %d of the block is not marked in the saved block slots", slot),
+			})
+		}
+	}
+	if repair && len(issues) > 0 {
+		if err := bkt.Put(savedBlockSlotsKey, bitfield); err != nil {
+			return err
+		}
+		markRepaired(issues)
+	}
+	report.Issues = append(report.Issues, issues...)
+	return nil
+}
+
+// This reports the state summaries of blocks which are not in the database, and deletes them if
+// repair is set.
+func verifyStateSummaries(tx *bolt.Tx, blocks map[[32]byte]*verifiedBlock, report *IntegrityReport, repair bool) error {
+	bkt := tx.Bucket(stateSummaryBucket)
+	var orphans [][]byte
+	if err := bkt.ForEach(func(key []byte, _ []byte) error {
+		report.StateSummaries++
+		if _, ok := blocks[bytesutil.ToBytes32(key)]; !ok {
+			orphans = append(orphans, append([]byte{}, key...))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, key := range orphans {
+		issue := &IntegrityIssue{
+			Kind:   OrphanStateSummaryIssue,
+			Root:   bytesutil.ToBytes32(key),
+			Detail: "state summary does not match a block",
+		}
+		if repair {
+			if err := bkt.Delete(key); err != nil {
+				return err
+			}
+			issue.Repaired = true
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+	return nil
+}
+
+// This reports the archived points whose state is not in the database and a last archived index
+// which is not an archived point. With repair set, such archived points are deleted and the last
+// archived index is moved back to the highest remaining archived point.
+func verifyArchivedPoints(tx *bolt.Tx, report *IntegrityReport, repair bool) error {
+	bkt := tx.Bucket(archivedIndexRootBucket)
+	states := tx.Bucket(stateBucket)
+	var missing [][]byte
+	var highest []byte
+	if err := bkt.ForEach(func(key []byte, root []byte) error {
+		if bytes.Equal(key, lastArchivedIndexKey) {
+			return nil
+		}
+		report.ArchivedPoints++
+		if states.Get(root) == nil {
+			missing = append(missing, append([]byte{}, key...))
+			report.Issues = append(report.Issues, &IntegrityIssue{
+				Kind:   MissingArchivedStateIssue,
+				Root:   bytesutil.ToBytes32(root),
+				Detail: fmt.Sprintf("state of archived point %d is not in the database", bytesutil.FromBytes8(key)),
+			})
+			return nil
+		}
+		if highest == nil || bytesutil.FromBytes8(key) > bytesutil.FromBytes8(highest) {
+			highest = append([]byte{}, key...)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if repair {
+		for _, key := range missing {
+			if err := bkt.Delete(key); err != nil {
+				return err
+			}
+		}
+		markRepaired(report.Issues[len(report.Issues)-len(missing):])
+	}
+
+	last := bkt.Get(lastArchivedIndexKey)
+	if last == nil {
+		return nil
+	}
+	if root := bkt.Get(last); root != nil && states.Get(root) != nil {
+		return nil
+	}
+	issue := &IntegrityIssue{
+		Kind:   InvalidLastArchivedIssue,
+		Detail: fmt.Sprintf("last archived index %d is not an archived point with a state", bytesutil.FromBytes8(last)),
+	}
+	if repair {
+		var err error
+		if highest == nil {
+			err = bkt.Delete(lastArchivedIndexKey)
+		} else {
+			err = bkt.Put(lastArchivedIndexKey, highest)
+		}
+		if err != nil {
+			return err
+		}
+		issue.Repaired = true
+	}
+	report.Issues = append(report.Issues, issue)
+	return nil
+}
+
+// This checks that the genesis, head, justified and finalized roots are blocks of the database
+// and that the finalized block roots index only holds blocks. With repair set, a missing head is
+// moved to the finalized block and the finalized block roots index is rebuilt.
+func (k *Store) verifyChainRoots(ctx context.Context, tx *bolt.Tx, blocks map[[32]byte]*verifiedBlock, report *IntegrityReport, repair bool) error {
+	has := func(enc []byte) bool {
+		_, ok := blocks[bytesutil.ToBytes32(enc)]
+		return ok
+	}
+	blocksBkt := tx.Bucket(blocksBucket)
+	if genesisRoot := blocksBkt.Get(genesisBlockRootKey); genesisRoot != nil && !has(genesisRoot) {
+		report.Issues = append(report.Issues, &IntegrityIssue{
+			Kind:   MissingGenesisBlockIssue,
+			Root:   bytesutil.ToBytes32(genesisRoot),
+			Detail: "genesis block is not in the database",
+		})
+	}
+
+	finalized := &ethpb.Checkpoint{}
+	if enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey); enc != nil {
+		if err := decode(enc, finalized); err != nil {
+			return err
+		}
+	}
+	finalizedKnown := isZeroRoot(finalized.Root) || has(finalized.Root)
+	if !finalizedKnown {
+		report.Issues = append(report.Issues, &IntegrityIssue{
+			Kind:   MissingFinalizedBlockIssue,
+			Root:   bytesutil.ToBytes32(finalized.Root),
+			Detail: fmt.Sprintf("finalized block of epoch %d is not in the database", finalized.Epoch),
+		})
+	}
+	if enc := tx.Bucket(checkpointBucket).Get(justifiedCheckpointKey); enc != nil {
+		justified := &ethpb.Checkpoint{}
+		if err := decode(enc, justified); err != nil {
+			return err
+		}
+		if !isZeroRoot(justified.Root) && !has(justified.Root) {
+			report.Issues = append(report.Issues, &IntegrityIssue{
+				Kind:   MissingJustifiedBlockIssue,
+				Root:   bytesutil.ToBytes32(justified.Root),
+				Detail: fmt.Sprintf("justified block of epoch %d is not in the database", justified.Epoch),
+			})
+		}
+	}
+
+	if headRoot := blocksBkt.Get(headBlockRootKey); headRoot != nil && !has(headRoot) {
+		issue := &IntegrityIssue{
+			Kind:   MissingHeadBlockIssue,
+			Root:   bytesutil.ToBytes32(headRoot),
+			Detail: "head block is not in the database",
+		}
+		if repair && !isZeroRoot(finalized.Root) && finalizedKnown && tx.Bucket(stateBucket).Get(finalized.Root) != nil {
+			if err := blocksBkt.Put(headBlockRootKey, finalized.Root); err != nil {
+				return err
+			}
+			issue.Repaired = true
+			issue.Detail += ", head moved to the finalized block"
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+
+	var issues []*IntegrityIssue
+	if err := tx.Bucket(finalizedBlockRootsIndexBucket).ForEach(func(key []byte, _ []byte) error {
+		if len(key) == 32 && !has(key) {
+			issues = append(issues, &IntegrityIssue{
+				Kind:   StaleFinalizedIndexIssue,
+				Root:   bytesutil.ToBytes32(key),
+				Detail: "finalized block roots index entry does not match a block",
+			})
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if repair && len(issues) > 0 && finalizedKnown && !isZeroRoot(finalized.Root) {
+		if err := tx.DeleteBucket(finalizedBlockRootsIndexBucket); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(finalizedBlockRootsIndexBucket); err != nil {
+			return err
+		}
+		if err := k.updateFinalizedBlockRoots(ctx, tx, finalized); err != nil {
+			return err
+		}
+		markRepaired(issues)
+	}
+	report.Issues = append(report.Issues, issues...)
+	return nil
+}
+
+func rebuildIndex(tx *bolt.Tx, bucket []byte, entries map[string][][32]byte) error {
+	if err := tx.DeleteBucket(bucket); err != nil {
+		return err
+	}
+	bkt, err := tx.CreateBucket(bucket)
+	if err != nil {
+		return err
+	}
+	for key, roots := range entries {
+		value := make([]byte, 0, len(roots)*32)
+		for _, root := range roots {
+			value = append(value, root[:]...)
+		}
+		if err := bkt.Put([]byte(key), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedRoots(blocks map[[32]byte]*verifiedBlock) [][32]byte {
+	roots := make([][32]byte, 0, len(blocks))
+	for root := range blocks {
+		roots = append(roots, root)
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if blocks[roots[i]].slot != blocks[roots[j]].slot {
+			return blocks[roots[i]].slot < blocks[roots[j]].slot
+		}
+		return bytes.Compare(roots[i][:], roots[j][:]) < 0
+	})
+	return roots
+}
+
+func containsRoot(roots [][32]byte, root [32]byte) bool {
+	for _, r := range roots {
+		if r == root {
+			return true
+		}
+	}
+	return false
+}
+
+func isZeroRoot(root []byte) bool {
+	return bytesutil.ToBytes32(root) == params.BeaconConfig().ZeroHash
+}
+
+func markRepaired(issues []*IntegrityIssue) {
+	for _, issue := range issues {
+		issue.Repaired = true
+	}
+}
@@ -0,0 +1,236 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+// This saves a genesis block with its state and a child block, and marks the genesis block as
+// the finalized and head block.
+func setupVerifiedChain(t *testing.T, db *Store) ([32]byte, [32]byte) {
+	ctx := context.Background()
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{ParentRoot: make([]byte, 32)}}
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: genesisRoot[:]}}
+	childRoot, err := stateutil.BlockRoot(child.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlocks(ctx, []*ethpb.SignedBeaconBlock{genesis, child}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Root: genesisRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveStateSummary(ctx, &pb.StateSummary{Slot: 1, Root: childRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveArchivedPointRoot(ctx, genesisRoot, 0); err != nil {
+		t.Fatal(err)
+	}
+	return genesisRoot, childRoot
+}
+
+func issueKinds(report *IntegrityReport) map[string]int {
+	kinds := make(map[string]int)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind]++
+	}
+	return kinds
+}
+
+func TestStore_VerifyIntegrity_Consistent(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	setupVerifiedChain(t, db)
+
+	report, err := db.VerifyIntegrity(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("Wanted no issues, received %v", report.Issues)
+	}
+	if report.Blocks != 2 || report.StateSummaries != 1 || report.ArchivedPoints != 1 {
+		t.Errorf("Unexpected verified object counts %+v", report)
+	}
+}
+
+func TestStore_VerifyIntegrity_DetectsAndRepairs(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	genesisRoot, childRoot := setupVerifiedChain(t, db)
+
+	orphan := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 5, ParentRoot: bytesutil.PadTo([]byte{'X'}, 32)}}
+	if err := db.SaveBlock(ctx, orphan); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveStateSummary(ctx, &pb.StateSummary{Slot: 3, Root: []byte{'Y'}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveArchivedPointRoot(ctx, [32]byte{'Z'}, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveLastArchivedIndex(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	missingHead := [32]byte{'H'}
+	if err := db.db.Update(func(tx *bolt.Tx) error {
+		// Lose the slot index of the child block, and point the head to an unknown block.
+		if err := tx.Bucket(blockSlotIndicesBucket).Delete([]byte("0000001")); err != nil {
+			return err
+		}
+		return tx.Bucket(blocksBucket).Put(headBlockRootKey, missingHead[:])
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := db.VerifyIntegrity(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := map[string]int{
+		MissingParentIssue:        1,
+		MissingBlockIndexIssue:    1,
+		OrphanStateSummaryIssue:   1,
+		MissingArchivedStateIssue: 1,
+		InvalidLastArchivedIssue:  1,
+		MissingHeadBlockIssue:     1,
+	}
+	kinds := issueKinds(report)
+	for kind, count := range wanted {
+		if kinds[kind] != count {
+			t.Errorf("Wanted %d %s issues, received %d", count, kind, kinds[kind])
+		}
+	}
+	if len(report.Issues) != 6 {
+		t.Errorf("Wanted %d issues, received %v", 6, report.Issues)
+	}
+	if report.Repaired() != 0 {
+		t.Errorf("Wanted no repaired issues without repair, received %d", report.Repaired())
+	}
+
+	report, err = db.VerifyIntegrity(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the missing parent block cannot be repaired.
+	if report.Repaired() != 5 {
+		t.Errorf("Wanted %d repaired issues, received %d", 5, report.Repaired())
+	}
+
+	report, err = db.VerifyIntegrity(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Kind != MissingParentIssue {
+		t.Errorf("Wanted only the missing parent issue after repair, received %v", report.Issues)
+	}
+	blks, err := db.Blocks(ctx, filters.NewFilter().SetStartSlot(1).SetEndSlot(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blks) != 1 {
+		t.Errorf("Wanted the child block to be indexed again, received %d blocks", len(blks))
+	}
+	head, err := db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headRoot, err := stateutil.BlockRoot(head.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headRoot != genesisRoot {
+		t.Errorf("Wanted head moved to the finalized root %#x, received %#x", genesisRoot, headRoot)
+	}
+	if db.HasStateSummary(ctx, [32]byte{'Y'}) || !db.HasStateSummary(ctx, childRoot) {
+		t.Error("Wanted only the orphan state summary to be deleted")
+	}
+	if db.HasArchivedPoint(ctx, 3) {
+		t.Error("Wanted the archived point without state to be deleted")
+	}
+	lastIndex, err := db.LastArchivedIndex(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastIndex != 0 {
+		t.Errorf("Wanted last archived index %d, received %d", 0, lastIndex)
+	}
+}
+
+func TestStore_VerifyIntegrity_AfterRetentionPruning(t *testing.T) {
+	db := setupDB(t)
+	defer teardownDB(t, db)
+	ctx := context.Background()
+
+	// The canonical chain genesis <- 1 <- ... <- 5 is finalized at slot 5, with an archived point at slot 2.
+	roots := make([][32]byte, 6)
+	parent := make([]byte, 32)
+	for slot := uint64(0); slot < 6; slot++ {
+		blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot, ParentRoot: parent}}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+		roots[slot] = root
+		parent = root[:]
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, roots[0]); err != nil {
+		t.Fatal(err)
+	}
+	for _, root := range [][32]byte{roots[0], roots[2], roots[5]} {
+		if err := db.SaveState(ctx, testutil.NewBeaconState(), root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SaveArchivedPointRoot(ctx, roots[2], 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: roots[5][:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, roots[5]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Prune(ctx, &iface.PruneOptions{RetentionSlots: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasBlock(ctx, roots[1]) || !db.HasBlock(ctx, roots[2]) {
+		t.Fatal("Expected the parent of the archived point to be pruned and the archived point to be kept")
+	}
+
+	report, err := db.VerifyIntegrity(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kinds := issueKinds(report); kinds[MissingParentIssue] != 0 {
+		t.Errorf("Wanted no missing parents after retention pruning, received %v", report.Issues)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/node"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var repairDBFlag = &cli.BoolFlag{
+	Name:  "repair",
+	Usage: "Rebuild the derived indices of the database and remove the entries pointing to missing objects.",
+}
+
+var compactDBFlag = &cli.BoolFlag{
+	Name:  "compact",
+	Usage: "Rewrite the database into a smaller file after pruning.",
+}
+
+var dbCommand = &cli.Command{
+	Name:     "db",
+	Category: "db",
+	Usage:    "Commands to inspect and maintain the beacon chain database",
+	Subcommands: []*cli.Command{
+		{
+			Name: "verify",
+			Usage: "Check that the blocks, state summaries, archived points, indices and chain roots of the " +
+				"database agree with each other. The beacon node must not be running.",
+			Flags:  []cli.Flag{cmd.DataDirFlag, repairDBFlag},
+			Action: verifyDB,
+		},
+		{
+			Name: "prune",
+			Usage: "Delete the data of forks older than the finalized checkpoint, and optionally the canonical blocks " +
+				"older than a retention window. The beacon node must not be running.",
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+				flags.PruneRetentionSlotsFlag,
+				flags.PruneDryRunFlag,
+				compactDBFlag,
+			},
+			Action: pruneDB,
+		},
+	},
+}
+
+// openDB opens the beacon chain database of the data directory, which must already exist.
+func openDB(ctx *cli.Context) (*kv.Store, error) {
+	dbPath := path.Join(ctx.String(cmd.DataDirFlag.Name), node.BeaconChainDBName)
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("no beacon chain database found in %s", dbPath)
+	}
+	return kv.NewKVStore(dbPath, cache.NewStateSummaryCache())
+}
+
+func verifyDB(ctx *cli.Context) error {
+	log := logrus.WithField("prefix", "db")
+	db, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+
+	repair := ctx.Bool(repairDBFlag.Name)
+	report, err := db.VerifyIntegrity(context.Background(), repair)
+	if err != nil {
+		return err
+	}
+	for _, issue := range report.Issues {
+		entry := log.WithFields(logrus.Fields{
+			"kind": issue.Kind,
+			"root": fmt.Sprintf("%#x", issue.Root),
+		})
+		if issue.Repaired {
+			entry.Info("Repaired: " + issue.Detail)
+		} else {
+			entry.Warn(issue.Detail)
+		}
+	}
+	log.WithFields(logrus.Fields{
+		"blocks":         report.Blocks,
+		"stateSummaries": report.StateSummaries,
+		"archivedPoints": report.ArchivedPoints,
+		"issues":         len(report.Issues),
+		"repaired":       report.Repaired(),
+	}).Info("Verified database")
+
+	if len(report.Issues) > report.Repaired() {
+		if !repair {
+			return fmt.Errorf("found %d issues in the database, run with --%s to fix the derived indices", len(report.Issues), repairDBFlag.Name)
+		}
+		return fmt.Errorf("%d issues in the database could not be repaired", len(report.Issues)-report.Repaired())
+	}
+	return nil
+}
+
+func pruneDB(ctx *cli.Context) error {
+	log := logrus.WithField("prefix", "db")
+	store, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+
+	dryRun := ctx.Bool(flags.PruneDryRunFlag.Name)
+	report, err := store.Prune(context.Background(), &db.PruneOptions{
+		RetentionSlots: ctx.Uint64(flags.PruneRetentionSlotsFlag.Name),
+		DryRun:         dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	fields := logrus.Fields{
+		"finalizedSlot":      report.FinalizedSlot,
+		"nonCanonicalBlocks": report.NonCanonicalBlocks,
+		"canonicalBlocks":    report.CanonicalBlocks,
+		"states":             report.States,
+		"stateSummaries":     report.StateSummaries,
+		"attestations":       report.Attestations,
+	}
+	if dryRun {
+		log.WithFields(fields).Info("Dry run, pruning would delete")
+		return nil
+	}
+	log.WithFields(fields).Info("Pruned database")
+
+	if !ctx.Bool(compactDBFlag.Name) {
+		return nil
+	}
+	compaction, err := store.Compact(context.Background())
+	if err != nil {
+		return err
+	}
+	log.WithFields(logrus.Fields{
+		"sizeBefore": compaction.SizeBefore,
+		"sizeAfter":  compaction.SizeAfter,
+	}).Info("Compacted database")
+	return nil
+}
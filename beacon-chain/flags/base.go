@@ -1,6 +1,8 @@
 package flags
 
 import (
+	"time"
+
 	"gopkg.in/urfave/cli.v2"
 )
 
@@ -122,4 +124,148 @@ var (
 		Usage: "The amount of blocks the local peer is bounded to request and respond to in a batch.",
 		Value: 64,
 	}
+	// BlockBatchLimitBurstFactor specifies the factor by which block batch limit may increase on burst.
+	BlockBatchLimitBurstFactor = &cli.IntFlag{
+		Name:  "block-batch-limit-burst-factor",
+		Usage: "The factor by which the block batch limit may be exceeded by a peer requesting blocks in a burst.",
+		Value: 10,
+	}
+	// RPCRequestLimit specifies the number of requests per second a peer may send for each of the
+	// status, ping and metadata protocols.
+	RPCRequestLimit = &cli.IntFlag{
+		Name:  "rpc-request-limit",
+		Usage: "The number of status, ping or metadata requests per second each peer may send for each protocol.",
+		Value: 2,
+	}
+	// RPCRequestBurst specifies the number of requests a peer may send in a burst for each of the
+	// status, ping and metadata protocols.
+	RPCRequestBurst = &cli.IntFlag{
+		Name:  "rpc-request-burst",
+		Usage: "The number of status, ping or metadata requests each peer may send in a burst for each protocol.",
+		Value: 10,
+	}
+	// CheckpointStateFlag defines the path to an SSZ encoded finalized beacon state to start the node from.
+	CheckpointStateFlag = &cli.StringFlag{
+		Name:  "checkpoint-state",
+		Usage: "Path to a trusted SSZ encoded finalized beacon state to start syncing from instead of genesis. Requires --checkpoint-block.",
+	}
+	// CheckpointBlockFlag defines the path to the SSZ encoded signed beacon block of the checkpoint state.
+	CheckpointBlockFlag = &cli.StringFlag{
+		Name:  "checkpoint-block",
+		Usage: "Path to the SSZ encoded signed beacon block matching --checkpoint-state.",
+	}
+	// CheckpointSyncRPCFlag defines a trusted beacon node to download the finalized state and block from.
+	CheckpointSyncRPCFlag = &cli.StringFlag{
+		Name:  "checkpoint-sync-rpc",
+		Usage: "gRPC endpoint of a beacon node to download the finalized state and block to start syncing from instead of genesis. Requires --checkpoint-sync-root and --checkpoint-sync-epoch.",
+	}
+	// CheckpointSyncRootFlag defines the trusted block root of the checkpoint downloaded with checkpoint sync.
+	CheckpointSyncRootFlag = &cli.StringFlag{
+		Name:  "checkpoint-sync-root",
+		Usage: "Hex encoded block root of a trusted finalized checkpoint. The checkpoint downloaded from --checkpoint-sync-rpc is rejected if its block does not have this root.",
+	}
+	// CheckpointSyncEpochFlag defines the trusted epoch of the checkpoint downloaded with checkpoint sync.
+	CheckpointSyncEpochFlag = &cli.Uint64Flag{
+		Name:  "checkpoint-sync-epoch",
+		Usage: "Epoch of the trusted finalized checkpoint given with --checkpoint-sync-root.",
+	}
+	// CheckpointSyncCertFlag defines the certificate used to verify the node serving checkpoint sync.
+	CheckpointSyncCertFlag = &cli.StringFlag{
+		Name:  "checkpoint-sync-tls-cert",
+		Usage: "Certificate for a secure gRPC connection to --checkpoint-sync-rpc.",
+	}
+	// PersistOperationPoolsFlag enables saving the pending attestations, slashings and exits in the database.
+	PersistOperationPoolsFlag = &cli.BoolFlag{
+		Name:  "persist-operation-pools",
+		Usage: "Save the pending attestations, slashings and voluntary exits in the database, so they are not lost on restart.",
+	}
+	// AttestationPoolPersistIntervalFlag defines how often the attestation pool is saved in the database.
+	AttestationPoolPersistIntervalFlag = &cli.DurationFlag{
+		Name: "attestation-pool-persist-interval",
+		Usage: "How often to save the attestation pool in the database when operation pools are persisted. " +
+			"Attestations received since the last save are lost if the node does not shut down cleanly.",
+		Value: time.Minute,
+	}
+	// ForkChoiceSnapshotIntervalFlag defines how often a snapshot of the fork choice store is saved to disk.
+	ForkChoiceSnapshotIntervalFlag = &cli.DurationFlag{
+		Name:  "forkchoice-snapshot-interval",
+		Usage: "How often to save a snapshot of the fork choice store in the data directory, 0 to disable snapshots.",
+		Value: 10 * time.Minute,
+	}
+	// ForkChoiceSnapshotRetentionFlag defines the number of fork choice snapshots kept on disk.
+	ForkChoiceSnapshotRetentionFlag = &cli.IntFlag{
+		Name:  "forkchoice-snapshot-retention",
+		Usage: "The number of fork choice snapshots to keep on disk, 0 to keep all of them.",
+		Value: 144,
+	}
+	// ValidatorPerformanceFlag enables tracking the per-epoch performance of every validator.
+	ValidatorPerformanceFlag = &cli.BoolFlag{
+		Name:  "validator-performance",
+		Usage: "Track the attestation, proposal and reward outcomes of every validator at each epoch, and save them in the database for historical queries.",
+	}
+	// BeaconAPIPort enables the standard Eth2 beacon node REST API on the given port.
+	BeaconAPIPort = &cli.IntFlag{
+		Name:  "beacon-api-port",
+		Usage: "Enable the standard Eth2 beacon node REST API on this port, 0 to disable it.",
+		Value: 0,
+	}
+	// StateSnapshotStrategyFlag defines how intermediate historical states are kept in memory to shorten state replays.
+	StateSnapshotStrategyFlag = &cli.StringFlag{
+		Name: "state-snapshot-strategy",
+		Usage: "The strategy keeping intermediate historical states in memory to shorten the replay of deep historical " +
+			"queries: none, exponential (snapshots spaced exponentially back from the latest one) or lru (the last requested states).",
+		Value: "none",
+	}
+	// StateSnapshotCountFlag defines the maximum number of intermediate historical states kept in memory.
+	StateSnapshotCountFlag = &cli.IntFlag{
+		Name:  "state-snapshot-count",
+		Usage: "The maximum number of intermediate historical states kept in memory by the snapshot strategy.",
+		Value: 8,
+	}
+	// MaxStateReplaySlotsFlag defines the maximum number of slots an RPC historical state query may replay.
+	MaxStateReplaySlotsFlag = &cli.IntFlag{
+		Name:  "max-state-replay-slots",
+		Usage: "Reject RPC queries of historical states whose estimated replay exceeds this number of slots, 0 to allow any query.",
+		Value: 0,
+	}
+	// PruneDBFlag enables deleting the data of forks older than the finalized checkpoint from the database.
+	PruneDBFlag = &cli.BoolFlag{
+		Name:  "prune-db",
+		Usage: "Delete the blocks, states and attestations of forks older than the finalized checkpoint from the database on every new finalized checkpoint.",
+	}
+	// PruneRetentionSlotsFlag defines the number of slots of canonical blocks kept before the finalized checkpoint.
+	PruneRetentionSlotsFlag = &cli.Uint64Flag{
+		Name:  "prune-retention-slots",
+		Usage: "When pruning, also delete the canonical blocks older than this number of slots before the finalized checkpoint, 0 to keep all of them.",
+		Value: 0,
+	}
+	// PruneDryRunFlag makes pruning only report the data it would delete.
+	PruneDryRunFlag = &cli.BoolFlag{
+		Name:  "prune-dry-run",
+		Usage: "When pruning, only log the data which would be deleted from the database.",
+	}
+	// DBCompactionIntervalFlag defines the minimum time between two compactions of the database file after pruning.
+	DBCompactionIntervalFlag = &cli.DurationFlag{
+		Name: "db-compaction-interval",
+		Usage: "When pruning, rewrite the database into a smaller file at most once per this interval, 0 to disable compaction. " +
+			"Database access is paused while the file is rewritten.",
+		Value: 0,
+	}
+	// FallbackWeb3ProviderFlag defines the mainchain RPC endpoints used when the web3provider is unhealthy.
+	FallbackWeb3ProviderFlag = &cli.StringSliceFlag{
+		Name: "fallback-web3provider",
+		Usage: "A mainchain web3 provider IPC or WebSocket endpoint used when the web3provider endpoint is unhealthy. " +
+			"This flag may be used multiple times, in order of preference, along with as many fallback-http-web3provider flags.",
+	}
+	// FallbackHTTPWeb3ProviderFlag defines the HTTP endpoints paired with the fallback web3 providers.
+	FallbackHTTPWeb3ProviderFlag = &cli.StringSliceFlag{
+		Name:  "fallback-http-web3provider",
+		Usage: "The http endpoint of the mainchain web3 provider given by the fallback-web3provider flag at the same position.",
+	}
+	// ETH1ChainIDFlag defines the chain ID an eth1 endpoint must serve to be considered healthy.
+	ETH1ChainIDFlag = &cli.Uint64Flag{
+		Name:  "eth1-chain-id",
+		Usage: "The chain ID of the mainchain, eth1 endpoints on another chain are considered unhealthy. 0 disables the check.",
+		Value: 0,
+	}
 )
@@ -19,6 +19,9 @@ type GlobalFlags struct {
 	MaxPageSize                       int
 	DeploymentBlock                   int
 	BlockBatchLimit                   int
+	BlockBatchLimitBurstFactor        int
+	RPCRequestLimit                   int
+	RPCRequestBurst                   int
 }
 
 var globalConfig *GlobalFlags
@@ -59,6 +62,9 @@ func ConfigureGlobalFlags(ctx *cli.Context) {
 		cfg.DisableDiscv5 = true
 	}
 	cfg.BlockBatchLimit = ctx.Int(BlockBatchLimit.Name)
+	cfg.BlockBatchLimitBurstFactor = ctx.Int(BlockBatchLimitBurstFactor.Name)
+	cfg.RPCRequestLimit = ctx.Int(RPCRequestLimit.Name)
+	cfg.RPCRequestBurst = ctx.Int(RPCRequestBurst.Name)
 	cfg.MaxPageSize = ctx.Int(RPCMaxPageSize.Name)
 	cfg.DeploymentBlock = ctx.Int(ContractDeploymentBlock.Name)
 	configureMinimumPeers(ctx, cfg)
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice/protoarray"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 )
 
 // ForkChoicer represents the full fork choice interface composed of all of the sub-interfaces.
@@ -40,4 +41,5 @@ type Getter interface {
 	Nodes() []*protoarray.Node
 	Node([32]byte) *protoarray.Node
 	HasNode([32]byte) bool
+	Dump() *rpcpb.ForkChoiceDump
 }
@@ -0,0 +1,75 @@
+package protoarray
+
+import (
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// Dump returns a copy of the fork choice store, with its block nodes, the links between them,
+// and the latest votes of the validators which have voted. Everything is copied while holding the
+// locks, so the dump is consistent with a single run of the fork choice.
+func (f *ForkChoice) Dump() *rpcpb.ForkChoiceDump {
+	f.votesLock.RLock()
+	defer f.votesLock.RUnlock()
+	f.store.nodeIndicesLock.RLock()
+	defer f.store.nodeIndicesLock.RUnlock()
+
+	nodes := f.store.nodes
+	dumpNodes := make([]*rpcpb.ForkChoiceNode, len(nodes))
+	for i, n := range nodes {
+		root := n.root
+		dumpNodes[i] = &rpcpb.ForkChoiceNode{
+			Slot:               n.Slot,
+			Root:               root[:],
+			ParentRoot:         nodeRoot(nodes, n.Parent),
+			ChildrenRoots:      make([][]byte, 0),
+			JustifiedEpoch:     n.justifiedEpoch,
+			FinalizedEpoch:     n.finalizedEpoch,
+			Weight:             n.Weight,
+			BestChildRoot:      nodeRoot(nodes, n.bestChild),
+			BestDescendantRoot: nodeRoot(nodes, n.BestDescendent),
+		}
+	}
+	for i, n := range nodes {
+		if n.Parent < uint64(len(nodes)) {
+			dumpNodes[n.Parent].ChildrenRoots = append(dumpNodes[n.Parent].ChildrenRoots, dumpNodes[i].Root)
+		}
+	}
+
+	votes := make([]*rpcpb.ForkChoiceVote, 0)
+	for i, v := range f.votes {
+		if v.currentRoot == params.BeaconConfig().ZeroHash && v.nextRoot == params.BeaconConfig().ZeroHash {
+			continue
+		}
+		currentRoot := v.currentRoot
+		nextRoot := v.nextRoot
+		vote := &rpcpb.ForkChoiceVote{
+			ValidatorIndex: uint64(i),
+			CurrentRoot:    currentRoot[:],
+			NextRoot:       nextRoot[:],
+			NextEpoch:      v.nextEpoch,
+		}
+		if i < len(f.balances) {
+			vote.Balance = f.balances[i]
+		}
+		votes = append(votes, vote)
+	}
+
+	finalizedRoot := f.store.finalizedRoot
+	return &rpcpb.ForkChoiceDump{
+		JustifiedEpoch: f.store.justifiedEpoch,
+		FinalizedEpoch: f.store.finalizedEpoch,
+		FinalizedRoot:  finalizedRoot[:],
+		Nodes:          dumpNodes,
+		Votes:          votes,
+	}
+}
+
+// This returns the root of the node at the index, or nil if there is no such node.
+func nodeRoot(nodes []*Node, index uint64) []byte {
+	if index >= uint64(len(nodes)) {
+		return nil
+	}
+	root := nodes[index].root
+	return root[:]
+}
@@ -0,0 +1,87 @@
+package protoarray
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestForkChoice_Dump(t *testing.T) {
+	ctx := context.Background()
+	f := New(0, 0, params.BeaconConfig().ZeroHash)
+	rootA := [32]byte{'A'}
+	rootB := [32]byte{'B'}
+	rootC := [32]byte{'C'}
+	if err := f.ProcessBlock(ctx, 0, rootA, params.BeaconConfig().ZeroHash, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ProcessBlock(ctx, 1, rootB, rootA, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ProcessBlock(ctx, 1, rootC, rootA, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	f.ProcessAttestation(ctx, []uint64{1}, rootB, 1)
+	if _, err := f.Head(ctx, 0, rootA, []uint64{10, 20}, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	dump := f.Dump()
+	if len(dump.Nodes) != 3 {
+		t.Fatalf("Wanted 3 nodes, received %d", len(dump.Nodes))
+	}
+	root := dump.Nodes[0]
+	if root.ParentRoot != nil {
+		t.Errorf("Expected no parent for the root node, received %#x", root.ParentRoot)
+	}
+	if len(root.ChildrenRoots) != 2 || !bytes.Equal(root.ChildrenRoots[0], rootB[:]) || !bytes.Equal(root.ChildrenRoots[1], rootC[:]) {
+		t.Errorf("Unexpected children of the root node %#x", root.ChildrenRoots)
+	}
+	if !bytes.Equal(root.BestDescendantRoot, rootB[:]) {
+		t.Errorf("Wanted best descendant %#x, received %#x", rootB, root.BestDescendantRoot)
+	}
+	if dump.Nodes[1].Weight != 20 {
+		t.Errorf("Wanted weight 20, received %d", dump.Nodes[1].Weight)
+	}
+	if len(dump.Votes) != 1 {
+		t.Fatalf("Wanted 1 vote, received %d", len(dump.Votes))
+	}
+	vote := dump.Votes[0]
+	if vote.ValidatorIndex != 1 || !bytes.Equal(vote.NextRoot, rootB[:]) || vote.NextEpoch != 1 || vote.Balance != 20 {
+		t.Errorf("Unexpected vote %v", vote)
+	}
+}
+
+func TestForkChoice_Dump_ConcurrentWithHead(t *testing.T) {
+	ctx := context.Background()
+	f := New(0, 0, params.BeaconConfig().ZeroHash)
+	rootA := [32]byte{'A'}
+	rootB := [32]byte{'B'}
+	if err := f.ProcessBlock(ctx, 0, rootA, params.BeaconConfig().ZeroHash, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ProcessBlock(ctx, 1, rootB, rootA, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := uint64(0); i < 100; i++ {
+			f.ProcessAttestation(ctx, []uint64{i}, rootB, 1)
+			if _, err := f.Head(ctx, 0, rootA, make([]uint64, i+1), 0); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if dump := f.Dump(); len(dump.Nodes) != 2 {
+			t.Errorf("Wanted 2 nodes, received %d", len(dump.Nodes))
+		}
+	}
+	wg.Wait()
+}
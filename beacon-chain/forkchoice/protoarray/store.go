@@ -41,10 +41,12 @@ func (f *ForkChoice) Head(ctx context.Context, justifiedEpoch uint64, justifiedR
 
 	newBalances := justifiedStateBalances
 
-	// Using the read lock is ok here, rest of the operations below is read only.
-	// The only time it writes to node indices is inserting and pruning blocks from the store.
-	f.store.nodeIndicesLock.RLock()
-	defer f.store.nodeIndicesLock.RUnlock()
+	f.votesLock.Lock()
+	defer f.votesLock.Unlock()
+	// The write lock is needed as the weights and best descendants of the nodes are updated,
+	// even though the node indices are not.
+	f.store.nodeIndicesLock.Lock()
+	defer f.store.nodeIndicesLock.Unlock()
 	deltas, newVotes, err := computeDeltas(ctx, f.store.nodeIndices, f.votes, f.balances, newBalances)
 	if err != nil {
 		return [32]byte{}, errors.Wrap(err, "Could not compute deltas")
@@ -65,6 +67,9 @@ func (f *ForkChoice) ProcessAttestation(ctx context.Context, validatorIndices []
 	ctx, span := trace.StartSpan(ctx, "protoArrayForkChoice.ProcessAttestation")
 	defer span.End()
 
+	f.votesLock.Lock()
+	defer f.votesLock.Unlock()
+
 	for _, index := range validatorIndices {
 		// Validator indices will grow the vote cache.
 		for index >= uint64(len(f.votes)) {
@@ -0,0 +1,65 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+// eventsPath is the path of the server-sent events endpoint of the events service.
+const eventsPath = "/eth/v1alpha1/events"
+
+// eventsHandler streams the events of the beacon node as server-sent events. The topics to
+// subscribe to are given as a comma separated list, such as "?topics=head,block". All topics
+// are streamed if none are given.
+func (g *Gateway) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	req := &rpcpb.StreamEventsRequest{}
+	if topics := r.URL.Query().Get("topics"); topics != "" {
+		for _, name := range strings.Split(topics, ",") {
+			topic, ok := rpcpb.EventTopic_value[strings.ToUpper(strings.TrimSpace(name))]
+			if !ok {
+				http.Error(w, fmt.Sprintf("Unknown topic %q", name), http.StatusBadRequest)
+				return
+			}
+			req.Topics = append(req.Topics, rpcpb.EventTopic(topic))
+		}
+	}
+
+	stream, err := rpcpb.NewEventsClient(g.conn).StreamEvents(r.Context(), req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Could not subscribe to events: %v", err), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	marshaler := &jsonpb.Marshaler{EmitDefaults: true}
+	for {
+		e, err := stream.Recv()
+		if err != nil {
+			if r.Context().Err() == nil {
+				log.WithError(err).Debug("Events stream closed")
+			}
+			return
+		}
+		data, err := marshaler.MarshalToString(e)
+		if err != nil {
+			log.WithError(err).Error("Could not marshal event")
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", strings.ToLower(e.Topic.String()), data); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	ptypes "github.com/gogo/protobuf/types"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// forkChoicePath is the path of the fork choice dump endpoint of the debug service.
+	forkChoicePath = "/eth/v1alpha1/debug/forkchoice"
+	// forkChoiceSnapshotsPath is the path of the endpoint listing the fork choice snapshots.
+	forkChoiceSnapshotsPath = "/eth/v1alpha1/debug/forkchoice/snapshots"
+)
+
+// forkChoiceHandler returns the fork choice store of the beacon node as JSON. The snapshot
+// taken at or before a unix time is returned instead if given, such as "?timestamp=1588000000".
+func (g *Gateway) forkChoiceHandler(w http.ResponseWriter, r *http.Request) {
+	client := rpcpb.NewDebugClient(g.conn)
+	var res proto.Message
+	var err error
+	if ts := r.URL.Query().Get("timestamp"); ts != "" {
+		timestamp, parseErr := strconv.ParseUint(ts, 10, 64)
+		if parseErr != nil {
+			http.Error(w, fmt.Sprintf("Invalid timestamp %q", ts), http.StatusBadRequest)
+			return
+		}
+		res, err = client.GetForkChoiceSnapshot(r.Context(), &rpcpb.ForkChoiceSnapshotRequest{Timestamp: timestamp})
+	} else {
+		res, err = client.GetForkChoice(r.Context(), &ptypes.Empty{})
+	}
+	writeDebugResponse(w, res, err)
+}
+
+// forkChoiceSnapshotsHandler lists the fork choice snapshots kept on disk by the beacon node.
+func (g *Gateway) forkChoiceSnapshotsHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := rpcpb.NewDebugClient(g.conn).ListForkChoiceSnapshots(r.Context(), &ptypes.Empty{})
+	writeDebugResponse(w, res, err)
+}
+
+func writeDebugResponse(w http.ResponseWriter, res proto.Message, err error) {
+	if err != nil {
+		code := http.StatusBadGateway
+		if status.Code(err) == codes.NotFound {
+			code = http.StatusNotFound
+		}
+		http.Error(w, status.Convert(err).Message(), code)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := (&jsonpb.Marshaler{EmitDefaults: true}).Marshal(w, res); err != nil {
+		log.WithError(err).Error("Could not write debug response")
+	}
+}
@@ -61,6 +61,10 @@ func (g *Gateway) Start() {
 		}
 	}
 
+	g.mux.HandleFunc(eventsPath, g.eventsHandler)
+	g.mux.HandleFunc(forkChoicePath, g.forkChoiceHandler)
+	g.mux.HandleFunc(forkChoiceSnapshotsPath, g.forkChoiceSnapshotsHandler)
+	g.mux.HandleFunc(reorgsPath, g.reorgsHandler)
 	g.mux.Handle("/", gwmux)
 
 	g.server = &http.Server{
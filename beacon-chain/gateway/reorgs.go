@@ -0,0 +1,28 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+// reorgsPath is the path of the reorg history endpoint of the debug service.
+const reorgsPath = "/eth/v1alpha1/debug/reorgs"
+
+// reorgsHandler returns the recent chain reorgs seen by the beacon node as JSON, latest first.
+// Shallow reorgs can be left out with a minimum depth, such as "?min_depth=4".
+func (g *Gateway) reorgsHandler(w http.ResponseWriter, r *http.Request) {
+	req := &rpcpb.ListReorgsRequest{}
+	if depth := r.URL.Query().Get("min_depth"); depth != "" {
+		minDepth, err := strconv.ParseUint(depth, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid minimum depth %q", depth), http.StatusBadRequest)
+			return
+		}
+		req.MinDepth = minDepth
+	}
+	res, err := rpcpb.NewDebugClient(g.conn).ListReorgs(r.Context(), req)
+	writeDebugResponse(w, res, err)
+}
@@ -41,6 +41,31 @@ var appFlags = []cli.Flag{
 	flags.UnsafeSync,
 	flags.DisableDiscv5,
 	flags.BlockBatchLimit,
+	flags.BlockBatchLimitBurstFactor,
+	flags.RPCRequestLimit,
+	flags.RPCRequestBurst,
+	flags.CheckpointStateFlag,
+	flags.CheckpointBlockFlag,
+	flags.CheckpointSyncRPCFlag,
+	flags.CheckpointSyncRootFlag,
+	flags.CheckpointSyncEpochFlag,
+	flags.CheckpointSyncCertFlag,
+	flags.PersistOperationPoolsFlag,
+	flags.AttestationPoolPersistIntervalFlag,
+	flags.ForkChoiceSnapshotIntervalFlag,
+	flags.ForkChoiceSnapshotRetentionFlag,
+	flags.ValidatorPerformanceFlag,
+	flags.BeaconAPIPort,
+	flags.StateSnapshotStrategyFlag,
+	flags.StateSnapshotCountFlag,
+	flags.MaxStateReplaySlotsFlag,
+	flags.PruneDBFlag,
+	flags.PruneRetentionSlotsFlag,
+	flags.PruneDryRunFlag,
+	flags.DBCompactionIntervalFlag,
+	flags.FallbackWeb3ProviderFlag,
+	flags.FallbackHTTPWeb3ProviderFlag,
+	flags.ETH1ChainIDFlag,
 	flags.InteropMockEth1DataVotesFlag,
 	flags.InteropGenesisStateFlag,
 	flags.InteropNumValidatorsFlag,
@@ -101,6 +126,7 @@ func main() {
 	app.Version = version.GetVersion()
 
 	app.Flags = appFlags
+	app.Commands = []*cli.Command{dbCommand}
 
 	app.Before = func(ctx *cli.Context) error {
 		// Load any flags from file, if specified.
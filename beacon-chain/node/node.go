@@ -4,12 +4,14 @@ package node
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/signal"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -27,13 +29,18 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/gateway"
 	interopcoldstart "github.com/prysmaticlabs/prysm/beacon-chain/interop-cold-start"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/kv"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/slashings"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/performance"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/backfill"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/checkpoint"
 	initialsync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync"
 	initialsyncold "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync-old"
 	"github.com/prysmaticlabs/prysm/shared"
@@ -52,8 +59,10 @@ import (
 
 var log = logrus.WithField("prefix", "node")
 
-const beaconChainDBName = "beaconchaindata"
+// BeaconChainDBName is the name of the beacon chain database directory within the data directory.
+const BeaconChainDBName = "beaconchaindata"
 const testSkipPowFlag = "test-skip-pow"
+const forkChoiceSnapshotsDirName = "forkchoice-snapshots"
 
 // BeaconNode defines a struct that handles the services running a random beacon chain
 // full PoS node. It handles the lifecycle of the entire system and registers
@@ -93,6 +102,7 @@ func NewBeaconNode(ctx *cli.Context) (*BeaconNode, error) {
 	flags.ConfigureGlobalFlags(ctx)
 	registry := shared.NewServiceRegistry()
 
+	attestationPool := attestations.NewPool()
 	beacon := &BeaconNode{
 		ctx:               ctx,
 		services:          registry,
@@ -100,7 +110,7 @@ func NewBeaconNode(ctx *cli.Context) (*BeaconNode, error) {
 		stateFeed:         new(event.Feed),
 		blockFeed:         new(event.Feed),
 		opFeed:            new(event.Feed),
-		attestationPool:   attestations.NewPool(),
+		attestationPool:   attestationPool,
 		exitPool:          voluntaryexits.NewPool(),
 		slashingsPool:     slashings.NewPool(),
 		stateSummaryCache: cache.NewStateSummaryCache(),
@@ -110,7 +120,19 @@ func NewBeaconNode(ctx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
-	beacon.startStateGen()
+	if err := beacon.initializeFromCheckpoint(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := beacon.startStateGen(ctx); err != nil {
+		return nil, err
+	}
+
+	if ctx.Bool(flags.PersistOperationPoolsFlag.Name) {
+		if err := beacon.persistOperationPools(attestationPool); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := beacon.registerP2P(ctx); err != nil {
 		return nil, err
@@ -142,6 +164,10 @@ func NewBeaconNode(ctx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if err := beacon.registerBackfillService(ctx); err != nil {
+		return nil, err
+	}
+
 	if err := beacon.registerRPCService(ctx); err != nil {
 		return nil, err
 	}
@@ -154,6 +180,10 @@ func NewBeaconNode(ctx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if err := beacon.registerPerformanceService(ctx); err != nil {
+		return nil, err
+	}
+
 	if !ctx.Bool(cmd.DisableMonitoringFlag.Name) {
 		if err := beacon.registerPrometheusService(ctx); err != nil {
 			return nil, err
@@ -232,7 +262,7 @@ func (b *BeaconNode) startForkChoice() {
 
 func (b *BeaconNode) startDB(ctx *cli.Context) error {
 	baseDir := ctx.String(cmd.DataDirFlag.Name)
-	dbPath := path.Join(baseDir, beaconChainDBName)
+	dbPath := path.Join(baseDir, BeaconChainDBName)
 	clearDB := ctx.Bool(cmd.ClearDB.Name)
 	forceClearDB := ctx.Bool(cmd.ForceClearDB.Name)
 
@@ -271,8 +301,80 @@ func (b *BeaconNode) startDB(ctx *cli.Context) error {
 	return nil
 }
 
-func (b *BeaconNode) startStateGen() {
+func (b *BeaconNode) initializeFromCheckpoint(ctx *cli.Context) error {
+	cfg := &checkpoint.Config{
+		BeaconDB:       b.db,
+		StatePath:      ctx.String(flags.CheckpointStateFlag.Name),
+		BlockPath:      ctx.String(flags.CheckpointBlockFlag.Name),
+		RemoteEndpoint: ctx.String(flags.CheckpointSyncRPCFlag.Name),
+		TrustedEpoch:   ctx.Uint64(flags.CheckpointSyncEpochFlag.Name),
+		CertPath:       ctx.String(flags.CheckpointSyncCertFlag.Name),
+	}
+	if root := ctx.String(flags.CheckpointSyncRootFlag.Name); root != "" {
+		decoded, err := hex.DecodeString(strings.TrimPrefix(root, "0x"))
+		if err != nil || len(decoded) != 32 {
+			return fmt.Errorf("invalid checkpoint sync root %s, expected 32 hex encoded bytes", root)
+		}
+		cfg.TrustedRoot = decoded
+	}
+	return checkpoint.Initialize(context.Background(), cfg)
+}
+
+func (b *BeaconNode) startStateGen(ctx *cli.Context) error {
 	b.stateGen = stategen.New(b.db, b.stateSummaryCache)
+
+	count := ctx.Int(flags.StateSnapshotCountFlag.Name)
+	switch strategy := ctx.String(flags.StateSnapshotStrategyFlag.Name); strategy {
+	case "none":
+	case "exponential":
+		b.stateGen.SetSnapshotStrategy(stategen.NewExponentialSnapshots(params.BeaconConfig().SlotsPerEpoch, count))
+	case "lru":
+		b.stateGen.SetSnapshotStrategy(stategen.NewLRUSnapshots(count))
+	default:
+		return fmt.Errorf("unknown state snapshot strategy %q", strategy)
+	}
+	return nil
+}
+
+// persistOperationPools reloads the operations saved in the database into the operation pools,
+// verifying them against the head state, and saves the slashings and exits inserted in the pools
+// from now on. The attestations are saved periodically by the attestation pool service instead.
+// Before the chain starts there is no head state, and nothing is reloaded.
+func (b *BeaconNode) persistOperationPools(attestationPool *kv.AttCaches) error {
+	ctx := context.Background()
+	headState, err := b.db.HeadState(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve head state")
+	}
+	if headState == nil {
+		headBlock, err := b.db.HeadBlock(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not retrieve head block")
+		}
+		if headBlock != nil {
+			headRoot, err := stateutil.BlockRoot(headBlock.Block)
+			if err != nil {
+				return errors.Wrap(err, "could not compute head block root")
+			}
+			headState, err = b.stateGen.StateByRoot(ctx, headRoot)
+			if err != nil {
+				return errors.Wrap(err, "could not retrieve head state")
+			}
+		}
+	}
+
+	if headState != nil {
+		if err := attestationPool.Reload(ctx, b.db, headState); err != nil {
+			return errors.Wrap(err, "could not reload attestation pool")
+		}
+	}
+	if err := b.slashingsPool.Persist(ctx, b.db, headState); err != nil {
+		return errors.Wrap(err, "could not persist slashings pool")
+	}
+	if err := b.exitPool.Persist(ctx, b.db, headState); err != nil {
+		return errors.Wrap(err, "could not persist exit pool")
+	}
+	return nil
 }
 
 func (b *BeaconNode) registerP2P(ctx *cli.Context) error {
@@ -329,8 +431,14 @@ func (b *BeaconNode) fetchP2P(ctx *cli.Context) p2p.P2P {
 }
 
 func (b *BeaconNode) registerAttestationPool() error {
+	var store kv.Store
+	if b.ctx.Bool(flags.PersistOperationPoolsFlag.Name) {
+		store = b.db
+	}
 	s, err := attestations.NewService(context.Background(), &attestations.Config{
-		Pool: b.attestationPool,
+		Pool:            b.attestationPool,
+		Store:           store,
+		PersistInterval: b.ctx.Duration(flags.AttestationPoolPersistIntervalFlag.Name),
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not register atts pool service")
@@ -363,6 +471,15 @@ func (b *BeaconNode) registerBlockchainService(ctx *cli.Context) error {
 		ForkChoiceStore:   b.forkChoiceStore,
 		OpsService:        opsService,
 		StateGen:          b.stateGen,
+
+		ForkChoiceSnapshotDir:       filepath.Join(ctx.String(cmd.DataDirFlag.Name), forkChoiceSnapshotsDirName),
+		ForkChoiceSnapshotInterval:  ctx.Duration(flags.ForkChoiceSnapshotIntervalFlag.Name),
+		ForkChoiceSnapshotRetention: ctx.Int(flags.ForkChoiceSnapshotRetentionFlag.Name),
+
+		PruneDB:              ctx.Bool(flags.PruneDBFlag.Name),
+		PruneRetentionSlots:  ctx.Uint64(flags.PruneRetentionSlotsFlag.Name),
+		PruneDryRun:          ctx.Bool(flags.PruneDryRunFlag.Name),
+		DBCompactionInterval: ctx.Duration(flags.DBCompactionIntervalFlag.Name),
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not register blockchain service")
@@ -383,6 +500,19 @@ func (b *BeaconNode) registerPOWChainService(cliCtx *cli.Context) error {
 		log.Fatalf("Invalid deposit contract address given: %s", depAddress)
 	}
 
+	fallbackURLs := cliCtx.StringSlice(flags.FallbackWeb3ProviderFlag.Name)
+	fallbackHTTPURLs := cliCtx.StringSlice(flags.FallbackHTTPWeb3ProviderFlag.Name)
+	if len(fallbackURLs) != len(fallbackHTTPURLs) {
+		return fmt.Errorf(
+			"%d %s flags given for %d %s flags",
+			len(fallbackHTTPURLs), flags.FallbackHTTPWeb3ProviderFlag.Name, len(fallbackURLs), flags.FallbackWeb3ProviderFlag.Name,
+		)
+	}
+	fallbacks := make([]powchain.Endpoint, len(fallbackURLs))
+	for i := range fallbackURLs {
+		fallbacks[i] = powchain.Endpoint{ETH1: fallbackURLs[i], HTTP: fallbackHTTPURLs[i]}
+	}
+
 	ctx := context.Background()
 	cfg := &powchain.Web3ServiceConfig{
 		ETH1Endpoint:    cliCtx.String(flags.Web3ProviderFlag.Name),
@@ -391,6 +521,8 @@ func (b *BeaconNode) registerPOWChainService(cliCtx *cli.Context) error {
 		BeaconDB:        b.db,
 		DepositCache:    b.depositCache,
 		StateNotifier:   b,
+		Fallbacks:       fallbacks,
+		ETH1ChainID:     cliCtx.Uint64(flags.ETH1ChainIDFlag.Name),
 	}
 	web3Service, err := powchain.NewService(ctx, cfg)
 	if err != nil {
@@ -482,6 +614,14 @@ func (b *BeaconNode) registerInitialSyncService(ctx *cli.Context) error {
 	return b.services.RegisterService(is)
 }
 
+func (b *BeaconNode) registerBackfillService(ctx *cli.Context) error {
+	svc := backfill.NewService(context.Background(), &backfill.Config{
+		P2P: b.fetchP2P(ctx),
+		DB:  b.db,
+	})
+	return b.services.RegisterService(svc)
+}
+
 func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 	var chainService *blockchain.Service
 	if err := b.services.FetchService(&chainService); err != nil {
@@ -532,6 +672,10 @@ func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 	slasherProvider := ctx.String(flags.SlasherProviderFlag.Name)
 
 	mockEth1DataVotes := ctx.Bool(flags.InteropMockEth1DataVotesFlag.Name)
+	var beaconAPIPort string
+	if apiPort := ctx.Int(flags.BeaconAPIPort.Name); apiPort > 0 {
+		beaconAPIPort = strconv.Itoa(apiPort)
+	}
 	rpcService := rpc.NewService(context.Background(), &rpc.Config{
 		Host:                  host,
 		Port:                  port,
@@ -540,6 +684,9 @@ func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 		BeaconDB:              b.db,
 		Broadcaster:           b.fetchP2P(ctx),
 		PeersFetcher:          b.fetchP2P(ctx),
+		PeerManager:           b.fetchP2P(ctx),
+		IdentityProvider:      b.fetchP2P(ctx),
+		MetadataProvider:      b.fetchP2P(ctx),
 		HeadFetcher:           chainService,
 		ForkFetcher:           chainService,
 		FinalizationFetcher:   chainService,
@@ -548,6 +695,7 @@ func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 		AttestationReceiver:   chainService,
 		GenesisTimeFetcher:    chainService,
 		GenesisFetcher:        chainService,
+		ForkChoiceFetcher:     chainService,
 		AttestationsPool:      b.attestationPool,
 		ExitPool:              b.exitPool,
 		SlashingsPool:         b.slashingsPool,
@@ -563,6 +711,8 @@ func (b *BeaconNode) registerRPCService(ctx *cli.Context) error {
 		SlasherCert:           slasherCert,
 		SlasherProvider:       slasherProvider,
 		StateGen:              b.stateGen,
+		BeaconAPIPort:         beaconAPIPort,
+		MaxStateReplaySlots:   uint64(ctx.Int(flags.MaxStateReplaySlotsFlag.Name)),
 	})
 
 	return b.services.RegisterService(rpcService)
@@ -643,3 +793,19 @@ func (b *BeaconNode) registerArchiverService(ctx *cli.Context) error {
 	})
 	return b.services.RegisterService(svc)
 }
+
+func (b *BeaconNode) registerPerformanceService(ctx *cli.Context) error {
+	if !ctx.Bool(flags.ValidatorPerformanceFlag.Name) {
+		return nil
+	}
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	svc := performance.NewService(context.Background(), &performance.Config{
+		BeaconDB:      b.db,
+		HeadFetcher:   chainService,
+		StateNotifier: b,
+	})
+	return b.services.RegisterService(svc)
+}
@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"context"
 	"sync"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -23,6 +24,13 @@ type AttCaches struct {
 	blockAtt           map[[32]byte][]*ethpb.Attestation
 }
 
+// Store persists the aggregated and unaggregated attestations of the caches, so they survive
+// a restart of the node.
+type Store interface {
+	PoolAttestations(ctx context.Context) ([]*ethpb.Attestation, error)
+	SavePoolAttestations(ctx context.Context, atts []*ethpb.Attestation) error
+}
+
 // NewAttCaches initializes a new attestation pool consists of multiple KV store in cache for
 // various kind of attestations.
 func NewAttCaches() *AttCaches {
@@ -0,0 +1,73 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+// Reload saves the attestations persisted in the store in the caches. The attestations which
+// expired, were included in a block, or target an epoch before the finalized checkpoint while the
+// node was down are skipped.
+func (p *AttCaches) Reload(ctx context.Context, store Store, headState *stateTrie.BeaconState) error {
+	atts, err := store.PoolAttestations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve persisted attestations")
+	}
+
+	included := make(map[[32]byte][]bitfield.Bitlist)
+	pending := append(headState.PreviousEpochAttestations(), headState.CurrentEpochAttestations()...)
+	for _, a := range pending {
+		r, err := hashFn(a.Data)
+		if err != nil {
+			return errors.Wrap(err, "could not tree hash attestation data")
+		}
+		included[r] = append(included[r], a.AggregationBits)
+	}
+	finalizedEpoch := headState.FinalizedCheckpointEpoch()
+
+	for _, att := range atts {
+		if att.Data == nil || att.Data.Target == nil || att.Data.Target.Epoch < finalizedEpoch {
+			continue
+		}
+		r, err := hashFn(att.Data)
+		if err != nil {
+			return errors.Wrap(err, "could not tree hash attestation data")
+		}
+		if expired(att.Data.Slot, headState.GenesisTime()) || votesIncluded(att, included[r]) {
+			continue
+		}
+		if helpers.IsAggregated(att) {
+			err = p.SaveAggregatedAttestation(att)
+		} else {
+			err = p.SaveUnaggregatedAttestation(att)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Return true if the votes of the attestation are all contained in one of the included votes.
+func votesIncluded(att *ethpb.Attestation, included []bitfield.Bitlist) bool {
+	for _, bits := range included {
+		if bits.Len() == att.AggregationBits.Len() && bits.Contains(att.AggregationBits) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return true if the input slot has been expired, that is one epoch behind the current time.
+func expired(slot uint64, genesisTime uint64) bool {
+	expirationSlot := slot + params.BeaconConfig().SlotsPerEpoch
+	expirationTime := genesisTime + expirationSlot*params.BeaconConfig().SecondsPerSlot
+	return uint64(roughtime.Now().Unix()) >= expirationTime
+}
@@ -0,0 +1,72 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+type mockStore struct {
+	atts []*ethpb.Attestation
+}
+
+func (m *mockStore) PoolAttestations(_ context.Context) ([]*ethpb.Attestation, error) {
+	return m.atts, nil
+}
+
+func (m *mockStore) SavePoolAttestations(_ context.Context, atts []*ethpb.Attestation) error {
+	m.atts = atts
+	return nil
+}
+
+func TestKV_Reload(t *testing.T) {
+	sig := bls.RandKey().Sign([]byte{'a'}).Marshal()
+	// The head slot is two epochs after genesis.
+	slot := 2 * params.BeaconConfig().SlotsPerEpoch
+	genesisTime := uint64(roughtime.Now().Unix()) - slot*params.BeaconConfig().SecondsPerSlot
+	target := &ethpb.Checkpoint{Epoch: 2}
+	includedData := &ethpb.AttestationData{Slot: slot, BeaconBlockRoot: []byte{'A'}, Target: target}
+	headState, err := stateTrie.InitializeFromProto(&pb.BeaconState{
+		Slot:                slot,
+		GenesisTime:         genesisTime,
+		FinalizedCheckpoint: &ethpb.Checkpoint{Epoch: 1},
+		CurrentEpochAttestations: []*pb.PendingAttestation{
+			{Data: includedData, AggregationBits: bitfield.Bitlist{0b1011}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := &ethpb.AttestationData{Slot: slot, Target: target}
+	unaggregated := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b1001}, Signature: sig}
+	aggregated := &ethpb.Attestation{Data: data, AggregationBits: bitfield.Bitlist{0b1110}, Signature: sig}
+	expiredAtt := &ethpb.Attestation{Data: &ethpb.AttestationData{Slot: 1, Target: target}, AggregationBits: bitfield.Bitlist{0b1001}, Signature: sig}
+	includedAtt := &ethpb.Attestation{Data: includedData, AggregationBits: bitfield.Bitlist{0b1010}, Signature: sig}
+	// This one is recent, but targets an epoch before the finalized checkpoint.
+	finalizedAtt := &ethpb.Attestation{
+		Data:            &ethpb.AttestationData{Slot: slot, Target: &ethpb.Checkpoint{Epoch: 0}},
+		AggregationBits: bitfield.Bitlist{0b1001},
+		Signature:       sig,
+	}
+	store := &mockStore{atts: []*ethpb.Attestation{unaggregated, aggregated, expiredAtt, includedAtt, finalizedAtt}}
+
+	cache := NewAttCaches()
+	if err := cache.Reload(context.Background(), store, headState); err != nil {
+		t.Fatal(err)
+	}
+	if atts := cache.UnaggregatedAttestations(); len(atts) != 1 || !proto.Equal(atts[0], unaggregated) {
+		t.Errorf("Expected unaggregated attestation to be reloaded, received %v", atts)
+	}
+	if atts := cache.AggregatedAttestations(); len(atts) != 1 || !proto.Equal(atts[0], aggregated) {
+		t.Errorf("Expected aggregated attestation to be reloaded, received %v", atts)
+	}
+}
@@ -0,0 +1,42 @@
+package attestations
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// Persist the attestations of the pool every epoch interval, unless another interval is configured.
+var persistAttsPoolPeriod = time.Duration(params.BeaconConfig().SlotsPerEpoch*params.BeaconConfig().SecondsPerSlot) * time.Second
+
+// This persists the attestations pool by running saveAttsPool at every persist interval.
+// Attestations are not written as they are inserted, as the pool is saved as a whole: the
+// attestations received since the last save are lost if the node stops without shutting
+// down cleanly, while a clean shutdown saves them in Stop.
+func (s *Service) persistAttsPool() {
+	ticker := time.NewTicker(s.persistInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.saveAttsPool(); err != nil {
+				log.WithError(err).Error("Could not persist attestation pool")
+			}
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting routine")
+			return
+		}
+	}
+}
+
+// This saves the aggregated and unaggregated attestations of the pool in the store, replacing
+// the ones saved before.
+func (s *Service) saveAttsPool() error {
+	atts := append(s.pool.AggregatedAttestations(), s.pool.UnaggregatedAttestations()...)
+	if err := s.store.SavePoolAttestations(context.Background(), atts); err != nil {
+		return errors.Wrap(err, "could not persist attestations")
+	}
+	return nil
+}
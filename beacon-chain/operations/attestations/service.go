@@ -2,8 +2,10 @@ package attestations
 
 import (
 	"context"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations/kv"
 )
 
 var forkChoiceProcessedRootsSize = 1 << 16
@@ -13,14 +15,21 @@ type Service struct {
 	ctx                      context.Context
 	cancel                   context.CancelFunc
 	pool                     Pool
+	store                    kv.Store
 	err                      error
 	forkChoiceProcessedRoots *lru.Cache
 	genesisTime              uint64
+	persistInterval          time.Duration
 }
 
 // Config options for the service.
 type Config struct {
 	Pool Pool
+	// Store persists the aggregated and unaggregated attestations of the pool, if set.
+	Store kv.Store
+	// PersistInterval is how often the attestations of the pool are saved in the store,
+	// every epoch if unset.
+	PersistInterval time.Duration
 }
 
 // NewService instantiates a new attestation pool service instance that will
@@ -31,12 +40,18 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 		return nil, err
 	}
 
+	persistInterval := cfg.PersistInterval
+	if persistInterval == 0 {
+		persistInterval = persistAttsPoolPeriod
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	return &Service{
 		ctx:                      ctx,
 		cancel:                   cancel,
 		pool:                     cfg.Pool,
+		store:                    cfg.Store,
 		forkChoiceProcessedRoots: cache,
+		persistInterval:          persistInterval,
 	}, nil
 }
 
@@ -44,12 +59,19 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 func (s *Service) Start() {
 	go s.prepareForkChoiceAtts()
 	go s.pruneAttsPool()
+	if s.store != nil {
+		go s.persistAttsPool()
+	}
 }
 
 // Stop the beacon block attestation pool service's main event loop
-// and associated goroutines.
+// and associated goroutines. The attestations of the pool are persisted
+// a last time, if a store is set.
 func (s *Service) Stop() error {
-	defer s.cancel()
+	s.cancel()
+	if s.store != nil {
+		return s.saveAttsPool()
+	}
 	return nil
 }
 
@@ -3,7 +3,13 @@ package attestations
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
 )
 
 func TestStop_OK(t *testing.T) {
@@ -29,3 +35,88 @@ func TestStatus_Error(t *testing.T) {
 		t.Errorf("Wanted: %v, got: %v", s.err, s.Status())
 	}
 }
+
+type mockStore struct {
+	atts []*ethpb.Attestation
+	lock sync.Mutex
+}
+
+func (m *mockStore) PoolAttestations(_ context.Context) ([]*ethpb.Attestation, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.atts, nil
+}
+
+func (m *mockStore) SavePoolAttestations(_ context.Context, atts []*ethpb.Attestation) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.atts = atts
+	return nil
+}
+
+func TestStop_PersistsPool(t *testing.T) {
+	store := &mockStore{}
+	s, err := NewService(context.Background(), &Config{Pool: NewPool(), Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	att := &ethpb.Attestation{
+		Data:            &ethpb.AttestationData{Slot: 1},
+		AggregationBits: bitfield.Bitlist{0b1001},
+		Signature:       make([]byte, 96),
+	}
+	if err := s.pool.SaveUnaggregatedAttestation(att); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.atts) != 1 || !proto.Equal(store.atts[0], att) {
+		t.Errorf("Expected the pool to be persisted when stopping, received %v", store.atts)
+	}
+}
+
+func TestPersistAttsPool_ConfiguredInterval(t *testing.T) {
+	store := &mockStore{}
+	s, err := NewService(context.Background(), &Config{
+		Pool:            NewPool(),
+		Store:           store,
+		PersistInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.cancel()
+	att := &ethpb.Attestation{
+		Data:            &ethpb.AttestationData{Slot: 1},
+		AggregationBits: bitfield.Bitlist{0b1001},
+		Signature:       make([]byte, 96),
+	}
+	if err := s.pool.SaveUnaggregatedAttestation(att); err != nil {
+		t.Fatal(err)
+	}
+
+	go s.persistAttsPool()
+	for i := 0; i < 100; i++ {
+		atts, err := store.PoolAttestations(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(atts) == 1 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Expected the pool to be persisted at the configured interval")
+}
+
+func TestNewService_DefaultPersistInterval(t *testing.T) {
+	s, err := NewService(context.Background(), &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.persistInterval != persistAttsPoolPeriod {
+		t.Errorf("Expected the pool to be persisted every epoch by default, received %v", s.persistInterval)
+	}
+}
@@ -0,0 +1,84 @@
+package slashings
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+)
+
+// Persist reloads the slashings saved in the store into the pool, then saves the slashings
+// inserted in the pool from now on. It must be called before the pool is used. Nothing is
+// reloaded if the head state is nil, as before the chain starts.
+func (p *Pool) Persist(ctx context.Context, store Store, headState *beaconstate.BeaconState) error {
+	if headState != nil {
+		if err := p.reload(ctx, store, headState); err != nil {
+			return err
+		}
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.store = store
+	return nil
+}
+
+// This inserts the slashings saved in the store into the pool. The slashings are verified
+// against the head state, as they may have become invalid while the node was down, and the
+// invalid ones are deleted from the store.
+func (p *Pool) reload(ctx context.Context, store Store, headState *beaconstate.BeaconState) error {
+	proposerSlashings, err := store.PoolProposerSlashings(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve persisted proposer slashings")
+	}
+	reloaded := 0
+	for _, slashing := range proposerSlashings {
+		if err := p.InsertProposerSlashing(ctx, headState, slashing); err != nil {
+			log.WithError(err).Debug("Dropping persisted proposer slashing")
+			if err := store.DeletePoolProposerSlashing(ctx, slashing); err != nil {
+				return errors.Wrap(err, "could not delete persisted proposer slashing")
+			}
+			continue
+		}
+		reloaded++
+	}
+
+	attesterSlashings, err := store.PoolAttesterSlashings(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve persisted attester slashings")
+	}
+	for _, slashing := range attesterSlashings {
+		if err := p.InsertAttesterSlashing(ctx, headState, slashing); err != nil {
+			log.WithError(err).Debug("Dropping persisted attester slashing")
+			if err := store.DeletePoolAttesterSlashing(ctx, slashing); err != nil {
+				return errors.Wrap(err, "could not delete persisted attester slashing")
+			}
+			continue
+		}
+		reloaded++
+	}
+	log.WithField("slashings", reloaded).Info("Reloaded persisted slashings")
+	return nil
+}
+
+// This deletes an attester slashing from the store, if the pool is persisted. The
+// pool lock must be held by the caller.
+func (p *Pool) deletePersistedAttesterSlashing(slashing *ethpb.AttesterSlashing) {
+	if p.store == nil {
+		return
+	}
+	if err := p.store.DeletePoolAttesterSlashing(context.Background(), slashing); err != nil {
+		log.WithError(err).Error("Could not delete persisted attester slashing")
+	}
+}
+
+// This deletes a proposer slashing from the store, if the pool is persisted. The
+// pool lock must be held by the caller.
+func (p *Pool) deletePersistedProposerSlashing(slashing *ethpb.ProposerSlashing) {
+	if p.store == nil {
+		return
+	}
+	if err := p.store.DeletePoolProposerSlashing(context.Background(), slashing); err != nil {
+		log.WithError(err).Error("Could not delete persisted proposer slashing")
+	}
+}
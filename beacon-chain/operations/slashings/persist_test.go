@@ -0,0 +1,104 @@
+package slashings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+type mockStore struct {
+	proposerSlashings []*ethpb.ProposerSlashing
+	attesterSlashings []*ethpb.AttesterSlashing
+}
+
+func (m *mockStore) PoolProposerSlashings(_ context.Context) ([]*ethpb.ProposerSlashing, error) {
+	return m.proposerSlashings, nil
+}
+
+func (m *mockStore) SavePoolProposerSlashing(_ context.Context, slashing *ethpb.ProposerSlashing) error {
+	m.proposerSlashings = append(m.proposerSlashings, slashing)
+	return nil
+}
+
+func (m *mockStore) DeletePoolProposerSlashing(_ context.Context, slashing *ethpb.ProposerSlashing) error {
+	for i, s := range m.proposerSlashings {
+		if proto.Equal(s, slashing) {
+			m.proposerSlashings = append(m.proposerSlashings[:i], m.proposerSlashings[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *mockStore) PoolAttesterSlashings(_ context.Context) ([]*ethpb.AttesterSlashing, error) {
+	return m.attesterSlashings, nil
+}
+
+func (m *mockStore) SavePoolAttesterSlashing(_ context.Context, slashing *ethpb.AttesterSlashing) error {
+	m.attesterSlashings = append(m.attesterSlashings, slashing)
+	return nil
+}
+
+func (m *mockStore) DeletePoolAttesterSlashing(_ context.Context, slashing *ethpb.AttesterSlashing) error {
+	for i, s := range m.attesterSlashings {
+		if proto.Equal(s, slashing) {
+			m.attesterSlashings = append(m.attesterSlashings[:i], m.attesterSlashings[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func TestPool_Persist(t *testing.T) {
+	ctx := context.Background()
+	beaconState, privKeys := testutil.DeterministicGenesisState(t, 64)
+	valid, err := testutil.GenerateProposerSlashingForValidator(beaconState, privKeys[1], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := testutil.GenerateProposerSlashingForValidator(beaconState, privKeys[2], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The validator was slashed while the node was down.
+	slashedVal, err := beaconState.ValidatorAtIndex(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slashedVal.Slashed = true
+	if err := beaconState.UpdateValidatorAtIndex(2, slashedVal); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &mockStore{proposerSlashings: []*ethpb.ProposerSlashing{valid, stale}}
+	p := NewPool()
+	if err := p.Persist(ctx, store, beaconState); err != nil {
+		t.Fatal(err)
+	}
+	pending := p.PendingProposerSlashings(ctx)
+	if len(pending) != 1 || !proto.Equal(pending[0], valid) {
+		t.Errorf("Expected only the valid slashing to be reloaded, received %v", pending)
+	}
+	if len(store.proposerSlashings) != 1 || !proto.Equal(store.proposerSlashings[0], valid) {
+		t.Errorf("Expected the invalid slashing to be deleted from the store, received %v", store.proposerSlashings)
+	}
+
+	newSlashing, err := testutil.GenerateProposerSlashingForValidator(beaconState, privKeys[3], 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.InsertProposerSlashing(ctx, beaconState, newSlashing); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.proposerSlashings) != 2 {
+		t.Errorf("Expected inserted slashing to be persisted, received %v", store.proposerSlashings)
+	}
+	p.MarkIncludedProposerSlashing(valid)
+	p.MarkIncludedProposerSlashing(newSlashing)
+	if len(store.proposerSlashings) != 0 {
+		t.Errorf("Expected included slashings to be deleted from the store, received %v", store.proposerSlashings)
+	}
+}
@@ -130,6 +130,12 @@ func (p *Pool) InsertAttesterSlashing(
 			return p.pendingAttesterSlashing[i].validatorToSlash < p.pendingAttesterSlashing[j].validatorToSlash
 		})
 	}
+
+	if p.store != nil {
+		if err := p.store.SavePoolAttesterSlashing(ctx, slashing); err != nil {
+			return errors.Wrap(err, "could not persist attester slashing")
+		}
+	}
 	return nil
 }
 
@@ -178,6 +184,12 @@ func (p *Pool) InsertProposerSlashing(
 	sort.Slice(p.pendingProposerSlashing, func(i, j int) bool {
 		return p.pendingProposerSlashing[i].Header_1.Header.ProposerIndex < p.pendingProposerSlashing[j].Header_1.Header.ProposerIndex
 	})
+
+	if p.store != nil {
+		if err := p.store.SavePoolProposerSlashing(ctx, slashing); err != nil {
+			return errors.Wrap(err, "could not persist proposer slashing")
+		}
+	}
 	return nil
 }
 
@@ -193,11 +205,13 @@ func (p *Pool) MarkIncludedAttesterSlashing(as *ethpb.AttesterSlashing) {
 			return p.pendingAttesterSlashing[i].validatorToSlash >= val
 		})
 		if i != len(p.pendingAttesterSlashing) && p.pendingAttesterSlashing[i].validatorToSlash == val {
+			p.deletePersistedAttesterSlashing(p.pendingAttesterSlashing[i].attesterSlashing)
 			p.pendingAttesterSlashing = append(p.pendingAttesterSlashing[:i], p.pendingAttesterSlashing[i+1:]...)
 		}
 		p.included[val] = true
 		numAttesterSlashingsIncluded.Inc()
 	}
+	p.deletePersistedAttesterSlashing(as)
 }
 
 // MarkIncludedProposerSlashing is used when an proposer slashing has been included in a beacon block.
@@ -210,10 +224,12 @@ func (p *Pool) MarkIncludedProposerSlashing(ps *ethpb.ProposerSlashing) {
 		return p.pendingProposerSlashing[i].Header_1.Header.ProposerIndex >= ps.Header_1.Header.ProposerIndex
 	})
 	if i != len(p.pendingProposerSlashing) && p.pendingProposerSlashing[i].Header_1.Header.ProposerIndex == ps.Header_1.Header.ProposerIndex {
+		p.deletePersistedProposerSlashing(p.pendingProposerSlashing[i])
 		p.pendingProposerSlashing = append(p.pendingProposerSlashing[:i], p.pendingProposerSlashing[i+1:]...)
 	}
 	p.included[ps.Header_1.Header.ProposerIndex] = true
 	numProposerSlashingsIncluded.Inc()
+	p.deletePersistedProposerSlashing(ps)
 }
 
 // this function checks a few items about a validator before proceeding with inserting
@@ -1,6 +1,7 @@
 package slashings
 
 import (
+	"context"
 	"sync"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -13,6 +14,17 @@ type Pool struct {
 	pendingProposerSlashing []*ethpb.ProposerSlashing
 	pendingAttesterSlashing []*PendingAttesterSlashing
 	included                map[uint64]bool
+	store                   Store
+}
+
+// Store persists the pending slashings of the pool, so they survive a restart of the node.
+type Store interface {
+	PoolProposerSlashings(ctx context.Context) ([]*ethpb.ProposerSlashing, error)
+	SavePoolProposerSlashing(ctx context.Context, slashing *ethpb.ProposerSlashing) error
+	DeletePoolProposerSlashing(ctx context.Context, slashing *ethpb.ProposerSlashing) error
+	PoolAttesterSlashings(ctx context.Context) ([]*ethpb.AttesterSlashing, error)
+	SavePoolAttesterSlashing(ctx context.Context, slashing *ethpb.AttesterSlashing) error
+	DeletePoolAttesterSlashing(ctx context.Context, slashing *ethpb.AttesterSlashing) error
 }
 
 // PendingAttesterSlashing represents an attester slashing in the operation pool.
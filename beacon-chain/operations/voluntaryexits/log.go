@@ -0,0 +1,7 @@
+package voluntaryexits
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "pool/exits")
@@ -0,0 +1,82 @@
+package voluntaryexits
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+)
+
+// Persist reloads the exits saved in the store into the pool, then saves the exits inserted in
+// the pool from now on. It must be called before the pool is used. Nothing is reloaded if the
+// head state is nil, as before the chain starts.
+func (p *Pool) Persist(ctx context.Context, store Store, headState *beaconstate.BeaconState) error {
+	if headState != nil {
+		if err := p.reload(ctx, store, headState); err != nil {
+			return err
+		}
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.store = store
+	return nil
+}
+
+// This inserts the exits saved in the store into the pool. The exits are verified against the
+// head state, as they may have become invalid while the node was down, and the invalid ones are
+// deleted from the store.
+func (p *Pool) reload(ctx context.Context, store Store, headState *beaconstate.BeaconState) error {
+	exits, err := store.PoolVoluntaryExits(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve persisted exits")
+	}
+	reloaded := 0
+	for _, exit := range exits {
+		if err := verifyPersistedExit(headState, exit); err != nil {
+			log.WithError(err).Debug("Dropping persisted exit")
+			if err := store.DeletePoolVoluntaryExit(ctx, exit); err != nil {
+				return errors.Wrap(err, "could not delete persisted exit")
+			}
+			continue
+		}
+		p.InsertVoluntaryExit(ctx, headState, exit)
+		reloaded++
+	}
+	log.WithField("exits", reloaded).Info("Reloaded persisted exits")
+	return nil
+}
+
+// This verifies a persisted exit against the head state, as an exit received from the network.
+// The validator may have exited or been slashed while the node was down.
+func verifyPersistedExit(state *beaconstate.BeaconState, exit *ethpb.SignedVoluntaryExit) error {
+	if exit == nil || exit.Exit == nil {
+		return errors.New("nil exit")
+	}
+	v, err := state.ValidatorAtIndex(exit.Exit.ValidatorIndex)
+	if err != nil {
+		return err
+	}
+	return blocks.VerifyExit(v, state.Slot(), state.Fork(), exit, state.GenesisValidatorRoot())
+}
+
+// This saves an exit in the store, if the pool is persisted. The pool lock must be held by the caller.
+func (p *Pool) savePersistedExit(ctx context.Context, exit *ethpb.SignedVoluntaryExit) {
+	if p.store == nil {
+		return
+	}
+	if err := p.store.SavePoolVoluntaryExit(ctx, exit); err != nil {
+		log.WithError(err).Error("Could not persist exit")
+	}
+}
+
+// This deletes an exit from the store, if the pool is persisted. The pool lock must be held by the caller.
+func (p *Pool) deletePersistedExit(ctx context.Context, exit *ethpb.SignedVoluntaryExit) {
+	if p.store == nil {
+		return
+	}
+	if err := p.store.DeletePoolVoluntaryExit(ctx, exit); err != nil {
+		log.WithError(err).Error("Could not delete persisted exit")
+	}
+}
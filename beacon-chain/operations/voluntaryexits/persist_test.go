@@ -0,0 +1,89 @@
+package voluntaryexits
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	beaconstate "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+type mockStore struct {
+	exits []*ethpb.SignedVoluntaryExit
+}
+
+func (m *mockStore) PoolVoluntaryExits(_ context.Context) ([]*ethpb.SignedVoluntaryExit, error) {
+	return m.exits, nil
+}
+
+func (m *mockStore) SavePoolVoluntaryExit(_ context.Context, exit *ethpb.SignedVoluntaryExit) error {
+	m.exits = append(m.exits, exit)
+	return nil
+}
+
+func (m *mockStore) DeletePoolVoluntaryExit(_ context.Context, exit *ethpb.SignedVoluntaryExit) error {
+	for i, e := range m.exits {
+		if proto.Equal(e, exit) {
+			m.exits = append(m.exits[:i], m.exits[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func signedExit(t *testing.T, state *beaconstate.BeaconState, priv *bls.SecretKey, idx uint64) *ethpb.SignedVoluntaryExit {
+	exit := &ethpb.VoluntaryExit{ValidatorIndex: idx}
+	domain, err := helpers.Domain(state.Fork(), exit.Epoch, params.BeaconConfig().DomainVoluntaryExit, state.GenesisValidatorRoot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := helpers.ComputeSigningRoot(exit, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ethpb.SignedVoluntaryExit{Exit: exit, Signature: priv.Sign(root[:]).Marshal()}
+}
+
+func TestPool_Persist(t *testing.T) {
+	ctx := context.Background()
+	state, privKeys := testutil.DeterministicGenesisState(t, 64)
+	// The validators have been active long enough to exit.
+	if err := state.SetSlot(params.BeaconConfig().PersistentCommitteePeriod * params.BeaconConfig().SlotsPerEpoch); err != nil {
+		t.Fatal(err)
+	}
+	valid := signedExit(t, state, privKeys[0], 0)
+	badSignature := signedExit(t, state, privKeys[0], 1)
+	exited := signedExit(t, state, privKeys[2], 2)
+	// The validator exited while the node was down.
+	exitedVal, err := state.ValidatorAtIndex(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exitedVal.ExitEpoch = 1
+	if err := state.UpdateValidatorAtIndex(2, exitedVal); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &mockStore{exits: []*ethpb.SignedVoluntaryExit{valid, badSignature, exited}}
+	p := NewPool()
+	if err := p.Persist(ctx, store, state); err != nil {
+		t.Fatal(err)
+	}
+	pending := p.PendingExits(state, 0)
+	if len(pending) != 1 || !proto.Equal(pending[0], valid) {
+		t.Errorf("Expected only the valid exit to be reloaded, received %v", pending)
+	}
+	if len(store.exits) != 1 || !proto.Equal(store.exits[0], valid) {
+		t.Errorf("Expected the invalid exits to be deleted from the store, received %v", store.exits)
+	}
+
+	p.MarkIncluded(valid)
+	if len(store.exits) != 0 {
+		t.Errorf("Expected included exit to be deleted from the store, received %v", store.exits)
+	}
+}
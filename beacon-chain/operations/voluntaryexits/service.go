@@ -17,6 +17,14 @@ type Pool struct {
 	lock     sync.RWMutex
 	pending  []*ethpb.SignedVoluntaryExit
 	included map[uint64]bool
+	store    Store
+}
+
+// Store persists the pending exits of the pool, so they survive a restart of the node.
+type Store interface {
+	PoolVoluntaryExits(ctx context.Context) ([]*ethpb.SignedVoluntaryExit, error)
+	SavePoolVoluntaryExit(ctx context.Context, exit *ethpb.SignedVoluntaryExit) error
+	DeletePoolVoluntaryExit(ctx context.Context, exit *ethpb.SignedVoluntaryExit) error
 }
 
 // NewPool accepts a head fetcher (for reading the validator set) and returns an initialized
@@ -71,7 +79,9 @@ func (p *Pool) InsertVoluntaryExit(ctx context.Context, state *beaconstate.Beaco
 	}); found != len(p.pending) {
 		// If an exit exists with this validator index, prefer one with an earlier exit epoch.
 		if p.pending[found].Exit.Epoch > exit.Exit.Epoch {
+			p.deletePersistedExit(ctx, p.pending[found])
 			p.pending[found] = exit
+			p.savePersistedExit(ctx, exit)
 		}
 		return
 	}
@@ -81,6 +91,7 @@ func (p *Pool) InsertVoluntaryExit(ctx context.Context, state *beaconstate.Beaco
 	sort.Slice(p.pending, func(i, j int) bool {
 		return p.pending[i].Exit.ValidatorIndex < p.pending[j].Exit.ValidatorIndex
 	})
+	p.savePersistedExit(ctx, exit)
 }
 
 // MarkIncluded is used when an exit has been included in a beacon block. Every block seen by this
@@ -92,7 +103,9 @@ func (p *Pool) MarkIncluded(exit *ethpb.SignedVoluntaryExit) {
 		return p.pending[i].Exit.ValidatorIndex == exit.Exit.ValidatorIndex
 	})
 	if i != len(p.pending) {
+		p.deletePersistedExit(context.Background(), p.pending[i])
 		p.pending = append(p.pending[:i], p.pending[i+1:]...)
 	}
 	p.included[exit.Exit.ValidatorIndex] = true
+	p.deletePersistedExit(context.Background(), exit)
 }
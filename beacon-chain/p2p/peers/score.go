@@ -0,0 +1,190 @@
+package peers
+
+import (
+	"sort"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// ScoreComponent is a kind of event contributing to the score of a peer.
+type ScoreComponent int
+
+const (
+	// BadResponse is a malformed or unexpected RPC request or response from the peer.
+	BadResponse ScoreComponent = iota
+	// InvalidBlock is a block from the peer failing validation.
+	InvalidBlock
+	// InvalidAttestation is an attestation from the peer failing validation.
+	InvalidAttestation
+	// RPCTimeout is an RPC request to the peer which timed out.
+	RPCTimeout
+	// EmptyBlocksResponse is a blocks by range request the peer answered without any block.
+	EmptyBlocksResponse
+	// FaultGoodbye is a goodbye message from the peer for a fault or error.
+	FaultGoodbye
+	// IrrelevantNetworkGoodbye is a goodbye message from a peer on another network.
+	IrrelevantNetworkGoodbye
+	// GossipDelivery is a gossip message from the peer which passed validation.
+	GossipDelivery
+	// RateLimited is an RPC request from the peer rejected for exceeding its rate limit.
+	RateLimited
+)
+
+// scoreWeights are the contributions of a single event of each component to the score of a peer,
+// in units of bad responses. A peer is bad once its score reaches minus the maximum number of
+// bad responses.
+var scoreWeights = map[ScoreComponent]float64{
+	BadResponse:              -1,
+	InvalidBlock:             -2,
+	InvalidAttestation:       -0.25,
+	RPCTimeout:               -0.5,
+	EmptyBlocksResponse:      -0.25,
+	FaultGoodbye:             -1,
+	IrrelevantNetworkGoodbye: -16,
+	GossipDelivery:           1.0 / 64,
+	RateLimited:              -0.5,
+}
+
+// maxGossipScore caps the score a peer earns with gossip deliveries, so a peer relaying a lot of
+// valid messages can absorb a few penalties but not hide misbehavior.
+const maxGossipScore = 1.0
+
+var scoreComponentNames = map[ScoreComponent]string{
+	BadResponse:              "bad_responses",
+	InvalidBlock:             "invalid_blocks",
+	InvalidAttestation:       "invalid_attestations",
+	RPCTimeout:               "rpc_timeouts",
+	EmptyBlocksResponse:      "empty_blocks_responses",
+	FaultGoodbye:             "fault_goodbyes",
+	IrrelevantNetworkGoodbye: "irrelevant_network_goodbyes",
+	GossipDelivery:           "gossip_deliveries",
+	RateLimited:              "rate_limited_requests",
+}
+
+// String returns the name of the score component.
+func (c ScoreComponent) String() string {
+	if name, ok := scoreComponentNames[c]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Increment records an event of the given score component for the given remote peer.
+// It is called for every gossip message, so it only takes the write lock of the status
+// for peers which are not known yet.
+func (p *Status) Increment(pid peer.ID, component ScoreComponent) {
+	p.lock.RLock()
+	status, ok := p.status[pid]
+	if ok {
+		status.increment(component)
+	}
+	p.lock.RUnlock()
+	if ok {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.fetch(pid).increment(component)
+}
+
+// Score returns the score of the given remote peer. Peers start with a score of 0, which
+// decreases with misbehavior and increases with useful gossip.
+// If the peer is unknown this will return 0.
+func (p *Status) Score(pid peer.ID) float64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.score()
+	}
+	return 0
+}
+
+// ScoreComponents returns the contribution of each component to the score of the given remote peer.
+// This will error if the peer does not exist.
+func (p *Status) ScoreComponents(pid peer.ID) (map[ScoreComponent]float64, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	status, ok := p.status[pid]
+	if !ok {
+		return nil, ErrPeerUnknown
+	}
+	return status.componentScores(), nil
+}
+
+// SortByScore sorts the given peers by decreasing score, in place, and returns them.
+func (p *Status) SortByScore(pids []peer.ID) []peer.ID {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	scores := make(map[peer.ID]float64, len(pids))
+	for _, pid := range pids {
+		if status, ok := p.status[pid]; ok {
+			scores[pid] = status.score()
+		}
+	}
+	sort.SliceStable(pids, func(i, j int) bool {
+		return scores[pids[i]] > scores[pids[j]]
+	})
+	return pids
+}
+
+func (s *peerStatus) increment(component ScoreComponent) {
+	s.scoreLock.Lock()
+	defer s.scoreLock.Unlock()
+	s.scoreEvents[component]++
+}
+
+func (s *peerStatus) events(component ScoreComponent) int {
+	s.scoreLock.Lock()
+	defer s.scoreLock.Unlock()
+	return s.scoreEvents[component]
+}
+
+// decay removes one event of each penalty component and halves the gossip deliveries.
+func (s *peerStatus) decay() {
+	s.scoreLock.Lock()
+	defer s.scoreLock.Unlock()
+	for component, count := range s.scoreEvents {
+		if component == GossipDelivery {
+			s.scoreEvents[component] = count / 2
+		} else if count > 0 {
+			s.scoreEvents[component] = count - 1
+		}
+	}
+}
+
+func (s *peerStatus) componentScores() map[ScoreComponent]float64 {
+	s.scoreLock.Lock()
+	defer s.scoreLock.Unlock()
+	components := make(map[ScoreComponent]float64, len(scoreWeights))
+	for component := range scoreWeights {
+		components[component] = s.componentScore(component)
+	}
+	return components
+}
+
+func (s *peerStatus) score() float64 {
+	s.scoreLock.Lock()
+	defer s.scoreLock.Unlock()
+	score := 0.0
+	for component := range scoreWeights {
+		score += s.componentScore(component)
+	}
+	return score
+}
+
+// componentScore must be called with the score lock held.
+func (s *peerStatus) componentScore(component ScoreComponent) float64 {
+	score := float64(s.scoreEvents[component]) * scoreWeights[component]
+	if component == GossipDelivery && score > maxGossipScore {
+		return maxGossipScore
+	}
+	return score
+}
+
+func (p *Status) isBad(s *peerStatus) bool {
+	return s.score() <= -float64(p.maxBadResponses)
+}
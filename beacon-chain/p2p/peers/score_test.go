@@ -0,0 +1,118 @@
+package peers_test
+
+import (
+	"sync"
+	"testing"
+
+	peer "github.com/libp2p/go-libp2p-peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func TestScore_WeightedComponents(t *testing.T) {
+	maxBadResponses := 4
+	p := peers.NewStatus(maxBadResponses)
+	pid := addPeer(t, p, peers.PeerConnected)
+
+	if score := p.Score(pid); score != 0 {
+		t.Errorf("Expected a new peer to have a score of 0, received %v", score)
+	}
+	p.Increment(pid, peers.InvalidAttestation)
+	p.Increment(pid, peers.RPCTimeout)
+	if score := p.Score(pid); score != -0.75 {
+		t.Errorf("Unexpected score: expected -0.75, received %v", score)
+	}
+	if p.IsBad(pid) {
+		t.Error("Peer marked as bad when should be good")
+	}
+
+	p.Increment(pid, peers.InvalidBlock)
+	p.Increment(pid, peers.InvalidBlock)
+	if !p.IsBad(pid) {
+		t.Errorf("Peer with score %v not marked as bad when it should be", p.Score(pid))
+	}
+	components, err := p.ScoreComponents(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if components[peers.InvalidBlock] != -4 {
+		t.Errorf("Unexpected invalid blocks score: expected -4, received %v", components[peers.InvalidBlock])
+	}
+	if len(p.Bad()) != 1 {
+		t.Errorf("Expected 1 bad peer, received %d", len(p.Bad()))
+	}
+}
+
+func TestScore_GossipDeliveriesAreCapped(t *testing.T) {
+	p := peers.NewStatus(2)
+	pid := addPeer(t, p, peers.PeerConnected)
+
+	for i := 0; i < 1000; i++ {
+		p.Increment(pid, peers.GossipDelivery)
+	}
+	if score := p.Score(pid); score != 1 {
+		t.Errorf("Expected gossip score to be capped at 1, received %v", score)
+	}
+	// Useful gossip offsets some penalties, but not enough to hide a bad peer.
+	p.IncrementBadResponses(pid)
+	p.IncrementBadResponses(pid)
+	if p.IsBad(pid) {
+		t.Error("Peer marked as bad when should be good")
+	}
+	p.Increment(pid, peers.IrrelevantNetworkGoodbye)
+	if !p.IsBad(pid) {
+		t.Error("Peer not marked as bad when it should be")
+	}
+
+	p.Decay()
+	components, err := p.ScoreComponents(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if components[peers.GossipDelivery] != 1 {
+		t.Errorf("Expected halved gossip deliveries to still be capped, received %v", components[peers.GossipDelivery])
+	}
+	if components[peers.BadResponse] != -1 {
+		t.Errorf("Expected one bad response after decay, received %v", components[peers.BadResponse])
+	}
+}
+
+func TestScore_ConcurrentIncrements(t *testing.T) {
+	p := peers.NewStatus(2)
+	pid1 := addPeer(t, p, peers.PeerConnected)
+	pid2 := addPeer(t, p, peers.PeerConnected)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p.Increment(pid1, peers.InvalidAttestation)
+				p.Increment(pid2, peers.GossipDelivery)
+				p.Score(pid1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if score := p.Score(pid1); score != -250 {
+		t.Errorf("Expected a score of -250 after 1000 invalid attestations, received %v", score)
+	}
+	if score := p.Score(pid2); score != 1 {
+		t.Errorf("Expected gossip score to be capped at 1, received %v", score)
+	}
+}
+
+func TestSortByScore(t *testing.T) {
+	p := peers.NewStatus(10)
+	good := addPeer(t, p, peers.PeerConnected)
+	neutral := addPeer(t, p, peers.PeerConnected)
+	bad := addPeer(t, p, peers.PeerConnected)
+	p.Increment(good, peers.GossipDelivery)
+	p.Increment(bad, peers.EmptyBlocksResponse)
+
+	sorted := p.SortByScore([]peer.ID{bad, neutral, good})
+	if sorted[0] != good || sorted[1] != neutral || sorted[2] != bad {
+		t.Errorf("Peers not sorted by decreasing score: %v", sorted)
+	}
+}
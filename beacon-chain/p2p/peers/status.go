@@ -16,7 +16,8 @@
 // - inactive if we are disconnecting or disconnected
 //
 // Peer information is persistent for the run of the service.  This allows for collection of useful long-term statistics such as
-// number of bad responses obtained from the peer, giving the basis for decisions to not talk to known-bad peers.
+// number of bad responses or invalid blocks obtained from the peer, which are weighted into a score giving the basis for decisions
+// to not talk to known-bad peers.
 package peers
 
 import (
@@ -72,7 +73,10 @@ type peerStatus struct {
 	enr                   *enr.Record
 	metaData              *pb.MetaData
 	chainStateLastUpdated time.Time
-	badResponses          int
+	// scoreLock guards the score events, which are recorded for every gossip message and so
+	// are updated under the read lock of the status rather than its write lock.
+	scoreLock   sync.Mutex
+	scoreEvents map[ScoreComponent]int
 }
 
 // NewStatus creates a new status entity.
@@ -84,6 +88,7 @@ func NewStatus(maxBadResponses int) *Status {
 }
 
 // MaxBadResponses returns the maximum number of bad responses a peer can provide before it is considered bad.
+// A peer is considered bad once its score, in units of bad responses, drops to minus this value.
 func (p *Status) MaxBadResponses() int {
 	return p.maxBadResponses
 }
@@ -107,7 +112,8 @@ func (p *Status) Add(record *enr.Record, pid peer.ID, address ma.Multiaddr, dire
 		address:   address,
 		direction: direction,
 		// Peers start disconnected; state will be updated when the handshake process begins.
-		peerState: PeerDisconnected,
+		peerState:   PeerDisconnected,
+		scoreEvents: make(map[ScoreComponent]int),
 	}
 	if record != nil {
 		status.enr = record
@@ -279,7 +285,7 @@ func (p *Status) IncrementBadResponses(pid peer.ID) {
 	defer p.lock.Unlock()
 
 	status := p.fetch(pid)
-	status.badResponses++
+	status.increment(BadResponse)
 }
 
 // BadResponses obtains the number of bad responses we have received from the given remote peer.
@@ -289,19 +295,19 @@ func (p *Status) BadResponses(pid peer.ID) (int, error) {
 	defer p.lock.RUnlock()
 
 	if status, ok := p.status[pid]; ok {
-		return status.badResponses, nil
+		return status.events(BadResponse), nil
 	}
 	return -1, ErrPeerUnknown
 }
 
-// IsBad states if the peer is to be considered bad.
+// IsBad states if the peer is to be considered bad, based on its score.
 // If the peer is unknown this will return `false`, which makes using this function easier than returning an error.
 func (p *Status) IsBad(pid peer.ID) bool {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 
 	if status, ok := p.status[pid]; ok {
-		return status.badResponses >= p.maxBadResponses
+		return p.isBad(status)
 	}
 	return false
 }
@@ -390,7 +396,7 @@ func (p *Status) Bad() []peer.ID {
 	defer p.lock.RUnlock()
 	peers := make([]peer.ID, 0)
 	for pid, status := range p.status {
-		if status.badResponses >= p.maxBadResponses {
+		if p.isBad(status) {
 			peers = append(peers, pid)
 		}
 	}
@@ -408,16 +414,15 @@ func (p *Status) All() []peer.ID {
 	return pids
 }
 
-// Decay reduces the bad responses of all peers, giving reformed peers a chance to join the network.
+// Decay reduces the penalties of all peers by one event of each component, giving reformed peers a chance to join the network.
+// Gossip deliveries are halved, so peers have to keep relaying useful messages to keep their score.
 // This can be run periodically, although note that each time it runs it does give all bad peers another chance as well to clog up
 // the network with bad responses, so should not be run too frequently; once an hour would be reasonable.
 func (p *Status) Decay() {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	for _, status := range p.status {
-		if status.badResponses > 0 {
-			status.badResponses--
-		}
+		status.decay()
 	}
 }
 
@@ -478,7 +483,7 @@ func (p *Status) BestFinalized(maxPeers int, ourFinalizedEpoch uint64) ([]byte,
 // fetch is a helper function that fetches a peer status, possibly creating it.
 func (p *Status) fetch(pid peer.ID) *peerStatus {
 	if _, ok := p.status[pid]; !ok {
-		p.status[pid] = &peerStatus{}
+		p.status[pid] = &peerStatus{scoreEvents: make(map[ScoreComponent]int)}
 	}
 	return p.status[pid]
 }
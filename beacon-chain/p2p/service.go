@@ -57,6 +57,16 @@ const lookupLimit = 15
 
 const prysmProtocolPrefix = "/prysm/0.0.0"
 
+// peerScoreTag is the connection manager tag holding the score of a peer, so that the
+// lowest scoring peers are pruned first once we are above the high watermark.
+const peerScoreTag = "peer-score"
+
+// peerScoreTagScale converts peer scores into the integer values of connection manager tags.
+const peerScoreTagScale = 100
+
+// Tag connected peers with their scores every 30 seconds.
+var scoreTagPeriod = 30 * time.Second
+
 // maxBadResponses is the maximum number of bad responses from a peer before we stop talking to it.
 const maxBadResponses = 3
 
@@ -276,6 +286,7 @@ func (s *Service) Start() {
 	})
 	runutil.RunEvery(s.ctx, time.Hour, s.Peers().Decay)
 	runutil.RunEvery(s.ctx, 10*time.Second, s.updateMetrics)
+	runutil.RunEvery(s.ctx, scoreTagPeriod, s.tagPeerScores)
 	runutil.RunEvery(s.ctx, refreshRate, func() {
 		currentEpoch := helpers.SlotToEpoch(helpers.SlotsSince(s.genesisTime))
 		s.RefreshENR(currentEpoch)
@@ -376,6 +387,19 @@ func (s *Service) MetadataSeq() uint64 {
 	return s.metaData.SeqNumber
 }
 
+// ENR returns the local node record, or nil if discovery is not running.
+func (s *Service) ENR() *enr.Record {
+	if s.dv5Listener == nil {
+		return nil
+	}
+	return s.dv5Listener.Self().Record()
+}
+
+// HostAddrs returns the addresses the libp2p host listens on.
+func (s *Service) HostAddrs() []ma.Multiaddr {
+	return s.host.Addrs()
+}
+
 // RefreshENR uses an epoch to refresh the enr entry for our node
 // with the tracked committee id's for the epoch, allowing our node
 // to be dynamically discoverable by others given our tracked committee id's.
@@ -485,6 +509,14 @@ func (s *Service) pingPeers() {
 	}
 }
 
+// tagPeerScores records the score of every connected peer in the connection manager, which
+// prunes the peers with the lowest total tag value first.
+func (s *Service) tagPeerScores() {
+	for _, pid := range s.peers.Connected() {
+		s.host.ConnManager().TagPeer(pid, peerScoreTag, int(s.peers.Score(pid)*peerScoreTagScale))
+	}
+}
+
 // Waits for the beacon state to be initialized, important
 // for initializing the p2p service as p2p needs to be aware
 // of genesis information for peering.
@@ -18,6 +18,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/protocol"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	swarmt "github.com/libp2p/go-libp2p-swarm/testing"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
 	peers "github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -257,6 +258,16 @@ func (p *TestP2P) RefreshENR(epoch uint64) {
 	return
 }
 
+// ENR mocks the p2p func.
+func (p *TestP2P) ENR() *enr.Record {
+	return new(enr.Record)
+}
+
+// HostAddrs returns the addresses of the test host.
+func (p *TestP2P) HostAddrs() []ma.Multiaddr {
+	return p.Host.Addrs()
+}
+
 // ForkDigest mocks the p2p func.
 func (p *TestP2P) ForkDigest() ([4]byte, error) {
 	return p.Digest, nil
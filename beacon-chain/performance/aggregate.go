@@ -0,0 +1,36 @@
+package performance
+
+import (
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+// Aggregate sums up the per-epoch performance of a validator over the given epochs.
+func Aggregate(records []*rpcpb.ValidatorEpochPerformance) *rpcpb.ValidatorPerformanceAggregate {
+	agg := &rpcpb.ValidatorPerformanceAggregate{Epochs: uint64(len(records))}
+	totalInclusionDistance := uint64(0)
+	for _, r := range records {
+		if r.AttestationIncluded {
+			agg.IncludedAttestations++
+			totalInclusionDistance += r.InclusionDistance
+		} else {
+			agg.MissedAttestations++
+		}
+		if r.CorrectSource {
+			agg.CorrectSources++
+		}
+		if r.CorrectTarget {
+			agg.CorrectTargets++
+		}
+		if r.CorrectHead {
+			agg.CorrectHeads++
+		}
+		agg.ProposedBlocks += r.ProposedBlocks
+		agg.MissedProposals += r.MissedProposals
+		agg.ProposerRewards += r.ProposerReward
+		agg.BalanceDelta += r.BalanceDelta
+	}
+	if agg.IncludedAttestations > 0 {
+		agg.AverageInclusionDistance = float64(totalInclusionDistance) / float64(agg.IncludedAttestations)
+	}
+	return agg
+}
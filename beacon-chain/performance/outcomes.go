@@ -0,0 +1,140 @@
+package performance
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/epoch/precompute"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+// epochProposals holds the number of blocks each validator proposed and missed during an epoch.
+type epochProposals struct {
+	epoch    uint64
+	proposed map[uint64]uint64
+	missed   map[uint64]uint64
+}
+
+// This sets the proposals of the validators in their records of the same epoch.
+func (p *epochProposals) merge(records []*rpcpb.ValidatorEpochPerformance) {
+	for _, r := range records {
+		r.ProposedBlocks = p.proposed[r.ValidatorIndex]
+		r.MissedProposals = p.missed[r.ValidatorIndex]
+	}
+}
+
+// This computes the outcomes of the duties of the validators from a state at the last slot of an
+// epoch. The attestations of the previous epoch are final at this point, as their inclusion window
+// closes with the epoch, and so are the block proposals of the epoch itself.
+func epochOutcomes(
+	ctx context.Context,
+	st *stateTrie.BeaconState,
+) ([]*rpcpb.ValidatorEpochPerformance, *epochProposals, error) {
+	epoch := helpers.CurrentEpoch(st)
+	proposals, err := proposalOutcomes(st, epoch)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not compute proposals")
+	}
+	if epoch == 0 {
+		return nil, proposals, nil
+	}
+
+	vp, bp, err := precompute.New(ctx, st)
+	if err != nil {
+		return nil, nil, err
+	}
+	vp, bp, err = precompute.ProcessAttestations(ctx, st, vp, bp)
+	if err != nil {
+		return nil, nil, err
+	}
+	proposerRewards, err := precompute.ProposersDelta(st, bp, vp)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not compute proposer rewards")
+	}
+	// The rewards and penalties are applied to a copy of the state as the epoch transition would,
+	// which records the balance of every validator before and after.
+	post, err := precompute.ProcessJustificationAndFinalizationPreCompute(st.Copy(), bp)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not process justification")
+	}
+	if _, err := precompute.ProcessRewardsAndPenaltiesPrecompute(post, bp, vp); err != nil {
+		return nil, nil, errors.Wrap(err, "could not process rewards and penalties")
+	}
+
+	records := make([]*rpcpb.ValidatorEpochPerformance, 0, len(vp))
+	for i, v := range vp {
+		if !v.IsActivePrevEpoch {
+			continue
+		}
+		r := &rpcpb.ValidatorEpochPerformance{
+			Epoch:          epoch - 1,
+			ValidatorIndex: uint64(i),
+			ProposerReward: proposerRewards[i],
+			BalanceDelta:   int64(v.AfterEpochTransitionBalance) - int64(v.BeforeEpochTransitionBalance),
+		}
+		// Attestations of the previous epoch are only included with the correct source.
+		if v.IsPrevEpochAttester {
+			r.AttestationIncluded = true
+			r.InclusionSlot = v.InclusionSlot
+			r.InclusionDistance = v.InclusionDistance
+			r.CorrectSource = true
+			r.CorrectTarget = v.IsPrevEpochTargetAttester
+			r.CorrectHead = v.IsPrevEpochHeadAttester
+		}
+		records = append(records, r)
+	}
+	return records, proposals, nil
+}
+
+// This computes the blocks proposed and missed by the assigned proposers of each slot of the
+// current epoch of the state, which must be at the last slot of the epoch.
+func proposalOutcomes(st *stateTrie.BeaconState, epoch uint64) (*epochProposals, error) {
+	// Computing the assignments sets the slot of the state.
+	_, proposerIndexToSlots, err := helpers.CommitteeAssignments(st.Copy(), epoch)
+	if err != nil {
+		return nil, err
+	}
+	proposals := &epochProposals{
+		epoch:    epoch,
+		proposed: make(map[uint64]uint64),
+		missed:   make(map[uint64]uint64),
+	}
+	for idx, slots := range proposerIndexToSlots {
+		for _, slot := range slots {
+			// The genesis block is not proposed.
+			if slot == 0 {
+				continue
+			}
+			proposed, err := hasBlockAtSlot(st, slot)
+			if err != nil {
+				return nil, err
+			}
+			if proposed {
+				proposals.proposed[idx]++
+			} else {
+				proposals.missed[idx]++
+			}
+		}
+	}
+	return proposals, nil
+}
+
+// This returns true if the chain of the state has a block at the given slot. A slot without a
+// block has the same block root as the slot before it.
+func hasBlockAtSlot(st *stateTrie.BeaconState, slot uint64) (bool, error) {
+	if slot == st.Slot() {
+		return st.LatestBlockHeader().Slot == slot, nil
+	}
+	root, err := helpers.BlockRootAtSlot(st, slot)
+	if err != nil {
+		return false, err
+	}
+	prevRoot, err := helpers.BlockRootAtSlot(st, slot-1)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(root, prevRoot), nil
+}
@@ -0,0 +1,175 @@
+// Package performance defines a service which tracks the outcomes of the duties of every validator
+// at each epoch transition of the canonical chain, such as the inclusion of their attestations,
+// the correctness of their votes, their block proposals and their rewards, and saves them to the
+// database for historical queries.
+package performance
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "performance")
+
+// epochQueueSize is the number of finished epochs which can wait to be tracked. Epochs finishing
+// while the queue is full are not tracked, so a slow database never delays the head updates.
+const epochQueueSize = 4
+
+// Service tracking the per-epoch performance of validators.
+type Service struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	beaconDB      db.NoHeadAccessDatabase
+	headFetcher   blockchain.HeadFetcher
+	stateNotifier statefeed.Notifier
+	lastHead      *stateTrie.BeaconState
+	epochStates   chan *stateTrie.BeaconState
+	nextEpoch     uint64
+	proposals     *epochProposals
+}
+
+// Config options for the performance service.
+type Config struct {
+	BeaconDB      db.NoHeadAccessDatabase
+	HeadFetcher   blockchain.HeadFetcher
+	StateNotifier statefeed.Notifier
+}
+
+// NewService initializes the service from configuration options.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:           ctx,
+		cancel:        cancel,
+		beaconDB:      cfg.BeaconDB,
+		headFetcher:   cfg.HeadFetcher,
+		stateNotifier: cfg.StateNotifier,
+		epochStates:   make(chan *stateTrie.BeaconState, epochQueueSize),
+	}
+}
+
+// Start the performance service event loop.
+func (s *Service) Start() {
+	go s.run(s.ctx)
+	go s.trackEpochs(s.ctx)
+}
+
+// Stop the performance service event loop.
+func (s *Service) Stop() error {
+	defer s.cancel()
+	return nil
+}
+
+// Status reports the healthy status of the performance service. Returning nil means service
+// is correctly running without error.
+func (s *Service) Status() error {
+	return nil
+}
+
+func (s *Service) run(ctx context.Context) {
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.stateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	for {
+		select {
+		case event := <-stateChannel:
+			if event.Type != statefeed.NewHead {
+				continue
+			}
+			headState, err := s.headFetcher.HeadState(ctx)
+			if err != nil {
+				log.WithError(err).Error("Head state is not available")
+				continue
+			}
+			s.onNewHead(headState)
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting goroutine")
+			return
+		case err := <-stateSub.Err():
+			log.WithError(err).Error("Subscription to state feed notifier failed")
+			return
+		}
+	}
+}
+
+// This queues the previous head for tracking once the chain has moved past its epoch. The
+// receive loop of the state feed only compares epochs, the outcomes are computed and saved by
+// trackEpochs.
+func (s *Service) onNewHead(headState *stateTrie.BeaconState) {
+	lastHead := s.lastHead
+	s.lastHead = headState
+	if lastHead == nil {
+		return
+	}
+	epoch := helpers.CurrentEpoch(lastHead)
+	if helpers.CurrentEpoch(headState) <= epoch {
+		return
+	}
+	select {
+	case s.epochStates <- lastHead:
+	default:
+		log.WithField("epoch", epoch).Warn("Too many epochs waiting to be tracked, skipping epoch")
+	}
+}
+
+func (s *Service) trackEpochs(ctx context.Context) {
+	for {
+		select {
+		case st := <-s.epochStates:
+			if err := s.trackEpoch(ctx, st); err != nil {
+				log.WithError(err).Error("Could not track validator performance")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// This tracks the epoch of a head the chain has moved past. The head state is advanced to the last
+// slot of its epoch, which is the state the epoch transition runs on.
+func (s *Service) trackEpoch(ctx context.Context, lastHead *stateTrie.BeaconState) error {
+	epoch := helpers.CurrentEpoch(lastHead)
+	if epoch < s.nextEpoch {
+		return nil
+	}
+
+	endSlot := helpers.StartSlot(epoch+1) - 1
+	if lastHead.Slot() < endSlot {
+		var err error
+		lastHead, err = state.ProcessSlots(ctx, lastHead, endSlot)
+		if err != nil {
+			return errors.Wrapf(err, "could not process slots up to %d", endSlot)
+		}
+	}
+	records, proposals, err := epochOutcomes(ctx, lastHead)
+	if err != nil {
+		return errors.Wrapf(err, "could not compute outcomes of epoch %d", epoch)
+	}
+	// The proposals of the previous epoch were computed at its own end, while its attestations
+	// could still be included.
+	if s.proposals != nil && epoch > 0 && s.proposals.epoch == epoch-1 {
+		s.proposals.merge(records)
+	}
+	s.proposals = proposals
+	s.nextEpoch = epoch + 1
+	if len(records) == 0 {
+		return nil
+	}
+	if err := s.beaconDB.SaveValidatorPerformance(ctx, records); err != nil {
+		return errors.Wrap(err, "could not save validator performance")
+	}
+	log.WithFields(logrus.Fields{
+		"epoch":      records[0].Epoch,
+		"validators": len(records),
+	}).Debug("Saved validator performance")
+	return nil
+}
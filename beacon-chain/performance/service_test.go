@@ -0,0 +1,199 @@
+package performance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// This returns a state at the given slot of epoch 1, where the committee of the first slot
+// attested in epoch 0 and a single block was proposed in epoch 1, at blockSlot.
+func setupState(t *testing.T, slot uint64, blockSlot uint64) (*stateTrie.BeaconState, []uint64) {
+	st, _ := testutil.DeterministicGenesisState(t, 64)
+	if err := st.SetSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+	for i := blockSlot; i < slot; i++ {
+		if err := st.UpdateBlockRootAtIndex(i, [32]byte{'a'}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := st.SetLatestBlockHeader(&ethpb.BeaconBlockHeader{Slot: blockSlot}); err != nil {
+		t.Fatal(err)
+	}
+	committee, err := helpers.BeaconCommitteeFromState(st, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bits := bitfield.NewBitlist(uint64(len(committee)))
+	for i := range committee {
+		bits.SetBitAt(uint64(i), true)
+	}
+	emptyRoot := make([]byte, 32)
+	if err := st.SetPreviousEpochAttestations([]*pb.PendingAttestation{{
+		Data: &ethpb.AttestationData{
+			BeaconBlockRoot: emptyRoot,
+			Source:          &ethpb.Checkpoint{Root: emptyRoot},
+			Target:          &ethpb.Checkpoint{Root: emptyRoot},
+		},
+		AggregationBits: bits,
+		InclusionDelay:  1,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	return st, committee
+}
+
+func TestEpochOutcomes(t *testing.T) {
+	spe := params.BeaconConfig().SlotsPerEpoch
+	blockSlot := spe + 2
+	st, committee := setupState(t, 2*spe-1, blockSlot)
+
+	records, proposals, err := epochOutcomes(context.Background(), st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 64 {
+		t.Fatalf("Wanted a record for each of the 64 validators, received %d", len(records))
+	}
+	attested := make(map[uint64]bool)
+	for _, idx := range committee {
+		attested[idx] = true
+	}
+	for _, r := range records {
+		if r.Epoch != 0 {
+			t.Errorf("Wanted records of epoch 0, received epoch %d", r.Epoch)
+		}
+		if !attested[r.ValidatorIndex] {
+			if r.AttestationIncluded || r.BalanceDelta >= 0 {
+				t.Errorf("Expected validator %d to miss its attestation and be penalized: %v", r.ValidatorIndex, r)
+			}
+			continue
+		}
+		if !r.AttestationIncluded || r.InclusionDistance != 1 || !r.CorrectSource || !r.CorrectTarget || !r.CorrectHead {
+			t.Errorf("Expected validator %d to have attested correctly: %v", r.ValidatorIndex, r)
+		}
+	}
+
+	if proposals.epoch != 1 {
+		t.Errorf("Wanted proposals of epoch 1, received epoch %d", proposals.epoch)
+	}
+	proposerState := st.Copy()
+	if err := proposerState.SetSlot(blockSlot); err != nil {
+		t.Fatal(err)
+	}
+	proposer, err := helpers.BeaconProposerIndex(proposerState)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proposals.proposed[proposer] != 1 || len(proposals.proposed) != 1 {
+		t.Errorf("Expected only validator %d to have proposed a block, received %v", proposer, proposals.proposed)
+	}
+	missed := uint64(0)
+	for _, count := range proposals.missed {
+		missed += count
+	}
+	if missed != spe-1 {
+		t.Errorf("Wanted %d missed proposals, received %d", spe-1, missed)
+	}
+}
+
+func TestService_OnNewHead(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx := context.Background()
+	spe := params.BeaconConfig().SlotsPerEpoch
+	s := NewService(ctx, &Config{BeaconDB: db})
+
+	// The previous head is at the end of epoch 1, the new head is in epoch 2.
+	lastHead, committee := setupState(t, 2*spe-1, spe+2)
+	newHead := lastHead.Copy()
+	if err := newHead.SetSlot(2 * spe); err != nil {
+		t.Fatal(err)
+	}
+	s.onNewHead(lastHead)
+	s.onNewHead(newHead)
+	trackQueued(t, s, 1)
+	if s.nextEpoch != 2 || s.proposals == nil || s.proposals.epoch != 1 {
+		t.Errorf("Expected epoch 1 to be tracked, next epoch is %d", s.nextEpoch)
+	}
+
+	records, err := db.ValidatorPerformance(ctx, committee[0], 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Epoch != 0 || !records[0].AttestationIncluded {
+		t.Errorf("Expected the attestation of epoch 0 to be saved, received %v", records)
+	}
+
+	// Going back to the tracked epoch after a reorg does not track it again.
+	s.onNewHead(lastHead)
+	s.onNewHead(newHead)
+	trackQueued(t, s, 1)
+	if s.nextEpoch != 2 {
+		t.Errorf("Wanted next epoch 2, received %d", s.nextEpoch)
+	}
+}
+
+func TestService_OnNewHead_QueueFull(t *testing.T) {
+	spe := params.BeaconConfig().SlotsPerEpoch
+	s := NewService(context.Background(), &Config{})
+	st, _ := setupState(t, spe, spe)
+	for i := uint64(0); i < epochQueueSize+2; i++ {
+		next := st.Copy()
+		if err := next.SetSlot(st.Slot() + spe); err != nil {
+			t.Fatal(err)
+		}
+		s.onNewHead(st)
+		st = next
+	}
+	if len(s.epochStates) != epochQueueSize {
+		t.Errorf("Wanted %d queued epochs, received %d", epochQueueSize, len(s.epochStates))
+	}
+}
+
+// This tracks the epochs queued by onNewHead, expecting the given number of them.
+func trackQueued(t *testing.T, s *Service, want int) {
+	if len(s.epochStates) != want {
+		t.Fatalf("Wanted %d queued epochs, received %d", want, len(s.epochStates))
+	}
+	for len(s.epochStates) > 0 {
+		if err := s.trackEpoch(context.Background(), <-s.epochStates); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	records := []*rpcpb.ValidatorEpochPerformance{
+		{Epoch: 1, AttestationIncluded: true, InclusionDistance: 1, CorrectSource: true, CorrectTarget: true, CorrectHead: true, BalanceDelta: 10},
+		{Epoch: 2, AttestationIncluded: true, InclusionDistance: 3, CorrectSource: true, CorrectTarget: true, ProposedBlocks: 1, ProposerReward: 5, BalanceDelta: 12},
+		{Epoch: 3, MissedProposals: 1, BalanceDelta: -8},
+	}
+	want := &rpcpb.ValidatorPerformanceAggregate{
+		Epochs:                   3,
+		IncludedAttestations:     2,
+		MissedAttestations:       1,
+		CorrectSources:           2,
+		CorrectTargets:           2,
+		CorrectHeads:             1,
+		AverageInclusionDistance: 2,
+		ProposedBlocks:           1,
+		MissedProposals:          1,
+		ProposerRewards:          5,
+		BalanceDelta:             14,
+	}
+	if agg := Aggregate(records); !proto.Equal(agg, want) {
+		t.Errorf("Wanted %v, received %v", want, agg)
+	}
+}
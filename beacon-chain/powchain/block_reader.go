@@ -40,7 +40,9 @@ func (s *Service) BlockExists(ctx context.Context, hash common.Hash) (bool, *big
 		return true, blkInfo.Number, nil
 	}
 	span.AddAttributes(trace.BoolAttribute("blockCacheHit", false))
-	block, err := s.blockFetcher.BlockByHash(ctx, hash)
+	conn, release := s.acquireConnection()
+	defer release()
+	block, err := conn.blockFetcher.BlockByHash(ctx, hash)
 	if err != nil {
 		return false, big.NewInt(0), errors.Wrap(err, "could not query block with given hash")
 	}
@@ -65,7 +67,9 @@ func (s *Service) BlockHashByHeight(ctx context.Context, height *big.Int) (commo
 		return blkInfo.Hash, nil
 	}
 	span.AddAttributes(trace.BoolAttribute("blockCacheHit", false))
-	block, err := s.blockFetcher.BlockByNumber(ctx, height)
+	conn, release := s.acquireConnection()
+	defer release()
+	block, err := conn.blockFetcher.BlockByNumber(ctx, height)
 	if err != nil {
 		return [32]byte{}, errors.Wrap(err, fmt.Sprintf("could not query block with height %d", height.Uint64()))
 	}
@@ -87,7 +91,9 @@ func (s *Service) BlockTimeByHeight(ctx context.Context, height *big.Int) (uint6
 		return blkInfo.Time, nil
 	}
 	span.AddAttributes(trace.BoolAttribute("blockCacheHit", false))
-	block, err := s.blockFetcher.BlockByNumber(ctx, height)
+	conn, release := s.acquireConnection()
+	defer release()
+	block, err := conn.blockFetcher.BlockByNumber(ctx, height)
 	if err != nil {
 		return 0, errors.Wrap(err, fmt.Sprintf("could not query block with height %d", height.Uint64()))
 	}
@@ -104,7 +110,9 @@ func (s *Service) BlockNumberByTimestamp(ctx context.Context, time uint64) (*big
 
 	if time != cachedEth1VotingStartTime || cachedEth1DataBlockHeight == nil {
 		votingBlockHeightCacheMiss.Inc()
-		head, err := s.blockFetcher.BlockByNumber(ctx, nil)
+		conn, release := s.acquireConnection()
+		defer release()
+		head, err := conn.blockFetcher.BlockByNumber(ctx, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -120,7 +128,7 @@ func (s *Service) BlockNumberByTimestamp(ctx context.Context, time uint64) (*big
 			}
 
 			if !exists {
-				blk, err := s.blockFetcher.BlockByNumber(ctx, bn)
+				blk, err := conn.blockFetcher.BlockByNumber(ctx, bn)
 				if err != nil {
 					return nil, err
 				}
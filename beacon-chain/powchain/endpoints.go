@@ -0,0 +1,329 @@
+package powchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	contracts "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+)
+
+// time to wait for the answers of an eth1 node to a health check.
+var healthCheckTimeout = 10 * time.Second
+
+// maximum age of the head block of a healthy eth1 node. The max mining time is 278 sec (block 7208027).
+var maxHeadAge = 5 * time.Minute
+
+// Endpoint defines the web3 provider endpoints of an eth1 node: an IPC or WebSocket endpoint
+// used for subscriptions, and an HTTP endpoint used for requests.
+type Endpoint struct {
+	ETH1 string
+	HTTP string
+}
+
+// EndpointStatus describes the health of an eth1 endpoint as of its last check.
+type EndpointStatus struct {
+	Endpoint
+	Active      bool
+	Healthy     bool
+	Err         error
+	LastChecked time.Time
+}
+
+// healthChecker defines the eth1 node queries used to check the health of an endpoint.
+type healthChecker interface {
+	SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error)
+}
+
+// eth1Connection holds the clients of the endpoints of an eth1 node. The requests in flight on
+// the connection are tracked, so it is only closed once they complete.
+type eth1Connection struct {
+	reader                Reader
+	logger                bind.ContractFilterer
+	client                Client
+	httpLogger            bind.ContractFilterer
+	blockFetcher          RPCBlockFetcher
+	rpcClient             RPCClient
+	health                healthChecker
+	depositContractCaller *contracts.DepositContractCaller
+	close                 func()
+	inFlight              sync.WaitGroup
+}
+
+// EndpointStatuses returns the status of the configured eth1 endpoints, in order of preference.
+func (s *Service) EndpointStatuses() []EndpointStatus {
+	s.endpointsLock.RLock()
+	defer s.endpointsLock.RUnlock()
+	statuses := make([]EndpointStatus, len(s.endpoints))
+	for i, status := range s.endpoints {
+		statuses[i] = *status
+		statuses[i].Active = i == s.activeEndpoint
+	}
+	return statuses
+}
+
+func (s *Service) activeEndpointError() error {
+	s.endpointsLock.RLock()
+	defer s.endpointsLock.RUnlock()
+	if len(s.endpoints) == 0 || s.endpoints[s.activeEndpoint].Err == nil {
+		return nil
+	}
+	active := s.endpoints[s.activeEndpoint]
+	return errors.Wrapf(active.Err, "eth1 endpoint %s is unhealthy", active.ETH1)
+}
+
+func (s *Service) dialETH1Nodes(endpoint Endpoint) (*eth1Connection, error) {
+	httpRPCClient, err := gethRPC.Dial(endpoint.HTTP)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := ethclient.NewClient(httpRPCClient)
+
+	rpcClient, err := gethRPC.Dial(endpoint.ETH1)
+	if err != nil {
+		httpClient.Close()
+		return nil, err
+	}
+	powClient := ethclient.NewClient(rpcClient)
+
+	depositContractCaller, err := contracts.NewDepositContractCaller(s.depositContractAddress, httpClient)
+	if err != nil {
+		httpClient.Close()
+		powClient.Close()
+		return nil, errors.Wrap(err, "could not create deposit contract caller")
+	}
+
+	return &eth1Connection{
+		reader:                powClient,
+		logger:                powClient,
+		client:                httpClient,
+		httpLogger:            httpClient,
+		blockFetcher:          httpClient,
+		rpcClient:             httpRPCClient,
+		health:                httpClient,
+		depositContractCaller: depositContractCaller,
+		close: func() {
+			httpClient.Close()
+			powClient.Close()
+		},
+	}, nil
+}
+
+// connectToPowChain connects to the first healthy eth1 endpoint in order of preference. If none
+// is healthy, it connects to the first reachable one so the node keeps following the eth1 chain.
+func (s *Service) connectToPowChain() error {
+	var fallback *eth1Connection
+	fallbackIndex := 0
+	for i := range s.endpoints {
+		conn, err := s.dialAndCheck(i)
+		if conn == nil {
+			continue
+		}
+		if err == nil {
+			if fallback != nil {
+				fallback.close()
+			}
+			s.useConnection(i, conn)
+			return nil
+		}
+		if fallback != nil {
+			conn.close()
+			continue
+		}
+		fallback, fallbackIndex = conn, i
+	}
+	if fallback == nil {
+		return errors.New("could not dial any eth1 endpoint")
+	}
+	log.WithField("endpoint", s.endpoints[fallbackIndex].ETH1).Warn("No healthy eth1 endpoint, using an unhealthy one")
+	s.useConnection(fallbackIndex, fallback)
+	return nil
+}
+
+// checkEndpoints checks the health of the active eth1 endpoint and of the endpoints preferred
+// over it. It switches to the first healthy endpoint in order of preference, which means going
+// back to a preferred endpoint once it recovers, and returns true if it did.
+func (s *Service) checkEndpoints() bool {
+	for i := range s.endpoints {
+		if i == s.activeEndpoint {
+			err := s.checkHealth(s.healthChecker)
+			s.setEndpointHealth(i, err)
+			if err == nil {
+				return false
+			}
+			continue
+		}
+		conn, err := s.dialAndCheck(i)
+		if conn == nil {
+			continue
+		}
+		if err != nil {
+			conn.close()
+			continue
+		}
+		s.useConnection(i, conn)
+		return true
+	}
+	return false
+}
+
+// checkEndpointsAndResubscribe checks the eth1 endpoints and moves the subscription to new heads
+// over to the new endpoint if the service switched endpoints.
+func (s *Service) checkEndpointsAndResubscribe(sub ethereum.Subscription) (ethereum.Subscription, error) {
+	if !s.checkEndpoints() {
+		return sub, nil
+	}
+	sub.Unsubscribe()
+	return s.subscribeNewHead()
+}
+
+// acquireConnection returns the clients of the active eth1 endpoint. They stay open until
+// release is called, even if the service switches endpoints in the meantime.
+func (s *Service) acquireConnection() (*eth1Connection, func()) {
+	s.connLock.RLock()
+	defer s.connLock.RUnlock()
+	conn := &eth1Connection{
+		reader:                s.reader,
+		logger:                s.logger,
+		client:                s.client,
+		httpLogger:            s.httpLogger,
+		blockFetcher:          s.blockFetcher,
+		rpcClient:             s.rpcClient,
+		health:                s.healthChecker,
+		depositContractCaller: s.depositContractCaller,
+	}
+	if s.conn == nil {
+		return conn, func() {}
+	}
+	s.conn.inFlight.Add(1)
+	return conn, s.conn.inFlight.Done
+}
+
+// subscribeNewHead subscribes to the new heads of the active eth1 endpoint. The subscription
+// ends when the connection is closed after a switch to another endpoint.
+func (s *Service) subscribeNewHead() (ethereum.Subscription, error) {
+	conn, release := s.acquireConnection()
+	defer release()
+	return conn.reader.SubscribeNewHead(s.ctx, s.headerChan)
+}
+
+// dialAndCheck dials the eth1 endpoint at the given position and checks its health. The
+// connection is nil if the endpoint could not be dialed, and the error is the health check result.
+func (s *Service) dialAndCheck(index int) (*eth1Connection, error) {
+	conn, err := s.dialEndpoint(s.endpoints[index].Endpoint)
+	if err != nil {
+		err = errors.Wrap(err, "could not dial eth1 node")
+		s.setEndpointHealth(index, err)
+		return nil, err
+	}
+	err = s.checkHealth(conn.health)
+	s.setEndpointHealth(index, err)
+	return conn, err
+}
+
+// checkHealth returns an error if the eth1 node is syncing, follows another chain than the
+// configured one or has not imported a block for too long.
+func (s *Service) checkHealth(checker healthChecker) error {
+	ctx, cancel := context.WithTimeout(s.ctx, healthCheckTimeout)
+	defer cancel()
+	progress, err := checker.SyncProgress(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get sync status")
+	}
+	if progress != nil {
+		return fmt.Errorf("node is syncing, at block %d of %d", progress.CurrentBlock, progress.HighestBlock)
+	}
+	if s.eth1ChainID != 0 {
+		chainID, err := checker.ChainID(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get chain ID")
+		}
+		if chainID.Uint64() != s.eth1ChainID {
+			return fmt.Errorf("node is on chain %d instead of chain %d", chainID.Uint64(), s.eth1ChainID)
+		}
+	}
+	header, err := checker.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "could not get head block")
+	}
+	if age := roughtime.Since(time.Unix(int64(header.Time), 0)); age > maxHeadAge {
+		return fmt.Errorf("head block %d is %v old", header.Number.Uint64(), age.Round(time.Second))
+	}
+	return nil
+}
+
+func (s *Service) setEndpointHealth(index int, err error) {
+	s.endpointsLock.Lock()
+	status := s.endpoints[index]
+	wasHealthy, checked := status.Healthy, !status.LastChecked.IsZero()
+	status.Healthy = err == nil
+	status.Err = err
+	status.LastChecked = roughtime.Now()
+	s.endpointsLock.Unlock()
+
+	label := strconv.Itoa(index)
+	if err != nil {
+		endpointHealthGauge.WithLabelValues(label).Set(0)
+		if wasHealthy || !checked {
+			log.WithError(err).WithField("endpoint", status.ETH1).Warn("Eth1 endpoint is unhealthy")
+		}
+		return
+	}
+	endpointHealthGauge.WithLabelValues(label).Set(1)
+	if !wasHealthy && checked {
+		log.WithField("endpoint", status.ETH1).Info("Eth1 endpoint is healthy again")
+	}
+}
+
+// useConnection makes the service use the given connection to the eth1 endpoint at the given
+// position. The previous connection is closed once the requests in flight on it complete.
+func (s *Service) useConnection(index int, conn *eth1Connection) {
+	s.connLock.Lock()
+	previousConn := s.conn
+	s.reader = conn.reader
+	s.logger = conn.logger
+	s.client = conn.client
+	s.httpLogger = conn.httpLogger
+	s.blockFetcher = conn.blockFetcher
+	s.rpcClient = conn.rpcClient
+	s.healthChecker = conn.health
+	s.depositContractCaller = conn.depositContractCaller
+	s.conn = conn
+	s.eth1Endpoint = s.endpoints[index].ETH1
+	s.httpEndpoint = s.endpoints[index].HTTP
+	s.connLock.Unlock()
+
+	s.endpointsLock.Lock()
+	previous := s.endpoints[s.activeEndpoint].Endpoint
+	switched := previousConn != nil && index != s.activeEndpoint
+	s.activeEndpoint = index
+	s.endpointsLock.Unlock()
+
+	if previousConn != nil {
+		go func() {
+			previousConn.inFlight.Wait()
+			previousConn.close()
+		}()
+	}
+	activeEndpointGauge.Set(float64(index))
+	if switched {
+		endpointSwitchCount.Inc()
+		log.WithFields(logrus.Fields{
+			"from": previous.ETH1,
+			"to":   s.endpoints[index].ETH1,
+		}).Warn("Switched eth1 endpoint")
+	}
+}
@@ -0,0 +1,249 @@
+package powchain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	gethTypes "github.com/ethereum/go-ethereum/core/types"
+	contracts "github.com/prysmaticlabs/prysm/contracts/deposit-contract"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+)
+
+// simulatedNode answers the health checks of an eth1 node following a simulated backend.
+type simulatedNode struct {
+	backend *backends.SimulatedBackend
+	syncing bool
+	closed  int32
+}
+
+// isClosed reports whether the last connection to the node was closed.
+func (n *simulatedNode) isClosed() bool {
+	return atomic.LoadInt32(&n.closed) == 1
+}
+
+// waitClosed waits for the last connection to the node to be closed.
+func (n *simulatedNode) waitClosed(t *testing.T) {
+	deadline := time.Now().Add(time.Second)
+	for !n.isClosed() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the connection to be closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (n *simulatedNode) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	if n.syncing {
+		return &ethereum.SyncProgress{CurrentBlock: 1, HighestBlock: 100}, nil
+	}
+	return nil, nil
+}
+
+func (n *simulatedNode) ChainID(ctx context.Context) (*big.Int, error) {
+	return n.backend.Blockchain().Config().ChainID, nil
+}
+
+func (n *simulatedNode) HeaderByNumber(ctx context.Context, number *big.Int) (*gethTypes.Header, error) {
+	return n.backend.Blockchain().CurrentHeader(), nil
+}
+
+// dialSimulatedNodes returns a dial function connecting to the simulated nodes by eth1 endpoint.
+func dialSimulatedNodes(nodes map[string]*simulatedNode) func(Endpoint) (*eth1Connection, error) {
+	return func(endpoint Endpoint) (*eth1Connection, error) {
+		node, ok := nodes[endpoint.ETH1]
+		if !ok {
+			return nil, errors.New("connection refused")
+		}
+		atomic.StoreInt32(&node.closed, 0)
+		return &eth1Connection{
+			reader:       &goodReader{backend: node.backend},
+			logger:       &goodLogger{backend: node.backend},
+			httpLogger:   &goodLogger{backend: node.backend},
+			blockFetcher: &goodFetcher{backend: node.backend},
+			health:       node,
+			close: func() {
+				atomic.StoreInt32(&node.closed, 1)
+			},
+		}, nil
+	}
+}
+
+// setupFreshBackend sets up a simulated backend whose head block was just mined.
+func setupFreshBackend(t *testing.T) *backends.SimulatedBackend {
+	testAcc, err := contracts.Setup()
+	if err != nil {
+		t.Fatalf("Unable to set up simulated backend %v", err)
+	}
+	head := testAcc.Backend.Blockchain().CurrentHeader()
+	if err := testAcc.Backend.AdjustTime(roughtime.Since(time.Unix(int64(head.Time), 0))); err != nil {
+		t.Fatal(err)
+	}
+	testAcc.Backend.Commit()
+	return testAcc.Backend
+}
+
+func setupEndpoints(urls ...string) []*EndpointStatus {
+	statuses := make([]*EndpointStatus, len(urls))
+	for i, url := range urls {
+		statuses[i] = &EndpointStatus{Endpoint: Endpoint{ETH1: url, HTTP: "http" + strings.TrimPrefix(url, "ws")}}
+	}
+	return statuses
+}
+
+func TestCheckHealth(t *testing.T) {
+	backend := setupFreshBackend(t)
+	chainID := backend.Blockchain().Config().ChainID.Uint64()
+	staleAcc, err := contracts.Setup()
+	if err != nil {
+		t.Fatalf("Unable to set up simulated backend %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		node    *simulatedNode
+		chainID uint64
+		wantErr string
+	}{
+		{name: "healthy", node: &simulatedNode{backend: backend}, chainID: chainID},
+		{name: "chain ID check disabled", node: &simulatedNode{backend: backend}},
+		{name: "syncing", node: &simulatedNode{backend: backend, syncing: true}, wantErr: "node is syncing"},
+		{name: "other chain", node: &simulatedNode{backend: backend}, chainID: chainID + 1, wantErr: "instead of chain"},
+		{name: "stale head", node: &simulatedNode{backend: staleAcc.Backend}, wantErr: "old"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{ctx: context.Background(), eth1ChainID: tt.chainID}
+			err := s.checkHealth(tt.node)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected a healthy node, received %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Wanted error containing %q, received %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConnectToPowChain_FailsOver(t *testing.T) {
+	backend := setupFreshBackend(t)
+	s := &Service{
+		ctx:       context.Background(),
+		endpoints: setupEndpoints("ws://down", "ws://syncing", "ws://healthy"),
+		dialEndpoint: dialSimulatedNodes(map[string]*simulatedNode{
+			"ws://syncing": {backend: backend, syncing: true},
+			"ws://healthy": {backend: backend},
+		}),
+	}
+	if err := s.connectToPowChain(); err != nil {
+		t.Fatal(err)
+	}
+	if s.eth1Endpoint != "ws://healthy" || s.httpEndpoint != "http://healthy" {
+		t.Errorf("Wanted the healthy endpoint to be used, received %s", s.eth1Endpoint)
+	}
+	statuses := s.EndpointStatuses()
+	for i, status := range statuses[:2] {
+		if status.Healthy || status.Active || status.Err == nil {
+			t.Errorf("Wanted endpoint %d to be reported unhealthy, received %+v", i, status)
+		}
+	}
+	if !statuses[2].Healthy || !statuses[2].Active {
+		t.Errorf("Wanted the healthy endpoint to be reported active, received %+v", statuses[2])
+	}
+	s.isRunning = true
+	if err := s.Status(); err != nil {
+		t.Errorf("Expected a healthy status, received %v", err)
+	}
+}
+
+func TestConnectToPowChain_NoHealthyEndpoint(t *testing.T) {
+	backend := setupFreshBackend(t)
+	s := &Service{
+		ctx:       context.Background(),
+		endpoints: setupEndpoints("ws://down", "ws://syncing"),
+		dialEndpoint: dialSimulatedNodes(map[string]*simulatedNode{
+			"ws://syncing": {backend: backend, syncing: true},
+		}),
+		isRunning: true,
+	}
+	if err := s.connectToPowChain(); err != nil {
+		t.Fatal(err)
+	}
+	if s.eth1Endpoint != "ws://syncing" {
+		t.Errorf("Wanted the reachable endpoint to be used, received %s", s.eth1Endpoint)
+	}
+	if err := s.Status(); err == nil || !strings.Contains(err.Error(), "node is syncing") {
+		t.Errorf("Wanted the unhealthy endpoint to be reported, received %v", err)
+	}
+
+	s.endpoints = setupEndpoints("ws://down")
+	s.conn = nil
+	if err := s.connectToPowChain(); err == nil {
+		t.Error("Expected an error with no reachable endpoint")
+	}
+}
+
+func TestCheckEndpoints_ReturnsToPreferredEndpoint(t *testing.T) {
+	backend := setupFreshBackend(t)
+	preferred := &simulatedNode{backend: backend, syncing: true}
+	fallback := &simulatedNode{backend: backend}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Service{
+		ctx:        ctx,
+		headerChan: make(chan *gethTypes.Header),
+		endpoints:  setupEndpoints("ws://preferred", "ws://fallback"),
+		dialEndpoint: dialSimulatedNodes(map[string]*simulatedNode{
+			"ws://preferred": preferred,
+			"ws://fallback":  fallback,
+		}),
+	}
+	if err := s.connectToPowChain(); err != nil {
+		t.Fatal(err)
+	}
+	if s.activeEndpoint != 1 {
+		t.Fatalf("Wanted the fallback endpoint to be used, received endpoint %d", s.activeEndpoint)
+	}
+	if !preferred.isClosed() {
+		t.Error("Expected the connection to the unhealthy endpoint to be closed")
+	}
+
+	// The fallback endpoint is kept as long as the preferred one is unhealthy.
+	if s.checkEndpoints() {
+		t.Error("Expected the service to keep the fallback endpoint")
+	}
+
+	preferred.syncing = false
+	sub, err := s.reader.SubscribeNewHead(s.ctx, s.headerChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A request in flight keeps the previous connection open until it completes.
+	_, release := s.acquireConnection()
+	if _, err := s.checkEndpointsAndResubscribe(sub); err != nil {
+		t.Fatal(err)
+	}
+	if s.activeEndpoint != 0 || s.eth1Endpoint != "ws://preferred" {
+		t.Errorf("Wanted the preferred endpoint to be used again, received endpoint %d", s.activeEndpoint)
+	}
+	if fallback.isClosed() {
+		t.Error("Expected the connection to the fallback endpoint to stay open with a request in flight")
+	}
+	release()
+	fallback.waitClosed(t)
+
+	// An unhealthy active endpoint fails over to the first healthy one.
+	preferred.syncing = true
+	if !s.checkEndpoints() || s.activeEndpoint != 1 {
+		t.Errorf("Wanted a failover to the fallback endpoint, received endpoint %d", s.activeEndpoint)
+	}
+}
@@ -52,7 +52,9 @@ func (s *Service) ProcessETH1Block(ctx context.Context, blkNum *big.Int) error {
 		FromBlock: blkNum,
 		ToBlock:   blkNum,
 	}
-	logs, err := s.httpLogger.FilterLogs(ctx, query)
+	conn, release := s.acquireConnection()
+	logs, err := conn.httpLogger.FilterLogs(ctx, query)
+	release()
 	if err != nil {
 		return err
 	}
@@ -257,7 +259,9 @@ func (s *Service) processPastLogs(ctx context.Context) error {
 	}
 	// To store all blocks.
 	headersMap := make(map[uint64]*gethTypes.Header)
-	rawLogCount, err := s.depositContractCaller.GetDepositCount(&bind.CallOpts{})
+	conn, release := s.acquireConnection()
+	rawLogCount, err := conn.depositContractCaller.GetDepositCount(&bind.CallOpts{})
+	release()
 	if err != nil {
 		return err
 	}
@@ -300,7 +304,9 @@ func (s *Service) processPastLogs(ctx context.Context) error {
 			query.ToBlock = s.LatestBlockHeight()
 			end = s.LatestBlockHeight().Uint64()
 		}
-		logs, err := s.httpLogger.FilterLogs(ctx, query)
+		conn, release := s.acquireConnection()
+		logs, err := conn.httpLogger.FilterLogs(ctx, query)
+		release()
 		if err != nil {
 			return err
 		}
@@ -16,7 +16,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	gethTypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	gethRPC "github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -51,11 +50,26 @@ var (
 		Name: "powchain_missed_deposit_logs",
 		Help: "The number of times a missed deposit log is detected",
 	})
+	endpointHealthGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "powchain_endpoint_healthy",
+		Help: "Whether the eth1 endpoint at the given position in the configured list passed its last health check",
+	}, []string{"endpoint"})
+	activeEndpointGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "powchain_active_endpoint",
+		Help: "The position in the configured list of the eth1 endpoint in use, 0 being the preferred endpoint",
+	})
+	endpointSwitchCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "powchain_endpoint_switches",
+		Help: "The number of times the service switched to another eth1 endpoint",
+	})
 )
 
 // time to wait before trying to reconnect with the eth1 node.
 var backOffPeriod = 6 * time.Second
 
+// time between two health checks of the eth1 endpoints.
+var healthCheckPeriod = 30 * time.Second
+
 // Reader defines a struct that can fetch latest header events from a web3 endpoint.
 type Reader interface {
 	SubscribeNewHead(ctx context.Context, ch chan<- *gethTypes.Header) (ethereum.Subscription, error)
@@ -148,6 +162,14 @@ type Service struct {
 	lastReceivedMerkleIndex int64 // Keeps track of the last received index to prevent log spam.
 	runError                error
 	preGenesisState         *stateTrie.BeaconState
+	endpoints               []*EndpointStatus
+	endpointsLock           sync.RWMutex
+	activeEndpoint          int
+	eth1ChainID             uint64
+	healthChecker           healthChecker
+	dialEndpoint            func(endpoint Endpoint) (*eth1Connection, error)
+	connLock                sync.RWMutex // Guards the clients and endpoints of the active connection.
+	conn                    *eth1Connection
 }
 
 // Web3ServiceConfig defines a config struct for web3 service to use through its life cycle.
@@ -158,16 +180,23 @@ type Web3ServiceConfig struct {
 	BeaconDB        db.HeadAccessDatabase
 	DepositCache    *depositcache.DepositCache
 	StateNotifier   statefeed.Notifier
+	Fallbacks       []Endpoint // Used in order when the endpoint above is unhealthy.
+	ETH1ChainID     uint64     // Endpoints on another chain are unhealthy, 0 disables the check.
 }
 
 // NewService sets up a new instance with an ethclient when
 // given a web3 endpoint as a string in the config.
 func NewService(ctx context.Context, config *Web3ServiceConfig) (*Service, error) {
-	if !strings.HasPrefix(config.ETH1Endpoint, "ws") && !strings.HasSuffix(config.ETH1Endpoint, "ipc") {
-		return nil, fmt.Errorf(
-			"powchain service requires either an IPC or WebSocket endpoint, provided %s",
-			config.ETH1Endpoint,
-		)
+	endpoints := append([]Endpoint{{ETH1: config.ETH1Endpoint, HTTP: config.HTTPEndPoint}}, config.Fallbacks...)
+	statuses := make([]*EndpointStatus, len(endpoints))
+	for i, endpoint := range endpoints {
+		if !strings.HasPrefix(endpoint.ETH1, "ws") && !strings.HasSuffix(endpoint.ETH1, "ipc") {
+			return nil, fmt.Errorf(
+				"powchain service requires either an IPC or WebSocket endpoint, provided %s",
+				endpoint.ETH1,
+			)
+		}
+		statuses[i] = &EndpointStatus{Endpoint: endpoint}
 	}
 	ctx, cancel := context.WithCancel(ctx)
 	depositTrie, err := trieutil.NewTrie(int(params.BeaconConfig().DepositContractTreeDepth))
@@ -186,6 +215,8 @@ func NewService(ctx context.Context, config *Web3ServiceConfig) (*Service, error
 		headerChan:   make(chan *gethTypes.Header),
 		eth1Endpoint: config.ETH1Endpoint,
 		httpEndpoint: config.HTTPEndPoint,
+		endpoints:    statuses,
+		eth1ChainID:  config.ETH1ChainID,
 		latestEth1Data: &protodb.LatestETH1Data{
 			BlockHeight:        0,
 			BlockTime:          0,
@@ -205,6 +236,7 @@ func NewService(ctx context.Context, config *Web3ServiceConfig) (*Service, error
 		lastReceivedMerkleIndex: -1,
 		preGenesisState:         genState,
 	}
+	s.dialEndpoint = s.dialETH1Nodes
 
 	eth1Data, err := config.BeaconDB.PowchainData(ctx)
 	if err != nil {
@@ -280,7 +312,7 @@ func (s *Service) Status() error {
 	if s.runError != nil {
 		return s.runError
 	}
-	return nil
+	return s.activeEndpointError()
 }
 
 // IsConnectedToETH1 checks if the beacon node is connected to a ETH1 Node.
@@ -310,8 +342,11 @@ func (s *Service) LatestBlockHash() common.Hash {
 	return bytesutil.ToBytes32(s.latestEth1Data.BlockHash)
 }
 
-// Client for interacting with the ETH1.0 chain.
+// Client for interacting with the ETH1.0 chain. The client is closed after the service switches
+// eth1 endpoints, so it should be fetched again for each use.
 func (s *Service) Client() Client {
+	s.connLock.RLock()
+	defer s.connLock.RUnlock()
 	return s.client
 }
 
@@ -320,7 +355,9 @@ func (s *Service) Client() Client {
 func (s *Service) AreAllDepositsProcessed() (bool, error) {
 	s.processingLock.RLock()
 	defer s.processingLock.RUnlock()
-	countByte, err := s.depositContractCaller.GetDepositCount(&bind.CallOpts{})
+	conn, release := s.acquireConnection()
+	defer release()
+	countByte, err := conn.depositContractCaller.GetDepositCount(&bind.CallOpts{})
 	if err != nil {
 		return false, errors.Wrap(err, "could not get deposit count")
 	}
@@ -332,50 +369,6 @@ func (s *Service) AreAllDepositsProcessed() (bool, error) {
 	return true, nil
 }
 
-func (s *Service) connectToPowChain() error {
-	powClient, httpClient, rpcClient, err := s.dialETH1Nodes()
-	if err != nil {
-		return errors.Wrap(err, "could not dial eth1 nodes")
-	}
-
-	depositContractCaller, err := contracts.NewDepositContractCaller(s.depositContractAddress, httpClient)
-	if err != nil {
-		return errors.Wrap(err, "could not create deposit contract caller")
-	}
-
-	s.initializeConnection(powClient, httpClient, rpcClient, depositContractCaller)
-	return nil
-}
-
-func (s *Service) dialETH1Nodes() (*ethclient.Client, *ethclient.Client, *gethRPC.Client, error) {
-	httpRPCClient, err := gethRPC.Dial(s.httpEndpoint)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-	httpClient := ethclient.NewClient(httpRPCClient)
-
-	rpcClient, err := gethRPC.Dial(s.eth1Endpoint)
-	if err != nil {
-		httpClient.Close()
-		return nil, nil, nil, err
-	}
-	powClient := ethclient.NewClient(rpcClient)
-
-	return powClient, httpClient, httpRPCClient, nil
-}
-
-func (s *Service) initializeConnection(powClient *ethclient.Client,
-	httpClient *ethclient.Client, rpcClient *gethRPC.Client, contractCaller *contracts.DepositContractCaller) {
-
-	s.reader = powClient
-	s.logger = powClient
-	s.client = httpClient
-	s.httpLogger = httpClient
-	s.blockFetcher = httpClient
-	s.depositContractCaller = contractCaller
-	s.rpcClient = rpcClient
-}
-
 func (s *Service) waitForConnection() {
 	err := s.connectToPowChain()
 	if err == nil {
@@ -411,7 +404,9 @@ func (s *Service) waitForConnection() {
 // initDataFromContract calls the deposit contract and finds the deposit count
 // and deposit root.
 func (s *Service) initDataFromContract() error {
-	root, err := s.depositContractCaller.GetDepositRoot(&bind.CallOpts{})
+	conn, release := s.acquireConnection()
+	defer release()
+	root, err := conn.depositContractCaller.GetDepositRoot(&bind.CallOpts{})
 	if err != nil {
 		return errors.Wrap(err, "could not retrieve deposit root")
 	}
@@ -488,7 +483,9 @@ func (s *Service) batchRequestHeaders(startBlock uint64, endBlock uint64) ([]*ge
 		headers = append(headers, header)
 		errors = append(errors, err)
 	}
-	ioErr := s.rpcClient.BatchCall(elems)
+	conn, release := s.acquireConnection()
+	ioErr := conn.rpcClient.BatchCall(elems)
+	release()
 	if ioErr != nil {
 		return nil, ioErr
 	}
@@ -562,14 +559,16 @@ func (s *Service) run(done <-chan struct{}) {
 		return
 	}
 
-	headSub, err := s.reader.SubscribeNewHead(s.ctx, s.headerChan)
+	headSub, err := s.subscribeNewHead()
 	if err != nil {
 		log.Errorf("Unable to subscribe to incoming ETH1.0 chain headers: %v", err)
 		s.runError = err
 		return
 	}
 
-	header, err := s.blockFetcher.HeaderByNumber(context.Background(), nil)
+	conn, release := s.acquireConnection()
+	header, err := conn.blockFetcher.HeaderByNumber(context.Background(), nil)
+	release()
 	if err != nil {
 		log.Errorf("Unable to retrieve latest ETH1.0 chain header: %v", err)
 		s.runError = err
@@ -586,8 +585,13 @@ func (s *Service) run(done <-chan struct{}) {
 	}
 
 	ticker := time.NewTicker(1 * time.Second)
-	defer headSub.Unsubscribe()
+	healthTicker := time.NewTicker(healthCheckPeriod)
+	lastHealthCheck := roughtime.Now()
+	defer func() {
+		headSub.Unsubscribe()
+	}()
 	defer ticker.Stop()
+	defer healthTicker.Stop()
 
 	for {
 		select {
@@ -601,7 +605,7 @@ func (s *Service) run(done <-chan struct{}) {
 			log.WithError(s.runError).Warn("Subscription to new head notifier failed")
 			s.connectedETH1 = false
 			s.waitForConnection()
-			headSub, err = s.reader.SubscribeNewHead(s.ctx, s.headerChan)
+			headSub, err = s.subscribeNewHead()
 			if err != nil {
 				log.WithError(err).Error("Unable to re-subscribe to incoming ETH1.0 chain headers")
 				s.runError = err
@@ -614,6 +618,23 @@ func (s *Service) run(done <-chan struct{}) {
 			}
 		case <-ticker.C:
 			s.handleDelayTicker()
+			// Do not wait for the next health check when requests to the endpoint fail.
+			if s.runError == nil || roughtime.Since(lastHealthCheck) < backOffPeriod {
+				continue
+			}
+			lastHealthCheck = roughtime.Now()
+			if headSub, err = s.checkEndpointsAndResubscribe(headSub); err != nil {
+				log.WithError(err).Error("Unable to re-subscribe to incoming ETH1.0 chain headers")
+				s.runError = err
+				return
+			}
+		case <-healthTicker.C:
+			lastHealthCheck = roughtime.Now()
+			if headSub, err = s.checkEndpointsAndResubscribe(headSub); err != nil {
+				log.WithError(err).Error("Unable to re-subscribe to incoming ETH1.0 chain headers")
+				s.runError = err
+				return
+			}
 		}
 	}
 }
@@ -59,6 +59,9 @@ func (bs *Server) ListValidatorAssignments(
 		)
 	}
 
+	if err := bs.checkReplayCost(ctx, helpers.StartSlot(requestedEpoch)); err != nil {
+		return nil, err
+	}
 	requestedState, err := bs.StateGen.StateBySlot(ctx, helpers.StartSlot(requestedEpoch))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not retrieve archived state for epoch %d: %v", requestedEpoch, err)
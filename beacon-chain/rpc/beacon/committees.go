@@ -80,6 +80,9 @@ func (bs *Server) retrieveCommitteesForEpoch(
 	epoch uint64,
 ) (map[uint64]*ethpb.BeaconCommittees_CommitteesList, []uint64, error) {
 	startSlot := helpers.StartSlot(epoch)
+	if err := bs.checkReplayCost(ctx, startSlot); err != nil {
+		return nil, nil, err
+	}
 	requestedState, err := bs.StateGen.StateBySlot(ctx, startSlot)
 	if err != nil {
 		return nil, nil, status.Error(codes.Internal, "Could not get state")
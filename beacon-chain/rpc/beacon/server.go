@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache/depositcache"
@@ -17,6 +18,8 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	pbp2p "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Server defines a server implementation of the gRPC Beacon Chain service,
@@ -43,4 +46,18 @@ type Server struct {
 	ReceivedAttestationsBuffer  chan *ethpb.Attestation
 	CollectedAttestationsBuffer chan []*ethpb.Attestation
 	StateGen                    *stategen.State
+	MaxStateReplaySlots         uint64
+}
+
+// checkReplayCost rejects the request with a resource exhausted error if computing the state at
+// the slot would replay more slots than allowed.
+func (bs *Server) checkReplayCost(ctx context.Context, slot uint64) error {
+	err := bs.StateGen.CheckReplayCost(ctx, slot, bs.MaxStateReplaySlots)
+	if err == nil {
+		return nil
+	}
+	if errors.Cause(err) == stategen.ErrReplayTooExpensive {
+		return status.Errorf(codes.ResourceExhausted, "Historical state query is too expensive: %v", err)
+	}
+	return status.Errorf(codes.Internal, "Could not estimate state replay cost: %v", err)
 }
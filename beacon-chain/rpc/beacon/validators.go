@@ -60,6 +60,9 @@ func (bs *Server) ListValidatorBalances(
 	res := make([]*ethpb.ValidatorBalances_Balance, 0)
 	filtered := map[uint64]bool{} // Track filtered validators to prevent duplication in the response.
 
+	if err := bs.checkReplayCost(ctx, helpers.StartSlot(requestedEpoch)); err != nil {
+		return nil, err
+	}
 	requestedState, err := bs.StateGen.StateBySlot(ctx, helpers.StartSlot(requestedEpoch))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not get state")
@@ -526,6 +529,9 @@ func (bs *Server) GetValidatorActiveSetChanges(
 	slashedIndices := make([]uint64, 0)
 	ejectedIndices := make([]uint64, 0)
 
+	if err := bs.checkReplayCost(ctx, helpers.StartSlot(requestedEpoch)); err != nil {
+		return nil, err
+	}
 	requestedState, err := bs.StateGen.StateBySlot(ctx, helpers.StartSlot(requestedEpoch))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not get state: %v", err)
@@ -721,6 +727,9 @@ func (bs *Server) GetValidatorParticipation(
 		)
 	}
 
+	if err := bs.checkReplayCost(ctx, helpers.StartSlot(requestedEpoch+1)); err != nil {
+		return nil, err
+	}
 	requestedState, err := bs.StateGen.StateBySlot(ctx, helpers.StartSlot(requestedEpoch+1))
 	if err != nil {
 		return nil, status.Error(codes.Internal, "Could not get state")
@@ -0,0 +1,589 @@
+package beaconapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+type genesisJSON struct {
+	GenesisTime           string `json:"genesis_time"`
+	GenesisValidatorsRoot string `json:"genesis_validators_root"`
+	GenesisForkVersion    string `json:"genesis_fork_version"`
+}
+
+type rootJSON struct {
+	Root string `json:"root"`
+}
+
+type finalityCheckpointsJSON struct {
+	PreviousJustified interface{} `json:"previous_justified"`
+	CurrentJustified  interface{} `json:"current_justified"`
+	Finalized         interface{} `json:"finalized"`
+}
+
+type validatorJSON struct {
+	Index     string         `json:"index"`
+	Balance   string         `json:"balance"`
+	Status    string         `json:"status"`
+	Validator *validatorData `json:"validator"`
+}
+
+type validatorData struct {
+	Pubkey                     string `json:"pubkey"`
+	WithdrawalCredentials      string `json:"withdrawal_credentials"`
+	EffectiveBalance           string `json:"effective_balance"`
+	Slashed                    bool   `json:"slashed"`
+	ActivationEligibilityEpoch string `json:"activation_eligibility_epoch"`
+	ActivationEpoch            string `json:"activation_epoch"`
+	ExitEpoch                  string `json:"exit_epoch"`
+	WithdrawableEpoch          string `json:"withdrawable_epoch"`
+}
+
+type committeeJSON struct {
+	Index      string   `json:"index"`
+	Slot       string   `json:"slot"`
+	Validators []string `json:"validators"`
+}
+
+type blockHeaderJSON struct {
+	Root      string                 `json:"root"`
+	Canonical bool                   `json:"canonical"`
+	Header    *signedBlockHeaderJSON `json:"header"`
+}
+
+type signedBlockHeaderJSON struct {
+	Message   interface{} `json:"message"`
+	Signature string      `json:"signature"`
+}
+
+func (s *Server) getGenesis(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	genesisTime := s.GenesisTimeFetcher.GenesisTime()
+	if genesisTime.IsZero() {
+		return newError(http.StatusNotFound, "Chain genesis info is not yet known")
+	}
+	root := s.GenesisFetcher.GenesisValidatorRoot()
+	writeData(w, &genesisJSON{
+		GenesisTime:           strconv.FormatInt(genesisTime.Unix(), 10),
+		GenesisValidatorsRoot: hexString(root[:]),
+		GenesisForkVersion:    hexString(params.BeaconConfig().GenesisForkVersion),
+	})
+	return nil
+}
+
+func (s *Server) getStateRoot(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	st, apiErr := s.stateByID(r.Context(), vars["state_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	root, err := st.HashTreeRoot(r.Context())
+	if err != nil {
+		return newError(http.StatusInternalServerError, "Could not compute state root: %v", err)
+	}
+	writeData(w, &rootJSON{Root: hexString(root[:])})
+	return nil
+}
+
+func (s *Server) getStateFork(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	st, apiErr := s.stateByID(r.Context(), vars["state_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	writeData(w, encodeJSON(st.Fork()))
+	return nil
+}
+
+func (s *Server) getFinalityCheckpoints(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	st, apiErr := s.stateByID(r.Context(), vars["state_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	writeData(w, &finalityCheckpointsJSON{
+		PreviousJustified: encodeJSON(st.PreviousJustifiedCheckpoint()),
+		CurrentJustified:  encodeJSON(st.CurrentJustifiedCheckpoint()),
+		Finalized:         encodeJSON(st.FinalizedCheckpoint()),
+	})
+	return nil
+}
+
+func (s *Server) listValidators(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	st, apiErr := s.stateByID(r.Context(), vars["state_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	var indices []uint64
+	ids := queryList(r, "id")
+	if len(ids) == 0 {
+		indices = make([]uint64, st.NumValidators())
+		for i := range indices {
+			indices[i] = uint64(i)
+		}
+	}
+	for _, id := range ids {
+		idx, apiErr := validatorIndexByID(st, id)
+		if apiErr != nil {
+			return apiErr
+		}
+		indices = append(indices, idx)
+	}
+	statuses := make(map[string]bool)
+	for _, status := range queryList(r, "status") {
+		statuses[status] = true
+	}
+
+	epoch := helpers.CurrentEpoch(st)
+	res := make([]*validatorJSON, 0, len(indices))
+	for _, idx := range indices {
+		v, err := validatorAtIndex(st, idx, epoch)
+		if err != nil {
+			return err
+		}
+		if len(statuses) > 0 && !statuses[v.Status] {
+			continue
+		}
+		res = append(res, v)
+	}
+	writeData(w, res)
+	return nil
+}
+
+func (s *Server) getValidator(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	st, apiErr := s.stateByID(r.Context(), vars["state_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	idx, apiErr := validatorIndexByID(st, vars["validator_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	v, apiErr := validatorAtIndex(st, idx, helpers.CurrentEpoch(st))
+	if apiErr != nil {
+		return apiErr
+	}
+	writeData(w, v)
+	return nil
+}
+
+func (s *Server) listCommittees(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	st, apiErr := s.stateByID(r.Context(), vars["state_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	epoch, apiErr := queryUint(r, "epoch", helpers.CurrentEpoch(st))
+	if apiErr != nil {
+		return apiErr
+	}
+	if epoch > helpers.NextEpoch(st) {
+		return newError(http.StatusBadRequest, "Epoch %d is after the next epoch of the state", epoch)
+	}
+	activeCount, err := helpers.ActiveValidatorCount(st, epoch)
+	if err != nil {
+		return newError(http.StatusBadRequest, "Could not compute committees of epoch %d: %v", epoch, err)
+	}
+	committeesPerSlot := helpers.SlotCommitteeCount(activeCount)
+
+	startSlot := helpers.StartSlot(epoch)
+	endSlot := startSlot + params.BeaconConfig().SlotsPerEpoch - 1
+	if r.URL.Query().Get("slot") != "" {
+		slot, apiErr := queryUint(r, "slot", 0)
+		if apiErr != nil {
+			return apiErr
+		}
+		if slot < startSlot || slot > endSlot {
+			return newError(http.StatusBadRequest, "Slot %d is not in epoch %d", slot, epoch)
+		}
+		startSlot, endSlot = slot, slot
+	}
+	startIndex, endIndex := uint64(0), committeesPerSlot-1
+	if r.URL.Query().Get("index") != "" {
+		index, apiErr := queryUint(r, "index", 0)
+		if apiErr != nil {
+			return apiErr
+		}
+		if index >= committeesPerSlot {
+			return newError(http.StatusBadRequest, "Committee index %d is out of range", index)
+		}
+		startIndex, endIndex = index, index
+	}
+
+	res := make([]*committeeJSON, 0)
+	for slot := startSlot; slot <= endSlot; slot++ {
+		for index := startIndex; index <= endIndex; index++ {
+			committee, err := helpers.BeaconCommitteeFromState(st, slot, index)
+			if err != nil {
+				return newError(http.StatusInternalServerError, "Could not compute committee: %v", err)
+			}
+			res = append(res, &committeeJSON{
+				Index:      strconv.FormatUint(index, 10),
+				Slot:       strconv.FormatUint(slot, 10),
+				Validators: uintStrings(committee),
+			})
+		}
+	}
+	writeData(w, res)
+	return nil
+}
+
+func (s *Server) listBlockHeaders(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	ctx := r.Context()
+	query := r.URL.Query()
+	var blks []*ethpb.SignedBeaconBlock
+	var roots [][32]byte
+	switch {
+	case query.Get("slot") != "":
+		slot, apiErr := queryUint(r, "slot", 0)
+		if apiErr != nil {
+			return apiErr
+		}
+		blks, roots, apiErr = s.blocksAtSlot(ctx, slot)
+		if apiErr != nil {
+			return apiErr
+		}
+	case query.Get("parent_root") != "":
+		parentRoot, err := parseRoot(query.Get("parent_root"))
+		if err != nil {
+			return newError(http.StatusBadRequest, "Invalid parent root: %v", err)
+		}
+		var apiErr *apiError
+		blks, roots, apiErr = s.childBlocks(ctx, parentRoot)
+		if apiErr != nil {
+			return apiErr
+		}
+	default:
+		blk, root, apiErr := s.blockByID(ctx, "head")
+		if apiErr != nil {
+			return apiErr
+		}
+		blks, roots = []*ethpb.SignedBeaconBlock{blk}, [][32]byte{root}
+	}
+
+	res := make([]*blockHeaderJSON, 0, len(blks))
+	for i, blk := range blks {
+		header, apiErr := s.blockHeader(r, blk, roots[i])
+		if apiErr != nil {
+			return apiErr
+		}
+		res = append(res, header)
+	}
+	writeData(w, res)
+	return nil
+}
+
+func (s *Server) getBlockHeader(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	blk, root, apiErr := s.blockByID(r.Context(), vars["block_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	header, apiErr := s.blockHeader(r, blk, root)
+	if apiErr != nil {
+		return apiErr
+	}
+	writeData(w, header)
+	return nil
+}
+
+func (s *Server) blockHeader(r *http.Request, blk *ethpb.SignedBeaconBlock, root [32]byte) (*blockHeaderJSON, *apiError) {
+	bodyRoot, err := stateutil.BlockBodyRoot(blk.Block.Body)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not compute block body root: %v", err)
+	}
+	canonical, apiErr := s.isCanonical(r.Context(), root, blk.Block.Slot)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	return &blockHeaderJSON{
+		Root:      hexString(root[:]),
+		Canonical: canonical,
+		Header: &signedBlockHeaderJSON{
+			Message: encodeJSON(&ethpb.BeaconBlockHeader{
+				Slot:          blk.Block.Slot,
+				ProposerIndex: blk.Block.ProposerIndex,
+				ParentRoot:    blk.Block.ParentRoot,
+				StateRoot:     blk.Block.StateRoot,
+				BodyRoot:      bodyRoot[:],
+			}),
+			Signature: hexString(blk.Signature),
+		},
+	}, nil
+}
+
+func (s *Server) submitBlock(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	blk := &ethpb.SignedBeaconBlock{}
+	if apiErr := readObject(r, blk); apiErr != nil {
+		return apiErr
+	}
+	if blk.Block == nil || blk.Block.Body == nil {
+		return newError(http.StatusBadRequest, "Block and block body must be set")
+	}
+	if _, err := s.ValidatorServer.ProposeBlock(r.Context(), blk); err != nil {
+		return fromGRPCError(err)
+	}
+	writeOK(w)
+	return nil
+}
+
+func (s *Server) getBlock(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	blk, _, apiErr := s.blockByID(r.Context(), vars["block_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	return writeObject(w, r, blk)
+}
+
+func (s *Server) getBlockRoot(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	_, root, apiErr := s.blockByID(r.Context(), vars["block_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	writeData(w, &rootJSON{Root: hexString(root[:])})
+	return nil
+}
+
+func (s *Server) listBlockAttestations(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	blk, _, apiErr := s.blockByID(r.Context(), vars["block_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	writeData(w, encodeJSON(blk.Block.Body.Attestations))
+	return nil
+}
+
+func (s *Server) getState(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	st, apiErr := s.stateByID(r.Context(), vars["state_id"])
+	if apiErr != nil {
+		return apiErr
+	}
+	return writeObject(w, r, st.InnerStateUnsafe())
+}
+
+func (s *Server) listPoolAttestations(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	query := r.URL.Query()
+	slot, apiErr := queryUint(r, "slot", 0)
+	if apiErr != nil {
+		return apiErr
+	}
+	committeeIndex, apiErr := queryUint(r, "committee_index", 0)
+	if apiErr != nil {
+		return apiErr
+	}
+	atts := append(s.AttestationsPool.AggregatedAttestations(), s.AttestationsPool.UnaggregatedAttestations()...)
+	res := make([]*ethpb.Attestation, 0, len(atts))
+	for _, att := range atts {
+		if query.Get("slot") != "" && att.Data.Slot != slot {
+			continue
+		}
+		if query.Get("committee_index") != "" && att.Data.CommitteeIndex != committeeIndex {
+			continue
+		}
+		res = append(res, att)
+	}
+	writeData(w, encodeJSON(res))
+	return nil
+}
+
+func (s *Server) submitAttestations(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	var atts []*ethpb.Attestation
+	if apiErr := readJSON(r, &atts); apiErr != nil {
+		return apiErr
+	}
+	var failures []*indexedFailure
+	for i, att := range atts {
+		if att.Data == nil {
+			failures = append(failures, &indexedFailure{Index: i, Message: "attestation data must be set"})
+			continue
+		}
+		if _, err := s.ValidatorServer.ProposeAttestation(r.Context(), att); err != nil {
+			failures = append(failures, &indexedFailure{Index: i, Message: fromGRPCError(err).msg})
+		}
+	}
+	if len(failures) > 0 {
+		apiErr := newError(http.StatusBadRequest, "Some attestations failed to be submitted")
+		apiErr.failures = failures
+		return apiErr
+	}
+	writeOK(w)
+	return nil
+}
+
+func (s *Server) listPoolAttesterSlashings(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	writeData(w, encodeJSON(s.SlashingsPool.PendingAttesterSlashings(r.Context())))
+	return nil
+}
+
+func (s *Server) submitAttesterSlashing(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	slashing := &ethpb.AttesterSlashing{}
+	if apiErr := readObject(r, slashing); apiErr != nil {
+		return apiErr
+	}
+	if slashing.Attestation_1 == nil || slashing.Attestation_2 == nil {
+		return newError(http.StatusBadRequest, "Both attestations of the slashing must be set")
+	}
+	if _, err := s.BeaconChainServer.SubmitAttesterSlashing(r.Context(), slashing); err != nil {
+		return fromGRPCError(err)
+	}
+	writeOK(w)
+	return nil
+}
+
+func (s *Server) listPoolProposerSlashings(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	writeData(w, encodeJSON(s.SlashingsPool.PendingProposerSlashings(r.Context())))
+	return nil
+}
+
+func (s *Server) submitProposerSlashing(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	slashing := &ethpb.ProposerSlashing{}
+	if apiErr := readObject(r, slashing); apiErr != nil {
+		return apiErr
+	}
+	if slashing.Header_1 == nil || slashing.Header_1.Header == nil || slashing.Header_2 == nil || slashing.Header_2.Header == nil {
+		return newError(http.StatusBadRequest, "Both headers of the slashing must be set")
+	}
+	if _, err := s.BeaconChainServer.SubmitProposerSlashing(r.Context(), slashing); err != nil {
+		return fromGRPCError(err)
+	}
+	writeOK(w)
+	return nil
+}
+
+func (s *Server) listPoolVoluntaryExits(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	st, apiErr := s.headState(r.Context())
+	if apiErr != nil {
+		return apiErr
+	}
+	writeData(w, encodeJSON(s.ExitPool.PendingExits(st, st.Slot())))
+	return nil
+}
+
+func (s *Server) submitVoluntaryExit(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	exit := &ethpb.SignedVoluntaryExit{}
+	if apiErr := readObject(r, exit); apiErr != nil {
+		return apiErr
+	}
+	if exit.Exit == nil {
+		return newError(http.StatusBadRequest, "Voluntary exit must be set")
+	}
+	if _, err := s.ValidatorServer.ProposeExit(r.Context(), exit); err != nil {
+		return fromGRPCError(err)
+	}
+	writeOK(w)
+	return nil
+}
+
+// validatorIndexByID resolves a validator id of the API, either an index or a 0x prefixed hex public key.
+func validatorIndexByID(st *stateTrie.BeaconState, id string) (uint64, *apiError) {
+	if strings.HasPrefix(id, "0x") {
+		pubKey, err := decodeHex(id, "")
+		if err != nil || len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+			return 0, newError(http.StatusBadRequest, "Invalid validator public key %s", id)
+		}
+		idx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(pubKey))
+		if !ok {
+			return 0, newError(http.StatusNotFound, "Validator %s not found", id)
+		}
+		return idx, nil
+	}
+	idx, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, newError(http.StatusBadRequest, "Invalid validator id %s", id)
+	}
+	if idx >= uint64(st.NumValidators()) {
+		return 0, newError(http.StatusNotFound, "Validator %d not found", idx)
+	}
+	return idx, nil
+}
+
+func validatorAtIndex(st *stateTrie.BeaconState, idx uint64, epoch uint64) (*validatorJSON, *apiError) {
+	v, err := st.ValidatorAtIndex(idx)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not retrieve validator %d: %v", idx, err)
+	}
+	balance, err := st.BalanceAtIndex(idx)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not retrieve balance of validator %d: %v", idx, err)
+	}
+	return &validatorJSON{
+		Index:   strconv.FormatUint(idx, 10),
+		Balance: strconv.FormatUint(balance, 10),
+		Status:  validatorStatus(v, epoch),
+		Validator: &validatorData{
+			Pubkey:                     hexString(v.PublicKey),
+			WithdrawalCredentials:      hexString(v.WithdrawalCredentials),
+			EffectiveBalance:           strconv.FormatUint(v.EffectiveBalance, 10),
+			Slashed:                    v.Slashed,
+			ActivationEligibilityEpoch: strconv.FormatUint(v.ActivationEligibilityEpoch, 10),
+			ActivationEpoch:            strconv.FormatUint(v.ActivationEpoch, 10),
+			ExitEpoch:                  strconv.FormatUint(v.ExitEpoch, 10),
+			WithdrawableEpoch:          strconv.FormatUint(v.WithdrawableEpoch, 10),
+		},
+	}, nil
+}
+
+// validatorStatus returns the status of a validator at an epoch, as defined by the standard API.
+func validatorStatus(v *ethpb.Validator, epoch uint64) string {
+	farFutureEpoch := params.BeaconConfig().FarFutureEpoch
+	switch {
+	case epoch < v.ActivationEpoch:
+		if v.ActivationEligibilityEpoch == farFutureEpoch {
+			return "pending_initialized"
+		}
+		return "pending_queued"
+	case epoch < v.ExitEpoch:
+		if v.ExitEpoch == farFutureEpoch {
+			return "active_ongoing"
+		}
+		if v.Slashed {
+			return "active_slashed"
+		}
+		return "active_exiting"
+	case epoch < v.WithdrawableEpoch:
+		if v.Slashed {
+			return "exited_slashed"
+		}
+		return "exited_unslashed"
+	case v.EffectiveBalance != 0:
+		return "withdrawal_possible"
+	default:
+		return "withdrawal_done"
+	}
+}
+
+// queryList returns the values of a query parameter, given either repeated or comma separated.
+func queryList(r *http.Request, key string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[key] {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// queryUint returns the value of an integer query parameter, or the default value if it is not set.
+func queryUint(r *http.Request, key string, defaultValue uint64) (uint64, *apiError) {
+	str := r.URL.Query().Get(key)
+	if str == "" {
+		return defaultValue, nil
+	}
+	v, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0, newError(http.StatusBadRequest, "Invalid %s %s", key, str)
+	}
+	return v, nil
+}
+
+func uintStrings(values []uint64) []string {
+	res := make([]string, len(values))
+	for i, v := range values {
+		res[i] = strconv.FormatUint(v, 10)
+	}
+	return res
+}
@@ -0,0 +1,197 @@
+package beaconapi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// encodeJSON converts a value, typically a protobuf object, into its representation in the standard
+// API. Object fields are named after their protobuf definition, integers are encoded as decimal
+// strings and byte arrays, including bitfields, as 0x prefixed hex strings.
+func encodeJSON(v interface{}) interface{} {
+	return encodeValue(reflect.ValueOf(v))
+}
+
+func encodeValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Struct:
+		obj := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			if name, ok := jsonFieldName(v.Type().Field(i)); ok {
+				obj[name] = encodeValue(v.Field(i))
+			}
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexString(b)
+		}
+		list := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			list[i] = encodeValue(v.Index(i))
+		}
+		return list
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	default:
+		return v.Interface()
+	}
+}
+
+// decodeJSON decodes the representation of a value in the standard API, as produced by encodeJSON,
+// into the value pointed to by v.
+func decodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	dst := reflect.ValueOf(v)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return errors.New("decoding destination must be a non nil pointer")
+	}
+	return decodeValue(raw, dst.Elem(), "")
+}
+
+func decodeValue(raw interface{}, v reflect.Value, path string) error {
+	if raw == nil {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(raw, v.Elem(), path)
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return fieldError(path, "expected an object")
+		}
+		for i := 0; i < v.NumField(); i++ {
+			name, ok := jsonFieldName(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+			if err := decodeValue(obj[name], v.Field(i), path+"."+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := decodeHex(raw, path)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(b).Convert(v.Type()))
+			return nil
+		}
+		list, ok := raw.([]interface{})
+		if !ok {
+			return fieldError(path, "expected a list")
+		}
+		s := reflect.MakeSlice(v.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := decodeValue(item, s.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(numberString(raw), 10, v.Type().Bits())
+		if err != nil {
+			return fieldError(path, "expected an unsigned integer")
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(numberString(raw), 10, v.Type().Bits())
+		if err != nil {
+			return fieldError(path, "expected an integer")
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fieldError(path, "expected a boolean")
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.String:
+		str, ok := raw.(string)
+		if !ok {
+			return fieldError(path, "expected a string")
+		}
+		v.SetString(str)
+		return nil
+	default:
+		return fieldError(path, fmt.Sprintf("unsupported type %s", v.Type()))
+	}
+}
+
+// jsonFieldName returns the name of a protobuf object field in the API, skipping the internal fields.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" || strings.HasPrefix(f.Name, "XXX_") {
+		return "", false
+	}
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return "", false
+	}
+	return name, true
+}
+
+// numberString accepts both quoted and plain JSON numbers.
+func numberString(raw interface{}) string {
+	switch n := raw.(type) {
+	case string:
+		return n
+	case json.Number:
+		return n.String()
+	default:
+		return ""
+	}
+}
+
+func decodeHex(raw interface{}, path string) ([]byte, error) {
+	str, ok := raw.(string)
+	if !ok || !strings.HasPrefix(str, "0x") {
+		return nil, fieldError(path, "expected a 0x prefixed hex string")
+	}
+	b, err := hex.DecodeString(str[2:])
+	if err != nil {
+		return nil, fieldError(path, "expected a 0x prefixed hex string")
+	}
+	return b, nil
+}
+
+func hexString(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func fieldError(path string, msg string) error {
+	if path == "" {
+		return errors.New(msg)
+	}
+	return fmt.Errorf("%s: %s", strings.TrimPrefix(path, "."), msg)
+}
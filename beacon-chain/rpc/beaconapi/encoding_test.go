@@ -0,0 +1,69 @@
+package beaconapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestEncodeJSON_RoundTrip(t *testing.T) {
+	att := &ethpb.Attestation{
+		AggregationBits: bitfield.Bitlist{0b1101},
+		Data: &ethpb.AttestationData{
+			Slot:            3,
+			CommitteeIndex:  1,
+			BeaconBlockRoot: []byte{'A'},
+			Source:          &ethpb.Checkpoint{Epoch: 1, Root: []byte{'B'}},
+			Target:          &ethpb.Checkpoint{Epoch: 2, Root: []byte{'C'}},
+		},
+		Signature: []byte{'D'},
+	}
+	enc, err := json.Marshal(encodeJSON(att))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"aggregation_bits":"0x0d","data":{"beacon_block_root":"0x41","committee_index":"1",` +
+		`"slot":"3","source":{"epoch":"1","root":"0x42"},"target":{"epoch":"2","root":"0x43"}},"signature":"0x44"}`
+	if string(enc) != want {
+		t.Errorf("Wanted %s, received %s", want, enc)
+	}
+
+	decoded := &ethpb.Attestation{}
+	if err := decodeJSON(enc, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(att, decoded) {
+		t.Errorf("Wanted %v, received %v", att, decoded)
+	}
+}
+
+func TestDecodeJSON_AcceptsPlainNumbers(t *testing.T) {
+	cp := &ethpb.Checkpoint{}
+	if err := decodeJSON([]byte(`{"epoch":5,"root":"0x01"}`), cp); err != nil {
+		t.Fatal(err)
+	}
+	if cp.Epoch != 5 {
+		t.Errorf("Wanted epoch %d, received %d", 5, cp.Epoch)
+	}
+}
+
+func TestDecodeJSON_InvalidField(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{input: `{"epoch":"abc"}`, wantErr: "epoch: expected an unsigned integer"},
+		{input: `{"root":"01"}`, wantErr: "root: expected a 0x prefixed hex string"},
+		{input: `{"root":"0xzz"}`, wantErr: "root: expected a 0x prefixed hex string"},
+		{input: `[]`, wantErr: "expected an object"},
+	}
+	for _, tt := range tests {
+		err := decodeJSON([]byte(tt.input), &ethpb.Checkpoint{})
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("Decoding %s: wanted error %q, received %v", tt.input, tt.wantErr, err)
+		}
+	}
+}
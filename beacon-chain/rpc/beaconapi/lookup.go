@@ -0,0 +1,278 @@
+package beaconapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+var errInvalidRootLength = errors.New("root must be 32 bytes long")
+
+// stateByID returns the state identified by a state id of the API, which is one of head, genesis,
+// finalized, justified, a slot or a 0x prefixed hex state root.
+func (s *Server) stateByID(ctx context.Context, id string) (*stateTrie.BeaconState, *apiError) {
+	switch id {
+	case "head":
+		return s.headState(ctx)
+	case "genesis":
+		st, err := s.BeaconDB.GenesisState(ctx)
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, "Could not retrieve genesis state: %v", err)
+		}
+		if st == nil {
+			return nil, newError(http.StatusNotFound, "Genesis state not found")
+		}
+		return st, nil
+	case "finalized":
+		return s.stateByBlockRoot(ctx, bytesutil.ToBytes32(s.FinalizationFetcher.FinalizedCheckpt().Root))
+	case "justified":
+		return s.stateByBlockRoot(ctx, bytesutil.ToBytes32(s.FinalizationFetcher.CurrentJustifiedCheckpt().Root))
+	}
+
+	if strings.HasPrefix(id, "0x") {
+		root, err := parseRoot(id)
+		if err != nil {
+			return nil, newError(http.StatusBadRequest, "Invalid state id %s: %v", id, err)
+		}
+		return s.stateByRoot(ctx, root)
+	}
+
+	slot, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "Invalid state id %s", id)
+	}
+	return s.stateBySlot(ctx, slot)
+}
+
+func (s *Server) headState(ctx context.Context) (*stateTrie.BeaconState, *apiError) {
+	st, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not retrieve head state: %v", err)
+	}
+	if st == nil {
+		return nil, newError(http.StatusServiceUnavailable, "Head state not available yet")
+	}
+	return st, nil
+}
+
+// stateByRoot returns the canonical state with the given state root. As states are indexed by
+// block root in the database, the root is looked up in the state roots of the head state, which
+// cover the last SlotsPerHistoricalRoot slots.
+func (s *Server) stateByRoot(ctx context.Context, root [32]byte) (*stateTrie.BeaconState, *apiError) {
+	headState, apiErr := s.headState(ctx)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	headStateRoot, err := headState.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not compute head state root: %v", err)
+	}
+	if headStateRoot == root {
+		return headState, nil
+	}
+	slotsPerHistoricalRoot := params.BeaconConfig().SlotsPerHistoricalRoot
+	stateRoots := headState.StateRoots()
+	for slot := headState.Slot(); slot > 0 && headState.Slot()-slot < slotsPerHistoricalRoot; slot-- {
+		if bytes.Equal(stateRoots[(slot-1)%slotsPerHistoricalRoot], root[:]) {
+			return s.stateBySlot(ctx, slot-1)
+		}
+	}
+	return nil, newError(http.StatusNotFound, "State root %#x not found in the last %d slots", root, slotsPerHistoricalRoot)
+}
+
+func (s *Server) stateByBlockRoot(ctx context.Context, root [32]byte) (*stateTrie.BeaconState, *apiError) {
+	var st *stateTrie.BeaconState
+	var err error
+	if featureconfig.Get().NewStateMgmt {
+		if !s.StateGen.StateSummaryExists(ctx, root) && root != params.BeaconConfig().ZeroHash {
+			return nil, newError(http.StatusNotFound, "State for block root %#x not found", root)
+		}
+		st, err = s.StateGen.StateByRoot(ctx, root)
+	} else {
+		st, err = s.BeaconDB.State(ctx, root)
+	}
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not retrieve state: %v", err)
+	}
+	if st == nil {
+		return nil, newError(http.StatusNotFound, "State for block root %#x not found", root)
+	}
+	return st, nil
+}
+
+// stateBySlot returns the canonical state at a slot, advancing the state of the last block
+// before the slot through empty slots if needed.
+func (s *Server) stateBySlot(ctx context.Context, slot uint64) (*stateTrie.BeaconState, *apiError) {
+	headSlot := s.HeadFetcher.HeadSlot()
+	if slot > headSlot {
+		return nil, newError(http.StatusNotFound, "Slot %d is after the head slot %d", slot, headSlot)
+	}
+	if slot == headSlot {
+		return s.headState(ctx)
+	}
+	if featureconfig.Get().NewStateMgmt {
+		if err := s.StateGen.CheckReplayCost(ctx, slot, s.MaxStateReplaySlots); err != nil {
+			if errors.Cause(err) == stategen.ErrReplayTooExpensive {
+				return nil, newError(http.StatusBadRequest, "Historical state query is too expensive: %v", err)
+			}
+			return nil, newError(http.StatusInternalServerError, "Could not estimate state replay cost: %v", err)
+		}
+		st, err := s.StateGen.StateBySlot(ctx, slot)
+		if err != nil {
+			return nil, newError(http.StatusInternalServerError, "Could not retrieve state at slot %d: %v", slot, err)
+		}
+		return st, nil
+	}
+	states, err := s.BeaconDB.HighestSlotStatesBelow(ctx, slot+1)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not retrieve state at slot %d: %v", slot, err)
+	}
+	if len(states) == 0 {
+		return nil, newError(http.StatusNotFound, "State at slot %d not found", slot)
+	}
+	st, err := state.ProcessSlots(ctx, states[0].Copy(), slot)
+	if err != nil {
+		return nil, newError(http.StatusInternalServerError, "Could not process slots up to %d: %v", slot, err)
+	}
+	return st, nil
+}
+
+// blockByID returns the block identified by a block id of the API, which is one of head, genesis,
+// finalized, a slot or a 0x prefixed hex block root, along with its root.
+func (s *Server) blockByID(ctx context.Context, id string) (*ethpb.SignedBeaconBlock, [32]byte, *apiError) {
+	var blk *ethpb.SignedBeaconBlock
+	var err error
+	switch id {
+	case "head":
+		blk, err = s.HeadFetcher.HeadBlock(ctx)
+	case "genesis":
+		blk, err = s.BeaconDB.GenesisBlock(ctx)
+	case "finalized":
+		blk, err = s.BeaconDB.Block(ctx, bytesutil.ToBytes32(s.FinalizationFetcher.FinalizedCheckpt().Root))
+	default:
+		if strings.HasPrefix(id, "0x") {
+			root, parseErr := parseRoot(id)
+			if parseErr != nil {
+				return nil, [32]byte{}, newError(http.StatusBadRequest, "Invalid block id %s: %v", id, parseErr)
+			}
+			blk, err = s.BeaconDB.Block(ctx, root)
+			break
+		}
+		slot, parseErr := strconv.ParseUint(id, 10, 64)
+		if parseErr != nil {
+			return nil, [32]byte{}, newError(http.StatusBadRequest, "Invalid block id %s", id)
+		}
+		return s.canonicalBlockAtSlot(ctx, slot)
+	}
+	if err != nil {
+		return nil, [32]byte{}, newError(http.StatusInternalServerError, "Could not retrieve block: %v", err)
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, [32]byte{}, newError(http.StatusNotFound, "Block %s not found", id)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return nil, [32]byte{}, newError(http.StatusInternalServerError, "Could not compute block root: %v", err)
+	}
+	return blk, root, nil
+}
+
+func (s *Server) canonicalBlockAtSlot(ctx context.Context, slot uint64) (*ethpb.SignedBeaconBlock, [32]byte, *apiError) {
+	blks, roots, apiErr := s.blocksAtSlot(ctx, slot)
+	if apiErr != nil {
+		return nil, [32]byte{}, apiErr
+	}
+	for i, blk := range blks {
+		canonical, apiErr := s.isCanonical(ctx, roots[i], slot)
+		if apiErr != nil {
+			return nil, [32]byte{}, apiErr
+		}
+		if canonical {
+			return blk, roots[i], nil
+		}
+	}
+	return nil, [32]byte{}, newError(http.StatusNotFound, "No canonical block found at slot %d", slot)
+}
+
+func (s *Server) blocksAtSlot(ctx context.Context, slot uint64) ([]*ethpb.SignedBeaconBlock, [][32]byte, *apiError) {
+	blks, err := s.BeaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(slot).SetEndSlot(slot))
+	if err != nil {
+		return nil, nil, newError(http.StatusInternalServerError, "Could not retrieve blocks at slot %d: %v", slot, err)
+	}
+	roots := make([][32]byte, len(blks))
+	for i, blk := range blks {
+		roots[i], err = stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, nil, newError(http.StatusInternalServerError, "Could not compute block root: %v", err)
+		}
+	}
+	return blks, roots, nil
+}
+
+// isCanonical returns true if the block at the given slot is part of the chain of the head block.
+func (s *Server) isCanonical(ctx context.Context, root [32]byte, slot uint64) (bool, *apiError) {
+	if s.BeaconDB.IsFinalizedBlock(ctx, root) {
+		return true, nil
+	}
+	headSlot := s.HeadFetcher.HeadSlot()
+	if slot > headSlot || headSlot-slot >= params.BeaconConfig().SlotsPerHistoricalRoot {
+		return false, nil
+	}
+	if slot == headSlot {
+		headRoot, err := s.HeadFetcher.HeadRoot(ctx)
+		if err != nil {
+			return false, newError(http.StatusInternalServerError, "Could not retrieve head root: %v", err)
+		}
+		return bytes.Equal(headRoot, root[:]), nil
+	}
+	headState, apiErr := s.headState(ctx)
+	if apiErr != nil {
+		return false, apiErr
+	}
+	rootAtSlot, err := helpers.BlockRootAtSlot(headState, slot)
+	if err != nil {
+		return false, newError(http.StatusInternalServerError, "Could not retrieve block root at slot %d: %v", slot, err)
+	}
+	return bytes.Equal(rootAtSlot, root[:]), nil
+}
+
+func parseRoot(str string) ([32]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(b) != 32 {
+		return [32]byte{}, errInvalidRootLength
+	}
+	return bytesutil.ToBytes32(b), nil
+}
+
+func (s *Server) childBlocks(ctx context.Context, parentRoot [32]byte) ([]*ethpb.SignedBeaconBlock, [][32]byte, *apiError) {
+	blks, err := s.BeaconDB.Blocks(ctx, filters.NewFilter().SetParentRoot(parentRoot[:]))
+	if err != nil {
+		return nil, nil, newError(http.StatusInternalServerError, "Could not retrieve blocks: %v", err)
+	}
+	roots := make([][32]byte, len(blks))
+	for i, blk := range blks {
+		roots[i], err = stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, nil, newError(http.StatusInternalServerError, "Could not compute block root: %v", err)
+		}
+	}
+	return blks, roots, nil
+}
@@ -0,0 +1,199 @@
+package beaconapi
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/shared/version"
+)
+
+type identityJSON struct {
+	PeerID             string        `json:"peer_id"`
+	ENR                string        `json:"enr"`
+	P2PAddresses       []string      `json:"p2p_addresses"`
+	DiscoveryAddresses []string      `json:"discovery_addresses"`
+	Metadata           *metadataJSON `json:"metadata"`
+}
+
+type metadataJSON struct {
+	SeqNumber string `json:"seq_number"`
+	Attnets   string `json:"attnets"`
+}
+
+type peerJSON struct {
+	PeerID             string `json:"peer_id"`
+	ENR                string `json:"enr"`
+	LastSeenP2PAddress string `json:"last_seen_p2p_address"`
+	State              string `json:"state"`
+	Direction          string `json:"direction"`
+}
+
+type versionJSON struct {
+	Version string `json:"version"`
+}
+
+type syncingJSON struct {
+	HeadSlot     string `json:"head_slot"`
+	SyncDistance string `json:"sync_distance"`
+	IsSyncing    bool   `json:"is_syncing"`
+}
+
+func (s *Server) getIdentity(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	pid := s.PeerManager.PeerID()
+	res := &identityJSON{
+		PeerID:             pid.Pretty(),
+		P2PAddresses:       make([]string, 0),
+		DiscoveryAddresses: make([]string, 0),
+	}
+	for _, addr := range s.IdentityProvider.HostAddrs() {
+		res.P2PAddresses = append(res.P2PAddresses, fmt.Sprintf("%s/p2p/%s", addr.String(), pid.Pretty()))
+	}
+	if record := s.IdentityProvider.ENR(); record != nil {
+		if str, err := enrString(record); err == nil {
+			res.ENR = str
+		}
+		if addr, ok := discoveryAddress(record, pid); ok {
+			res.DiscoveryAddresses = append(res.DiscoveryAddresses, addr)
+		}
+	}
+	if md := s.MetadataProvider.Metadata(); md != nil {
+		res.Metadata = &metadataJSON{
+			SeqNumber: strconv.FormatUint(md.SeqNumber, 10),
+			Attnets:   hexString(md.Attnets),
+		}
+	}
+	writeData(w, res)
+	return nil
+}
+
+func (s *Server) listPeers(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	states := make(map[string]bool)
+	for _, state := range queryList(r, "state") {
+		states[state] = true
+	}
+	directions := make(map[string]bool)
+	for _, direction := range queryList(r, "direction") {
+		directions[direction] = true
+	}
+
+	res := make([]*peerJSON, 0)
+	for _, pid := range s.PeersFetcher.Peers().All() {
+		p := s.peerInfo(pid)
+		if len(states) > 0 && !states[p.State] {
+			continue
+		}
+		if len(directions) > 0 && !directions[p.Direction] {
+			continue
+		}
+		res = append(res, p)
+	}
+	writeData(w, res)
+	return nil
+}
+
+func (s *Server) getPeer(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	pid, err := peer.IDB58Decode(vars["peer_id"])
+	if err != nil {
+		return newError(http.StatusBadRequest, "Invalid peer id %s: %v", vars["peer_id"], err)
+	}
+	if _, err := s.PeersFetcher.Peers().ConnectionState(pid); err != nil {
+		return newError(http.StatusNotFound, "Peer %s not found", vars["peer_id"])
+	}
+	writeData(w, s.peerInfo(pid))
+	return nil
+}
+
+func (s *Server) peerInfo(pid peer.ID) *peerJSON {
+	status := s.PeersFetcher.Peers()
+	res := &peerJSON{PeerID: pid.Pretty()}
+	if record, err := status.ENR(pid); err == nil && record != nil {
+		if str, err := enrString(record); err == nil {
+			res.ENR = str
+		}
+	}
+	if addr, err := status.Address(pid); err == nil && addr != nil {
+		res.LastSeenP2PAddress = fmt.Sprintf("%s/p2p/%s", addr.String(), pid.Pretty())
+	}
+	state, err := status.ConnectionState(pid)
+	if err == nil {
+		switch state {
+		case peers.PeerConnecting:
+			res.State = "connecting"
+		case peers.PeerConnected:
+			res.State = "connected"
+		case peers.PeerDisconnecting:
+			res.State = "disconnecting"
+		default:
+			res.State = "disconnected"
+		}
+	}
+	direction, err := status.Direction(pid)
+	if err == nil {
+		switch direction {
+		case network.DirInbound:
+			res.Direction = "inbound"
+		case network.DirOutbound:
+			res.Direction = "outbound"
+		}
+	}
+	return res
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	writeData(w, &versionJSON{Version: version.GetVersion()})
+	return nil
+}
+
+func (s *Server) getSyncStatus(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	headSlot := s.HeadFetcher.HeadSlot()
+	var distance uint64
+	if currentSlot := s.GenesisTimeFetcher.CurrentSlot(); currentSlot > headSlot {
+		distance = currentSlot - headSlot
+	}
+	writeData(w, &syncingJSON{
+		HeadSlot:     strconv.FormatUint(headSlot, 10),
+		SyncDistance: strconv.FormatUint(distance, 10),
+		IsSyncing:    s.SyncChecker.Syncing(),
+	})
+	return nil
+}
+
+// getHealth responds with 200 if the node is synced, and with 206 while it is syncing.
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	if s.SyncChecker.Syncing() {
+		w.WriteHeader(http.StatusPartialContent)
+		return nil
+	}
+	writeOK(w)
+	return nil
+}
+
+// enrString returns the text representation of a node record.
+func enrString(record *enr.Record) (string, error) {
+	enc, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		return "", err
+	}
+	return "enr:" + base64.RawURLEncoding.EncodeToString(enc), nil
+}
+
+// discoveryAddress returns the discv5 address advertised in a node record.
+func discoveryAddress(record *enr.Record, pid peer.ID) (string, bool) {
+	var ip enr.IPv4
+	var udp enr.UDP
+	if err := record.Load(&ip); err != nil {
+		return "", false
+	}
+	if err := record.Load(&udp); err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("/ip4/%s/udp/%d/p2p/%s", net.IP(ip).String(), udp, pid.Pretty()), true
+}
@@ -0,0 +1,299 @@
+// Package beaconapi defines an HTTP server implementing the standard Eth2 beacon node REST API,
+// serving the beacon, node and validator namespaces directly from the services of the node.
+package beaconapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/slashings"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logrus.WithField("prefix", "beacon-api")
+
+const (
+	jsonMediaType = "application/json"
+	sszMediaType  = "application/octet-stream"
+	// maxRequestBodySize bounds the size of the request bodies read by the server.
+	maxRequestBodySize = 10 << 20
+)
+
+// Server defines an HTTP server implementation of the standard Eth2 beacon node API.
+// Block production, attestation data and the submission of operations are delegated
+// to the gRPC servers of the node, so both APIs apply the same validation.
+type Server struct {
+	BeaconDB            db.ReadOnlyDatabase
+	HeadFetcher         blockchain.HeadFetcher
+	FinalizationFetcher blockchain.FinalizationFetcher
+	GenesisTimeFetcher  blockchain.TimeFetcher
+	GenesisFetcher      blockchain.GenesisFetcher
+	StateGen            *stategen.State
+	AttestationsPool    attestations.Pool
+	SlashingsPool       *slashings.Pool
+	ExitPool            *voluntaryexits.Pool
+	PeersFetcher        p2p.PeersProvider
+	PeerManager         p2p.PeerManager
+	IdentityProvider    p2p.IdentityProvider
+	MetadataProvider    p2p.MetadataProvider
+	SyncChecker         sync.Checker
+	ValidatorServer     ethpb.BeaconNodeValidatorServer
+	BeaconChainServer   ethpb.BeaconChainServer
+	MaxStateReplaySlots uint64
+}
+
+// apiError is an error carrying the HTTP status code to respond with.
+type apiError struct {
+	code     int
+	msg      string
+	failures []*indexedFailure
+}
+
+// indexedFailure describes why one of the objects of a request submitting a list of objects failed.
+type indexedFailure struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+func newError(code int, format string, args ...interface{}) *apiError {
+	return &apiError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// fromGRPCError converts an error returned by one of the gRPC servers of the node.
+func fromGRPCError(err error) *apiError {
+	st, ok := status.FromError(err)
+	if !ok {
+		return newError(http.StatusInternalServerError, "%v", err)
+	}
+	code := http.StatusInternalServerError
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		code = http.StatusBadRequest
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.Unavailable:
+		code = http.StatusServiceUnavailable
+	}
+	return newError(code, "%s", st.Message())
+}
+
+// handlerFunc handles a request matched to a route, given the path parameters of the route.
+type handlerFunc func(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// Handler returns the HTTP handler serving all the endpoints of the API.
+func (s *Server) Handler() http.Handler {
+	routes := []*route{
+		// Beacon namespace.
+		newRoute(http.MethodGet, "/eth/v1/beacon/genesis", s.getGenesis),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/root", s.getStateRoot),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/fork", s.getStateFork),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/finality_checkpoints", s.getFinalityCheckpoints),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators", s.listValidators),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators/{validator_id}", s.getValidator),
+		newRoute(http.MethodGet, "/eth/v1/beacon/states/{state_id}/committees", s.listCommittees),
+		newRoute(http.MethodGet, "/eth/v1/beacon/headers", s.listBlockHeaders),
+		newRoute(http.MethodGet, "/eth/v1/beacon/headers/{block_id}", s.getBlockHeader),
+		newRoute(http.MethodPost, "/eth/v1/beacon/blocks", s.submitBlock),
+		newRoute(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}", s.getBlock),
+		newRoute(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/root", s.getBlockRoot),
+		newRoute(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/attestations", s.listBlockAttestations),
+		newRoute(http.MethodGet, "/eth/v1/beacon/pool/attestations", s.listPoolAttestations),
+		newRoute(http.MethodPost, "/eth/v1/beacon/pool/attestations", s.submitAttestations),
+		newRoute(http.MethodGet, "/eth/v1/beacon/pool/attester_slashings", s.listPoolAttesterSlashings),
+		newRoute(http.MethodPost, "/eth/v1/beacon/pool/attester_slashings", s.submitAttesterSlashing),
+		newRoute(http.MethodGet, "/eth/v1/beacon/pool/proposer_slashings", s.listPoolProposerSlashings),
+		newRoute(http.MethodPost, "/eth/v1/beacon/pool/proposer_slashings", s.submitProposerSlashing),
+		newRoute(http.MethodGet, "/eth/v1/beacon/pool/voluntary_exits", s.listPoolVoluntaryExits),
+		newRoute(http.MethodPost, "/eth/v1/beacon/pool/voluntary_exits", s.submitVoluntaryExit),
+		newRoute(http.MethodGet, "/eth/v1/debug/beacon/states/{state_id}", s.getState),
+		// Node namespace.
+		newRoute(http.MethodGet, "/eth/v1/node/identity", s.getIdentity),
+		newRoute(http.MethodGet, "/eth/v1/node/peers", s.listPeers),
+		newRoute(http.MethodGet, "/eth/v1/node/peers/{peer_id}", s.getPeer),
+		newRoute(http.MethodGet, "/eth/v1/node/version", s.getVersion),
+		newRoute(http.MethodGet, "/eth/v1/node/syncing", s.getSyncStatus),
+		newRoute(http.MethodGet, "/eth/v1/node/health", s.getHealth),
+		// Validator namespace.
+		newRoute(http.MethodGet, "/eth/v1/validator/duties/attester/{epoch}", s.getAttesterDuties),
+		newRoute(http.MethodGet, "/eth/v1/validator/duties/proposer/{epoch}", s.getProposerDuties),
+		newRoute(http.MethodGet, "/eth/v1/validator/blocks/{slot}", s.produceBlock),
+		newRoute(http.MethodGet, "/eth/v1/validator/attestation_data", s.produceAttestationData),
+		newRoute(http.MethodGet, "/eth/v1/validator/aggregate_attestation", s.getAggregateAttestation),
+		newRoute(http.MethodPost, "/eth/v1/validator/aggregate_and_proofs", s.submitAggregateAndProofs),
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		segments := splitPath(r.URL.Path)
+		pathMatched := false
+		for _, rt := range routes {
+			params, ok := rt.match(segments)
+			if !ok {
+				continue
+			}
+			pathMatched = true
+			if rt.method != r.Method {
+				continue
+			}
+			if err := rt.handler(w, r, params); err != nil {
+				writeError(w, err)
+			}
+			return
+		}
+		if pathMatched {
+			writeError(w, newError(http.StatusMethodNotAllowed, "Method %s not allowed", r.Method))
+			return
+		}
+		writeError(w, newError(http.StatusNotFound, "Endpoint %s not found", r.URL.Path))
+	})
+}
+
+func newRoute(method string, path string, handler handlerFunc) *route {
+	return &route{method: method, segments: splitPath(path), handler: handler}
+}
+
+// match returns the path parameters of the route if the path segments match it.
+func (rt *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params[seg[1:len(seg)-1]] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// dataResponse is the envelope of all the successful JSON responses of the API.
+type dataResponse struct {
+	Data interface{} `json:"data"`
+}
+
+type errorResponse struct {
+	Code     int               `json:"code"`
+	Message  string            `json:"message"`
+	Failures []*indexedFailure `json:"failures,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", jsonMediaType)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, &dataResponse{Data: data})
+}
+
+// writeOK acknowledges a request which has no data to respond with.
+func writeOK(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	writeJSON(w, err.code, &errorResponse{Code: err.code, Message: err.msg, Failures: err.failures})
+}
+
+// writeObject writes a protobuf object either SSZ encoded or in its JSON representation,
+// depending on the media types accepted by the client.
+func writeObject(w http.ResponseWriter, r *http.Request, obj proto.Message) *apiError {
+	if !acceptsSSZ(r) {
+		writeData(w, encodeJSON(obj))
+		return nil
+	}
+	enc, err := ssz.Marshal(obj)
+	if err != nil {
+		return newError(http.StatusInternalServerError, "Could not encode response: %v", err)
+	}
+	w.Header().Set("Content-Type", sszMediaType)
+	if _, err := w.Write(enc); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+	return nil
+}
+
+// acceptsSSZ returns true if the client prefers SSZ encoded responses to JSON ones.
+func acceptsSSZ(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case sszMediaType:
+			return true
+		case jsonMediaType:
+			return false
+		}
+	}
+	return false
+}
+
+// readObject decodes the request body into a protobuf object, either from SSZ or from its JSON
+// representation depending on the content type of the request.
+func readObject(r *http.Request, obj proto.Message) *apiError {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		return newError(http.StatusBadRequest, "Could not read request body: %v", err)
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == sszMediaType {
+		if err := ssz.Unmarshal(body, obj); err != nil {
+			return newError(http.StatusBadRequest, "Could not decode SSZ request body: %v", err)
+		}
+		return nil
+	}
+	if err := decodeJSON(body, obj); err != nil {
+		return newError(http.StatusBadRequest, "Could not decode request body: %v", err)
+	}
+	return nil
+}
+
+// readJSON decodes a request body holding a JSON value of any type, such as a list of objects.
+func readJSON(r *http.Request, v interface{}) *apiError {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		return newError(http.StatusBadRequest, "Could not read request body: %v", err)
+	}
+	if err := decodeJSON(body, v); err != nil {
+		return newError(http.StatusBadRequest, "Could not decode request body: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package beaconapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func doRequest(t *testing.T, s *Server, method string, target string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeData(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	res := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(res.Data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServer_Routing(t *testing.T) {
+	s := &Server{SyncChecker: &mockSync.Sync{IsSyncing: false}}
+
+	rec := doRequest(t, s, http.MethodGet, "/eth/v1/node/health", "")
+	if rec.Code != http.StatusOK {
+		t.Errorf("Wanted status %d, received %d", http.StatusOK, rec.Code)
+	}
+	rec = doRequest(t, s, http.MethodPost, "/eth/v1/node/health", "")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Wanted status %d, received %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/node/unknown", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, rec.Code)
+	}
+
+	s.SyncChecker = &mockSync.Sync{IsSyncing: true}
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/node/health", "")
+	if rec.Code != http.StatusPartialContent {
+		t.Errorf("Wanted status %d, received %d", http.StatusPartialContent, rec.Code)
+	}
+}
+
+func TestServer_GetGenesis(t *testing.T) {
+	root := [32]byte{'a'}
+	chain := &mock.ChainService{Genesis: time.Unix(100, 0), ValidatorsRoot: root}
+	s := &Server{GenesisTimeFetcher: chain, GenesisFetcher: chain}
+
+	rec := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/genesis", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	res := &genesisJSON{}
+	decodeData(t, rec, res)
+	if res.GenesisTime != "100" {
+		t.Errorf("Wanted genesis time %s, received %s", "100", res.GenesisTime)
+	}
+	if res.GenesisValidatorsRoot != hexString(root[:]) {
+		t.Errorf("Wanted genesis validators root %s, received %s", hexString(root[:]), res.GenesisValidatorsRoot)
+	}
+
+	s.GenesisTimeFetcher = &mock.ChainService{}
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/genesis", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestServer_GetBlock(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 0, Body: &ethpb.BeaconBlockBody{}}}
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: genesisRoot[:], Body: &ethpb.BeaconBlockBody{}}}
+	headRoot, err := stateutil.BlockRoot(head.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlocks(ctx, []*ethpb.SignedBeaconBlock{genesis, head}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	st, _ := testutil.DeterministicGenesisState(t, 1)
+	if err := st.SetSlot(1); err != nil {
+		t.Fatal(err)
+	}
+	chain := &mock.ChainService{
+		State:               st,
+		Root:                headRoot[:],
+		Block:               head,
+		FinalizedCheckPoint: &ethpb.Checkpoint{Root: genesisRoot[:]},
+	}
+	s := &Server{BeaconDB: db, HeadFetcher: chain, FinalizationFetcher: chain}
+
+	tests := []struct {
+		id   string
+		want *ethpb.SignedBeaconBlock
+	}{
+		{id: "head", want: head},
+		{id: "genesis", want: genesis},
+		{id: "finalized", want: genesis},
+		{id: "1", want: head},
+		{id: hexString(genesisRoot[:]), want: genesis},
+	}
+	for _, tt := range tests {
+		rec := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/"+tt.id, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Block %s: wanted status %d, received %d: %s", tt.id, http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var data json.RawMessage
+		decodeData(t, rec, &data)
+		blk := &ethpb.SignedBeaconBlock{}
+		if err := decodeJSON(data, blk); err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(blk, tt.want) {
+			t.Errorf("Block %s: wanted %v, received %v", tt.id, tt.want, blk)
+		}
+	}
+
+	rec := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/head", sszMediaType)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, rec.Code)
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := ssz.Unmarshal(rec.Body.Bytes(), blk); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(blk, head) {
+		t.Errorf("Wanted %v, received %v", head, blk)
+	}
+
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/head/root", "")
+	res := &rootJSON{}
+	decodeData(t, rec, res)
+	if res.Root != hexString(headRoot[:]) {
+		t.Errorf("Wanted root %s, received %s", hexString(headRoot[:]), res.Root)
+	}
+
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/0x1234", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d, received %d", http.StatusBadRequest, rec.Code)
+	}
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/2", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestServer_ListValidators(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 4)
+	s := &Server{HeadFetcher: &mock.ChainService{State: st}}
+
+	rec := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators?id=1,3", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var res []*validatorJSON
+	decodeData(t, rec, &res)
+	if len(res) != 2 {
+		t.Fatalf("Wanted %d validators, received %d", 2, len(res))
+	}
+	if res[0].Index != "1" || res[1].Index != "3" {
+		t.Errorf("Wanted validators 1 and 3, received %s and %s", res[0].Index, res[1].Index)
+	}
+	if res[0].Status != "active_ongoing" {
+		t.Errorf("Wanted status %s, received %s", "active_ongoing", res[0].Status)
+	}
+
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators?status=pending_queued", "")
+	res = nil
+	decodeData(t, rec, &res)
+	if len(res) != 0 {
+		t.Errorf("Wanted no validators, received %d", len(res))
+	}
+
+	rec = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators/10", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestValidatorStatus(t *testing.T) {
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	tests := []struct {
+		validator *ethpb.Validator
+		epoch     uint64
+		want      string
+	}{
+		{
+			validator: &ethpb.Validator{ActivationEligibilityEpoch: farFuture, ActivationEpoch: farFuture, ExitEpoch: farFuture, WithdrawableEpoch: farFuture},
+			want:      "pending_initialized",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEligibilityEpoch: 1, ActivationEpoch: 5, ExitEpoch: farFuture, WithdrawableEpoch: farFuture},
+			epoch:     2,
+			want:      "pending_queued",
+		},
+		{
+			validator: &ethpb.Validator{ExitEpoch: farFuture, WithdrawableEpoch: farFuture},
+			epoch:     2,
+			want:      "active_ongoing",
+		},
+		{
+			validator: &ethpb.Validator{ExitEpoch: 10, WithdrawableEpoch: 20},
+			epoch:     2,
+			want:      "active_exiting",
+		},
+		{
+			validator: &ethpb.Validator{ExitEpoch: 10, WithdrawableEpoch: 20, Slashed: true},
+			epoch:     2,
+			want:      "active_slashed",
+		},
+		{
+			validator: &ethpb.Validator{ExitEpoch: 10, WithdrawableEpoch: 20},
+			epoch:     12,
+			want:      "exited_unslashed",
+		},
+		{
+			validator: &ethpb.Validator{ExitEpoch: 10, WithdrawableEpoch: 20, Slashed: true},
+			epoch:     12,
+			want:      "exited_slashed",
+		},
+		{
+			validator: &ethpb.Validator{ExitEpoch: 10, WithdrawableEpoch: 20, EffectiveBalance: 1},
+			epoch:     25,
+			want:      "withdrawal_possible",
+		},
+		{
+			validator: &ethpb.Validator{ExitEpoch: 10, WithdrawableEpoch: 20},
+			epoch:     25,
+			want:      "withdrawal_done",
+		},
+	}
+	for _, tt := range tests {
+		if got := validatorStatus(tt.validator, tt.epoch); got != tt.want {
+			t.Errorf("validatorStatus() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestServer_StateByRoot(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+
+	genesis, _ := testutil.DeterministicGenesisState(t, 4)
+	genesisBlockRoot := [32]byte{'a'}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisBlockRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, genesis, genesisBlockRoot); err != nil {
+		t.Fatal(err)
+	}
+	genesisRoot, err := genesis.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := genesis.Copy()
+	if err := head.SetSlot(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := head.UpdateStateRootAtIndex(0, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	headRoot, err := head.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{BeaconDB: db, HeadFetcher: &mock.ChainService{State: head}}
+
+	st, apiErr := s.stateByRoot(ctx, headRoot)
+	if apiErr != nil {
+		t.Fatal(apiErr)
+	}
+	if st.Slot() != 1 {
+		t.Errorf("Wanted the head state, received the state at slot %d", st.Slot())
+	}
+	st, apiErr = s.stateByRoot(ctx, genesisRoot)
+	if apiErr != nil {
+		t.Fatal(apiErr)
+	}
+	if st.Slot() != 0 {
+		t.Errorf("Wanted the genesis state, received the state at slot %d", st.Slot())
+	}
+
+	// A block root is not a state root.
+	_, apiErr = s.stateByRoot(ctx, genesisBlockRoot)
+	if apiErr == nil || apiErr.code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %v", http.StatusNotFound, apiErr)
+	}
+}
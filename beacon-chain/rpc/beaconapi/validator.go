@@ -0,0 +1,248 @@
+package beaconapi
+
+import (
+	"net/http"
+	"strconv"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+type attesterDutyJSON struct {
+	Pubkey                  string `json:"pubkey"`
+	ValidatorIndex          string `json:"validator_index"`
+	CommitteeIndex          string `json:"committee_index"`
+	CommitteeLength         string `json:"committee_length"`
+	CommitteesAtSlot        string `json:"committees_at_slot"`
+	ValidatorCommitteeIndex string `json:"validator_committee_index"`
+	Slot                    string `json:"slot"`
+}
+
+type proposerDutyJSON struct {
+	Pubkey         string `json:"pubkey"`
+	ValidatorIndex string `json:"validator_index"`
+	Slot           string `json:"slot"`
+}
+
+func (s *Server) getAttesterDuties(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	epoch, st, apiErr := s.dutiesState(r, vars["epoch"])
+	if apiErr != nil {
+		return apiErr
+	}
+	indices := queryList(r, "index")
+	if len(indices) == 0 {
+		return newError(http.StatusBadRequest, "At least one validator index must be requested")
+	}
+	assignments, _, err := helpers.CommitteeAssignments(st, epoch)
+	if err != nil {
+		return newError(http.StatusInternalServerError, "Could not compute committee assignments: %v", err)
+	}
+	activeCount, err := helpers.ActiveValidatorCount(st, epoch)
+	if err != nil {
+		return newError(http.StatusInternalServerError, "Could not count active validators: %v", err)
+	}
+	committeesAtSlot := helpers.SlotCommitteeCount(activeCount)
+
+	res := make([]*attesterDutyJSON, 0, len(indices))
+	for _, id := range indices {
+		idx, apiErr := validatorIndexByID(st, id)
+		if apiErr != nil {
+			return apiErr
+		}
+		assignment, ok := assignments[idx]
+		if !ok {
+			continue
+		}
+		var position int
+		for i, member := range assignment.Committee {
+			if member == idx {
+				position = i
+				break
+			}
+		}
+		pubKey := st.PubkeyAtIndex(idx)
+		res = append(res, &attesterDutyJSON{
+			Pubkey:                  hexString(pubKey[:]),
+			ValidatorIndex:          strconv.FormatUint(idx, 10),
+			CommitteeIndex:          strconv.FormatUint(assignment.CommitteeIndex, 10),
+			CommitteeLength:         strconv.Itoa(len(assignment.Committee)),
+			CommitteesAtSlot:        strconv.FormatUint(committeesAtSlot, 10),
+			ValidatorCommitteeIndex: strconv.Itoa(position),
+			Slot:                    strconv.FormatUint(assignment.AttesterSlot, 10),
+		})
+	}
+	writeData(w, res)
+	return nil
+}
+
+func (s *Server) getProposerDuties(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	epoch, st, apiErr := s.dutiesState(r, vars["epoch"])
+	if apiErr != nil {
+		return apiErr
+	}
+	_, proposerSlots, err := helpers.CommitteeAssignments(st, epoch)
+	if err != nil {
+		return newError(http.StatusInternalServerError, "Could not compute proposer assignments: %v", err)
+	}
+	proposers := make(map[uint64]uint64)
+	for idx, slots := range proposerSlots {
+		for _, slot := range slots {
+			proposers[slot] = idx
+		}
+	}
+	res := make([]*proposerDutyJSON, 0, len(proposers))
+	startSlot := helpers.StartSlot(epoch)
+	for slot := startSlot; slot < startSlot+params.BeaconConfig().SlotsPerEpoch; slot++ {
+		idx, ok := proposers[slot]
+		if !ok {
+			continue
+		}
+		pubKey := st.PubkeyAtIndex(idx)
+		res = append(res, &proposerDutyJSON{
+			Pubkey:         hexString(pubKey[:]),
+			ValidatorIndex: strconv.FormatUint(idx, 10),
+			Slot:           strconv.FormatUint(slot, 10),
+		})
+	}
+	writeData(w, res)
+	return nil
+}
+
+// dutiesState returns a copy of the head state advanced to the start of the requested epoch,
+// from which the duties of the epoch can be computed.
+func (s *Server) dutiesState(r *http.Request, epochParam string) (uint64, *stateTrie.BeaconState, *apiError) {
+	if s.SyncChecker.Syncing() {
+		return 0, nil, newError(http.StatusServiceUnavailable, "Syncing to latest head, not ready to respond")
+	}
+	epoch, err := strconv.ParseUint(epochParam, 10, 64)
+	if err != nil {
+		return 0, nil, newError(http.StatusBadRequest, "Invalid epoch %s", epochParam)
+	}
+	st, apiErr := s.headState(r.Context())
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	if epoch > helpers.NextEpoch(st) {
+		return 0, nil, newError(http.StatusBadRequest, "Epoch %d is after the next epoch %d", epoch, helpers.NextEpoch(st))
+	}
+	st = st.Copy()
+	if startSlot := helpers.StartSlot(epoch); st.Slot() < startSlot {
+		st, err = state.ProcessSlots(r.Context(), st, startSlot)
+		if err != nil {
+			return 0, nil, newError(http.StatusInternalServerError, "Could not process slots up to %d: %v", startSlot, err)
+		}
+	}
+	return epoch, st, nil
+}
+
+func (s *Server) produceBlock(w http.ResponseWriter, r *http.Request, vars map[string]string) *apiError {
+	slot, err := strconv.ParseUint(vars["slot"], 10, 64)
+	if err != nil {
+		return newError(http.StatusBadRequest, "Invalid slot %s", vars["slot"])
+	}
+	query := r.URL.Query()
+	randaoReveal, err := decodeHex(query.Get("randao_reveal"), "randao_reveal")
+	if err != nil {
+		return newError(http.StatusBadRequest, "Invalid randao reveal: %v", err)
+	}
+	var graffiti []byte
+	if query.Get("graffiti") != "" {
+		graffiti, err = decodeHex(query.Get("graffiti"), "graffiti")
+		if err != nil {
+			return newError(http.StatusBadRequest, "Invalid graffiti: %v", err)
+		}
+	}
+	blk, err := s.ValidatorServer.GetBlock(r.Context(), &ethpb.BlockRequest{
+		Slot:         slot,
+		RandaoReveal: randaoReveal,
+		Graffiti:     graffiti,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+	return writeObject(w, r, blk)
+}
+
+func (s *Server) produceAttestationData(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	if r.URL.Query().Get("slot") == "" || r.URL.Query().Get("committee_index") == "" {
+		return newError(http.StatusBadRequest, "Slot and committee index must be set")
+	}
+	slot, apiErr := queryUint(r, "slot", 0)
+	if apiErr != nil {
+		return apiErr
+	}
+	committeeIndex, apiErr := queryUint(r, "committee_index", 0)
+	if apiErr != nil {
+		return apiErr
+	}
+	data, err := s.ValidatorServer.GetAttestationData(r.Context(), &ethpb.AttestationDataRequest{
+		Slot:           slot,
+		CommitteeIndex: committeeIndex,
+	})
+	if err != nil {
+		return fromGRPCError(err)
+	}
+	writeData(w, encodeJSON(data))
+	return nil
+}
+
+// getAggregateAttestation returns the aggregated attestation of the pool with the most votes
+// for the requested attestation data.
+func (s *Server) getAggregateAttestation(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	dataRoot, err := parseRoot(r.URL.Query().Get("attestation_data_root"))
+	if err != nil {
+		return newError(http.StatusBadRequest, "Invalid attestation data root: %v", err)
+	}
+	slot, apiErr := queryUint(r, "slot", 0)
+	if apiErr != nil {
+		return apiErr
+	}
+	var best *ethpb.Attestation
+	for _, att := range s.AttestationsPool.AggregatedAttestations() {
+		if att.Data.Slot != slot {
+			continue
+		}
+		root, err := ssz.HashTreeRoot(att.Data)
+		if err != nil {
+			return newError(http.StatusInternalServerError, "Could not compute attestation data root: %v", err)
+		}
+		if root != dataRoot {
+			continue
+		}
+		if best == nil || att.AggregationBits.Count() > best.AggregationBits.Count() {
+			best = att
+		}
+	}
+	if best == nil {
+		return newError(http.StatusNotFound, "No aggregated attestation found for data root %#x", dataRoot)
+	}
+	writeData(w, encodeJSON(best))
+	return nil
+}
+
+func (s *Server) submitAggregateAndProofs(w http.ResponseWriter, r *http.Request, _ map[string]string) *apiError {
+	var aggregates []*ethpb.SignedAggregateAttestationAndProof
+	if apiErr := readJSON(r, &aggregates); apiErr != nil {
+		return apiErr
+	}
+	var failures []*indexedFailure
+	for i, aggregate := range aggregates {
+		_, err := s.ValidatorServer.SubmitSignedAggregateSelectionProof(r.Context(), &ethpb.SignedAggregateSubmitRequest{
+			SignedAggregateAndProof: aggregate,
+		})
+		if err != nil {
+			failures = append(failures, &indexedFailure{Index: i, Message: fromGRPCError(err).msg})
+		}
+	}
+	if len(failures) > 0 {
+		apiErr := newError(http.StatusBadRequest, "Some aggregates failed to be submitted")
+		apiErr.failures = failures
+		return apiErr
+	}
+	writeOK(w)
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package checkpoint defines a gRPC server serving the finalized state and block of the
+// beacon node to other nodes starting with checkpoint sync.
+package checkpoint
+
+import (
+	"context"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server defines a server implementation of the gRPC checkpoint sync service,
+// providing the finalized state and block of the node.
+type Server struct {
+	BeaconDB            db.ReadOnlyDatabase
+	FinalizationFetcher blockchain.FinalizationFetcher
+	StateGen            *stategen.State
+}
+
+// GetFinalizedCheckpoint returns the SSZ encoded finalized block of the node and its post state.
+func (cs *Server) GetFinalizedCheckpoint(ctx context.Context, _ *ptypes.Empty) (*rpcpb.FinalizedCheckpoint, error) {
+	cp := cs.FinalizationFetcher.FinalizedCheckpt()
+	root := bytesutil.ToBytes32(cp.Root)
+
+	blk, err := cs.BeaconDB.Block(ctx, root)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not retrieve finalized block: %v", err)
+	}
+	if blk == nil {
+		return nil, status.Errorf(codes.NotFound, "Finalized block %#x not found", root)
+	}
+	var st *stateTrie.BeaconState
+	if featureconfig.Get().NewStateMgmt {
+		st, err = cs.StateGen.StateByRoot(ctx, root)
+	} else {
+		st, err = cs.BeaconDB.State(ctx, root)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not retrieve finalized state: %v", err)
+	}
+	if st == nil {
+		return nil, status.Errorf(codes.NotFound, "Finalized state %#x not found", root)
+	}
+
+	encodedState, err := ssz.Marshal(st.InnerStateUnsafe())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not encode finalized state: %v", err)
+	}
+	encodedBlock, err := ssz.Marshal(blk)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not encode finalized block: %v", err)
+	}
+	return &rpcpb.FinalizedCheckpoint{
+		Epoch:        cp.Epoch,
+		EncodedState: encodedState,
+		EncodedBlock: encodedBlock,
+		Root:         cp.Root,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package checkpoint
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestServer_GetFinalizedCheckpoint(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+
+	st, _ := testutil.DeterministicGenesisState(t, 16)
+	if err := st.SetSlot(64); err != nil {
+		t.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 64, Body: &ethpb.BeaconBlockBody{}}}
+	root, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, root); err != nil {
+		t.Fatal(err)
+	}
+
+	cs := &Server{
+		BeaconDB:            db,
+		FinalizationFetcher: &mock.ChainService{FinalizedCheckPoint: &ethpb.Checkpoint{Epoch: 2, Root: root[:]}},
+	}
+	res, err := cs.GetFinalizedCheckpoint(ctx, &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Epoch != 2 {
+		t.Errorf("Wanted epoch 2, received %d", res.Epoch)
+	}
+	if !bytes.Equal(res.Root, root[:]) {
+		t.Errorf("Wanted checkpoint root %#x, received %#x", root, res.Root)
+	}
+	decodedState := &pb.BeaconState{}
+	if err := ssz.Unmarshal(res.EncodedState, decodedState); err != nil {
+		t.Fatal(err)
+	}
+	if decodedState.Slot != 64 {
+		t.Errorf("Wanted state at slot 64, received %d", decodedState.Slot)
+	}
+	decodedBlock := &ethpb.SignedBeaconBlock{}
+	if err := ssz.Unmarshal(res.EncodedBlock, decodedBlock); err != nil {
+		t.Fatal(err)
+	}
+	if decodedBlock.Block.Slot != 64 {
+		t.Errorf("Wanted block at slot 64, received %d", decodedBlock.Block.Slot)
+	}
+}
+
+func TestServer_GetFinalizedCheckpoint_MissingBlock(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+
+	cs := &Server{
+		BeaconDB:            db,
+		FinalizationFetcher: &mock.ChainService{FinalizedCheckPoint: &ethpb.Checkpoint{Epoch: 2, Root: []byte{'a'}}},
+	}
+	if _, err := cs.GetFinalizedCheckpoint(context.Background(), &ptypes.Empty{}); err == nil {
+		t.Error("Expected an error for a missing finalized block")
+	}
+}
@@ -0,0 +1,113 @@
+// Package debug defines a gRPC server exposing internal information of the beacon node,
+// such as the scores of its peers or its fork choice store, which is not part of the public
+// beacon chain API.
+package debug
+
+import (
+	"context"
+	"fmt"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server defines a server implementation of the gRPC debug service.
+type Server struct {
+	BeaconDB          db.ReadOnlyDatabase
+	PeersFetcher      p2p.PeersProvider
+	ForkChoiceFetcher blockchain.ForkChoiceFetcher
+}
+
+// ListPeers lists the peers connected to this node along with their scores.
+func (ds *Server) ListPeers(ctx context.Context, _ *ptypes.Empty) (*rpcpb.DebugPeerResponses, error) {
+	peerStatus := ds.PeersFetcher.Peers()
+	res := make([]*rpcpb.DebugPeerResponse, 0)
+	for _, pid := range peerStatus.Connected() {
+		multiaddr, err := peerStatus.Address(pid)
+		if err != nil {
+			continue
+		}
+		direction, err := peerStatus.Direction(pid)
+		if err != nil {
+			continue
+		}
+		components, err := peerStatus.ScoreComponents(pid)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not retrieve score of peer %s: %v", pid.Pretty(), err)
+		}
+		pbDirection := "UNKNOWN"
+		switch direction {
+		case network.DirInbound:
+			pbDirection = "INBOUND"
+		case network.DirOutbound:
+			pbDirection = "OUTBOUND"
+		}
+		componentScores := make(map[string]float64, len(components))
+		for component, score := range components {
+			componentScores[component.String()] = score
+		}
+		res = append(res, &rpcpb.DebugPeerResponse{
+			PeerId:    pid.Pretty(),
+			Address:   fmt.Sprintf("%s/p2p/%s", multiaddr.String(), pid.Pretty()),
+			Direction: pbDirection,
+			ScoreInfo: &rpcpb.ScoreInfo{
+				OverallScore:    peerStatus.Score(pid),
+				ComponentScores: componentScores,
+			},
+		})
+	}
+	return &rpcpb.DebugPeerResponses{Responses: res}, nil
+}
+
+// GetForkChoice returns the current fork choice store of the node, with its nodes and the
+// latest votes of the validators.
+func (ds *Server) GetForkChoice(ctx context.Context, _ *ptypes.Empty) (*rpcpb.ForkChoiceDump, error) {
+	return ds.ForkChoiceFetcher.ForkChoiceDump(), nil
+}
+
+// ListForkChoiceSnapshots lists the fork choice snapshots kept on disk by the node.
+func (ds *Server) ListForkChoiceSnapshots(ctx context.Context, _ *ptypes.Empty) (*rpcpb.ForkChoiceSnapshots, error) {
+	snapshots, err := ds.ForkChoiceFetcher.ForkChoiceSnapshots()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not list fork choice snapshots: %v", err)
+	}
+	return &rpcpb.ForkChoiceSnapshots{Snapshots: snapshots}, nil
+}
+
+// GetForkChoiceSnapshot returns the latest fork choice snapshot taken at or before the requested time.
+func (ds *Server) GetForkChoiceSnapshot(
+	ctx context.Context,
+	req *rpcpb.ForkChoiceSnapshotRequest,
+) (*rpcpb.ForkChoiceDump, error) {
+	dump, err := ds.ForkChoiceFetcher.ForkChoiceSnapshot(req.Timestamp)
+	if err == blockchain.ErrNoForkChoiceSnapshot {
+		return nil, status.Errorf(codes.NotFound, "No fork choice snapshot taken at or before %d", req.Timestamp)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not retrieve fork choice snapshot: %v", err)
+	}
+	return dump, nil
+}
+
+// ListReorgs returns the recent chain reorgs seen by the node, latest first, optionally only
+// those at least as deep as the requested depth.
+func (ds *Server) ListReorgs(ctx context.Context, req *rpcpb.ListReorgsRequest) (*rpcpb.Reorgs, error) {
+	reorgs, err := ds.BeaconDB.Reorgs(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not retrieve reorgs: %v", err)
+	}
+	res := make([]*rpcpb.Reorg, 0, len(reorgs))
+	for i := len(reorgs) - 1; i >= 0; i-- {
+		if reorgs[i].Depth < req.MinDepth {
+			continue
+		}
+		res = append(res, reorgs[i])
+	}
+	return &rpcpb.Reorgs{Reorgs: res}, nil
+}
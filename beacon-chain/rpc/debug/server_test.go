@@ -0,0 +1,116 @@
+package debug
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ptypes "github.com/gogo/protobuf/types"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	mockP2p "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+)
+
+func TestServer_ListPeers(t *testing.T) {
+	peersProvider := &mockP2p.MockPeersProvider{}
+	ds := &Server{
+		PeersFetcher: peersProvider,
+	}
+	bad := peersProvider.Peers().Connected()[0]
+	peersProvider.Peers().Increment(bad, peers.InvalidBlock)
+
+	res, err := ds.ListPeers(context.Background(), &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Responses) != 2 {
+		t.Fatalf("Expected 2 peers, received %d: %v", len(res.Responses), res.Responses)
+	}
+	for _, resp := range res.Responses {
+		info := resp.ScoreInfo
+		if resp.PeerId != bad.Pretty() {
+			if info.OverallScore != 0 {
+				t.Errorf("Expected peer %s to have a score of 0, received %f", resp.PeerId, info.OverallScore)
+			}
+			continue
+		}
+		if info.OverallScore >= 0 {
+			t.Errorf("Expected peer %s to have a negative score, received %f", resp.PeerId, info.OverallScore)
+		}
+		if info.ComponentScores[peers.InvalidBlock.String()] != info.OverallScore {
+			t.Errorf(
+				"Expected the score to be made up of invalid blocks only, received %v",
+				info.ComponentScores,
+			)
+		}
+	}
+}
+
+func TestServer_GetForkChoice(t *testing.T) {
+	dump := &rpcpb.ForkChoiceDump{
+		Timestamp: 100,
+		HeadSlot:  1,
+		Nodes: []*rpcpb.ForkChoiceNode{
+			{Slot: 0, Root: []byte{'a'}, ChildrenRoots: [][]byte{{'b'}}},
+			{Slot: 1, Root: []byte{'b'}, ParentRoot: []byte{'a'}, Weight: 32},
+		},
+		Votes: []*rpcpb.ForkChoiceVote{{ValidatorIndex: 3, NextRoot: []byte{'b'}, Balance: 32}},
+	}
+	ds := &Server{
+		ForkChoiceFetcher: &mock.ChainService{ForkChoice: dump},
+	}
+
+	res, err := ds.GetForkChoice(context.Background(), &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(res, dump) {
+		t.Errorf("Wanted %v, received %v", dump, res)
+	}
+	snapshots, err := ds.ListForkChoiceSnapshots(context.Background(), &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots.Snapshots) != 1 || snapshots.Snapshots[0].Timestamp != dump.Timestamp {
+		t.Errorf("Expected a single snapshot at %d, received %v", dump.Timestamp, snapshots.Snapshots)
+	}
+	if _, err := ds.GetForkChoiceSnapshot(context.Background(), &rpcpb.ForkChoiceSnapshotRequest{Timestamp: 99}); err == nil {
+		t.Error("Expected an error for a time before the first snapshot")
+	}
+	res, err = ds.GetForkChoiceSnapshot(context.Background(), &rpcpb.ForkChoiceSnapshotRequest{Timestamp: 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(res, dump) {
+		t.Errorf("Wanted %v, received %v", dump, res)
+	}
+}
+
+func TestServer_ListReorgs(t *testing.T) {
+	db := dbutil.SetupDB(t)
+	defer dbutil.TeardownDB(t, db)
+	ctx := context.Background()
+	for i, depth := range []uint64{1, 5, 2, 8} {
+		if err := db.SaveReorg(ctx, &rpcpb.Reorg{Timestamp: uint64(i), Depth: depth}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ds := &Server{BeaconDB: db}
+
+	res, err := ds.ListReorgs(ctx, &rpcpb.ListReorgsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Reorgs) != 4 || res.Reorgs[0].Timestamp != 3 {
+		t.Errorf("Expected all reorgs, latest first, received %v", res.Reorgs)
+	}
+	res, err = ds.ListReorgs(ctx, &rpcpb.ListReorgsRequest{MinDepth: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Reorgs) != 2 || res.Reorgs[0].Depth != 8 || res.Reorgs[1].Depth != 5 {
+		t.Errorf("Expected the reorgs at least 5 slots deep, received %v", res.Reorgs)
+	}
+}
@@ -0,0 +1,214 @@
+// Package events defines a gRPC server streaming the events of the beacon node, such as
+// head changes, checkpoint changes, reorgs and received operations, to its subscribers.
+package events
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	blockfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/block"
+	opfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// eventQueueSize is the number of events buffered for each subscriber. Events are dropped
+// for a subscriber once its buffer is full, so a slow subscriber never stalls the feeds.
+const eventQueueSize = 256
+
+var droppedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "events_dropped_total",
+	Help: "Count the number of events dropped because a subscriber did not keep up with the stream.",
+}, []string{"topic"})
+
+// Server defines a server implementation of the gRPC events service.
+type Server struct {
+	Ctx                 context.Context
+	StateNotifier       statefeed.Notifier
+	BlockNotifier       blockfeed.Notifier
+	OperationNotifier   opfeed.Notifier
+	FinalizationFetcher blockchain.FinalizationFetcher
+}
+
+// StreamEvents streams the events of the requested topics, or of all topics if none is requested.
+func (s *Server) StreamEvents(req *rpcpb.StreamEventsRequest, stream rpcpb.Events_StreamEventsServer) error {
+	topics := make(map[rpcpb.EventTopic]bool, len(req.Topics))
+	for _, topic := range req.Topics {
+		topics[topic] = true
+	}
+
+	// Checkpoint events are only sent for checkpoints which change after subscribing.
+	lastFinalized := s.FinalizationFetcher.FinalizedCheckpt()
+	lastJustified := s.FinalizationFetcher.CurrentJustifiedCheckpt()
+
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	blockChannel := make(chan *feed.Event, 1)
+	blockSub := s.BlockNotifier.BlockFeed().Subscribe(blockChannel)
+	defer blockSub.Unsubscribe()
+	opChannel := make(chan *feed.Event, 1)
+	opSub := s.OperationNotifier.OperationFeed().Subscribe(opChannel)
+	defer opSub.Unsubscribe()
+
+	// Events are sent from a separate goroutine, so reading from the feeds never waits on the client.
+	queue := make(chan *rpcpb.Event, eventQueueSize)
+	quit := make(chan struct{})
+	defer close(quit)
+	sendErr := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case e := <-queue:
+				if err := stream.Send(e); err != nil {
+					sendErr <- err
+					return
+				}
+			case <-quit:
+				return
+			}
+		}
+	}()
+	enqueue := func(e *rpcpb.Event) {
+		if len(topics) > 0 && !topics[e.Topic] {
+			return
+		}
+		select {
+		case queue <- e:
+		default:
+			droppedEvents.WithLabelValues(e.Topic.String()).Inc()
+		}
+	}
+
+	for {
+		select {
+		case event := <-stateChannel:
+			switch event.Type {
+			case statefeed.NewHead:
+				data, ok := event.Data.(*statefeed.NewHeadData)
+				if !ok {
+					continue
+				}
+				enqueue(&rpcpb.Event{
+					Topic: rpcpb.EventTopic_HEAD,
+					Data: &rpcpb.Event_Head{Head: &rpcpb.HeadEvent{
+						Slot:            data.Slot,
+						BlockRoot:       data.BlockRoot[:],
+						StateRoot:       data.StateRoot,
+						EpochTransition: data.EpochTransition,
+					}},
+				})
+			case statefeed.Reorg:
+				data, ok := event.Data.(*statefeed.ReorgData)
+				if !ok {
+					continue
+				}
+				enqueue(&rpcpb.Event{
+					Topic: rpcpb.EventTopic_CHAIN_REORG,
+					Data: &rpcpb.Event_Reorg{Reorg: &rpcpb.ReorgEvent{
+						Slot:           data.NewSlot,
+						Depth:          data.Depth,
+						OldHeadBlock:   data.OldHeadRoot[:],
+						OldHeadSlot:    data.OldSlot,
+						NewHeadBlock:   data.NewHeadRoot[:],
+						CommonAncestor: data.CommonAncestorRoot[:],
+					}},
+				})
+			case statefeed.BlockProcessed:
+				finalized := s.FinalizationFetcher.FinalizedCheckpt()
+				if checkpointChanged(lastFinalized, finalized) {
+					lastFinalized = finalized
+					enqueue(checkpointEvent(rpcpb.EventTopic_FINALIZED_CHECKPOINT, finalized))
+				}
+				justified := s.FinalizationFetcher.CurrentJustifiedCheckpt()
+				if checkpointChanged(lastJustified, justified) {
+					lastJustified = justified
+					enqueue(checkpointEvent(rpcpb.EventTopic_JUSTIFIED_CHECKPOINT, justified))
+				}
+			}
+		case event := <-blockChannel:
+			if event.Type != blockfeed.ReceivedBlock {
+				continue
+			}
+			data, ok := event.Data.(*blockfeed.ReceivedBlockData)
+			if !ok || data.SignedBlock == nil || data.SignedBlock.Block == nil {
+				continue
+			}
+			root, err := stateutil.BlockRoot(data.SignedBlock.Block)
+			if err != nil {
+				return status.Errorf(codes.Internal, "Could not get block root: %v", err)
+			}
+			enqueue(&rpcpb.Event{
+				Topic: rpcpb.EventTopic_BLOCK,
+				Data: &rpcpb.Event_Block{Block: &rpcpb.BlockEvent{
+					Slot:      data.SignedBlock.Block.Slot,
+					BlockRoot: root[:],
+				}},
+			})
+		case event := <-opChannel:
+			switch event.Type {
+			case opfeed.UnaggregatedAttReceived:
+				data, ok := event.Data.(*opfeed.UnAggregatedAttReceivedData)
+				if !ok {
+					continue
+				}
+				enqueue(&rpcpb.Event{
+					Topic: rpcpb.EventTopic_ATTESTATION,
+					Data:  &rpcpb.Event_Attestation{Attestation: data.Attestation},
+				})
+			case opfeed.AggregatedAttReceived:
+				data, ok := event.Data.(*opfeed.AggregatedAttReceivedData)
+				if !ok {
+					continue
+				}
+				enqueue(&rpcpb.Event{
+					Topic: rpcpb.EventTopic_ATTESTATION,
+					Data:  &rpcpb.Event_Aggregate{Aggregate: data.Attestation},
+				})
+			case opfeed.ExitReceived:
+				data, ok := event.Data.(*opfeed.ExitReceivedData)
+				if !ok {
+					continue
+				}
+				enqueue(&rpcpb.Event{
+					Topic: rpcpb.EventTopic_VOLUNTARY_EXIT,
+					Data:  &rpcpb.Event_VoluntaryExit{VoluntaryExit: data.Exit},
+				})
+			}
+		case err := <-sendErr:
+			return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+		case <-stateSub.Err():
+			return status.Error(codes.Aborted, "Subscriber closed, exiting goroutine")
+		case <-blockSub.Err():
+			return status.Error(codes.Aborted, "Subscriber closed, exiting goroutine")
+		case <-opSub.Err():
+			return status.Error(codes.Aborted, "Subscriber closed, exiting goroutine")
+		case <-s.Ctx.Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		}
+	}
+}
+
+func checkpointChanged(last *ethpb.Checkpoint, current *ethpb.Checkpoint) bool {
+	return current != nil && !proto.Equal(last, current)
+}
+
+func checkpointEvent(topic rpcpb.EventTopic, cp *ethpb.Checkpoint) *rpcpb.Event {
+	return &rpcpb.Event{
+		Topic: topic,
+		Data: &rpcpb.Event_Checkpoint{Checkpoint: &rpcpb.CheckpointEvent{
+			Epoch: cp.Epoch,
+			Root:  cp.Root,
+		}},
+	}
+}
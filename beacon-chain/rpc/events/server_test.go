@@ -0,0 +1,188 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"google.golang.org/grpc"
+)
+
+type eventsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *rpcpb.Event
+}
+
+func (s *eventsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *eventsStream) Send(e *rpcpb.Event) error {
+	select {
+	case s.sent <- e:
+	case <-s.ctx.Done():
+	}
+	return nil
+}
+
+// checkpointFetcher serves the checkpoints of the mock chain service under lock, so tests can
+// change them while the server reads them from its own routine.
+type checkpointFetcher struct {
+	lock         sync.RWMutex
+	chainService *mock.ChainService
+}
+
+func (f *checkpointFetcher) FinalizedCheckpt() *ethpb.Checkpoint {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.chainService.FinalizedCheckpt()
+}
+
+func (f *checkpointFetcher) CurrentJustifiedCheckpt() *ethpb.Checkpoint {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.chainService.CurrentJustifiedCheckpt()
+}
+
+func (f *checkpointFetcher) PreviousJustifiedCheckpt() *ethpb.Checkpoint {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.chainService.PreviousJustifiedCheckpt()
+}
+
+func (f *checkpointFetcher) setFinalizedCheckpt(cp *ethpb.Checkpoint) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.chainService.FinalizedCheckPoint = cp
+}
+
+func setupServer(ctx context.Context) (*Server, *checkpointFetcher) {
+	chainService := &mock.ChainService{
+		FinalizedCheckPoint:        &ethpb.Checkpoint{Epoch: 1, Root: []byte{'A'}},
+		CurrentJustifiedCheckPoint: &ethpb.Checkpoint{Epoch: 2, Root: []byte{'B'}},
+	}
+	fetcher := &checkpointFetcher{chainService: chainService}
+	return &Server{
+		Ctx:                 ctx,
+		StateNotifier:       chainService.StateNotifier(),
+		BlockNotifier:       chainService.BlockNotifier(),
+		OperationNotifier:   chainService.OperationNotifier(),
+		FinalizationFetcher: fetcher,
+	}, fetcher
+}
+
+// sendUntilSubscribed sends the event until the server has subscribed to the feed.
+func sendUntilSubscribed(s *Server, e *feed.Event) {
+	for sent := 0; sent == 0; {
+		sent = s.StateNotifier.StateFeed().Send(e)
+	}
+}
+
+func TestStreamEvents_FiltersTopics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, _ := setupServer(ctx)
+	stream := &eventsStream{ctx: ctx, sent: make(chan *rpcpb.Event, 2)}
+	go func() {
+		req := &rpcpb.StreamEventsRequest{Topics: []rpcpb.EventTopic{rpcpb.EventTopic_HEAD}}
+		if err := server.StreamEvents(req, stream); err != nil && ctx.Err() == nil {
+			t.Error(err)
+		}
+	}()
+
+	sendUntilSubscribed(server, &feed.Event{
+		Type: statefeed.Reorg,
+		Data: &statefeed.ReorgData{NewSlot: 5, OldSlot: 4, Depth: 1},
+	})
+	server.StateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.NewHead,
+		Data: &statefeed.NewHeadData{Slot: 5, BlockRoot: [32]byte{'C'}, EpochTransition: true},
+	})
+
+	select {
+	case e := <-stream.sent:
+		if e.Topic != rpcpb.EventTopic_HEAD {
+			t.Fatalf("Expected head event, received %v", e.Topic)
+		}
+		head := e.GetHead()
+		if head.Slot != 5 || !head.EpochTransition {
+			t.Errorf("Unexpected head event %v", head)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive head event")
+	}
+	select {
+	case e := <-stream.sent:
+		t.Errorf("Received unexpected event %v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStreamEvents_CheckpointChanged(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, fetcher := setupServer(ctx)
+	stream := &eventsStream{ctx: ctx, sent: make(chan *rpcpb.Event, 2)}
+	go func() {
+		if err := server.StreamEvents(&rpcpb.StreamEventsRequest{}, stream); err != nil && ctx.Err() == nil {
+			t.Error(err)
+		}
+	}()
+
+	// Checkpoints which did not change since subscribing are not sent.
+	sendUntilSubscribed(server, &feed.Event{Type: statefeed.BlockProcessed, Data: &statefeed.BlockProcessedData{Slot: 1}})
+	fetcher.setFinalizedCheckpt(&ethpb.Checkpoint{Epoch: 2, Root: []byte{'B'}})
+	server.StateNotifier.StateFeed().Send(&feed.Event{Type: statefeed.BlockProcessed, Data: &statefeed.BlockProcessedData{Slot: 2}})
+
+	select {
+	case e := <-stream.sent:
+		if e.Topic != rpcpb.EventTopic_FINALIZED_CHECKPOINT {
+			t.Fatalf("Expected finalized checkpoint event, received %v", e.Topic)
+		}
+		if e.GetCheckpoint().Epoch != 2 {
+			t.Errorf("Expected finalized epoch 2, received %d", e.GetCheckpoint().Epoch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive finalized checkpoint event")
+	}
+	select {
+	case e := <-stream.sent:
+		t.Errorf("Received unexpected event %v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStreamEvents_SlowSubscriberDoesNotBlockFeed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server, _ := setupServer(ctx)
+	// The stream never accepts any event.
+	stream := &eventsStream{ctx: ctx, sent: make(chan *rpcpb.Event)}
+	go func() {
+		if err := server.StreamEvents(&rpcpb.StreamEventsRequest{}, stream); err != nil && ctx.Err() == nil {
+			t.Error(err)
+		}
+	}()
+
+	headEvent := &feed.Event{Type: statefeed.NewHead, Data: &statefeed.NewHeadData{Slot: 1}}
+	sendUntilSubscribed(server, headEvent)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*eventQueueSize; i++ {
+			server.StateNotifier.StateFeed().Send(headEvent)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Feed was stalled by a slow subscriber")
+	}
+}
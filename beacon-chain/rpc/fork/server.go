@@ -0,0 +1,21 @@
+// Package fork defines a gRPC server serving the fork of the chain head, which validator clients
+// need to sign with the domains of the current fork.
+package fork
+
+import (
+	"context"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// Server defines a server implementation of the gRPC fork info service.
+type Server struct {
+	ForkFetcher blockchain.ForkFetcher
+}
+
+// GetHeadFork returns the fork of the head state, or the genesis fork before the node has a head.
+func (fs *Server) GetHeadFork(_ context.Context, _ *ptypes.Empty) (*pb.Fork, error) {
+	return fs.ForkFetcher.CurrentFork(), nil
+}
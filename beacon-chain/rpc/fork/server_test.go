@@ -0,0 +1,28 @@
+package fork
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ptypes "github.com/gogo/protobuf/types"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+func TestServer_GetHeadFork(t *testing.T) {
+	want := &pb.Fork{
+		PreviousVersion: []byte{0, 0, 0, 1},
+		CurrentVersion:  []byte{0, 0, 0, 2},
+		Epoch:           10,
+	}
+	fs := &Server{ForkFetcher: &mock.ChainService{Fork: want}}
+
+	fork, err := fs.GetHeadFork(context.Background(), &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(fork, want) {
+		t.Errorf("Wanted fork %v, received %v", want, fork)
+	}
+}
@@ -0,0 +1,84 @@
+// Package performance defines a gRPC server serving the per-epoch performance of validators, as
+// tracked by the beacon node at every epoch transition.
+package performance
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	perf "github.com/prysmaticlabs/prysm/beacon-chain/performance"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxEpochRange is the maximum number of epochs the performance can be requested over at once.
+const maxEpochRange = 4096
+
+// Server defines a server implementation of the gRPC performance service.
+type Server struct {
+	BeaconDB    db.ReadOnlyDatabase
+	HeadFetcher blockchain.HeadFetcher
+}
+
+// ListValidatorPerformance returns the per-epoch performance of the requested validators over
+// a range of epochs, along with its aggregates over the range.
+func (ps *Server) ListValidatorPerformance(
+	ctx context.Context,
+	req *rpcpb.ValidatorPerformanceRequest,
+) (*rpcpb.ValidatorPerformanceResponse, error) {
+	if req.EndEpoch < req.StartEpoch {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"End epoch %d is before start epoch %d",
+			req.EndEpoch,
+			req.StartEpoch,
+		)
+	}
+	if req.EndEpoch-req.StartEpoch >= maxEpochRange {
+		return nil, status.Errorf(codes.InvalidArgument, "Requested more than %d epochs", maxEpochRange)
+	}
+
+	indices := append([]uint64{}, req.Indices...)
+	if len(req.PublicKeys) > 0 {
+		headState, err := ps.HeadFetcher.HeadState(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not get head state: %v", err)
+		}
+		for _, key := range req.PublicKeys {
+			idx, ok := headState.ValidatorIndexByPubkey(bytesutil.ToBytes48(key))
+			if !ok {
+				return nil, status.Errorf(codes.NotFound, "Could not find validator with public key %#x", key)
+			}
+			indices = append(indices, idx)
+		}
+	}
+	if len(indices) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Must request at least one validator")
+	}
+	if len(indices) > flags.Get().MaxPageSize {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"Requested %d validators, the maximum is %d",
+			len(indices),
+			flags.Get().MaxPageSize,
+		)
+	}
+
+	series := make([]*rpcpb.ValidatorPerformanceSeries, 0, len(indices))
+	for _, idx := range indices {
+		records, err := ps.BeaconDB.ValidatorPerformance(ctx, idx, req.StartEpoch, req.EndEpoch)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not retrieve performance of validator %d: %v", idx, err)
+		}
+		series = append(series, &rpcpb.ValidatorPerformanceSeries{
+			ValidatorIndex: idx,
+			Epochs:         records,
+			Aggregate:      perf.Aggregate(records),
+		})
+	}
+	return &rpcpb.ValidatorPerformanceResponse{Validators: series}, nil
+}
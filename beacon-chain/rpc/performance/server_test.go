@@ -0,0 +1,89 @@
+package performance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func init() {
+	flags.Init(&flags.GlobalFlags{
+		MaxPageSize: 250,
+	})
+}
+
+func TestServer_ListValidatorPerformance(t *testing.T) {
+	db := dbTest.SetupDB(t)
+	defer dbTest.TeardownDB(t, db)
+	ctx := context.Background()
+	headState, _ := testutil.DeterministicGenesisState(t, 8)
+	records := make([]*rpcpb.ValidatorEpochPerformance, 0)
+	for epoch := uint64(0); epoch < 4; epoch++ {
+		for idx := uint64(0); idx < 8; idx++ {
+			records = append(records, &rpcpb.ValidatorEpochPerformance{
+				Epoch:               epoch,
+				ValidatorIndex:      idx,
+				AttestationIncluded: epoch != 2,
+				BalanceDelta:        10,
+			})
+		}
+	}
+	if err := db.SaveValidatorPerformance(ctx, records); err != nil {
+		t.Fatal(err)
+	}
+	ps := &Server{
+		BeaconDB:    db,
+		HeadFetcher: &mock.ChainService{State: headState},
+	}
+
+	pubKey := headState.PubkeyAtIndex(3)
+	res, err := ps.ListValidatorPerformance(ctx, &rpcpb.ValidatorPerformanceRequest{
+		Indices:    []uint64{1},
+		PublicKeys: [][]byte{pubKey[:]},
+		StartEpoch: 1,
+		EndEpoch:   3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Validators) != 2 || res.Validators[0].ValidatorIndex != 1 || res.Validators[1].ValidatorIndex != 3 {
+		t.Fatalf("Expected the performance of validators 1 and 3, received %v", res.Validators)
+	}
+	for _, series := range res.Validators {
+		if len(series.Epochs) != 3 || series.Epochs[0].Epoch != 1 {
+			t.Errorf("Expected epochs 1 to 3, received %v", series.Epochs)
+		}
+		want := &rpcpb.ValidatorPerformanceAggregate{
+			Epochs:               3,
+			IncludedAttestations: 2,
+			MissedAttestations:   1,
+			BalanceDelta:         30,
+		}
+		if !proto.Equal(series.Aggregate, want) {
+			t.Errorf("Wanted %v, received %v", want, series.Aggregate)
+		}
+	}
+}
+
+func TestServer_ListValidatorPerformance_InvalidRequest(t *testing.T) {
+	ps := &Server{}
+	ctx := context.Background()
+	if _, err := ps.ListValidatorPerformance(ctx, &rpcpb.ValidatorPerformanceRequest{
+		Indices:    []uint64{1},
+		StartEpoch: 2,
+		EndEpoch:   1,
+	}); err == nil || !strings.Contains(err.Error(), "is before start epoch") {
+		t.Errorf("Expected an error for an invalid range, received %v", err)
+	}
+	if _, err := ps.ListValidatorPerformance(ctx, &rpcpb.ValidatorPerformanceRequest{}); err == nil ||
+		!strings.Contains(err.Error(), "at least one validator") {
+		t.Errorf("Expected an error for a request without validators, received %v", err)
+	}
+}
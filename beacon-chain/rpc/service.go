@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
 
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -26,11 +27,18 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/beacon"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/beaconapi"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/checkpoint"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/debug"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/events"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/fork"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/node"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/performance"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/validator"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
 	pbp2p "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -60,6 +68,7 @@ type Service struct {
 	participationFetcher   blockchain.ParticipationFetcher
 	genesisTimeFetcher     blockchain.TimeFetcher
 	genesisFetcher         blockchain.GenesisFetcher
+	forkChoiceFetcher      blockchain.ForkChoiceFetcher
 	attestationReceiver    blockchain.AttestationReceiver
 	blockReceiver          blockchain.BlockReceiver
 	powChainService        powchain.Chain
@@ -91,6 +100,12 @@ type Service struct {
 	slasherCredentialError error
 	slasherClient          slashpb.SlasherClient
 	stateGen               *stategen.State
+	peerManager            p2p.PeerManager
+	identityProvider       p2p.IdentityProvider
+	metadataProvider       p2p.MetadataProvider
+	beaconAPIPort          string
+	beaconAPIServer        *http.Server
+	maxStateReplaySlots    uint64
 }
 
 // Config options for the beacon node RPC server.
@@ -110,6 +125,7 @@ type Config struct {
 	ChainStartFetcher     powchain.ChainStartFetcher
 	GenesisTimeFetcher    blockchain.TimeFetcher
 	GenesisFetcher        blockchain.GenesisFetcher
+	ForkChoiceFetcher     blockchain.ForkChoiceFetcher
 	MockEth1Votes         bool
 	AttestationsPool      attestations.Pool
 	ExitPool              *voluntaryexits.Pool
@@ -125,6 +141,11 @@ type Config struct {
 	BlockNotifier         blockfeed.Notifier
 	OperationNotifier     opfeed.Notifier
 	StateGen              *stategen.State
+	PeerManager           p2p.PeerManager
+	IdentityProvider      p2p.IdentityProvider
+	MetadataProvider      p2p.MetadataProvider
+	BeaconAPIPort         string
+	MaxStateReplaySlots   uint64
 }
 
 // NewService instantiates a new RPC service instance that will
@@ -141,6 +162,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		participationFetcher:  cfg.ParticipationFetcher,
 		genesisTimeFetcher:    cfg.GenesisTimeFetcher,
 		genesisFetcher:        cfg.GenesisFetcher,
+		forkChoiceFetcher:     cfg.ForkChoiceFetcher,
 		attestationReceiver:   cfg.AttestationReceiver,
 		blockReceiver:         cfg.BlockReceiver,
 		p2p:                   cfg.Broadcaster,
@@ -166,6 +188,11 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		slasherProvider:       cfg.SlasherProvider,
 		slasherCert:           cfg.SlasherCert,
 		stateGen:              cfg.StateGen,
+		peerManager:           cfg.PeerManager,
+		identityProvider:      cfg.IdentityProvider,
+		metadataProvider:      cfg.MetadataProvider,
+		beaconAPIPort:         cfg.BeaconAPIPort,
+		maxStateReplaySlots:   cfg.MaxStateReplaySlots,
 	}
 }
 
@@ -264,12 +291,42 @@ func (s *Service) Start() {
 		AttestationNotifier:         s.operationNotifier,
 		Broadcaster:                 s.p2p,
 		StateGen:                    s.stateGen,
+		MaxStateReplaySlots:         s.maxStateReplaySlots,
 		ReceivedAttestationsBuffer:  make(chan *ethpb.Attestation, 100),
 		CollectedAttestationsBuffer: make(chan []*ethpb.Attestation, 100),
 	}
+	checkpointServer := &checkpoint.Server{
+		BeaconDB:            s.beaconDB,
+		FinalizationFetcher: s.finalizationFetcher,
+		StateGen:            s.stateGen,
+	}
+	debugServer := &debug.Server{
+		BeaconDB:          s.beaconDB,
+		PeersFetcher:      s.peersFetcher,
+		ForkChoiceFetcher: s.forkChoiceFetcher,
+	}
+	eventsServer := &events.Server{
+		Ctx:                 s.ctx,
+		StateNotifier:       s.stateNotifier,
+		BlockNotifier:       s.blockNotifier,
+		OperationNotifier:   s.operationNotifier,
+		FinalizationFetcher: s.finalizationFetcher,
+	}
+	forkServer := &fork.Server{
+		ForkFetcher: s.forkFetcher,
+	}
+	performanceServer := &performance.Server{
+		BeaconDB:    s.beaconDB,
+		HeadFetcher: s.headFetcher,
+	}
 	ethpb.RegisterNodeServer(s.grpcServer, nodeServer)
 	ethpb.RegisterBeaconChainServer(s.grpcServer, beaconChainServer)
 	ethpb.RegisterBeaconNodeValidatorServer(s.grpcServer, validatorServer)
+	rpcpb.RegisterCheckpointSyncServer(s.grpcServer, checkpointServer)
+	rpcpb.RegisterDebugServer(s.grpcServer, debugServer)
+	rpcpb.RegisterEventsServer(s.grpcServer, eventsServer)
+	rpcpb.RegisterForkInfoServer(s.grpcServer, forkServer)
+	rpcpb.RegisterPerformanceServer(s.grpcServer, performanceServer)
 
 	// Register reflection service on gRPC server.
 	reflection.Register(s.grpcServer)
@@ -281,11 +338,44 @@ func (s *Service) Start() {
 			}
 		}
 	}()
+	if s.beaconAPIPort != "" {
+		s.startBeaconAPI(&beaconapi.Server{
+			BeaconDB:            s.beaconDB,
+			HeadFetcher:         s.headFetcher,
+			FinalizationFetcher: s.finalizationFetcher,
+			GenesisTimeFetcher:  s.genesisTimeFetcher,
+			GenesisFetcher:      s.genesisFetcher,
+			StateGen:            s.stateGen,
+			AttestationsPool:    s.attestationsPool,
+			SlashingsPool:       s.slashingsPool,
+			ExitPool:            s.exitPool,
+			PeersFetcher:        s.peersFetcher,
+			PeerManager:         s.peerManager,
+			IdentityProvider:    s.identityProvider,
+			MetadataProvider:    s.metadataProvider,
+			SyncChecker:         s.syncService,
+			ValidatorServer:     validatorServer,
+			BeaconChainServer:   beaconChainServer,
+			MaxStateReplaySlots: s.maxStateReplaySlots,
+		})
+	}
 	if featureconfig.Get().EnableSlasherConnection {
 		s.startSlasherClient()
 	}
 }
 
+// This serves the standard beacon node REST API over HTTP, next to the gRPC server.
+func (s *Service) startBeaconAPI(server *beaconapi.Server) {
+	address := fmt.Sprintf("%s:%s", s.host, s.beaconAPIPort)
+	s.beaconAPIServer = &http.Server{Addr: address, Handler: server.Handler()}
+	log.WithField("address", address).Info("Beacon REST API listening on port")
+	go func() {
+		if err := s.beaconAPIServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Could not serve beacon REST API: %v", err)
+		}
+	}()
+}
+
 func (s *Service) startSlasherClient() {
 	var dialOpt grpc.DialOption
 	if s.slasherCert != "" {
@@ -328,6 +418,11 @@ func (s *Service) Stop() error {
 		s.grpcServer.GracefulStop()
 		log.Debug("Initiated graceful stop of gRPC server")
 	}
+	if s.beaconAPIServer != nil {
+		if err := s.beaconAPIServer.Shutdown(context.Background()); err != nil {
+			log.WithError(err).Error("Could not stop beacon REST API server")
+		}
+	}
 	if s.slasherConn != nil {
 		if err := s.slasherConn.Close(); err != nil {
 			return err
@@ -380,35 +380,6 @@ func (vs *Server) defaultEth1DataResponse(ctx context.Context, currentHeight *bi
 	}, nil
 }
 
-// This filters the input attestations to return a list of valid attestations to be packaged inside a beacon block.
-func (vs *Server) filterAttestationsForBlockInclusion(ctx context.Context, state *stateTrie.BeaconState, atts []*ethpb.Attestation) ([]*ethpb.Attestation, error) {
-	ctx, span := trace.StartSpan(ctx, "ProposerServer.filterAttestationsForBlockInclusion")
-	defer span.End()
-
-	validAtts := make([]*ethpb.Attestation, 0, len(atts))
-	inValidAtts := make([]*ethpb.Attestation, 0, len(atts))
-
-	// TODO(3916): Insert optimizations to sort out the most profitable attestations
-	for i, att := range atts {
-		if i == int(params.BeaconConfig().MaxAttestations) {
-			break
-		}
-
-		if _, err := blocks.ProcessAttestation(ctx, state, att); err != nil {
-			inValidAtts = append(inValidAtts, att)
-			continue
-
-		}
-		validAtts = append(validAtts, att)
-	}
-
-	if err := vs.deleteAttsInPool(inValidAtts); err != nil {
-		return nil, err
-	}
-
-	return validAtts, nil
-}
-
 // The input attestations are processed and seen by the node, this deletes them from pool
 // so proposers don't include them in a block for the future.
 func (vs *Server) deleteAttsInPool(atts []*ethpb.Attestation) error {
@@ -437,35 +408,3 @@ func constructMerkleProof(trie *trieutil.SparseMerkleTrie, index int, deposit *e
 	deposit.Proof = proof
 	return deposit, nil
 }
-
-func (vs *Server) packAttestations(ctx context.Context, slot uint64) ([]*ethpb.Attestation, error) {
-	ctx, span := trace.StartSpan(ctx, "validatorServer.packAttestations")
-	defer span.End()
-	st, err := vs.HeadFetcher.HeadState(ctx)
-	if err != nil {
-		return nil, errors.Wrap(err, "could fetch head state")
-	}
-	if st.Slot() < slot {
-		st, err = state.ProcessSlots(ctx, st, slot)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not advance state")
-		}
-	}
-
-	atts := vs.AttPool.AggregatedAttestations()
-	atts, err = vs.filterAttestationsForBlockInclusion(ctx, st, atts)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not filter attestations")
-	}
-
-	// If there is any room left in the block, consider unaggregated attestations as well.
-	if len(atts) < int(params.BeaconConfig().MaxAttestations) {
-		uAtts := vs.AttPool.UnaggregatedAttestations()
-		uAtts, err = vs.filterAttestationsForBlockInclusion(ctx, st, uAtts)
-		if len(uAtts)+len(atts) > int(params.BeaconConfig().MaxAttestations) {
-			uAtts = uAtts[:int(params.BeaconConfig().MaxAttestations)-len(atts)]
-		}
-		atts = append(atts, uAtts...)
-	}
-	return atts, nil
-}
@@ -0,0 +1,257 @@
+package validator
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"go.opencensus.io/trace"
+)
+
+// attPackingTimeBudget is the time allowed to select the attestations of a block proposal. Once it
+// runs out, the attestations selected so far are packed.
+var attPackingTimeBudget = time.Second
+
+var (
+	packedAttestations = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "proposer_packed_attestations",
+		Help: "The number of attestations packed in the last block proposal",
+	})
+	packedNewVotes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "proposer_packed_new_votes",
+		Help: "The number of validator votes not yet included in the chain added by the last block proposal",
+	})
+	unpackedNewVotes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "proposer_unpacked_new_votes",
+		Help: "The number of validator votes not yet included in the chain left out of the last block proposal",
+	})
+	attPackingDuration = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "proposer_attestation_packing_milliseconds",
+		Help: "The time taken to pack the attestations of the last block proposal",
+	})
+	attPackingTimeouts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "proposer_attestation_packing_timeouts_total",
+		Help: "The number of block proposals whose attestation packing ran out of time",
+	})
+)
+
+// packingCandidate is an attestation which may be packed in a block, along with the root of its data.
+type packingCandidate struct {
+	att      *ethpb.Attestation
+	dataRoot [32]byte
+}
+
+// packAttestations returns the attestations to include in a block proposed at the given slot.
+// The attestations of the pool are verified and merged per attestation data, then the ones adding
+// the most validator votes not yet included in the chain are picked greedily, which gives a near
+// maximal coverage of the votes.
+func (vs *Server) packAttestations(ctx context.Context, slot uint64) ([]*ethpb.Attestation, error) {
+	ctx, span := trace.StartSpan(ctx, "validatorServer.packAttestations")
+	defer span.End()
+	start := time.Now()
+	deadline := start.Add(attPackingTimeBudget)
+
+	st, err := vs.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could fetch head state")
+	}
+	if st.Slot() < slot {
+		st, err = state.ProcessSlots(ctx, st, slot)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not advance state")
+		}
+	}
+
+	// The votes of the pending attestations are computed before verifying the candidates,
+	// as verification adds the candidates to the pending attestations of the state.
+	included, err := includedVotes(st)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute included votes")
+	}
+
+	atts := append(vs.AttPool.AggregatedAttestations(), vs.AttPool.UnaggregatedAttestations()...)
+	// Verify the attestations with the most votes first, so they are verified even if time runs out.
+	sort.SliceStable(atts, func(i, j int) bool {
+		return atts[i].AggregationBits.Count() > atts[j].AggregationBits.Count()
+	})
+	atts, timedOut, err := vs.verifyAttestationsForBlockInclusion(ctx, st, atts, deadline)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not filter attestations")
+	}
+	candidates, err := mergeAttestations(atts)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not merge attestations")
+	}
+	packed, newVotes, leftOut, selectTimedOut := selectAttestations(candidates, included, params.BeaconConfig().MaxAttestations, deadline)
+
+	if timedOut || selectTimedOut {
+		attPackingTimeouts.Inc()
+	}
+	packedAttestations.Set(float64(len(packed)))
+	packedNewVotes.Set(float64(newVotes))
+	unpackedNewVotes.Set(float64(leftOut))
+	attPackingDuration.Set(float64(time.Since(start).Milliseconds()))
+	return packed, nil
+}
+
+// This verifies the input attestations against the state until the deadline, and returns the valid
+// ones. Invalid attestations are deleted from the pool.
+func (vs *Server) verifyAttestationsForBlockInclusion(
+	ctx context.Context,
+	state *stateTrie.BeaconState,
+	atts []*ethpb.Attestation,
+	deadline time.Time,
+) ([]*ethpb.Attestation, bool, error) {
+	ctx, span := trace.StartSpan(ctx, "ProposerServer.verifyAttestationsForBlockInclusion")
+	defer span.End()
+
+	validAtts := make([]*ethpb.Attestation, 0, len(atts))
+	inValidAtts := make([]*ethpb.Attestation, 0, len(atts))
+	timedOut := false
+	for _, att := range atts {
+		if time.Now().After(deadline) {
+			timedOut = true
+			break
+		}
+		if _, err := blocks.ProcessAttestation(ctx, state, att); err != nil {
+			inValidAtts = append(inValidAtts, att)
+			continue
+		}
+		validAtts = append(validAtts, att)
+	}
+
+	if err := vs.deleteAttsInPool(inValidAtts); err != nil {
+		return nil, false, err
+	}
+	return validAtts, timedOut, nil
+}
+
+// This returns the aggregation bits of the votes already included in the pending attestations of
+// the state, per attestation data root.
+func includedVotes(st *stateTrie.BeaconState) (map[[32]byte]bitfield.Bitlist, error) {
+	included := make(map[[32]byte]bitfield.Bitlist)
+	pending := append(st.PreviousEpochAttestations(), st.CurrentEpochAttestations()...)
+	for _, a := range pending {
+		root, err := ssz.HashTreeRoot(a.Data)
+		if err != nil {
+			return nil, err
+		}
+		addVotes(included, root, a.AggregationBits)
+	}
+	return included, nil
+}
+
+// This merges the attestations sharing the same data whose aggregation bits do not overlap, and drops
+// the attestations whose votes are all contained in another one.
+func mergeAttestations(atts []*ethpb.Attestation) ([]*packingCandidate, error) {
+	byData := make(map[[32]byte][]*ethpb.Attestation)
+	var roots [][32]byte
+	for _, att := range atts {
+		root, err := ssz.HashTreeRoot(att.Data)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byData[root]; !ok {
+			roots = append(roots, root)
+		}
+		byData[root] = append(byData[root], att)
+	}
+
+	candidates := make([]*packingCandidate, 0, len(atts))
+	for _, root := range roots {
+		merged, err := helpers.AggregateAttestations(byData[root])
+		if err != nil {
+			return nil, err
+		}
+		for _, att := range merged {
+			candidates = append(candidates, &packingCandidate{att: att, dataRoot: root})
+		}
+	}
+	return candidates, nil
+}
+
+// This greedily picks up to max candidates, each time the one adding the most votes not yet included.
+// It returns the picked attestations, the number of new votes they add, the number of new votes left
+// out, and whether the deadline was reached.
+func selectAttestations(
+	candidates []*packingCandidate,
+	included map[[32]byte]bitfield.Bitlist,
+	max uint64,
+	deadline time.Time,
+) ([]*ethpb.Attestation, uint64, uint64, bool) {
+	picked := make([]bool, len(candidates))
+	selected := make([]*ethpb.Attestation, 0, max)
+	var votes uint64
+	timedOut := false
+	for uint64(len(selected)) < max {
+		if time.Now().After(deadline) {
+			timedOut = true
+			break
+		}
+		best := -1
+		var bestVotes uint64
+		for i, c := range candidates {
+			if picked[i] {
+				continue
+			}
+			if n := newVotes(c.att.AggregationBits, included[c.dataRoot]); n > bestVotes {
+				best = i
+				bestVotes = n
+			}
+		}
+		if best < 0 {
+			break
+		}
+		picked[best] = true
+		c := candidates[best]
+		selected = append(selected, c.att)
+		votes += bestVotes
+		addVotes(included, c.dataRoot, c.att.AggregationBits)
+	}
+
+	// Count the new votes of the candidates which were not picked.
+	var leftOut uint64
+	for i, c := range candidates {
+		if picked[i] {
+			continue
+		}
+		leftOut += newVotes(c.att.AggregationBits, included[c.dataRoot])
+		addVotes(included, c.dataRoot, c.att.AggregationBits)
+	}
+	return selected, votes, leftOut, timedOut
+}
+
+// This returns the number of votes of bits which are not in included.
+func newVotes(bits bitfield.Bitlist, included bitfield.Bitlist) uint64 {
+	if included == nil || included.Len() != bits.Len() {
+		return bits.Count()
+	}
+	var n uint64
+	for i := uint64(0); i < bits.Len(); i++ {
+		if bits.BitAt(i) && !included.BitAt(i) {
+			n++
+		}
+	}
+	return n
+}
+
+// This adds the votes of bits to the included votes of the attestation data root.
+func addVotes(included map[[32]byte]bitfield.Bitlist, root [32]byte, bits bitfield.Bitlist) {
+	votes, ok := included[root]
+	if !ok || votes.Len() != bits.Len() {
+		included[root] = bits
+		return
+	}
+	included[root] = votes.Or(bits)
+}
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+func bitlistWith(length uint64, indices ...uint64) bitfield.Bitlist {
+	bits := bitfield.NewBitlist(length)
+	for _, i := range indices {
+		bits.SetBitAt(i, true)
+	}
+	return bits
+}
+
+func TestSelectAttestations_MaximizesNewVotes(t *testing.T) {
+	rootA := [32]byte{'A'}
+	rootB := [32]byte{'B'}
+	included := map[[32]byte]bitfield.Bitlist{
+		rootA: bitlistWith(8, 0, 1, 2),
+	}
+	mostlyIncluded := &ethpb.Attestation{AggregationBits: bitlistWith(8, 0, 1, 2, 3)}
+	newOnA := &ethpb.Attestation{AggregationBits: bitlistWith(8, 4, 5)}
+	newOnB := &ethpb.Attestation{AggregationBits: bitlistWith(8, 0, 1, 2)}
+	candidates := []*packingCandidate{
+		{att: mostlyIncluded, dataRoot: rootA},
+		{att: newOnA, dataRoot: rootA},
+		{att: newOnB, dataRoot: rootB},
+	}
+
+	selected, votes, leftOut, timedOut := selectAttestations(candidates, included, 2, time.Now().Add(time.Minute))
+	if timedOut {
+		t.Error("Did not expect selection to time out")
+	}
+	if !reflect.DeepEqual(selected, []*ethpb.Attestation{newOnB, newOnA}) {
+		t.Errorf("Did not select the attestations adding the most votes, received %v", selected)
+	}
+	if votes != 5 {
+		t.Errorf("Wanted 5 new votes, received %d", votes)
+	}
+	if leftOut != 1 {
+		t.Errorf("Wanted 1 new vote left out, received %d", leftOut)
+	}
+}
+
+func TestSelectAttestations_SkipsIncludedVotes(t *testing.T) {
+	root := [32]byte{'A'}
+	included := map[[32]byte]bitfield.Bitlist{
+		root: bitlistWith(4, 0, 1),
+	}
+	candidates := []*packingCandidate{
+		{att: &ethpb.Attestation{AggregationBits: bitlistWith(4, 0)}, dataRoot: root},
+		{att: &ethpb.Attestation{AggregationBits: bitlistWith(4, 1)}, dataRoot: root},
+	}
+
+	selected, votes, _, _ := selectAttestations(candidates, included, 128, time.Now().Add(time.Minute))
+	if len(selected) != 0 || votes != 0 {
+		t.Errorf("Expected no attestation to be selected, received %v", selected)
+	}
+}
+
+func TestSelectAttestations_StopsAtDeadline(t *testing.T) {
+	candidates := []*packingCandidate{
+		{att: &ethpb.Attestation{AggregationBits: bitlistWith(4, 0)}, dataRoot: [32]byte{'A'}},
+	}
+
+	selected, _, leftOut, timedOut := selectAttestations(candidates, map[[32]byte]bitfield.Bitlist{}, 128, time.Now().Add(-time.Second))
+	if !timedOut {
+		t.Error("Expected selection to time out")
+	}
+	if len(selected) != 0 {
+		t.Errorf("Expected no attestation to be selected, received %v", selected)
+	}
+	if leftOut != 1 {
+		t.Errorf("Wanted 1 new vote left out, received %d", leftOut)
+	}
+}
+
+func TestMergeAttestations_MergesDisjointBits(t *testing.T) {
+	sig := bls.RandKey().Sign([]byte("foo")).Marshal()
+	data := &ethpb.AttestationData{
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+	}
+	otherData := &ethpb.AttestationData{
+		Slot:            1,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+	}
+	atts := []*ethpb.Attestation{
+		{Data: data, AggregationBits: bitlistWith(4, 0), Signature: sig},
+		{Data: data, AggregationBits: bitlistWith(4, 1, 2), Signature: sig},
+		{Data: data, AggregationBits: bitlistWith(4, 1), Signature: sig},
+		{Data: otherData, AggregationBits: bitlistWith(4, 0), Signature: sig},
+	}
+
+	candidates, err := mergeAttestations(atts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("Wanted 2 candidates, received %d", len(candidates))
+	}
+	if !reflect.DeepEqual(candidates[0].att.AggregationBits, bitlistWith(4, 0, 1, 2)) {
+		t.Errorf("Attestations were not merged, received bits %v", candidates[0].att.AggregationBits)
+	}
+	if candidates[0].dataRoot == candidates[1].dataRoot {
+		t.Error("Expected attestations with different data to keep different roots")
+	}
+}
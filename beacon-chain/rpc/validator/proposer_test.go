@@ -1333,7 +1333,7 @@ func TestFilterAttestation_OK(t *testing.T) {
 			Target:         &ethpb.Checkpoint{}},
 		}
 	}
-	received, err := proposerServer.filterAttestationsForBlockInclusion(context.Background(), state, atts)
+	received, _, err := proposerServer.verifyAttestationsForBlockInclusion(context.Background(), state, atts, time.Now().Add(time.Minute))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1377,7 +1377,7 @@ func TestFilterAttestation_OK(t *testing.T) {
 		atts[i].Signature = bls.AggregateSignatures(sigs).Marshal()[:]
 	}
 
-	received, err = proposerServer.filterAttestationsForBlockInclusion(context.Background(), state, atts)
+	received, _, err = proposerServer.verifyAttestationsForBlockInclusion(context.Background(), state, atts, time.Now().Add(time.Minute))
 	if err != nil {
 		t.Fatal(err)
 	}
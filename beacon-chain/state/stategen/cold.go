@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 
 	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/sirupsen/logrus"
@@ -17,7 +18,7 @@ func (s *State) saveColdState(ctx context.Context, blockRoot [32]byte, state *st
 	ctx, span := trace.StartSpan(ctx, "stateGen.saveColdState")
 	defer span.End()
 
-	if state.Slot()%s.slotsPerArchivedPoint != 0 {
+	if s.slotsPerArchivedPoint == 0 || state.Slot()%s.slotsPerArchivedPoint != 0 {
 		return nil
 	}
 
@@ -46,7 +47,7 @@ func (s *State) loadColdStateByRoot(ctx context.Context, blockRoot [32]byte) (*s
 		return nil, errors.Wrap(err, "could not get state summary")
 	}
 
-	return s.ComputeStateUpToSlot(ctx, summary.Slot)
+	return s.computeColdState(ctx, summary.Slot)
 }
 
 // This loads a cold state by slot.
@@ -54,5 +55,94 @@ func (s *State) loadColdStateBySlot(ctx context.Context, slot uint64) (*state.Be
 	ctx, span := trace.StartSpan(ctx, "stateGen.loadColdStateBySlot")
 	defer span.End()
 
-	return s.ComputeStateUpToSlot(ctx, slot)
+	return s.computeColdState(ctx, slot)
+}
+
+// This tracks a cold state replay in progress, which concurrent requests of the same slot wait for.
+type coldReplay struct {
+	done  chan struct{}
+	state *state.BeaconState
+	err   error
+}
+
+// This computes the cold state at the input slot. Concurrent requests of the same slot share a
+// single replay, and each of them receives its own copy of the resulting state. The replay does
+// not run under the context of the request starting it, so cancelling a request only stops it
+// from waiting for the replay.
+func (s *State) computeColdState(ctx context.Context, slot uint64) (*state.BeaconState, error) {
+	s.replayLock.Lock()
+	replay, ok := s.replays[slot]
+	if !ok {
+		replay = &coldReplay{done: make(chan struct{})}
+		s.replays[slot] = replay
+		go s.runColdReplay(trace.NewContext(context.Background(), trace.FromContext(ctx)), slot, replay)
+	}
+	s.replayLock.Unlock()
+
+	select {
+	case <-replay.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if replay.err != nil {
+		return nil, replay.err
+	}
+	return replay.state.Copy(), nil
+}
+
+// This runs a shared cold state replay, and releases the requests waiting for it once done.
+func (s *State) runColdReplay(ctx context.Context, slot uint64, replay *coldReplay) {
+	replay.state, replay.err = s.replayColdState(ctx, slot)
+	if replay.err == nil && s.snapshots != nil {
+		s.snapshots.Save(replay.state)
+	}
+
+	s.replayLock.Lock()
+	delete(s.replays, slot)
+	s.replayLock.Unlock()
+	close(replay.done)
+}
+
+// This replays the cold state at the input slot, starting from the closest snapshot if it is
+// closer than the archived point.
+func (s *State) replayColdState(ctx context.Context, slot uint64) (*state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.replayColdState")
+	defer span.End()
+
+	if s.snapshots == nil || slot == 0 {
+		return s.ComputeStateUpToSlot(ctx, slot)
+	}
+	// Without archived points, the replay starts from genesis.
+	var archivedSlot uint64
+	if s.slotsPerArchivedPoint > 0 {
+		archivedSlot = slot - slot%s.slotsPerArchivedPoint
+	}
+	snapshotSlot, ok := s.snapshots.NearestSlot(slot)
+	if !ok || snapshotSlot <= archivedSlot {
+		return s.ComputeStateUpToSlot(ctx, slot)
+	}
+	snapshot := s.snapshots.Nearest(slot)
+	if snapshot == nil {
+		return s.ComputeStateUpToSlot(ctx, slot)
+	}
+	if snapshot.Slot() == slot {
+		return snapshot, nil
+	}
+
+	lastBlockRoot, lastBlockSlot, err := s.lastSavedBlock(ctx, slot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get last saved block")
+	}
+	var blks []*ethpb.SignedBeaconBlock
+	if lastBlockSlot > snapshot.Slot() {
+		blks, err = s.LoadBlocks(ctx, snapshot.Slot()+1, lastBlockSlot, lastBlockRoot)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load blocks")
+		}
+	}
+	st, err := s.ReplayBlocks(ctx, snapshot, blks, slot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not replay blocks")
+	}
+	return st, nil
 }
@@ -132,3 +132,108 @@ func TestLoadColdStateBySlot_CanGet(t *testing.T) {
 		t.Error("Did not correctly save state")
 	}
 }
+
+func TestLoadColdStateBySlot_ReplaysFromSnapshot(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+
+	service := New(db, cache.NewStateSummaryCache())
+	snapshots := NewLRUSnapshots(4)
+	service.SetSnapshotStrategy(snapshots)
+
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{}}
+	blkRoot, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveGenesisBlockRoot(ctx, blkRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveState(ctx, beaconState, blkRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	// Mark the snapshot to tell it apart from the archived genesis state.
+	snapshot := beaconState.Copy()
+	if err := snapshot.SetSlot(150); err != nil {
+		t.Fatal(err)
+	}
+	if err := snapshot.SetEth1DepositIndex(99); err != nil {
+		t.Fatal(err)
+	}
+	snapshots.Save(snapshot)
+
+	loadedState, err := service.loadColdStateBySlot(ctx, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedState.Slot() != 200 {
+		t.Errorf("Wanted slot %d, received %d", 200, loadedState.Slot())
+	}
+	if loadedState.Eth1DepositIndex() != 99 {
+		t.Error("Did not replay from the snapshot")
+	}
+	if slot, ok := snapshots.NearestSlot(200); !ok || slot != 200 {
+		t.Errorf("Wanted the computed state to be snapshotted, nearest snapshot is at slot %d", slot)
+	}
+}
+
+func TestSaveColdState_NoArchivedPoints(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = 0
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+	r := [32]byte{'a'}
+	if err := service.saveColdState(ctx, r, beaconState); err != nil {
+		t.Fatal(err)
+	}
+	if service.beaconDB.HasState(ctx, r) {
+		t.Error("Wanted no state saved without archived points")
+	}
+}
+
+func TestComputeColdState_CancelledRequestKeepsReplaying(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+
+	service := New(db, cache.NewStateSummaryCache())
+	snapshots := NewLRUSnapshots(4)
+	service.SetSnapshotStrategy(snapshots)
+
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{}}
+	blkRoot, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveGenesisBlockRoot(ctx, blkRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveState(ctx, beaconState, blkRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := service.computeColdState(cancelledCtx, 200); err != context.Canceled {
+		t.Fatalf("Wanted %v, received %v", context.Canceled, err)
+	}
+
+	// The replay started by the cancelled request completes for the other requests.
+	loadedState, err := service.computeColdState(ctx, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedState.Slot() != 200 {
+		t.Errorf("Wanted slot %d, received %d", 200, loadedState.Slot())
+	}
+	if slot, ok := snapshots.NearestSlot(200); !ok || slot != 200 {
+		t.Errorf("Wanted the computed state to be snapshotted, nearest snapshot is at slot %d", slot)
+	}
+}
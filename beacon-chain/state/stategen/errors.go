@@ -9,3 +9,6 @@ var errUnknownBoundaryRoot = errors.New("unknown boundary root")
 var errUnknownState = errors.New("unknown state")
 var errUnknownBlock = errors.New("unknown block")
 var errSlotNonArchivedPoint = errors.New("slot is not an archived point index")
+
+// ErrReplayTooExpensive is returned when the estimated replay of a state exceeds the allowed cost.
+var ErrReplayTooExpensive = errors.New("state replay is too expensive")
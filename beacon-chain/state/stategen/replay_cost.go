@@ -0,0 +1,70 @@
+package stategen
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+)
+
+// ReplayCost estimates the work needed to compute a state by slot, as the range of slots
+// replayed from the closest stored or snapshotted state.
+type ReplayCost struct {
+	StartSlot  uint64
+	TargetSlot uint64
+}
+
+// Slots returns the number of slots to process to reach the target slot.
+func (c *ReplayCost) Slots() uint64 {
+	return c.TargetSlot - c.StartSlot
+}
+
+// EstimateReplayCost returns the estimated cost of computing the state at the input slot with
+// StateBySlot, without loading any state.
+func (s *State) EstimateReplayCost(ctx context.Context, slot uint64) (*ReplayCost, error) {
+	cost := &ReplayCost{TargetSlot: slot}
+
+	// Hot states are replayed from the epoch boundary state before the slot.
+	if slot >= s.splitInfo.slot {
+		cost.StartSlot = helpers.StartSlot(helpers.SlotToEpoch(slot))
+		if cost.StartSlot < s.splitInfo.slot {
+			cost.StartSlot = s.splitInfo.slot
+		}
+		return cost, nil
+	}
+
+	// Cold states are replayed from the last archived point, or from a closer snapshot.
+	if s.slotsPerArchivedPoint > 0 {
+		for index := slot / s.slotsPerArchivedPoint; index > 0; index-- {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if s.beaconDB.HasArchivedPoint(ctx, index) {
+				cost.StartSlot = index * s.slotsPerArchivedPoint
+				break
+			}
+		}
+	}
+	if s.snapshots != nil {
+		if snapshotSlot, ok := s.snapshots.NearestSlot(slot); ok && snapshotSlot > cost.StartSlot {
+			cost.StartSlot = snapshotSlot
+		}
+	}
+	return cost, nil
+}
+
+// CheckReplayCost returns ErrReplayTooExpensive if computing the state at the input slot would
+// replay more than maxSlots slots. A maximum of zero allows any replay.
+func (s *State) CheckReplayCost(ctx context.Context, slot uint64, maxSlots uint64) error {
+	if maxSlots == 0 {
+		return nil
+	}
+	cost, err := s.EstimateReplayCost(ctx, slot)
+	if err != nil {
+		return err
+	}
+	if cost.Slots() > maxSlots {
+		return errors.Wrapf(ErrReplayTooExpensive, "replaying slots %d to %d exceeds the limit of %d slots", cost.StartSlot, cost.TargetSlot, maxSlots)
+	}
+	return nil
+}
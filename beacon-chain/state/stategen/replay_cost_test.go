@@ -0,0 +1,86 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestEstimateReplayCost(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = 64
+	service.splitInfo = &splitSlotAndRoot{slot: 1000}
+	if err := db.SaveArchivedPointRoot(ctx, [32]byte{'a'}, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hot states are replayed from the epoch boundary.
+	cost, err := service.EstimateReplayCost(ctx, 1005)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost.StartSlot != 1000 || cost.Slots() != 5 {
+		t.Errorf("Wanted replay from slot %d, received %d", 1000, cost.StartSlot)
+	}
+	cost, err = service.EstimateReplayCost(ctx, 1100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wanted := 1100 - 1100%params.BeaconConfig().SlotsPerEpoch; cost.StartSlot != wanted {
+		t.Errorf("Wanted replay from slot %d, received %d", wanted, cost.StartSlot)
+	}
+
+	// Cold states are replayed from the last existing archived point.
+	cost, err = service.EstimateReplayCost(ctx, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost.StartSlot != 128 {
+		t.Errorf("Wanted replay from slot %d, received %d", 128, cost.StartSlot)
+	}
+
+	// Or from a closer snapshot.
+	snapshots := NewLRUSnapshots(1)
+	beaconState, _ := testutil.DeterministicGenesisState(t, 1)
+	if err := beaconState.SetSlot(250); err != nil {
+		t.Fatal(err)
+	}
+	snapshots.Save(beaconState)
+	service.SetSnapshotStrategy(snapshots)
+	cost, err = service.EstimateReplayCost(ctx, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost.StartSlot != 250 {
+		t.Errorf("Wanted replay from slot %d, received %d", 250, cost.StartSlot)
+	}
+}
+
+func TestCheckReplayCost(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = 64
+	service.splitInfo = &splitSlotAndRoot{slot: 1000}
+
+	if err := service.CheckReplayCost(ctx, 500, 0); err != nil {
+		t.Errorf("Wanted no limit, received %v", err)
+	}
+	if err := service.CheckReplayCost(ctx, 500, 1000); err != nil {
+		t.Errorf("Wanted replay of 500 slots to be allowed, received %v", err)
+	}
+	if err := service.CheckReplayCost(ctx, 500, 100); errors.Cause(err) != ErrReplayTooExpensive {
+		t.Errorf("Wanted %v, received %v", ErrReplayTooExpensive, err)
+	}
+}
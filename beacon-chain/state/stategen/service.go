@@ -21,6 +21,9 @@ type State struct {
 	hotStateCache           *cache.HotStateCache
 	splitInfo               *splitSlotAndRoot
 	stateSummaryCache       *cache.StateSummaryCache
+	snapshots               SnapshotStrategy
+	replays                 map[uint64]*coldReplay
+	replayLock              sync.Mutex
 }
 
 // This tracks the split point. The point where slot and the block root of
@@ -39,9 +42,16 @@ func New(db db.NoHeadAccessDatabase, stateSummaryCache *cache.StateSummaryCache)
 		splitInfo:               &splitSlotAndRoot{slot: 0, root: params.BeaconConfig().ZeroHash},
 		slotsPerArchivedPoint:   params.BeaconConfig().SlotsPerArchivedPoint,
 		stateSummaryCache:       stateSummaryCache,
+		replays:                 make(map[uint64]*coldReplay),
 	}
 }
 
+// SetSnapshotStrategy sets the strategy keeping intermediate cold states in memory, to shorten
+// the replay of historical states. A nil strategy disables the snapshots.
+func (s *State) SetSnapshotStrategy(strategy SnapshotStrategy) {
+	s.snapshots = strategy
+}
+
 // Resume resumes a new state management object from previously saved finalized check point in DB.
 func (s *State) Resume(ctx context.Context) (*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "stateGen.Resume")
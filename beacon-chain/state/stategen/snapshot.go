@@ -0,0 +1,176 @@
+package stategen
+
+import (
+	"sort"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+)
+
+// SnapshotStrategy decides which of the cold states computed by replaying blocks are kept in
+// memory as intermediate snapshots, so later queries can replay from a closer state than the
+// last archived point. Only finalized states are given to a strategy, which makes a snapshot
+// valid for any query at or after its slot.
+type SnapshotStrategy interface {
+	// Save offers a computed state to the strategy, which may keep it or ignore it.
+	Save(st *state.BeaconState)
+	// Nearest returns a copy of the snapshot with the highest slot at or below the input slot,
+	// or nil if there is none.
+	Nearest(slot uint64) *state.BeaconState
+	// NearestSlot returns the slot of the snapshot Nearest would return, without copying it.
+	NearestSlot(slot uint64) (uint64, bool)
+}
+
+// ExponentialSnapshots keeps snapshots whose spacing doubles with the distance from the latest
+// one, so recent history is densely covered while the number of snapshots stays logarithmic in
+// the covered range.
+type ExponentialSnapshots struct {
+	interval  uint64
+	max       int
+	snapshots []*state.BeaconState
+	lock      sync.RWMutex
+}
+
+// NewExponentialSnapshots returns a strategy keeping up to max snapshots of states on slots
+// which are multiples of interval.
+func NewExponentialSnapshots(interval uint64, max int) *ExponentialSnapshots {
+	if interval == 0 {
+		interval = 1
+	}
+	return &ExponentialSnapshots{
+		interval: interval,
+		max:      max,
+	}
+}
+
+// Save keeps the state if it lies on the snapshot interval, then thins out the older snapshots.
+func (e *ExponentialSnapshots) Save(st *state.BeaconState) {
+	if st == nil || st.Slot()%e.interval != 0 || e.max <= 0 {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	i := sort.Search(len(e.snapshots), func(i int) bool {
+		return e.snapshots[i].Slot() >= st.Slot()
+	})
+	if i < len(e.snapshots) && e.snapshots[i].Slot() == st.Slot() {
+		return
+	}
+	e.snapshots = append(e.snapshots, nil)
+	copy(e.snapshots[i+1:], e.snapshots[i:])
+	e.snapshots[i] = st.Copy()
+	e.thin()
+}
+
+// This drops the snapshots which are no longer aligned with their distance from the latest one:
+// a snapshot at a distance of [2^k, 2^(k+1)) intervals is kept only if its slot is a multiple of
+// 2^k intervals, which leaves one or two snapshots per range. The oldest snapshots beyond the
+// maximum are dropped.
+func (e *ExponentialSnapshots) thin() {
+	latest := e.snapshots[len(e.snapshots)-1].Slot()
+	kept := make([]*state.BeaconState, 0, len(e.snapshots))
+	for _, st := range e.snapshots {
+		bucket := distanceBucket((latest - st.Slot()) / e.interval)
+		if bucket > 0 && (st.Slot()/e.interval)%(1<<uint(bucket)) != 0 {
+			continue
+		}
+		kept = append(kept, st)
+	}
+	if len(kept) > e.max {
+		kept = kept[len(kept)-e.max:]
+	}
+	e.snapshots = kept
+}
+
+// Nearest returns a copy of the closest snapshot at or below the slot.
+func (e *ExponentialSnapshots) Nearest(slot uint64) *state.BeaconState {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	i := sort.Search(len(e.snapshots), func(i int) bool {
+		return e.snapshots[i].Slot() > slot
+	})
+	if i == 0 {
+		return nil
+	}
+	return e.snapshots[i-1].Copy()
+}
+
+// NearestSlot returns the slot of the closest snapshot at or below the slot.
+func (e *ExponentialSnapshots) NearestSlot(slot uint64) (uint64, bool) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	i := sort.Search(len(e.snapshots), func(i int) bool {
+		return e.snapshots[i].Slot() > slot
+	})
+	if i == 0 {
+		return 0, false
+	}
+	return e.snapshots[i-1].Slot(), true
+}
+
+// distanceBucket returns floor(log2(distance)), with a distance of zero in its own bucket.
+func distanceBucket(distance uint64) int {
+	bucket := -1
+	for distance > 0 {
+		distance >>= 1
+		bucket++
+	}
+	return bucket
+}
+
+// LRUSnapshots keeps the most recently requested states, which suits nodes serving repeated
+// queries around the same historical slots.
+type LRUSnapshots struct {
+	cache *lru.Cache
+}
+
+// NewLRUSnapshots returns a strategy keeping the last size requested states.
+func NewLRUSnapshots(size int) *LRUSnapshots {
+	if size <= 0 {
+		size = 1
+	}
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+	return &LRUSnapshots{cache: cache}
+}
+
+// Save keeps a copy of the state, evicting the least recently used one if the cache is full.
+func (l *LRUSnapshots) Save(st *state.BeaconState) {
+	if st == nil {
+		return
+	}
+	l.cache.Add(st.Slot(), st.Copy())
+}
+
+// Nearest returns a copy of the closest cached state at or below the slot.
+func (l *LRUSnapshots) Nearest(slot uint64) *state.BeaconState {
+	nearest, ok := l.NearestSlot(slot)
+	if !ok {
+		return nil
+	}
+	item, ok := l.cache.Get(nearest)
+	if !ok || item == nil {
+		return nil
+	}
+	return item.(*state.BeaconState).Copy()
+}
+
+// NearestSlot returns the slot of the closest cached state at or below the slot.
+func (l *LRUSnapshots) NearestSlot(slot uint64) (uint64, bool) {
+	var nearest uint64
+	found := false
+	for _, key := range l.cache.Keys() {
+		s := key.(uint64)
+		if s <= slot && (!found || s > nearest) {
+			nearest = s
+			found = true
+		}
+	}
+	return nearest, found
+}
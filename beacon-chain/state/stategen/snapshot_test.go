@@ -0,0 +1,80 @@
+package stategen
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestExponentialSnapshots_SpacingDoubles(t *testing.T) {
+	beaconState, _ := testutil.DeterministicGenesisState(t, 1)
+	snapshots := NewExponentialSnapshots(4, 10)
+	for slot := uint64(0); slot <= 64; slot++ {
+		if err := beaconState.SetSlot(slot); err != nil {
+			t.Fatal(err)
+		}
+		snapshots.Save(beaconState)
+	}
+
+	// With the latest snapshot at 64, the snapshot slots are aligned on 2^k intervals
+	// at a distance of 2^k to 2^(k+1) intervals.
+	wanted := []uint64{0, 32, 48, 56, 60, 64}
+	if len(snapshots.snapshots) != len(wanted) {
+		t.Fatalf("Wanted %d snapshots, received %d", len(wanted), len(snapshots.snapshots))
+	}
+	for i, st := range snapshots.snapshots {
+		if st.Slot() != wanted[i] {
+			t.Errorf("Wanted snapshot %d at slot %d, received %d", i, wanted[i], st.Slot())
+		}
+	}
+
+	if slot, ok := snapshots.NearestSlot(59); !ok || slot != 56 {
+		t.Errorf("Wanted nearest snapshot at slot %d, received %d", 56, slot)
+	}
+	if st := snapshots.Nearest(31); st == nil || st.Slot() != 0 {
+		t.Error("Wanted the snapshot at slot 0")
+	}
+}
+
+func TestExponentialSnapshots_KeepsMax(t *testing.T) {
+	beaconState, _ := testutil.DeterministicGenesisState(t, 1)
+	snapshots := NewExponentialSnapshots(1, 3)
+	for slot := uint64(1); slot <= 100; slot++ {
+		if err := beaconState.SetSlot(slot); err != nil {
+			t.Fatal(err)
+		}
+		snapshots.Save(beaconState)
+	}
+	if len(snapshots.snapshots) != 3 {
+		t.Fatalf("Wanted %d snapshots, received %d", 3, len(snapshots.snapshots))
+	}
+	if st := snapshots.Nearest(100); st == nil || st.Slot() != 100 {
+		t.Error("Did not keep the latest snapshot")
+	}
+}
+
+func TestLRUSnapshots_Nearest(t *testing.T) {
+	beaconState, _ := testutil.DeterministicGenesisState(t, 1)
+	snapshots := NewLRUSnapshots(2)
+	for _, slot := range []uint64{10, 30, 20} {
+		if err := beaconState.SetSlot(slot); err != nil {
+			t.Fatal(err)
+		}
+		snapshots.Save(beaconState)
+	}
+
+	// The state at slot 10 was evicted.
+	if _, ok := snapshots.NearestSlot(15); ok {
+		t.Error("Wanted no snapshot below slot 15")
+	}
+	st := snapshots.Nearest(25)
+	if st == nil || st.Slot() != 20 {
+		t.Fatal("Wanted the snapshot at slot 20")
+	}
+	if err := st.SetSlot(21); err != nil {
+		t.Fatal(err)
+	}
+	if slot, _ := snapshots.NearestSlot(25); slot != 20 {
+		t.Error("Modifying the returned state changed the snapshot")
+	}
+}
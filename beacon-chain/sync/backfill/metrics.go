@@ -0,0 +1,21 @@
+package backfill
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	backfilledBlocksCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "backfill_blocks_total",
+		Help: "Count of blocks backfilled before the block history start slot.",
+	})
+	backfillFailedBatchesCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "backfill_failed_batches_total",
+		Help: "Count of backfill batches which could not be fetched or verified.",
+	})
+	blockHistoryStartSlotGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "backfill_block_history_start_slot",
+		Help: "The slot from which the node holds every block up to its head.",
+	})
+)
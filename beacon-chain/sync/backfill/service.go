@@ -0,0 +1,282 @@
+// Package backfill defines a service which fills in the blocks preceding the oldest block of a
+// node which did not sync from genesis, such as a node started with checkpoint sync.
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/kevinms/leakybucket-go"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = shared.Service(&Service{})
+
+const (
+	// batchSize is the number of slots requested from a peer at once.
+	batchSize = 32
+	// allowedBlocksPerSecond matches the rate at which peers serve blocks by range.
+	allowedBlocksPerSecond = 32.0
+	// retryInterval is the time waited before retrying a batch which could not be backfilled.
+	retryInterval = 5 * time.Second
+)
+
+var (
+	errNoPeersAvailable = errors.New("no peers available to backfill from")
+	errParentMismatch   = errors.New("blocks do not link up to the block history")
+	errGenesisNotFound  = errors.New("reached slot 0 without finding the genesis block")
+)
+
+// Config to set up the backfill service.
+type Config struct {
+	P2P p2p.P2P
+	DB  db.NoHeadAccessDatabase
+}
+
+// Service fetches blocks in reverse, from the block history start slot down to genesis, and
+// saves them once their parent root links are verified. It does not run the state transition
+// on them. Progress is saved with every batch, so backfill resumes from where it stopped after
+// a restart.
+type Service struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	p2p          p2p.P2P
+	db           db.NoHeadAccessDatabase
+	rateLimiter  *leakybucket.Collector
+	cursor       uint64   // blocks below the history start are fetched below this slot
+	expectedRoot [32]byte // root the next backfilled block must have
+	complete     bool
+}
+
+// NewService configures the backfill service.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:         ctx,
+		cancel:      cancel,
+		p2p:         cfg.P2P,
+		db:          cfg.DB,
+		rateLimiter: leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksPerSecond, false /* deleteEmptyBuckets */),
+	}
+}
+
+// Start the backfill service in the background.
+func (s *Service) Start() {
+	go s.run()
+}
+
+// Stop the backfill service.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status of the backfill service.
+func (s *Service) Status() error {
+	return nil
+}
+
+func (s *Service) run() {
+	anchorRoot, err := s.db.AnchorBlockRoot(s.ctx)
+	if err != nil {
+		log.WithError(err).Error("Could not get anchor block root")
+		return
+	}
+	if anchorRoot == params.BeaconConfig().ZeroHash {
+		log.Debug("Node synced from genesis, no blocks to backfill")
+		return
+	}
+	if err := s.resume(s.ctx); err != nil {
+		log.WithError(err).Error("Could not resume backfill")
+		return
+	}
+	if !s.complete {
+		log.WithField("historyStartSlot", s.cursor).Info("Backfilling blocks before the block history start")
+	}
+	for !s.complete {
+		if s.ctx.Err() != nil {
+			return
+		}
+		if err := s.backfillBatch(s.ctx); err != nil {
+			if err != errNoPeersAvailable {
+				backfillFailedBatchesCounter.Inc()
+			}
+			log.WithError(err).Debug("Could not backfill blocks, retrying")
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}
+	log.Info("Backfilled blocks back to genesis")
+}
+
+// resume initializes the backfill progress from the oldest block saved in the database.
+func (s *Service) resume(ctx context.Context) error {
+	startSlot, err := s.db.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get block history start slot")
+	}
+	startRoot, err := s.db.BlockHistoryStartRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get block history start root")
+	}
+	oldest, err := s.db.Block(ctx, startRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not get oldest block")
+	}
+	if oldest == nil || oldest.Block == nil {
+		return fmt.Errorf("missing oldest block in database: block root=%#x", startRoot)
+	}
+	blockHistoryStartSlotGauge.Set(float64(startSlot))
+	s.cursor = startSlot
+	s.expectedRoot = bytesutil.ToBytes32(oldest.Block.ParentRoot)
+	s.complete = oldest.Block.Slot == 0
+	return nil
+}
+
+// backfillBatch requests the batch of slots below the cursor from a peer, and saves the
+// blocks which link up to the block history.
+func (s *Service) backfillBatch(ctx context.Context) error {
+	pid, err := s.selectPeer()
+	if err != nil {
+		return err
+	}
+	start := uint64(0)
+	if s.cursor > batchSize {
+		start = s.cursor - batchSize
+	}
+	blocks, err := s.requestBlocks(ctx, pid, start, s.cursor-start)
+	if err != nil {
+		return errors.Wrapf(err, "could not request blocks from peer %s", pid.Pretty())
+	}
+	verified, err := verifyBatch(blocks, start, s.cursor, s.expectedRoot)
+	if err != nil {
+		// Peers only serve the canonical chain, start over from the history start with another peer.
+		s.p2p.Peers().Increment(pid, peers.InvalidBlock)
+		if err := s.resume(ctx); err != nil {
+			return err
+		}
+		return errors.Wrapf(err, "invalid blocks from peer %s", pid.Pretty())
+	}
+	s.cursor = start
+	if len(verified) == 0 {
+		// The whole batch consists of skipped slots.
+		if start == 0 {
+			if err := s.resume(ctx); err != nil {
+				return err
+			}
+			return errGenesisNotFound
+		}
+		return nil
+	}
+
+	if err := s.db.SaveBackfilledBlocks(ctx, verified); err != nil {
+		return errors.Wrap(err, "could not save backfilled blocks")
+	}
+	oldest := verified[0].Block
+	s.expectedRoot = bytesutil.ToBytes32(oldest.ParentRoot)
+	s.complete = oldest.Slot == 0
+	backfilledBlocksCounter.Add(float64(len(verified)))
+	blockHistoryStartSlotGauge.Set(float64(oldest.Slot))
+	log.WithFields(logrus.Fields{
+		"blocks":           len(verified),
+		"historyStartSlot": oldest.Slot,
+		"peer":             pid.Pretty(),
+	}).Debug("Backfilled blocks")
+	return nil
+}
+
+// verifyBatch checks that the blocks, received for the slots in [start, end), form a chain of
+// parent root links up to the expected root, and returns them sorted by increasing slot.
+func verifyBatch(blocks []*eth.SignedBeaconBlock, start, end uint64, expectedRoot [32]byte) ([]*eth.SignedBeaconBlock, error) {
+	for i := len(blocks) - 1; i >= 0; i-- {
+		blk := blocks[i]
+		if blk == nil || blk.Block == nil {
+			return nil, errors.New("nil block")
+		}
+		if blk.Block.Slot < start || blk.Block.Slot >= end {
+			return nil, fmt.Errorf("block at slot %d is out of the requested range [%d, %d)", blk.Block.Slot, start, end)
+		}
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, err
+		}
+		if root != expectedRoot {
+			return nil, errors.Wrapf(errParentMismatch, "block root %#x at slot %d, expected %#x", root, blk.Block.Slot, expectedRoot)
+		}
+		expectedRoot = bytesutil.ToBytes32(blk.Block.ParentRoot)
+	}
+	return blocks, nil
+}
+
+// selectPeer randomly selects a peer which finalized the epoch of the cursor, and therefore
+// holds the blocks preceding it.
+func (s *Service) selectPeer() (peer.ID, error) {
+	epoch := helpers.SlotToEpoch(s.cursor)
+	_, _, pids := s.p2p.Peers().BestFinalized(params.BeaconConfig().MaxPeersToSync, epoch)
+	candidates := make([]peer.ID, 0, len(pids))
+	for _, pid := range pids {
+		if !s.p2p.Peers().IsBad(pid) {
+			candidates = append(candidates, pid)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", errNoPeersAvailable
+	}
+	randGenerator := rand.New(rand.NewSource(roughtime.Now().Unix()))
+	return candidates[randGenerator.Intn(len(candidates))], nil
+}
+
+// requestBlocks requests the blocks of count slots from the start slot from a peer.
+func (s *Service) requestBlocks(ctx context.Context, pid peer.ID, start, count uint64) ([]*eth.SignedBeaconBlock, error) {
+	if s.rateLimiter.Remaining(pid.String()) < int64(count) {
+		time.Sleep(s.rateLimiter.TillEmpty(pid.String()))
+	}
+	s.rateLimiter.Add(pid.String(), int64(count))
+	req := &p2ppb.BeaconBlocksByRangeRequest{
+		StartSlot: start,
+		Count:     count,
+		Step:      1,
+	}
+	stream, err := s.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := stream.Close(); err != nil {
+			log.WithError(err).Error("Failed to close stream")
+		}
+	}()
+
+	resp := make([]*eth.SignedBeaconBlock, 0, count)
+	for {
+		blk, err := prysmsync.ReadChunkedBlock(stream, s.p2p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, blk)
+	}
+	return resp, nil
+}
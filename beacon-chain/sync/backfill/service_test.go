@@ -0,0 +1,237 @@
+package backfill
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p-core/network"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	p2pt "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// makeChain returns a chain of blocks from genesis, skipping every fifth slot. Chains made
+// with different seeds share no block.
+func makeChain(t *testing.T, slots uint64, seed byte) []*eth.SignedBeaconBlock {
+	parentRoot := [32]byte{seed}
+	chain := make([]*eth.SignedBeaconBlock, 0, slots)
+	for slot := uint64(0); slot < slots; slot++ {
+		if slot%5 == 4 {
+			continue
+		}
+		blk := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: slot, ParentRoot: parentRoot[:]}}
+		root, err := ssz.HashTreeRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentRoot = root
+		chain = append(chain, blk)
+	}
+	return chain
+}
+
+// saveAnchor saves the last block of the chain as the anchor of the database.
+func saveAnchor(t *testing.T, beaconDB db.Database, chain []*eth.SignedBeaconBlock) {
+	ctx := context.Background()
+	anchor := chain[len(chain)-1]
+	root, err := ssz.HashTreeRoot(anchor.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := beaconDB.SaveBlock(ctx, anchor); err != nil {
+		t.Fatal(err)
+	}
+	if err := beaconDB.SaveAnchorBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if err := beaconDB.SaveBlockHistoryStartSlot(ctx, anchor.Block.Slot); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// connectPeer connects a peer serving the given chain by range to the host.
+func connectPeer(t *testing.T, host *p2pt.TestP2P, chain []*eth.SignedBeaconBlock) *p2pt.TestP2P {
+	const topic = "/eth2/beacon_chain/req/beacon_blocks_by_range/1/ssz"
+	peer := p2pt.NewTestP2P(t)
+	peer.SetStreamHandler(topic, func(stream network.Stream) {
+		defer func() {
+			if err := stream.Close(); err != nil {
+				t.Log(err)
+			}
+		}()
+		req := &p2ppb.BeaconBlocksByRangeRequest{}
+		if err := peer.Encoding().DecodeWithLength(stream, req); err != nil {
+			t.Error(err)
+			return
+		}
+		for _, blk := range chain {
+			if blk.Block.Slot < req.StartSlot || blk.Block.Slot >= req.StartSlot+req.Count {
+				continue
+			}
+			if err := prysmsync.WriteChunk(stream, peer.Encoding(), blk); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	peer.Connect(host)
+	host.Peers().Add(new(enr.Record), peer.PeerID(), nil, network.DirOutbound)
+	host.Peers().SetConnectionState(peer.PeerID(), peers.PeerConnected)
+	host.Peers().SetChainState(peer.PeerID(), &p2ppb.Status{
+		ForkDigest:     params.BeaconConfig().GenesisForkVersion,
+		FinalizedRoot:  []byte("finalized_root"),
+		FinalizedEpoch: 10,
+		HeadRoot:       []byte("head_root"),
+		HeadSlot:       320,
+	})
+	return peer
+}
+
+func TestBackfill_ToGenesis(t *testing.T) {
+	beaconDB := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, beaconDB)
+	ctx := context.Background()
+
+	chain := makeChain(t, 100, 0)
+	saveAnchor(t, beaconDB, chain)
+	host := p2pt.NewTestP2P(t)
+	connectPeer(t, host, chain)
+
+	s := NewService(ctx, &Config{P2P: host, DB: beaconDB})
+	if err := s.resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; !s.complete; i++ {
+		if i > 10 {
+			t.Fatal("Backfill did not complete")
+		}
+		if err := s.backfillBatch(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	start, err := beaconDB.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 0 {
+		t.Errorf("Wanted block history start slot 0, received %d", start)
+	}
+	blocks, err := beaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(0).SetEndSlot(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != len(chain) {
+		t.Errorf("Wanted %d blocks, received %d", len(chain), len(blocks))
+	}
+	genesis, err := beaconDB.GenesisBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if genesis == nil || genesis.Block.Slot != 0 {
+		t.Errorf("Expected genesis block to be backfilled, received %v", genesis)
+	}
+}
+
+func TestBackfill_ResumesFromHistoryStart(t *testing.T) {
+	beaconDB := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, beaconDB)
+	ctx := context.Background()
+
+	chain := makeChain(t, 100, 0)
+	saveAnchor(t, beaconDB, chain)
+	host := p2pt.NewTestP2P(t)
+	connectPeer(t, host, chain)
+
+	s := NewService(ctx, &Config{P2P: host, DB: beaconDB})
+	if err := s.resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.backfillBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	firstStart, err := beaconDB.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new service, as after a restart, continues below the saved history start.
+	s = NewService(ctx, &Config{P2P: host, DB: beaconDB})
+	if err := s.resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s.cursor != firstStart {
+		t.Errorf("Wanted to resume from slot %d, resumed from %d", firstStart, s.cursor)
+	}
+	if err := s.backfillBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	start, err := beaconDB.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start >= firstStart {
+		t.Errorf("Expected history start to move below %d, received %d", firstStart, start)
+	}
+}
+
+func TestBackfill_RejectsForkedPeer(t *testing.T) {
+	beaconDB := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, beaconDB)
+	ctx := context.Background()
+
+	chain := makeChain(t, 100, 0)
+	saveAnchor(t, beaconDB, chain)
+	// The peer serves blocks of another chain below the anchor.
+	forked := makeChain(t, 99, 1)
+	host := p2pt.NewTestP2P(t)
+	peer := connectPeer(t, host, forked)
+
+	s := NewService(ctx, &Config{P2P: host, DB: beaconDB})
+	if err := s.resume(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.backfillBatch(ctx); err == nil {
+		t.Fatal("Expected blocks of another chain to be rejected")
+	}
+	components, err := host.Peers().ScoreComponents(peer.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if components[peers.InvalidBlock] >= 0 {
+		t.Errorf("Expected peer to be penalized for invalid blocks, received score components %v", components)
+	}
+	start, err := beaconDB.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != chain[len(chain)-1].Block.Slot {
+		t.Errorf("Expected block history start to be unchanged, received %d", start)
+	}
+}
+
+func TestVerifyBatch_BrokenLink(t *testing.T) {
+	chain := makeChain(t, 10, 0)
+	last := chain[len(chain)-1]
+	expectedRoot, err := ssz.HashTreeRoot(last.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifyBatch(chain, 0, 10, expectedRoot); err != nil {
+		t.Fatalf("Unexpected error for a linked chain: %v", err)
+	}
+	chain[2].Block.ParentRoot = []byte("broken")
+	if _, err := verifyBatch(chain, 0, 10, expectedRoot); err == nil {
+		t.Error("Expected an error for a broken parent link")
+	}
+	if _, err := verifyBatch(chain, 5, 10, expectedRoot); err == nil {
+		t.Error("Expected an error for blocks out of the requested range")
+	}
+}
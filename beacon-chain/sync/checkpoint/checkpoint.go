@@ -0,0 +1,255 @@
+// Package checkpoint initializes the database of a beacon node from a trusted finalized state
+// and block, so the node can sync forward from them instead of from genesis.
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// maxCheckpointMsgSize is the largest finalized checkpoint accepted from a remote node. The SSZ
+// encoded state of a large validator set is far beyond the default gRPC limit of 4MB.
+const maxCheckpointMsgSize = 1 << 30
+
+// Config options for checkpoint sync. Either both StatePath and BlockPath, or RemoteEndpoint
+// are expected to be set. The checkpoint downloaded from RemoteEndpoint is only accepted if its
+// block root and epoch are TrustedRoot and TrustedEpoch, which come from a source the operator
+// trusts rather than from the remote node.
+type Config struct {
+	BeaconDB       db.HeadAccessDatabase
+	StatePath      string
+	BlockPath      string
+	RemoteEndpoint string
+	TrustedRoot    []byte
+	TrustedEpoch   uint64
+	CertPath       string
+}
+
+// Initialize saves the trusted finalized state and block of the config as the anchor of the
+// database. It does nothing if checkpoint sync is not configured, or if the database already
+// holds a chain, in which case the node resumes from it.
+func Initialize(ctx context.Context, cfg *Config) error {
+	if cfg.StatePath == "" && cfg.BlockPath == "" && cfg.RemoteEndpoint == "" {
+		return nil
+	}
+	if cfg.RemoteEndpoint != "" && (cfg.StatePath != "" || cfg.BlockPath != "") {
+		return errors.New("checkpoint sync from files and from a remote node are mutually exclusive")
+	}
+	if cfg.RemoteEndpoint == "" && (cfg.StatePath == "" || cfg.BlockPath == "") {
+		return errors.New("checkpoint sync from files requires both a state and a block")
+	}
+	if cfg.RemoteEndpoint != "" && len(cfg.TrustedRoot) != 32 {
+		return errors.New("checkpoint sync from a remote node requires the trusted checkpoint root and epoch")
+	}
+
+	headBlock, err := cfg.BeaconDB.HeadBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get head block")
+	}
+	if headBlock != nil {
+		log.Warn("Database already holds a chain, ignoring checkpoint sync flags")
+		return nil
+	}
+
+	var st *pb.BeaconState
+	var blk *ethpb.SignedBeaconBlock
+	var epoch uint64
+	if cfg.RemoteEndpoint != "" {
+		st, blk, err = fetchCheckpoint(ctx, cfg)
+		epoch = cfg.TrustedEpoch
+	} else {
+		st, blk, err = readCheckpoint(cfg.StatePath, cfg.BlockPath)
+		if err == nil {
+			// The block is the checkpoint root of the first epoch starting at or after its slot.
+			slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+			epoch = (blk.Block.Slot + slotsPerEpoch - 1) / slotsPerEpoch
+		}
+	}
+	if err != nil {
+		return err
+	}
+	state, err := stateTrie.InitializeFromProto(st)
+	if err != nil {
+		return errors.Wrap(err, "could not initialize checkpoint state")
+	}
+	return SaveAnchor(ctx, cfg.BeaconDB, state, blk, epoch)
+}
+
+// SaveAnchor saves a finalized block and its post state as the anchor of an empty database:
+// the head, justified and finalized checkpoint of the node at the given epoch, as well as the
+// start of its block history. Blocks before the anchor are not available until they are
+// backfilled.
+func SaveAnchor(
+	ctx context.Context,
+	beaconDB db.HeadAccessDatabase,
+	state *stateTrie.BeaconState,
+	blk *ethpb.SignedBeaconBlock,
+	epoch uint64,
+) error {
+	if blk == nil || blk.Block == nil {
+		return errors.New("nil checkpoint block")
+	}
+	if blk.Block.Slot > epoch*params.BeaconConfig().SlotsPerEpoch {
+		return errors.Errorf("checkpoint block slot %d is after the start of checkpoint epoch %d", blk.Block.Slot, epoch)
+	}
+	if state.Slot() != blk.Block.Slot {
+		return errors.Errorf("checkpoint state slot %d does not match block slot %d", state.Slot(), blk.Block.Slot)
+	}
+	stateRoot, err := state.HashTreeRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not hash checkpoint state")
+	}
+	if stateRoot != bytesutil.ToBytes32(blk.Block.StateRoot) {
+		return errors.Errorf("checkpoint state root %#x does not match block state root %#x", stateRoot, blk.Block.StateRoot)
+	}
+	blockRoot, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		return errors.Wrap(err, "could not hash checkpoint block")
+	}
+	slot := blk.Block.Slot
+
+	if err := beaconDB.SaveBlock(ctx, blk); err != nil {
+		return errors.Wrap(err, "could not save checkpoint block")
+	}
+	if err := beaconDB.SaveStateSummary(ctx, &pb.StateSummary{Slot: slot, Root: blockRoot[:]}); err != nil {
+		return errors.Wrap(err, "could not save checkpoint state summary")
+	}
+	if err := beaconDB.SaveState(ctx, state, blockRoot); err != nil {
+		return errors.Wrap(err, "could not save checkpoint state")
+	}
+	archivedIndex := slot / params.BeaconConfig().SlotsPerArchivedPoint
+	if err := beaconDB.SaveArchivedPointRoot(ctx, blockRoot, archivedIndex); err != nil {
+		return errors.Wrap(err, "could not save checkpoint archived point")
+	}
+	if err := beaconDB.SaveLastArchivedIndex(ctx, archivedIndex); err != nil {
+		return errors.Wrap(err, "could not save last archived index")
+	}
+	// The anchor must be recorded before the finalized checkpoint, which indexes the
+	// finalized chain back to it.
+	if err := beaconDB.SaveAnchorBlockRoot(ctx, blockRoot); err != nil {
+		return errors.Wrap(err, "could not save anchor block root")
+	}
+	if err := beaconDB.SaveBlockHistoryStartSlot(ctx, slot); err != nil {
+		return errors.Wrap(err, "could not save block history start slot")
+	}
+	if err := beaconDB.SaveHeadBlockRoot(ctx, blockRoot); err != nil {
+		return errors.Wrap(err, "could not save head block root")
+	}
+	checkpoint := &ethpb.Checkpoint{Epoch: epoch, Root: blockRoot[:]}
+	if err := beaconDB.SaveJustifiedCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrap(err, "could not save justified checkpoint")
+	}
+	if err := beaconDB.SaveFinalizedCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrap(err, "could not save finalized checkpoint")
+	}
+
+	log.WithFields(logrus.Fields{
+		"slot":      slot,
+		"epoch":     epoch,
+		"blockRoot": fmt.Sprintf("%#x", bytesutil.Trunc(blockRoot[:])),
+	}).Info("Initialized database from checkpoint")
+	return nil
+}
+
+func readCheckpoint(statePath string, blockPath string) (*pb.BeaconState, *ethpb.SignedBeaconBlock, error) {
+	enc, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read checkpoint state")
+	}
+	st := &pb.BeaconState{}
+	if err := ssz.Unmarshal(enc, st); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint state")
+	}
+	enc, err = ioutil.ReadFile(blockPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read checkpoint block")
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := ssz.Unmarshal(enc, blk); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint block")
+	}
+	if blk.Block == nil {
+		return nil, nil, errors.New("nil checkpoint block")
+	}
+	return st, blk, nil
+}
+
+// This downloads the finalized checkpoint of a remote node, and returns its state and its block
+// once they are verified to be the ones of the trusted checkpoint.
+func fetchCheckpoint(ctx context.Context, cfg *Config) (*pb.BeaconState, *ethpb.SignedBeaconBlock, error) {
+	endpoint := cfg.RemoteEndpoint
+	var dialOpt grpc.DialOption
+	if cfg.CertPath != "" {
+		creds, err := credentials.NewClientTLSFromFile(cfg.CertPath, "")
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not get valid credentials")
+		}
+		dialOpt = grpc.WithTransportCredentials(creds)
+	} else {
+		dialOpt = grpc.WithInsecure()
+		log.Warn("You are using an insecure gRPC connection! Please provide a certificate to use a secure connection.")
+	}
+	conn, err := grpc.DialContext(
+		ctx,
+		endpoint,
+		dialOpt,
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCheckpointMsgSize)),
+	)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not dial checkpoint sync endpoint %s", endpoint)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.WithError(err).Error("Could not close connection to checkpoint sync endpoint")
+		}
+	}()
+	log.WithField("endpoint", endpoint).Info("Downloading finalized checkpoint")
+	cp, err := rpcpb.NewCheckpointSyncClient(conn).GetFinalizedCheckpoint(ctx, &ptypes.Empty{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not download finalized checkpoint")
+	}
+	return decodeCheckpoint(cp, cfg.TrustedRoot, cfg.TrustedEpoch)
+}
+
+// This decodes the state and block of a finalized checkpoint, and checks the block is the block of
+// the trusted checkpoint root and epoch. Nothing sent by the remote node is trusted: the state is
+// checked against the state root of the block when saving the anchor.
+func decodeCheckpoint(cp *rpcpb.FinalizedCheckpoint, trustedRoot []byte, trustedEpoch uint64) (*pb.BeaconState, *ethpb.SignedBeaconBlock, error) {
+	if cp.Epoch != trustedEpoch {
+		return nil, nil, errors.Errorf("checkpoint epoch %d does not match trusted checkpoint epoch %d", cp.Epoch, trustedEpoch)
+	}
+	st := &pb.BeaconState{}
+	if err := ssz.Unmarshal(cp.EncodedState, st); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint state")
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := ssz.Unmarshal(cp.EncodedBlock, blk); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint block")
+	}
+	if blk.Block == nil {
+		return nil, nil, errors.New("nil checkpoint block")
+	}
+	blockRoot, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not hash checkpoint block")
+	}
+	if blockRoot != bytesutil.ToBytes32(trustedRoot) {
+		return nil, nil, errors.Errorf("checkpoint block root %#x does not match trusted checkpoint root %#x", blockRoot, trustedRoot)
+	}
+	return st, blk, nil
+}
@@ -0,0 +1,252 @@
+package checkpoint
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func checkpointStateAndBlock(t *testing.T, slot uint64) (*stateTrie.BeaconState, *ethpb.SignedBeaconBlock) {
+	st := testutil.NewBeaconState()
+	if err := st.SetSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{
+		Block: &ethpb.BeaconBlock{
+			Slot:       slot,
+			ParentRoot: make([]byte, 32),
+			StateRoot:  stateRoot[:],
+		},
+	}
+	return st, blk
+}
+
+func TestSaveAnchor(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx := context.Background()
+
+	slot := 3*params.BeaconConfig().SlotsPerEpoch + 1
+	st, blk := checkpointStateAndBlock(t, slot)
+	if err := SaveAnchor(ctx, db, st, blk, 4); err != nil {
+		t.Fatal(err)
+	}
+	blockRoot, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headBlock, err := db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headBlock == nil || headBlock.Block.Slot != slot {
+		t.Errorf("Expected head block at slot %d, received %v", slot, headBlock)
+	}
+	savedState, err := db.State(ctx, blockRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if savedState == nil || savedState.Slot() != slot {
+		t.Errorf("Expected checkpoint state at slot %d, received %v", slot, savedState)
+	}
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finalized.Epoch != 4 || blockRoot != bytesutil.ToBytes32(finalized.Root) {
+		t.Errorf("Unexpected finalized checkpoint %v", finalized)
+	}
+	if !db.IsFinalizedBlock(ctx, blockRoot) {
+		t.Error("Expected checkpoint block to be finalized")
+	}
+	anchor, err := db.AnchorBlockRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anchor != blockRoot {
+		t.Errorf("Wanted anchor root %#x, received %#x", blockRoot, anchor)
+	}
+	start, err := db.BlockHistoryStartSlot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != slot {
+		t.Errorf("Wanted block history start slot %d, received %d", slot, start)
+	}
+	if db.LastArchivedIndexRoot(ctx) != blockRoot {
+		t.Error("Expected checkpoint block to be the last archived point")
+	}
+}
+
+func TestSaveAnchor_StateRootMismatch(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx := context.Background()
+
+	st, blk := checkpointStateAndBlock(t, params.BeaconConfig().SlotsPerEpoch)
+	blk.Block.StateRoot = []byte("bad")
+	if err := SaveAnchor(ctx, db, st, blk, 1); err == nil {
+		t.Fatal("Expected a state root mismatch error")
+	}
+	headBlock, err := db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headBlock != nil {
+		t.Error("Expected nothing to be saved")
+	}
+}
+
+func TestSaveAnchor_BlockAfterEpochStart(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+
+	st, blk := checkpointStateAndBlock(t, params.BeaconConfig().SlotsPerEpoch+1)
+	if err := SaveAnchor(context.Background(), db, st, blk, 1); err == nil {
+		t.Fatal("Expected an error for a block after the start of the checkpoint epoch")
+	}
+}
+
+func TestDecodeCheckpoint(t *testing.T) {
+	st, blk := checkpointStateAndBlock(t, params.BeaconConfig().SlotsPerEpoch)
+	encodedState, err := ssz.Marshal(st.InnerStateUnsafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedBlock, err := ssz.Marshal(blk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockRoot, err := ssz.HashTreeRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp := &rpcpb.FinalizedCheckpoint{
+		Epoch:        1,
+		EncodedState: encodedState,
+		EncodedBlock: encodedBlock,
+		Root:         blockRoot[:],
+	}
+	if _, decoded, err := decodeCheckpoint(cp, blockRoot[:], 1); err != nil {
+		t.Fatal(err)
+	} else if decoded.Block.Slot != blk.Block.Slot {
+		t.Errorf("Wanted block at slot %d, received %d", blk.Block.Slot, decoded.Block.Slot)
+	}
+
+	if _, _, err := decodeCheckpoint(cp, make([]byte, 32), 1); err == nil {
+		t.Error("Expected an error for a block which is not the trusted checkpoint root")
+	}
+	if _, _, err := decodeCheckpoint(cp, blockRoot[:], 2); err == nil {
+		t.Error("Expected an error for a checkpoint which is not at the trusted epoch")
+	}
+	// The root sent by the remote node is not trusted.
+	cp.Root = make([]byte, 32)
+	if _, _, err := decodeCheckpoint(cp, cp.Root, 1); err == nil {
+		t.Error("Expected an error for a block which does not match the root sent along with it")
+	}
+}
+
+func TestInitialize_FromFiles(t *testing.T) {
+	db := testDB.SetupDB(t)
+	defer testDB.TeardownDB(t, db)
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "checkpoint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	st, blk := checkpointStateAndBlock(t, params.BeaconConfig().SlotsPerEpoch)
+	encodedState, err := ssz.Marshal(st.InnerStateUnsafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedBlock, err := ssz.Marshal(blk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{
+		BeaconDB:  db,
+		StatePath: filepath.Join(dir, "state.ssz"),
+		BlockPath: filepath.Join(dir, "block.ssz"),
+	}
+	if err := ioutil.WriteFile(cfg.StatePath, encodedState, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(cfg.BlockPath, encodedBlock, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Initialize(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+	headBlock, err := db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headBlock == nil || headBlock.Block.Slot != blk.Block.Slot {
+		t.Errorf("Expected head block at slot %d, received %v", blk.Block.Slot, headBlock)
+	}
+
+	// A database holding a chain is left untouched.
+	other, otherBlk := checkpointStateAndBlock(t, 2*params.BeaconConfig().SlotsPerEpoch)
+	encodedState, err = ssz.Marshal(other.InnerStateUnsafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodedBlock, err = ssz.Marshal(otherBlk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(cfg.StatePath, encodedState, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(cfg.BlockPath, encodedBlock, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Initialize(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+	headBlock, err = db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headBlock.Block.Slot != blk.Block.Slot {
+		t.Errorf("Expected head block to remain at slot %d, received %d", blk.Block.Slot, headBlock.Block.Slot)
+	}
+}
+
+func TestInitialize_InvalidConfig(t *testing.T) {
+	if err := Initialize(context.Background(), &Config{StatePath: "state.ssz"}); err == nil {
+		t.Error("Expected an error for a state without a block")
+	}
+	if err := Initialize(context.Background(), &Config{StatePath: "state.ssz", BlockPath: "block.ssz", RemoteEndpoint: "localhost:4000"}); err == nil {
+		t.Error("Expected an error for both files and a remote endpoint")
+	}
+	if err := Initialize(context.Background(), &Config{RemoteEndpoint: "localhost:4000"}); err == nil {
+		t.Error("Expected an error for a remote endpoint without a trusted checkpoint root")
+	}
+	if err := Initialize(context.Background(), &Config{}); err != nil {
+		t.Errorf("Expected no error without checkpoint sync configured, received %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"math"
 	"math/rand"
+	"net"
 	"sort"
 	"sync"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
 	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/mathutil"
@@ -378,6 +380,9 @@ func (f *blocksFetcher) requestBlocks(
 	f.Unlock()
 	stream, err := f.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
 	if err != nil {
+		if isTimeout(err) {
+			f.p2p.Peers().Increment(pid, peers.RPCTimeout)
+		}
 		return nil, err
 	}
 	defer func() {
@@ -393,14 +398,29 @@ func (f *blocksFetcher) requestBlocks(
 			break
 		}
 		if err != nil {
+			if isTimeout(err) {
+				f.p2p.Peers().Increment(pid, peers.RPCTimeout)
+			}
 			return nil, err
 		}
 		resp = append(resp, blk)
 	}
+	if len(resp) == 0 && req.Count > 0 {
+		f.p2p.Peers().Increment(pid, peers.EmptyBlocksResponse)
+	}
 
 	return resp, nil
 }
 
+// isTimeout returns true if the error is caused by a request deadline being exceeded.
+func isTimeout(err error) bool {
+	if errors.Cause(err) == context.DeadlineExceeded {
+		return true
+	}
+	netErr, ok := errors.Cause(err).(net.Error)
+	return ok && netErr.Timeout()
+}
+
 // selectFailOverPeer randomly selects fail over peer from the list of available peers.
 func selectFailOverPeer(excludedPID peer.ID, peers []peer.ID) (peer.ID, error) {
 	for i, pid := range peers {
@@ -444,7 +464,7 @@ func (f *blocksFetcher) waitForMinimumPeers(ctx context.Context) ([]peer.ID, err
 	}
 }
 
-// selectPeers returns transformed list of peers (randomized, constrained if necessary).
+// selectPeers returns transformed list of peers (randomized, ordered by score, constrained if necessary).
 func (f *blocksFetcher) selectPeers(peers []peer.ID) []peer.ID {
 	if len(peers) == 0 {
 		return peers
@@ -456,6 +476,8 @@ func (f *blocksFetcher) selectPeers(peers []peer.ID) []peer.ID {
 	randGenerator.Shuffle(len(peers), func(i, j int) {
 		peers[i], peers[j] = peers[j], peers[i]
 	})
+	// Prefer peers with the best scores, shuffling only breaks ties among them.
+	peers = f.p2p.Peers().SortByScore(peers)
 
 	required := params.BeaconConfig().MaxPeersToSync
 	if flags.Get().MinimumSyncPeers < required {
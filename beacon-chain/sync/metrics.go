@@ -39,6 +39,13 @@ var (
 		},
 		[]string{"topic"},
 	)
+	rpcRequestsThrottledCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_rpc_requests_throttled_total",
+			Help: "Count of RPC requests rejected for exceeding the rate limit of the peer.",
+		},
+		[]string{"topic"},
+	)
 	numberOfTimesResyncedCounter = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "number_of_times_resynced",
@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"sync"
+
+	"github.com/kevinms/leakybucket-go"
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+var errRateLimited = errors.New(rateLimitedError)
+
+// limiter keeps a token bucket per peer for each req/resp protocol we serve. Blocks by
+// range and blocks by root requests draw blocks from the same budget, while every other
+// protocol has a budget of requests of its own.
+type limiter struct {
+	lock       sync.Mutex
+	limiterMap map[string]*leakybucket.Collector
+}
+
+// newRateLimiter creates the rate limiter of the req/resp protocols, with the limits configured
+// by the block batch limit and rpc request limit flags.
+func newRateLimiter() *limiter {
+	blockCollector := leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */)
+	newRequestCollector := func() *leakybucket.Collector {
+		return leakybucket.NewCollector(allowedRequestsPerSecond, allowedRequestsBurst, false /* deleteEmptyBuckets */)
+	}
+	return &limiter{
+		limiterMap: map[string]*leakybucket.Collector{
+			p2p.RPCBlocksByRangeTopic: blockCollector,
+			p2p.RPCBlocksByRootTopic:  blockCollector,
+			p2p.RPCStatusTopic:        newRequestCollector(),
+			p2p.RPCPingTopic:          newRequestCollector(),
+			p2p.RPCMetaDataTopic:      newRequestCollector(),
+		},
+	}
+}
+
+// take charges amount against the budget of the remote peer of the stream for the given
+// protocol topic. It returns errRateLimited, without charging anything, if the peer has not
+// enough budget left.
+func (l *limiter) take(stream libp2pcore.Stream, topic string, amount int64) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	collector, ok := l.limiterMap[topic]
+	if !ok {
+		return errors.Errorf("no rate limit for topic %s", topic)
+	}
+	key := stream.Conn().RemotePeer().String()
+	if amount > collector.Remaining(key) {
+		return errRateLimited
+	}
+	collector.Add(key, amount)
+	return nil
+}
+
+// validateRequest charges a request of amount units, blocks or requests depending on the
+// protocol, to the remote peer of the stream. A peer exceeding its budget receives an invalid
+// request response, is penalized in its score and is disconnected once it is considered bad.
+func (r *Service) validateRequest(stream libp2pcore.Stream, topic string, amount int64) error {
+	err := r.rateLimiter.take(stream, topic, amount)
+	if err != errRateLimited {
+		return err
+	}
+	pid := stream.Conn().RemotePeer()
+	rpcRequestsThrottledCounter.WithLabelValues(topic).Inc()
+	r.p2p.Peers().Increment(pid, peers.RateLimited)
+	r.writeErrorResponseToStream(responseCodeInvalidRequest, rateLimitedError, stream)
+	if r.p2p.Peers().IsBad(pid) {
+		log.WithField("peer", pid).Debug("Disconnecting bad peer")
+		if err := r.p2p.Disconnect(pid); err != nil {
+			log.WithError(err).Error("Failed to disconnect peer")
+		}
+	}
+	return err
+}
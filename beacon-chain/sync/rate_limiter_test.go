@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestRateLimiter_ExceedingBudgetIsRejected(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	r := &Service{p2p: p1, rateLimiter: newRateLimiter()}
+
+	pcl := protocol.ID("/testing")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	p2.Host.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		code, errMsg, err := ReadStatusCode(stream, &encoder.SszNetworkEncoder{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != responseCodeInvalidRequest {
+			t.Errorf("Expected response code %d, received %d", responseCodeInvalidRequest, code)
+		}
+		if errMsg != rateLimitedError {
+			t.Errorf("Expected error message %q, received %q", rateLimitedError, errMsg)
+		}
+	})
+	stream, err := p1.Host.NewStream(context.Background(), p2.Host.ID(), pcl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int64(0); i < allowedRequestsBurst; i++ {
+		if err := r.validateRequest(stream, p2p.RPCPingTopic, 1); err != nil {
+			t.Fatalf("Request %d within burst was rejected: %v", i, err)
+		}
+	}
+	if err := r.validateRequest(stream, p2p.RPCPingTopic, 1); err != errRateLimited {
+		t.Fatalf("Expected request above burst to be rate limited, received %v", err)
+	}
+	if testutil.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+
+	components, err := p1.Peers().ScoreComponents(p2.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if components[peers.RateLimited] >= 0 {
+		t.Errorf("Expected peer to be penalized for exceeding its rate limit, received %v", components)
+	}
+}
+
+func TestRateLimiter_ProtocolsHaveSeparateBudgets(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	l := newRateLimiter()
+
+	stream, err := p1.Host.NewStream(context.Background(), p2.Host.ID(), protocol.ID("/testing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.take(stream, p2p.RPCStatusTopic, allowedRequestsBurst); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.take(stream, p2p.RPCStatusTopic, 1); err != errRateLimited {
+		t.Errorf("Expected status request to be rate limited, received %v", err)
+	}
+	if err := l.take(stream, p2p.RPCMetaDataTopic, 1); err != nil {
+		t.Errorf("Expected metadata request to be allowed, received %v", err)
+	}
+
+	// Blocks by range and blocks by root requests share a budget of blocks.
+	if err := l.take(stream, p2p.RPCBlocksByRangeTopic, allowedBlocksBurst); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.take(stream, p2p.RPCBlocksByRootTopic, int64(allowedBlocksPerSecond)); err != errRateLimited {
+		t.Errorf("Expected blocks by root request to be rate limited, received %v", err)
+	}
+}
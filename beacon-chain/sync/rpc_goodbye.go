@@ -8,6 +8,7 @@ import (
 	libp2pcore "github.com/libp2p/go-libp2p-core"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 )
 
 const (
@@ -22,6 +23,13 @@ var goodByes = map[uint64]string{
 	codeGenericError:   "fault/error",
 }
 
+// goodbyeScores maps the goodbye reasons which reflect badly on the sending peer to the
+// score component they count towards.
+var goodbyeScores = map[uint64]peers.ScoreComponent{
+	codeWrongNetwork: peers.IrrelevantNetworkGoodbye,
+	codeGenericError: peers.FaultGoodbye,
+}
+
 // goodbyeRPCHandler reads the incoming goodbye rpc message from the peer.
 func (r *Service) goodbyeRPCHandler(ctx context.Context, msg interface{}, stream libp2pcore.Stream) error {
 	defer func() {
@@ -39,6 +47,9 @@ func (r *Service) goodbyeRPCHandler(ctx context.Context, msg interface{}, stream
 	}
 	log := log.WithField("Reason", goodbyeMessage(*m))
 	log.WithField("peer", stream.Conn().RemotePeer()).Info("Peer has sent a goodbye message")
+	if component, ok := goodbyeScores[*m]; ok {
+		r.p2p.Peers().Increment(stream.Conn().RemotePeer(), component)
+	}
 	// closes all streams with the peer
 	return r.p2p.Disconnect(stream.Conn().RemotePeer())
 }
@@ -21,6 +21,9 @@ func (r *Service) metaDataHandler(ctx context.Context, msg interface{}, stream l
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	setRPCStreamDeadlines(stream)
+	if err := r.validateRequest(stream, p2p.RPCMetaDataTopic, 1); err != nil {
+		return err
+	}
 
 	if _, err := stream.Write([]byte{responseCodeSuccess}); err != nil {
 		return err
@@ -34,8 +34,9 @@ func TestMetaDataRPCHandler_ReceivesMetadata(t *testing.T) {
 	defer db.TeardownDB(t, d)
 
 	r := &Service{
-		db:  d,
-		p2p: p1,
+		db:          d,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 	}
 
 	// Setup streams
@@ -91,13 +92,15 @@ func TestMetadataRPCHandler_SendsMetadata(t *testing.T) {
 	defer db.TeardownDB(t, d)
 
 	r := &Service{
-		db:  d,
-		p2p: p1,
+		db:          d,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 	}
 
 	r2 := &Service{
-		db:  d,
-		p2p: p2,
+		db:          d,
+		p2p:         p2,
+		rateLimiter: newRateLimiter(),
 	}
 
 	// Setup streams
@@ -26,6 +26,9 @@ func (r *Service) pingHandler(ctx context.Context, msg interface{}, stream libp2
 	if !ok {
 		return fmt.Errorf("wrong message type for ping, got %T, wanted *uint64", msg)
 	}
+	if err := r.validateRequest(stream, p2p.RPCPingTopic, 1); err != nil {
+		return err
+	}
 	valid, err := r.validateSequenceNum(*m, stream.Conn().RemotePeer())
 	if err != nil {
 		return err
@@ -37,8 +37,9 @@ func TestPingRPCHandler_ReceivesPing(t *testing.T) {
 	defer db.TeardownDB(t, d)
 
 	r := &Service{
-		db:  d,
-		p2p: p1,
+		db:          d,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 	}
 
 	p1.Peers().Add(new(enr.Record), p2.Host.ID(), p2.Host.Addrs()[0], network.DirUnknown)
@@ -102,8 +103,9 @@ func TestPingRPCHandler_SendsPing(t *testing.T) {
 	defer db.TeardownDB(t, d)
 
 	r := &Service{
-		db:  d,
-		p2p: p1,
+		db:          d,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 	}
 
 	p1.Peers().Add(new(enr.Record), p2.Host.ID(), p2.Host.Addrs()[0], network.DirUnknown)
@@ -113,8 +115,9 @@ func TestPingRPCHandler_SendsPing(t *testing.T) {
 	p2.Peers().SetMetadata(p1.Host.ID(), p1.LocalMetadata)
 
 	r2 := &Service{
-		db:  d,
-		p2p: p2,
+		db:          d,
+		p2p:         p2,
+		rateLimiter: newRateLimiter(),
 	}
 	// Setup streams
 	pcl := protocol.ID("/eth2/beacon_chain/req/ping/1/ssz")
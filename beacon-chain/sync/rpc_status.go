@@ -147,6 +147,9 @@ func (r *Service) statusRPCHandler(ctx context.Context, msg interface{}, stream
 	if !ok {
 		return errors.New("message is not type *pb.Status")
 	}
+	if err := r.validateRequest(stream, p2p.RPCStatusTopic, 1); err != nil {
+		return err
+	}
 
 	if err := r.validateStatusMessage(m, stream); err != nil {
 		log.WithField("peer", stream.Conn().RemotePeer()).Debug("Invalid fork version from peer")
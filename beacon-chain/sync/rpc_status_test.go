@@ -35,7 +35,9 @@ func TestHelloRPCHandler_Disconnects_OnForkVersionMismatch(t *testing.T) {
 		t.Error("Expected peers to be connected")
 	}
 
-	r := &Service{p2p: p1,
+	r := &Service{
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 		chain: &mock.ChainService{
 			Genesis:        time.Now(),
 			ValidatorsRoot: [32]byte{'A'},
@@ -111,7 +113,8 @@ func TestHelloRPCHandler_ReturnsHelloMessage(t *testing.T) {
 	}
 
 	r := &Service{
-		p2p: p1,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 		chain: &mock.ChainService{
 			State:               genesisState,
 			FinalizedCheckPoint: finalizedCheckpt,
@@ -189,7 +192,8 @@ func TestHandshakeHandlers_Roundtrip(t *testing.T) {
 		t.Fatal(err)
 	}
 	r := &Service{
-		p2p: p1,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 		chain: &mock.ChainService{
 			State:               st,
 			FinalizedCheckPoint: &ethpb.Checkpoint{},
@@ -208,7 +212,8 @@ func TestHandshakeHandlers_Roundtrip(t *testing.T) {
 	}
 
 	r2 := &Service{
-		p2p: p2,
+		p2p:         p2,
+		rateLimiter: newRateLimiter(),
 	}
 	p2.Digest, err = r.forkDigest()
 	if err != nil {
@@ -343,7 +348,8 @@ func TestStatusRPCRequest_RequestSent(t *testing.T) {
 	}
 
 	r := &Service{
-		p2p: p1,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 		chain: &mock.ChainService{
 			State:               genesisState,
 			FinalizedCheckPoint: finalizedCheckpt,
@@ -425,7 +431,8 @@ func TestStatusRPCRequest_BadPeerHandshake(t *testing.T) {
 	}
 
 	r := &Service{
-		p2p: p1,
+		p2p:         p1,
+		rateLimiter: newRateLimiter(),
 		chain: &mock.ChainService{
 			State:               genesisState,
 			FinalizedCheckPoint: finalizedCheckpt,
@@ -7,7 +7,6 @@ import (
 
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	lru "github.com/hashicorp/golang-lru"
-	"github.com/kevinms/leakybucket-go"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
@@ -30,6 +29,8 @@ var _ = shared.Service(&Service{})
 
 var allowedBlocksPerSecond float64
 var allowedBlocksBurst int64
+var allowedRequestsPerSecond float64
+var allowedRequestsBurst int64
 
 const rangeLimit = 1000
 const seenBlockSize = 1000
@@ -86,7 +87,7 @@ type Service struct {
 	validateBlockLock         sync.RWMutex
 	stateNotifier             statefeed.Notifier
 	blockNotifier             blockfeed.Notifier
-	blocksRateLimiter         *leakybucket.Collector
+	rateLimiter               *limiter
 	attestationNotifier       operation.Notifier
 	seenBlockLock             sync.RWMutex
 	seenBlockCache            *lru.Cache
@@ -104,9 +105,11 @@ type Service struct {
 
 // NewRegularSync service.
 func NewRegularSync(cfg *Config) *Service {
-	// Intialize block limits.
+	// Intialize block and request limits.
 	allowedBlocksPerSecond = float64(flags.Get().BlockBatchLimit)
-	allowedBlocksBurst = int64(10 * allowedBlocksPerSecond)
+	allowedBlocksBurst = int64(flags.Get().BlockBatchLimitBurstFactor) * int64(allowedBlocksPerSecond)
+	allowedRequestsPerSecond = float64(flags.Get().RPCRequestLimit)
+	allowedRequestsBurst = int64(flags.Get().RPCRequestBurst)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	r := &Service{
@@ -127,7 +130,7 @@ func NewRegularSync(cfg *Config) *Service {
 		blockNotifier:        cfg.BlockNotifier,
 		stateSummaryCache:    cfg.StateSummaryCache,
 		stateGen:             cfg.StateGen,
-		blocksRateLimiter:    leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */),
+		rateLimiter:          newRateLimiter(),
 	}
 
 	r.registerRPCHandlers()
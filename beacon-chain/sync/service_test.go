@@ -14,6 +14,8 @@ import (
 func init() {
 	allowedBlocksPerSecond = 64
 	allowedBlocksBurst = int64(10 * allowedBlocksPerSecond)
+	allowedRequestsPerSecond = 2
+	allowedRequestsBurst = 10
 }
 
 func TestService_StatusZeroEpoch(t *testing.T) {
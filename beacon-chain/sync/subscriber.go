@@ -15,6 +15,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
 	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/messagehandler"
 	"github.com/prysmaticlabs/prysm/shared/p2putils"
@@ -128,7 +129,7 @@ func (r *Service) subscribeWithBase(base proto.Message, topic string, validator
 	topic += r.p2p.Encoding().ProtocolSuffix()
 	log := log.WithField("topic", topic)
 
-	if err := r.p2p.PubSub().RegisterTopicValidator(wrapAndReportValidation(topic, validator)); err != nil {
+	if err := r.p2p.PubSub().RegisterTopicValidator(r.wrapAndReportValidation(topic, validator)); err != nil {
 		log.WithError(err).Error("Failed to register validator")
 	}
 
@@ -195,7 +196,7 @@ func (r *Service) subscribeWithBase(base proto.Message, topic string, validator
 
 // Wrap the pubsub validator with a metric monitoring function. This function increments the
 // appropriate counter if the particular message fails to validate.
-func wrapAndReportValidation(topic string, v pubsub.Validator) (string, pubsub.Validator) {
+func (r *Service) wrapAndReportValidation(topic string, v pubsub.Validator) (string, pubsub.Validator) {
 	return topic, func(ctx context.Context, pid peer.ID, msg *pubsub.Message) bool {
 		defer messagehandler.HandlePanic(ctx, msg)
 		ctx, _ = context.WithTimeout(ctx, pubsubMessageTimeout)
@@ -203,6 +204,8 @@ func wrapAndReportValidation(topic string, v pubsub.Validator) (string, pubsub.V
 		b := v(ctx, pid, msg)
 		if !b {
 			messageFailedValidationCounter.WithLabelValues(topic).Inc()
+		} else if pid != r.p2p.PeerID() {
+			r.p2p.Peers().Increment(pid, peers.GossipDelivery)
 		}
 		return b
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
@@ -105,6 +106,7 @@ func (r *Service) validateBeaconBlockPubSub(ctx context.Context, pid peer.ID, ms
 
 		if err := blocks.VerifyBlockHeaderSignature(parentState, blk); err != nil {
 			log.WithError(err).WithField("blockSlot", blk.Block.Slot).Warn("Could not verify block signature")
+			r.p2p.Peers().Increment(pid, peers.InvalidBlock)
 			return false
 		}
 
@@ -120,6 +122,7 @@ func (r *Service) validateBeaconBlockPubSub(ctx context.Context, pid peer.ID, ms
 		}
 		if blk.Block.ProposerIndex != idx {
 			log.WithError(err).WithField("blockSlot", blk.Block.Slot).Warn("Incorrect proposer index")
+			r.p2p.Peers().Increment(pid, peers.InvalidBlock)
 			return false
 		}
 	}
@@ -94,6 +94,31 @@ var appHelpFlagGroups = []flagGroup{
 			flags.SlotsPerArchivedPoint,
 			flags.DisableDiscv5,
 			flags.BlockBatchLimit,
+			flags.BlockBatchLimitBurstFactor,
+			flags.RPCRequestLimit,
+			flags.RPCRequestBurst,
+			flags.CheckpointStateFlag,
+			flags.CheckpointBlockFlag,
+			flags.CheckpointSyncRPCFlag,
+			flags.CheckpointSyncRootFlag,
+			flags.CheckpointSyncEpochFlag,
+			flags.CheckpointSyncCertFlag,
+			flags.PersistOperationPoolsFlag,
+			flags.AttestationPoolPersistIntervalFlag,
+			flags.ForkChoiceSnapshotIntervalFlag,
+			flags.ForkChoiceSnapshotRetentionFlag,
+			flags.ValidatorPerformanceFlag,
+			flags.BeaconAPIPort,
+			flags.StateSnapshotStrategyFlag,
+			flags.StateSnapshotCountFlag,
+			flags.MaxStateReplaySlotsFlag,
+			flags.PruneDBFlag,
+			flags.PruneRetentionSlotsFlag,
+			flags.PruneDryRunFlag,
+			flags.DBCompactionIntervalFlag,
+			flags.FallbackWeb3ProviderFlag,
+			flags.FallbackHTTPWeb3ProviderFlag,
+			flags.ETH1ChainIDFlag,
 		},
 	},
 	{
@@ -20,7 +20,12 @@ func (bs *Service) ChainHead(
 ) (*ethpb.ChainHead, error) {
 	ctx, span := trace.StartSpan(ctx, "beaconclient.ChainHead")
 	defer span.End()
-	res, err := bs.beaconClient.GetChainHead(ctx, &ptypes.Empty{})
+	var res *ethpb.ChainHead
+	err := bs.request(ctx, func(src *beaconSource) error {
+		var err error
+		res, err = src.beaconClient.GetChainHead(ctx, &ptypes.Empty{})
+		return err
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not retrieve chain head")
 	}
@@ -30,11 +35,7 @@ func (bs *Service) ChainHead(
 // Poll the beacon node every syncStatusPollingInterval until the node
 // is no longer syncing.
 func (bs *Service) querySyncStatus(ctx context.Context) {
-	status, err := bs.nodeClient.GetSyncStatus(ctx, &ptypes.Empty{})
-	if err != nil {
-		log.WithError(err).Error("Could not fetch sync status")
-	}
-	if !status.Syncing {
+	if !bs.syncing(ctx) {
 		log.Info("Beacon node is fully synced, starting slashing detection")
 		return
 	}
@@ -43,11 +44,7 @@ func (bs *Service) querySyncStatus(ctx context.Context) {
 	for {
 		select {
 		case <-ticker.C:
-			status, err := bs.nodeClient.GetSyncStatus(ctx, &ptypes.Empty{})
-			if err != nil {
-				log.WithError(err).Error("Could not fetch sync status")
-			}
-			if !status.Syncing {
+			if !bs.syncing(ctx) {
 				log.Info("Beacon node is fully synced, starting slashing detection")
 				return
 			}
@@ -58,3 +55,19 @@ func (bs *Service) querySyncStatus(ctx context.Context) {
 		}
 	}
 }
+
+// syncing returns true while the beacon node answering the request is syncing, or if no
+// beacon node answers.
+func (bs *Service) syncing(ctx context.Context) bool {
+	var status *ethpb.SyncStatus
+	err := bs.request(ctx, func(src *beaconSource) error {
+		var err error
+		status, err = src.nodeClient.GetSyncStatus(ctx, &ptypes.Empty{})
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Error("Could not fetch sync status")
+		return true
+	}
+	return status.Syncing
+}
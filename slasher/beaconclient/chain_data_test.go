@@ -2,6 +2,7 @@ package beaconclient
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -19,7 +20,7 @@ func TestService_ChainHead(t *testing.T) {
 	client := mock.NewMockBeaconChainClient(ctrl)
 
 	bs := Service{
-		beaconClient: client,
+		sources: []*beaconSource{{beaconClient: client}},
 	}
 	wanted := &ethpb.ChainHead{
 		HeadSlot:      4,
@@ -43,7 +44,7 @@ func TestService_QuerySyncStatus(t *testing.T) {
 	client := mock.NewMockNodeClient(ctrl)
 
 	bs := Service{
-		nodeClient: client,
+		sources: []*beaconSource{{nodeClient: client}},
 	}
 	syncStatusPollingInterval = time.Millisecond
 	client.EXPECT().GetSyncStatus(gomock.Any(), gomock.Any()).Return(&ethpb.SyncStatus{
@@ -56,3 +57,33 @@ func TestService_QuerySyncStatus(t *testing.T) {
 	testutil.AssertLogsContain(t, hook, "Waiting for beacon node to be fully synced...")
 	testutil.AssertLogsContain(t, hook, "Beacon node is fully synced")
 }
+
+func TestService_ChainHead_FallsBackToNextBeaconNode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	lagging := mock.NewMockBeaconChainClient(ctrl)
+	failing := mock.NewMockBeaconChainClient(ctrl)
+	healthy := mock.NewMockBeaconChainClient(ctrl)
+
+	laggingSrc := &beaconSource{provider: "localhost:4000", beaconClient: lagging}
+	failingSrc := &beaconSource{provider: "localhost:4001", beaconClient: failing}
+	healthySrc := &beaconSource{provider: "localhost:4002", beaconClient: healthy}
+	bs := Service{
+		sources: []*beaconSource{laggingSrc, failingSrc, healthySrc},
+	}
+	bs.recordBlock(failingSrc, 10+maxSourceLag+1)
+	bs.recordBlock(healthySrc, 10+maxSourceLag+1)
+	bs.recordBlock(laggingSrc, 10)
+
+	// The lagging beacon node is only tried after the healthy ones.
+	wanted := &ethpb.ChainHead{HeadSlot: 4}
+	failing.EXPECT().GetChainHead(gomock.Any(), gomock.Any()).Return(nil, errors.New("unavailable"))
+	healthy.EXPECT().GetChainHead(gomock.Any(), gomock.Any()).Return(wanted, nil)
+	res, err := bs.ChainHead(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(res, wanted) {
+		t.Errorf("Wanted %v, received %v", wanted, res)
+	}
+}
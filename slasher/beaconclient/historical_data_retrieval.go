@@ -17,11 +17,33 @@ func (bs *Service) RequestHistoricalAttestations(
 ) ([]*ethpb.IndexedAttestation, error) {
 	ctx, span := trace.StartSpan(ctx, "beaconclient.RequestHistoricalAttestations")
 	defer span.End()
+	var indexedAtts []*ethpb.IndexedAttestation
+	// Page tokens are specific to a beacon node, so the attestations are requested again
+	// from the start when falling back to another node.
+	err := bs.request(ctx, func(src *beaconSource) error {
+		var err error
+		indexedAtts, err = requestEpochAttestations(ctx, src.beaconClient, epoch)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.slasherDB.SaveIndexedAttestations(ctx, indexedAtts); err != nil {
+		return nil, errors.Wrap(err, "could not save indexed attestations")
+	}
+	return indexedAtts, nil
+}
+
+func requestEpochAttestations(
+	ctx context.Context,
+	client ethpb.BeaconChainClient,
+	epoch uint64,
+) ([]*ethpb.IndexedAttestation, error) {
 	indexedAtts := make([]*ethpb.IndexedAttestation, 0)
 	res := &ethpb.ListIndexedAttestationsResponse{}
 	var err error
 	for {
-		res, err = bs.beaconClient.ListIndexedAttestations(ctx, &ethpb.ListIndexedAttestationsRequest{
+		res, err = client.ListIndexedAttestations(ctx, &ethpb.ListIndexedAttestationsRequest{
 			QueryFilter: &ethpb.ListIndexedAttestationsRequest_Epoch{
 				Epoch: epoch,
 			},
@@ -42,8 +64,5 @@ func (bs *Service) RequestHistoricalAttestations(
 			break
 		}
 	}
-	if err := bs.slasherDB.SaveIndexedAttestations(ctx, indexedAtts); err != nil {
-		return nil, errors.Wrap(err, "could not save indexed attestations")
-	}
 	return indexedAtts, nil
 }
@@ -24,8 +24,8 @@ func TestService_RequestHistoricalAttestations(t *testing.T) {
 	client := mock.NewMockBeaconChainClient(ctrl)
 
 	bs := Service{
-		beaconClient: client,
-		slasherDB:    db,
+		sources:   []*beaconSource{{beaconClient: client}},
+		slasherDB: db,
 	}
 
 	numAtts := 1000
@@ -10,4 +10,16 @@ var (
 		Name: "slasher_attestations_received_total",
 		Help: "The # of attestations received by slasher",
 	})
+	duplicateObjectsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "slasher_duplicate_objects_received_total",
+		Help: "The # of blocks and attestations dropped by slasher as already received from another beacon node",
+	}, []string{"kind"})
+	sourceLagSlots = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "slasher_beacon_source_lag_slots",
+		Help: "The # of slots a beacon node slasher receives data from is behind the most advanced one",
+	}, []string{"provider"})
+	sourceHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "slasher_beacon_source_healthy",
+		Help: "Whether slasher receives blocks and attestations from a beacon node without falling behind",
+	}, []string{"provider"})
 )
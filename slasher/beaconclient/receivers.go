@@ -3,14 +3,13 @@ package beaconclient
 import (
 	"context"
 	"errors"
-	"io"
 	"time"
 
 	ptypes "github.com/gogo/protobuf/types"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
@@ -19,101 +18,122 @@ import (
 var reconnectPeriod = 5 * time.Second
 
 // receiveBlocks starts a gRPC client stream listener to obtain
-// blocks from the beacon node. Upon receiving a block, the service
-// broadcasts it to a feed for other services in slasher to subscribe to.
-func (bs *Service) receiveBlocks(ctx context.Context) {
+// blocks from a beacon node. Upon receiving a block not yet received from
+// another beacon node, the service broadcasts it to a feed for other
+// services in slasher to subscribe to.
+func (bs *Service) receiveBlocks(ctx context.Context, src *beaconSource) {
 	ctx, span := trace.StartSpan(ctx, "beaconclient.receiveBlocks")
 	defer span.End()
-	stream, err := bs.beaconClient.StreamBlocks(ctx, &ptypes.Empty{})
+	stream, err := src.beaconClient.StreamBlocks(ctx, &ptypes.Empty{})
 	if err != nil {
-		log.WithError(err).Error("Failed to retrieve blocks stream")
-		return
+		log.WithError(err).WithField("provider", src.provider).Error("Failed to retrieve blocks stream")
+		bs.setStreamErr(src, blocksStream, err)
+		stream, err = bs.restartBlockStream(ctx, src)
+		if err != nil {
+			log.WithError(err).Error("Could not restart stream")
+			return
+		}
 	}
 	for {
 		res, err := stream.Recv()
-		// If the stream is closed, we stop the loop.
-		if err == io.EOF {
-			break
-		}
 		// If context is canceled we stop the loop.
 		if ctx.Err() == context.Canceled {
 			log.WithError(ctx.Err()).Error("Context canceled - shutting down blocks receiver")
 			return
 		}
+		// If the stream is closed or fails, we restart it as the beacon node may come back.
 		if err != nil {
 			if e, ok := status.FromError(err); ok {
-				switch e.Code() {
-				case codes.Canceled:
-					stream, err = bs.restartBlockStream(ctx)
-					if err != nil {
-						log.WithError(err).Error("Could not restart stream")
-						return
-					}
-					break
-				default:
-					log.WithError(err).Errorf("Could not receive block from beacon node. rpc status: %v", e.Code())
-					return
-				}
+				log.WithError(err).WithField("provider", src.provider).Errorf("Could not receive block from beacon node. rpc status: %v", e.Code())
 			} else {
-				log.WithError(err).Error("Could not receive blocks from beacon node")
+				log.WithError(err).WithField("provider", src.provider).Error("Could not receive blocks from beacon node")
+			}
+			bs.setStreamErr(src, blocksStream, err)
+			stream, err = bs.restartBlockStream(ctx, src)
+			if err != nil {
+				log.WithError(err).Error("Could not restart stream")
 				return
 			}
+			continue
 		}
-		if res == nil {
+		if res == nil || res.Block == nil {
+			continue
+		}
+		bs.recordBlock(src, res.Block.Slot)
+		// The block root is the signing root of the header of the block.
+		root, err := ssz.HashTreeRoot(res.Block)
+		if err != nil {
+			log.WithError(err).Error("Could not compute block root")
+			continue
+		}
+		if !bs.firstSeen(root) {
+			duplicateObjectsReceived.WithLabelValues("block").Inc()
 			continue
 		}
-		log.WithField("slot", res.Block.Slot).Info("Received block from beacon node")
+		log.WithFields(logrus.Fields{
+			"slot":     res.Block.Slot,
+			"provider": src.provider,
+		}).Info("Received block from beacon node")
 		// We send the received block over the block feed.
 		bs.blockFeed.Send(res)
 	}
 }
 
 // receiveAttestations starts a gRPC client stream listener to obtain
-// attestations from the beacon node. Upon receiving an attestation, the service
-// broadcasts it to a feed for other services in slasher to subscribe to.
-func (bs *Service) receiveAttestations(ctx context.Context) {
+// attestations from a beacon node. Upon receiving an attestation not yet received
+// from another beacon node, the service buffers it to be saved and broadcast
+// to a feed for other services in slasher to subscribe to.
+func (bs *Service) receiveAttestations(ctx context.Context, src *beaconSource) {
 	ctx, span := trace.StartSpan(ctx, "beaconclient.receiveAttestations")
 	defer span.End()
-	stream, err := bs.beaconClient.StreamIndexedAttestations(ctx, &ptypes.Empty{})
+	stream, err := src.beaconClient.StreamIndexedAttestations(ctx, &ptypes.Empty{})
 	if err != nil {
-		log.WithError(err).Error("Failed to retrieve attestations stream")
-		return
+		log.WithError(err).WithField("provider", src.provider).Error("Failed to retrieve attestations stream")
+		bs.setStreamErr(src, attestationsStream, err)
+		stream, err = bs.restartIndexedAttestationStream(ctx, src)
+		if err != nil {
+			log.WithError(err).Error("Could not restart stream")
+			return
+		}
 	}
 
-	go bs.collectReceivedAttestations(ctx)
 	for {
 		res, err := stream.Recv()
-		// If the stream is closed, we stop the loop.
-		if err == io.EOF {
-			break
-		}
 		// If context is canceled we stop the loop.
 		if ctx.Err() == context.Canceled {
 			log.WithError(ctx.Err()).Error("Context canceled - shutting down attestations receiver")
 			return
 		}
+		// If the stream is closed or fails, we restart it as the beacon node may come back.
 		if err != nil {
 			if e, ok := status.FromError(err); ok {
-				switch e.Code() {
-				case codes.Canceled:
-					stream, err = bs.restartIndexedAttestationStream(ctx)
-					if err != nil {
-						log.WithError(err).Error("Could not restart stream")
-						return
-					}
-					break
-				default:
-					log.WithError(err).Errorf("Could not receive attestations from beacon node. rpc status: %v", e.Code())
-					return
-				}
+				log.WithError(err).WithField("provider", src.provider).Errorf("Could not receive attestations from beacon node. rpc status: %v", e.Code())
 			} else {
-				log.WithError(err).Error("Could not receive attestations from beacon node")
+				log.WithError(err).WithField("provider", src.provider).Error("Could not receive attestations from beacon node")
+			}
+			bs.setStreamErr(src, attestationsStream, err)
+			stream, err = bs.restartIndexedAttestationStream(ctx, src)
+			if err != nil {
+				log.WithError(err).Error("Could not restart stream")
 				return
 			}
+			continue
 		}
 		if res == nil {
 			continue
 		}
+		bs.recordAttestation(src)
+		// Aggregates with the same data share the signing root of the data, so attestations are
+		// deduplicated by the root of the whole indexed attestation.
+		root, err := ssz.HashTreeRoot(res)
+		if err != nil {
+			log.WithError(err).Error("Could not compute indexed attestation root")
+			continue
+		}
+		if !bs.firstSeen(root) {
+			duplicateObjectsReceived.WithLabelValues("attestation").Inc()
+			continue
+		}
 		bs.receivedAttestationsBuffer <- res
 	}
 }
@@ -158,17 +178,22 @@ func (bs *Service) collectReceivedAttestations(ctx context.Context) {
 	}
 }
 
-func (bs *Service) restartIndexedAttestationStream(ctx context.Context) (ethpb.BeaconChain_StreamIndexedAttestationsClient, error) {
+func (bs *Service) restartIndexedAttestationStream(
+	ctx context.Context,
+	src *beaconSource,
+) (ethpb.BeaconChain_StreamIndexedAttestationsClient, error) {
 	ticker := time.NewTicker(reconnectPeriod)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			log.Info("Context closed, attempting to restart attestation stream")
-			stream, err := bs.beaconClient.StreamIndexedAttestations(ctx, &ptypes.Empty{})
+			log.WithField("provider", src.provider).Info("Context closed, attempting to restart attestation stream")
+			stream, err := src.beaconClient.StreamIndexedAttestations(ctx, &ptypes.Empty{})
 			if err != nil {
 				continue
 			}
 			log.Info("Attestation stream restarted...")
+			bs.setStreamErr(src, attestationsStream, nil)
 			return stream, nil
 		case <-ctx.Done():
 			log.Debug("Context closed, exiting reconnect routine")
@@ -178,17 +203,19 @@ func (bs *Service) restartIndexedAttestationStream(ctx context.Context) (ethpb.B
 
 }
 
-func (bs *Service) restartBlockStream(ctx context.Context) (ethpb.BeaconChain_StreamBlocksClient, error) {
+func (bs *Service) restartBlockStream(ctx context.Context, src *beaconSource) (ethpb.BeaconChain_StreamBlocksClient, error) {
 	ticker := time.NewTicker(reconnectPeriod)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			log.Info("Context closed, attempting to restart block stream")
-			stream, err := bs.beaconClient.StreamBlocks(ctx, &ptypes.Empty{})
+			log.WithField("provider", src.provider).Info("Context closed, attempting to restart block stream")
+			stream, err := src.beaconClient.StreamBlocks(ctx, &ptypes.Empty{})
 			if err != nil {
 				continue
 			}
 			log.Info("Block stream restarted...")
+			bs.setStreamErr(src, blocksStream, nil)
 			return stream, nil
 		case <-ctx.Done():
 			log.Debug("Context closed, exiting reconnect routine")
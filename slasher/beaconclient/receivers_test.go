@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
+
 	ptypes "github.com/gogo/protobuf/types"
 	"github.com/golang/mock/gomock"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -19,8 +21,7 @@ func TestService_ReceiveBlocks(t *testing.T) {
 	client := mock.NewMockBeaconChainClient(ctrl)
 
 	bs := Service{
-		beaconClient: client,
-		blockFeed:    new(event.Feed),
+		blockFeed: new(event.Feed),
 	}
 	stream := mock.NewMockBeaconChain_StreamBlocksClient(ctrl)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -35,7 +36,7 @@ func TestService_ReceiveBlocks(t *testing.T) {
 	).Do(func() {
 		cancel()
 	})
-	bs.receiveBlocks(ctx)
+	bs.receiveBlocks(ctx, &beaconSource{beaconClient: client})
 }
 
 func TestService_ReceiveAttestations(t *testing.T) {
@@ -44,7 +45,6 @@ func TestService_ReceiveAttestations(t *testing.T) {
 	client := mock.NewMockBeaconChainClient(ctrl)
 
 	bs := Service{
-		beaconClient:                client,
 		blockFeed:                   new(event.Feed),
 		receivedAttestationsBuffer:  make(chan *ethpb.IndexedAttestation, 1),
 		collectedAttestationsBuffer: make(chan []*ethpb.IndexedAttestation, 1),
@@ -67,7 +67,7 @@ func TestService_ReceiveAttestations(t *testing.T) {
 	).Do(func() {
 		cancel()
 	})
-	bs.receiveAttestations(ctx)
+	bs.receiveAttestations(ctx, &beaconSource{beaconClient: client})
 }
 
 func TestService_ReceiveAttestations_Batched(t *testing.T) {
@@ -76,7 +76,6 @@ func TestService_ReceiveAttestations_Batched(t *testing.T) {
 	client := mock.NewMockBeaconChainClient(ctrl)
 
 	bs := Service{
-		beaconClient:                client,
 		blockFeed:                   new(event.Feed),
 		slasherDB:                   testDB.SetupSlasherDB(t, false),
 		attestationFeed:             new(event.Feed),
@@ -107,7 +106,8 @@ func TestService_ReceiveAttestations_Batched(t *testing.T) {
 		cancel()
 	})
 
-	go bs.receiveAttestations(ctx)
+	go bs.collectReceivedAttestations(ctx)
+	go bs.receiveAttestations(ctx, &beaconSource{beaconClient: client})
 	bs.receivedAttestationsBuffer <- att
 	att.Data.Target.Epoch = 6
 	bs.receivedAttestationsBuffer <- att
@@ -118,3 +118,47 @@ func TestService_ReceiveAttestations_Batched(t *testing.T) {
 		t.Fatalf("Expected %d received attestations to be batched", len(atts))
 	}
 }
+
+func TestService_ReceiveBlocks_DeduplicatesAcrossBeaconNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	seenObjects, err := lru.New(seenObjectsCacheSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs := Service{
+		blockFeed:   new(event.Feed),
+		seenObjects: seenObjects,
+	}
+	blocksChan := make(chan *ethpb.SignedBeaconBlock, 2)
+	sub := bs.blockFeed.Subscribe(blocksChan)
+	defer sub.Unsubscribe()
+
+	// Both beacon nodes stream the same block.
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 5, ParentRoot: make([]byte, 32)}}
+	for _, provider := range []string{"localhost:4000", "localhost:4001"} {
+		client := mock.NewMockBeaconChainClient(ctrl)
+		stream := mock.NewMockBeaconChain_StreamBlocksClient(ctrl)
+		ctx, cancel := context.WithCancel(context.Background())
+		client.EXPECT().StreamBlocks(
+			gomock.Any(),
+			&ptypes.Empty{},
+		).Return(stream, nil)
+		stream.EXPECT().Recv().Return(blk, nil)
+		stream.EXPECT().Recv().Return(nil, context.Canceled).Do(func() {
+			cancel()
+		})
+		src := &beaconSource{provider: provider, beaconClient: client}
+		bs.sources = append(bs.sources, src)
+		bs.receiveBlocks(ctx, src)
+	}
+
+	if len(blocksChan) != 1 {
+		t.Errorf("Wanted %d block sent to the detection service, received %d", 1, len(blocksChan))
+	}
+	for _, status := range bs.SourceStatuses() {
+		if status.HeadSlot != 5 || !status.Healthy {
+			t.Errorf("Wanted beacon node %s to be healthy at slot %d, received %+v", status.Provider, 5, status)
+		}
+	}
+}
@@ -7,10 +7,12 @@ package beaconclient
 
 import (
 	"context"
+	"sync"
 
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/shared/event"
@@ -43,11 +45,8 @@ type Service struct {
 	ctx                         context.Context
 	cancel                      context.CancelFunc
 	cert                        string
-	conn                        *grpc.ClientConn
 	provider                    string
-	beaconClient                ethpb.BeaconChainClient
 	slasherDB                   db.Database
-	nodeClient                  ethpb.NodeClient
 	clientFeed                  *event.Feed
 	blockFeed                   *event.Feed
 	attestationFeed             *event.Feed
@@ -58,11 +57,17 @@ type Service struct {
 	receivedAttestationsBuffer  chan *ethpb.IndexedAttestation
 	collectedAttestationsBuffer chan []*ethpb.IndexedAttestation
 	publicKeyCache              *cache.PublicKeyCache
+	additionalProviders         []string
+	sources                     []*beaconSource
+	sourcesLock                 sync.RWMutex
+	highestSlot                 uint64
+	seenObjects                 *lru.Cache
 }
 
 // Config options for the beaconclient service.
 type Config struct {
 	BeaconProvider        string
+	AdditionalProviders   []string // Beacon nodes to also receive blocks and attestations from.
 	BeaconCert            string
 	SlasherDB             db.Database
 	ProposerSlashingsFeed *event.Feed
@@ -76,6 +81,10 @@ func NewBeaconClientService(ctx context.Context, cfg *Config) (*Service, error)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create new cache")
 	}
+	seenObjects, err := lru.New(seenObjectsCacheSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create seen objects cache")
+	}
 
 	return &Service{
 		cert:                        cfg.BeaconCert,
@@ -93,6 +102,8 @@ func NewBeaconClientService(ctx context.Context, cfg *Config) (*Service, error)
 		receivedAttestationsBuffer:  make(chan *ethpb.IndexedAttestation, 1),
 		collectedAttestationsBuffer: make(chan []*ethpb.IndexedAttestation, 1),
 		publicKeyCache:              publicKeyCache,
+		additionalProviders:         cfg.AdditionalProviders,
+		seenObjects:                 seenObjects,
 	}, nil
 }
 
@@ -114,22 +125,31 @@ func (bs *Service) ClientReadyFeed() *event.Feed {
 	return bs.clientFeed
 }
 
-// Stop the beacon client service by closing the gRPC connection.
+// Stop the beacon client service by closing the gRPC connections.
 func (bs *Service) Stop() error {
 	bs.cancel()
 	log.Info("Stopping service")
-	if bs.conn != nil {
-		return bs.conn.Close()
+	var closeErr error
+	for _, src := range bs.sources {
+		if err := src.conn.Close(); err != nil {
+			closeErr = err
+		}
 	}
-	return nil
+	return closeErr
 }
 
 // Status returns an error if there exists a gRPC connection error
-// in the service.
+// in the service, or if no beacon node is healthy.
 func (bs *Service) Status() error {
-	if bs.conn == nil {
+	bs.sourcesLock.RLock()
+	numSources := len(bs.sources)
+	bs.sourcesLock.RUnlock()
+	if numSources == 0 {
 		return errors.New("no connection to beacon RPC")
 	}
+	if bs.healthySources() == 0 {
+		return errors.New("no healthy beacon node")
+	}
 	return nil
 }
 
@@ -163,14 +183,26 @@ func (bs *Service) Start() {
 			grpc_prometheus.UnaryClientInterceptor,
 		)),
 	}
-	conn, err := grpc.DialContext(bs.ctx, bs.provider, beaconOpts...)
-	if err != nil {
-		log.Fatalf("Could not dial endpoint: %s, %v", bs.provider, err)
+	// Blocks and attestations are received from every beacon node, while requests
+	// and slashing submissions go to a healthy one.
+	providers := append([]string{bs.provider}, bs.additionalProviders...)
+	sources := make([]*beaconSource, 0, len(providers))
+	for _, provider := range providers {
+		conn, err := grpc.DialContext(bs.ctx, provider, beaconOpts...)
+		if err != nil {
+			log.Fatalf("Could not dial endpoint: %s, %v", provider, err)
+		}
+		sources = append(sources, &beaconSource{
+			provider:     provider,
+			conn:         conn,
+			beaconClient: ethpb.NewBeaconChainClient(conn),
+			nodeClient:   ethpb.NewNodeClient(conn),
+		})
 	}
-	log.Info("Successfully started gRPC connection")
-	bs.conn = conn
-	bs.beaconClient = ethpb.NewBeaconChainClient(bs.conn)
-	bs.nodeClient = ethpb.NewNodeClient(bs.conn)
+	bs.sourcesLock.Lock()
+	bs.sources = sources
+	bs.sourcesLock.Unlock()
+	log.WithField("beaconNodes", len(sources)).Info("Successfully started gRPC connection")
 
 	// We poll for the sync status of the beacon node until it is fully synced.
 	bs.querySyncStatus(bs.ctx)
@@ -185,7 +217,10 @@ func (bs *Service) Start() {
 	go bs.subscribeDetectedProposerSlashings(bs.ctx, bs.proposerSlashingsChan)
 	go bs.subscribeDetectedAttesterSlashings(bs.ctx, bs.attesterSlashingsChan)
 
-	// We listen to a stream of blocks and attestations from the beacon node.
-	go bs.receiveBlocks(bs.ctx)
-	go bs.receiveAttestations(bs.ctx)
+	// We listen to a stream of blocks and attestations from each beacon node.
+	go bs.collectReceivedAttestations(bs.ctx)
+	for _, src := range bs.sources {
+		go bs.receiveBlocks(bs.ctx, src)
+		go bs.receiveAttestations(bs.ctx, src)
+	}
 }
@@ -0,0 +1,181 @@
+package beaconclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"google.golang.org/grpc"
+)
+
+// seenObjectsCacheSize defines the number of block and attestation roots remembered
+// to deduplicate the objects received from several beacon nodes.
+var seenObjectsCacheSize = 1 << 16
+
+// maxSourceLag defines how many slots a beacon node can fall behind the most advanced
+// one before it is considered unhealthy.
+var maxSourceLag = params.BeaconConfig().SlotsPerEpoch
+
+// Streams received from a beacon node.
+const (
+	blocksStream       = "blocks"
+	attestationsStream = "attestations"
+)
+
+// beaconSource is a beacon node the slasher ingests blocks and attestations from. Its
+// stream and head fields are guarded by the sources lock of the service.
+type beaconSource struct {
+	provider     string
+	conn         *grpc.ClientConn
+	beaconClient ethpb.BeaconChainClient
+	nodeClient   ethpb.NodeClient
+	blocksErr    error
+	attsErr      error
+	headSlot     uint64
+	lastReceived time.Time
+}
+
+// SourceStatus describes the state of a beacon node the slasher ingests data from.
+type SourceStatus struct {
+	Provider     string
+	Healthy      bool
+	HeadSlot     uint64 // Slot of the latest block received from the node.
+	Lag          uint64 // Slots behind the most advanced beacon node.
+	LastReceived time.Time
+	Err          error
+}
+
+// SourceStatuses returns the state of the beacon nodes the slasher ingests data from.
+func (bs *Service) SourceStatuses() []SourceStatus {
+	bs.sourcesLock.RLock()
+	defer bs.sourcesLock.RUnlock()
+	statuses := make([]SourceStatus, len(bs.sources))
+	for i, src := range bs.sources {
+		statuses[i] = bs.sourceStatus(src)
+	}
+	return statuses
+}
+
+// healthySources returns the number of healthy beacon nodes.
+func (bs *Service) healthySources() int {
+	healthy := 0
+	for _, status := range bs.SourceStatuses() {
+		if status.Healthy {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// requestSources returns the beacon nodes to send requests to, the healthy ones first and
+// each group in its configured order.
+func (bs *Service) requestSources() []*beaconSource {
+	bs.sourcesLock.RLock()
+	defer bs.sourcesLock.RUnlock()
+	healthy := make([]*beaconSource, 0, len(bs.sources))
+	var unhealthy []*beaconSource
+	for _, src := range bs.sources {
+		if bs.sourceStatus(src).Healthy {
+			healthy = append(healthy, src)
+		} else {
+			unhealthy = append(unhealthy, src)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// request sends a request to a healthy beacon node, and falls back to the next beacon node
+// while the request fails. It returns the error of the last beacon node tried.
+func (bs *Service) request(ctx context.Context, fn func(src *beaconSource) error) error {
+	err := errors.New("no beacon node to send the request to")
+	for _, src := range bs.requestSources() {
+		if err = fn(src); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		log.WithError(err).WithField("provider", src.provider).Debug("Request to beacon node failed")
+	}
+	return err
+}
+
+// sourceStatus must be called with the sources lock held.
+func (bs *Service) sourceStatus(src *beaconSource) SourceStatus {
+	status := SourceStatus{
+		Provider:     src.provider,
+		HeadSlot:     src.headSlot,
+		LastReceived: src.lastReceived,
+	}
+	if bs.highestSlot > src.headSlot {
+		status.Lag = bs.highestSlot - src.headSlot
+	}
+	switch {
+	case src.blocksErr != nil:
+		status.Err = errors.Wrap(src.blocksErr, "blocks stream is down")
+	case src.attsErr != nil:
+		status.Err = errors.Wrap(src.attsErr, "attestations stream is down")
+	case status.Lag > maxSourceLag:
+		status.Err = errors.Errorf("beacon node is %d slots behind", status.Lag)
+	}
+	status.Healthy = status.Err == nil
+	return status
+}
+
+// setStreamErr records the error of a stream of the beacon node, nil once the stream is up.
+func (bs *Service) setStreamErr(src *beaconSource, stream string, err error) {
+	bs.sourcesLock.Lock()
+	if stream == blocksStream {
+		src.blocksErr = err
+	} else {
+		src.attsErr = err
+	}
+	bs.sourcesLock.Unlock()
+	bs.updateSourceMetrics()
+	if err != nil && bs.healthySources() == 0 {
+		log.Error("No healthy beacon node left to receive blocks and attestations from")
+	}
+}
+
+// recordBlock records a block received from the beacon node, in order to track its lag.
+func (bs *Service) recordBlock(src *beaconSource, slot uint64) {
+	bs.sourcesLock.Lock()
+	if slot > src.headSlot {
+		src.headSlot = slot
+	}
+	if slot > bs.highestSlot {
+		bs.highestSlot = slot
+	}
+	src.lastReceived = time.Now()
+	bs.sourcesLock.Unlock()
+	bs.updateSourceMetrics()
+}
+
+func (bs *Service) recordAttestation(src *beaconSource) {
+	bs.sourcesLock.Lock()
+	src.lastReceived = time.Now()
+	bs.sourcesLock.Unlock()
+}
+
+// firstSeen returns true the first time a block or attestation root is received from
+// any beacon node, and false for the copies received from the other nodes.
+func (bs *Service) firstSeen(root [32]byte) bool {
+	if bs.seenObjects == nil {
+		return true
+	}
+	seen, _ := bs.seenObjects.ContainsOrAdd(root, true)
+	return !seen
+}
+
+func (bs *Service) updateSourceMetrics() {
+	for _, status := range bs.SourceStatuses() {
+		sourceLagSlots.WithLabelValues(status.Provider).Set(float64(status.Lag))
+		healthy := 0.0
+		if status.Healthy {
+			healthy = 1
+		}
+		sourceHealthy.WithLabelValues(status.Provider).Set(healthy)
+	}
+}
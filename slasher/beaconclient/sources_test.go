@@ -0,0 +1,55 @@
+package beaconclient
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestService_SourceStatuses(t *testing.T) {
+	ahead := &beaconSource{provider: "localhost:4000"}
+	behind := &beaconSource{provider: "localhost:4001"}
+	bs := Service{sources: []*beaconSource{ahead, behind}}
+
+	bs.recordBlock(ahead, 10+maxSourceLag)
+	bs.recordBlock(behind, 10)
+	statuses := bs.SourceStatuses()
+	if !statuses[0].Healthy || statuses[0].Lag != 0 {
+		t.Errorf("Wanted the most advanced beacon node to be healthy, received %+v", statuses[0])
+	}
+	if !statuses[1].Healthy || statuses[1].Lag != maxSourceLag {
+		t.Errorf("Wanted a beacon node %d slots behind to be healthy, received %+v", maxSourceLag, statuses[1])
+	}
+
+	bs.recordBlock(ahead, 11+maxSourceLag)
+	if statuses := bs.SourceStatuses(); statuses[1].Healthy || statuses[1].Err == nil {
+		t.Errorf("Wanted a lagging beacon node to be unhealthy, received %+v", statuses[1])
+	}
+	if healthy := bs.healthySources(); healthy != 1 {
+		t.Errorf("Wanted %d healthy beacon node, received %d", 1, healthy)
+	}
+
+	bs.setStreamErr(ahead, attestationsStream, errors.New("connection refused"))
+	if healthy := bs.healthySources(); healthy != 0 {
+		t.Errorf("Wanted no healthy beacon node, received %d", healthy)
+	}
+	bs.setStreamErr(ahead, attestationsStream, nil)
+	if !bs.SourceStatuses()[0].Healthy {
+		t.Error("Wanted the beacon node to be healthy once its stream is restarted")
+	}
+}
+
+func TestService_Status(t *testing.T) {
+	bs := Service{}
+	if err := bs.Status(); err == nil {
+		t.Error("Wanted an error without beacon node")
+	}
+	src := &beaconSource{provider: "localhost:4000"}
+	bs.sources = []*beaconSource{src}
+	if err := bs.Status(); err != nil {
+		t.Errorf("Wanted no error with a healthy beacon node, received %v", err)
+	}
+	bs.setStreamErr(src, blocksStream, errors.New("connection refused"))
+	if err := bs.Status(); err == nil {
+		t.Error("Wanted an error without healthy beacon node")
+	}
+}
@@ -10,7 +10,7 @@ import (
 // subscribeDetectedProposerSlashings subscribes to an event feed for
 // slashing objects from the slasher runtime. Upon receiving
 // a proposer slashing from the feed, we submit the object to the
-// connected beacon node via a client RPC.
+// healthy beacon node via a client RPC.
 func (bs *Service) subscribeDetectedProposerSlashings(ctx context.Context, ch chan *ethpb.ProposerSlashing) {
 	ctx, span := trace.StartSpan(ctx, "beaconclient.submitProposerSlashing")
 	defer span.End()
@@ -19,7 +19,10 @@ func (bs *Service) subscribeDetectedProposerSlashings(ctx context.Context, ch ch
 	for {
 		select {
 		case slashing := <-ch:
-			if _, err := bs.beaconClient.SubmitProposerSlashing(ctx, slashing); err != nil {
+			if err := bs.request(ctx, func(src *beaconSource) error {
+				_, err := src.beaconClient.SubmitProposerSlashing(ctx, slashing)
+				return err
+			}); err != nil {
 				log.Error(err)
 			}
 		case <-sub.Err():
@@ -35,7 +38,7 @@ func (bs *Service) subscribeDetectedProposerSlashings(ctx context.Context, ch ch
 // subscribeDetectedAttesterSlashings subscribes to an event feed for
 // slashing objects from the slasher runtime. Upon receiving an
 // attester slashing from the feed, we submit the object to the
-// connected beacon node via a client RPC.
+// healthy beacon node via a client RPC.
 func (bs *Service) subscribeDetectedAttesterSlashings(ctx context.Context, ch chan *ethpb.AttesterSlashing) {
 	ctx, span := trace.StartSpan(ctx, "beaconclient.submitAttesterSlashing")
 	defer span.End()
@@ -44,7 +47,10 @@ func (bs *Service) subscribeDetectedAttesterSlashings(ctx context.Context, ch ch
 	for {
 		select {
 		case slashing := <-ch:
-			if _, err := bs.beaconClient.SubmitAttesterSlashing(ctx, slashing); err != nil {
+			if err := bs.request(ctx, func(src *beaconSource) error {
+				_, err := src.beaconClient.SubmitAttesterSlashing(ctx, slashing)
+				return err
+			}); err != nil {
 				log.Error(err)
 			}
 		case <-sub.Err():
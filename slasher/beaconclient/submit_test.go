@@ -19,7 +19,7 @@ func TestService_SubscribeDetectedProposerSlashings(t *testing.T) {
 	client := mock.NewMockBeaconChainClient(ctrl)
 
 	bs := Service{
-		beaconClient:          client,
+		sources:               []*beaconSource{{beaconClient: client}},
 		proposerSlashingsFeed: new(event.Feed),
 	}
 
@@ -61,7 +61,7 @@ func TestService_SubscribeDetectedAttesterSlashings(t *testing.T) {
 	client := mock.NewMockBeaconChainClient(ctrl)
 
 	bs := Service{
-		beaconClient:          client,
+		sources:               []*beaconSource{{beaconClient: client}},
 		attesterSlashingsFeed: new(event.Feed),
 	}
 
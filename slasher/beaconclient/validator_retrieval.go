@@ -42,8 +42,13 @@ func (bs *Service) FindOrGetPublicKeys(
 	if notFound == 0 {
 		return validators, nil
 	}
-	vc, err := bs.beaconClient.ListValidators(ctx, &ethpb.ListValidatorsRequest{
-		Indices: validatorIndices,
+	var vc *ethpb.Validators
+	err := bs.request(ctx, func(src *beaconSource) error {
+		var err error
+		vc, err = src.beaconClient.ListValidators(ctx, &ethpb.ListValidatorsRequest{
+			Indices: validatorIndices,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not request validators public key: %d", validatorIndices)
@@ -25,7 +25,7 @@ func TestService_RequestValidator(t *testing.T) {
 		t.Fatalf("could not create new cache: %v", err)
 	}
 	bs := Service{
-		beaconClient:   client,
+		sources:        []*beaconSource{{beaconClient: client}},
 		publicKeyCache: validatorCache,
 	}
 	wanted := &ethpb.Validators{
@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"context"
 	"os"
 	"path"
 	"time"
@@ -101,13 +102,16 @@ func NewKVStore(dirPath string, cfg *Config) (*Store, error) {
 			historicBlockHeadersBucket,
 			compressedIdxAttsBucket,
 			validatorsPublicKeysBucket,
-			validatorsMinMaxSpanBucket,
+			validatorsMinMaxSpanChunksBucket,
 			slashingBucket,
 			chainDataBucket,
 		)
 	}); err != nil {
 		return nil, err
 	}
+	if err := kv.migrateSpanMaps(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "could not migrate span maps")
+	}
 
 	return kv, err
 }
@@ -10,6 +10,10 @@ const (
 	chainHeadKey         = "CHAIN_HEAD"
 	cachedSpanerEpochs   = 256
 	spannerEncodedLength = 7
+	// Min-max spans are stored in chunks of spanChunkValidators validators
+	// over spanChunkEpochs epochs.
+	spanChunkValidators = 256
+	spanChunkEpochs     = 16
 )
 
 var (
@@ -25,6 +29,8 @@ var (
 	// In order to quickly detect surround and surrounded attestations we need to store
 	// the min and max span for each validator for each epoch.
 	// see https://github.com/protolambda/eth2-surround/blob/master/README.md#min-max-surround
+	validatorsMinMaxSpanChunksBucket = []byte("validators-min-max-span-chunks-bucket")
+	// Previous layout of the min and max spans, a nested bucket per epoch, kept to be migrated.
 	validatorsMinMaxSpanBucket = []byte("validators-min-max-span-bucket")
 )
 
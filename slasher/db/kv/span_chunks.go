@@ -0,0 +1,225 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/slasher/detection/attestations/types"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// spanChunk holds the min/max spans of spanChunkValidators validators over spanChunkEpochs
+// epochs, stored validator by validator and epoch by epoch. A zero span means no span.
+type spanChunk []byte
+
+func newSpanChunk() spanChunk {
+	return make(spanChunk, spanChunkValidators*spanChunkEpochs*spannerEncodedLength)
+}
+
+// spanChunkKey returns the key of the chunk holding the span of the validator at the epoch:
+// the epoch chunk then the validator chunk, so the chunks of an epoch are stored together.
+func spanChunkKey(epoch uint64, validatorIdx uint64) []byte {
+	return append(bytesutil.Bytes8(epoch/spanChunkEpochs), bytesutil.Bytes8(validatorIdx/spanChunkValidators)...)
+}
+
+func (c spanChunk) offset(validatorIdx uint64, epoch uint64) uint64 {
+	return ((validatorIdx%spanChunkValidators)*spanChunkEpochs + epoch%spanChunkEpochs) * spannerEncodedLength
+}
+
+func (c spanChunk) span(validatorIdx uint64, epoch uint64) types.Span {
+	enc := c[c.offset(validatorIdx, epoch):]
+	return types.Span{
+		MinSpan:     bytesutil.FromBytes2(enc[:2]),
+		MaxSpan:     bytesutil.FromBytes2(enc[2:4]),
+		SigBytes:    [2]byte{enc[4], enc[5]},
+		HasAttested: bytesutil.ToBool(enc[6]),
+	}
+}
+
+func (c spanChunk) setSpan(validatorIdx uint64, epoch uint64, span types.Span) {
+	copy(c[c.offset(validatorIdx, epoch):], marshalSpan(span))
+}
+
+func (c spanChunk) isEmpty() bool {
+	for _, b := range c {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// readSpanChunk decompresses the chunk stored at the key, nil if there is none.
+func readSpanChunk(bucket *bolt.Bucket, key []byte) (spanChunk, error) {
+	enc := bucket.Get(key)
+	if enc == nil {
+		return nil, nil
+	}
+	chunk, err := snappy.Decode(nil, enc)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decompress span chunk")
+	}
+	if len(chunk) != spanChunkValidators*spanChunkEpochs*spannerEncodedLength {
+		return nil, errors.New("wrong data length for span chunk")
+	}
+	return chunk, nil
+}
+
+// writeSpanChunks compresses and stores the chunks by key, and deletes the empty ones.
+func writeSpanChunks(bucket *bolt.Bucket, chunks map[string]spanChunk) error {
+	for key, chunk := range chunks {
+		if chunk.isEmpty() {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := bucket.Put([]byte(key), snappy.Encode(nil, chunk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveSpans writes the spans by epoch and validator index. The spans of a batch are grouped by
+// chunk, so each chunk is read and written once whatever the number of spans it receives.
+func saveSpans(tx *bolt.Tx, epochsSpans map[uint64]map[uint64]types.Span) error {
+	bucket := tx.Bucket(validatorsMinMaxSpanChunksBucket)
+	chunks := make(map[string]spanChunk)
+	for epoch, spanMap := range epochsSpans {
+		for idx, span := range spanMap {
+			key := string(spanChunkKey(epoch, idx))
+			chunk, ok := chunks[key]
+			if !ok {
+				var err error
+				chunk, err = readSpanChunk(bucket, []byte(key))
+				if err != nil {
+					return err
+				}
+				if chunk == nil {
+					chunk = newSpanChunk()
+				}
+				chunks[key] = chunk
+			}
+			chunk.setSpan(idx, epoch, span)
+		}
+	}
+	return writeSpanChunks(bucket, chunks)
+}
+
+// epochSpans reads the spans of all validators at the epoch.
+func epochSpans(tx *bolt.Tx, epoch uint64) (map[uint64]types.Span, error) {
+	spanMap := make(map[uint64]types.Span)
+	bucket := tx.Bucket(validatorsMinMaxSpanChunksBucket)
+	prefix := bytesutil.Bytes8(epoch / spanChunkEpochs)
+	c := bucket.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		chunk, err := readSpanChunk(bucket, k)
+		if err != nil {
+			return nil, err
+		}
+		firstIdx := bytesutil.FromBytes8(k[8:]) * spanChunkValidators
+		for idx := firstIdx; idx < firstIdx+spanChunkValidators; idx++ {
+			if span := chunk.span(idx, epoch); span != (types.Span{}) {
+				spanMap[idx] = span
+			}
+		}
+	}
+	return spanMap, nil
+}
+
+// deleteEpochSpans deletes the spans of all validators at the epoch.
+func deleteEpochSpans(tx *bolt.Tx, epoch uint64) error {
+	bucket := tx.Bucket(validatorsMinMaxSpanChunksBucket)
+	prefix := bytesutil.Bytes8(epoch / spanChunkEpochs)
+	chunks := make(map[string]spanChunk)
+	c := bucket.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		chunk, err := readSpanChunk(bucket, k)
+		if err != nil {
+			return err
+		}
+		firstIdx := bytesutil.FromBytes8(k[8:]) * spanChunkValidators
+		for idx := firstIdx; idx < firstIdx+spanChunkValidators; idx++ {
+			chunk.setSpan(idx, epoch, types.Span{})
+		}
+		chunks[string(k)] = chunk
+	}
+	return writeSpanChunks(bucket, chunks)
+}
+
+// migrateSpanMaps moves the spans stored in the previous layout, a nested bucket per epoch with
+// a key per validator, into span chunks. The epochs of an epoch chunk are moved in a transaction,
+// so an interrupted migration resumes on the next start.
+func (db *Store) migrateSpanMaps(ctx context.Context) error {
+	var epochs []uint64
+	if err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(validatorsMinMaxSpanBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if v == nil {
+				epochs = append(epochs, bytesutil.FromBytes8(k))
+			}
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	// Keys are little endian, so the epochs are sorted to be grouped by epoch chunk.
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+	if len(epochs) > 0 {
+		log.WithField("epochs", len(epochs)).Info("Migrating span maps to span chunks")
+	}
+
+	for start := 0; start < len(epochs); {
+		end := start
+		for end < len(epochs) && epochs[end]/spanChunkEpochs == epochs[start]/spanChunkEpochs {
+			end++
+		}
+		if err := db.update(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket(validatorsMinMaxSpanBucket)
+			epochsSpans := make(map[uint64]map[uint64]types.Span, end-start)
+			for _, epoch := range epochs[start:end] {
+				epochBucket := bucket.Bucket(bytesutil.Bytes8(epoch))
+				spanMap := make(map[uint64]types.Span, epochBucket.Stats().KeyN)
+				if err := epochBucket.ForEach(func(k, v []byte) error {
+					span, err := unmarshalSpan(ctx, v)
+					if err != nil {
+						return err
+					}
+					spanMap[bytesutil.FromBytes8(k)] = span
+					return nil
+				}); err != nil {
+					return err
+				}
+				epochsSpans[epoch] = spanMap
+			}
+			if err := saveSpans(tx, epochsSpans); err != nil {
+				return err
+			}
+			for _, epoch := range epochs[start:end] {
+				if err := bucket.DeleteBucket(bytesutil.Bytes8(epoch)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return errors.Wrapf(err, "could not migrate span maps of epochs %d to %d", epochs[start], epochs[end-1])
+		}
+		start = end
+	}
+
+	return db.update(func(tx *bolt.Tx) error {
+		if tx.Bucket(validatorsMinMaxSpanBucket) == nil {
+			return nil
+		}
+		return tx.DeleteBucket(validatorsMinMaxSpanBucket)
+	})
+}
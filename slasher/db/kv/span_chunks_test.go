@@ -0,0 +1,252 @@
+package kv
+
+import (
+	"context"
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/slasher/detection/attestations/types"
+	bolt "go.etcd.io/bbolt"
+	"gopkg.in/urfave/cli.v2"
+)
+
+const benchmarkValidators = 100000
+
+// saveLegacySpans writes the spans in the layout used before span chunks, a nested
+// bucket per epoch with a key per validator.
+func saveLegacySpans(tx *bolt.Tx, epochsSpans map[uint64]map[uint64]types.Span) error {
+	bucket, err := tx.CreateBucketIfNotExists(validatorsMinMaxSpanBucket)
+	if err != nil {
+		return err
+	}
+	for epoch, spanMap := range epochsSpans {
+		epochBucket, err := bucket.CreateBucketIfNotExists(bytesutil.Bytes8(epoch))
+		if err != nil {
+			return err
+		}
+		for idx, span := range spanMap {
+			if err := epochBucket.Put(bytesutil.Bytes8(idx), marshalSpan(span)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func legacyEpochSpans(ctx context.Context, tx *bolt.Tx, epoch uint64) (map[uint64]types.Span, error) {
+	spanMap := make(map[uint64]types.Span)
+	epochBucket := tx.Bucket(validatorsMinMaxSpanBucket).Bucket(bytesutil.Bytes8(epoch))
+	err := epochBucket.ForEach(func(k, v []byte) error {
+		span, err := unmarshalSpan(ctx, v)
+		if err != nil {
+			return err
+		}
+		spanMap[bytesutil.FromBytes8(k)] = span
+		return nil
+	})
+	return spanMap, err
+}
+
+func TestSpanChunk_SetSpan(t *testing.T) {
+	chunk := newSpanChunk()
+	if !chunk.isEmpty() {
+		t.Error("Expected a new span chunk to be empty")
+	}
+	want := types.Span{MinSpan: 3, MaxSpan: 7, SigBytes: [2]byte{1, 2}, HasAttested: true}
+	chunk.setSpan(spanChunkValidators+5, 2*spanChunkEpochs+3, want)
+	if got := chunk.span(5, 3); got != want {
+		t.Errorf("Wanted span %v, received %v", want, got)
+	}
+	if got := chunk.span(5, 4); got != (types.Span{}) {
+		t.Errorf("Wanted no span at a neighbouring epoch, received %v", got)
+	}
+	chunk.setSpan(5, 3, types.Span{})
+	if !chunk.isEmpty() {
+		t.Error("Expected the span chunk to be empty after clearing its span")
+	}
+}
+
+func TestStore_SaveSpans_DeletesEmptyChunks(t *testing.T) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	db := setupDB(t, cli.NewContext(&app, set, nil))
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	db.spanCacheEnabled = false
+
+	span := types.Span{MinSpan: 1, MaxSpan: 2}
+	if err := db.SaveValidatorEpochSpan(ctx, 1000, 40, span); err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.EpochSpanByValidatorIndex(ctx, 1000, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != span {
+		t.Errorf("Wanted span %v, received %v", span, got)
+	}
+	if err := db.DeleteValidatorSpanByEpoch(ctx, 1000, 40); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.view(func(tx *bolt.Tx) error {
+		if n := tx.Bucket(validatorsMinMaxSpanChunksBucket).Stats().KeyN; n != 0 {
+			t.Errorf("Wanted no span chunks left, received %d", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_MigrateSpanMaps(t *testing.T) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	db := setupDB(t, cli.NewContext(&app, set, nil))
+	defer teardownDB(t, db)
+	ctx := context.Background()
+	db.spanCacheEnabled = false
+
+	// The epochs are spread over several epoch chunks.
+	epochsSpans := make(map[uint64]map[uint64]types.Span)
+	for i, tt := range spanTests {
+		epochsSpans[tt.epoch+uint64(i)*spanChunkEpochs] = tt.spanMap
+	}
+	epochsSpans[5] = map[uint64]types.Span{300: {MinSpan: 1, MaxSpan: 4}}
+	if err := db.update(func(tx *bolt.Tx) error {
+		return saveLegacySpans(tx, epochsSpans)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.migrateSpanMaps(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for epoch, want := range epochsSpans {
+		spanMap, _, err := db.EpochSpansMap(ctx, epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(spanMap, want) {
+			t.Errorf("Wanted span map %v at epoch %d, received %v", want, epoch, spanMap)
+		}
+	}
+	if err := db.view(func(tx *bolt.Tx) error {
+		if tx.Bucket(validatorsMinMaxSpanBucket) != nil {
+			t.Error("Expected the previous span maps bucket to be deleted")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Migrating again is a no-op.
+	if err := db.migrateSpanMaps(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// benchmarkSpans returns the span maps of an epoch of attestations by all validators,
+// each attestation updating the spans of the given number of epochs.
+func benchmarkSpans(epochs uint64) map[uint64]map[uint64]types.Span {
+	epochsSpans := make(map[uint64]map[uint64]types.Span, epochs)
+	for epoch := uint64(0); epoch < epochs; epoch++ {
+		spanMap := make(map[uint64]types.Span, benchmarkValidators)
+		for idx := uint64(0); idx < benchmarkValidators; idx++ {
+			spanMap[idx] = types.Span{MinSpan: uint16(epochs - epoch), SigBytes: [2]byte{byte(idx), 1}, HasAttested: epoch == 0}
+		}
+		epochsSpans[epoch] = spanMap
+	}
+	return epochsSpans
+}
+
+func reportDBSize(b *testing.B, db *Store) {
+	size, err := db.Size()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportMetric(float64(size), "db-bytes")
+}
+
+func BenchmarkStore_SaveSpans_Legacy(b *testing.B) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	db := setupDB(b, cli.NewContext(&app, set, nil))
+	defer teardownDB(b, db)
+	epochsSpans := benchmarkSpans(4)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.update(func(tx *bolt.Tx) error {
+			return saveLegacySpans(tx, epochsSpans)
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	reportDBSize(b, db)
+}
+
+func BenchmarkStore_SaveSpans_Chunks(b *testing.B) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	db := setupDB(b, cli.NewContext(&app, set, nil))
+	defer teardownDB(b, db)
+	epochsSpans := benchmarkSpans(4)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.update(func(tx *bolt.Tx) error {
+			return saveSpans(tx, epochsSpans)
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	reportDBSize(b, db)
+}
+
+func BenchmarkStore_EpochSpans_Legacy(b *testing.B) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	db := setupDB(b, cli.NewContext(&app, set, nil))
+	defer teardownDB(b, db)
+	ctx := context.Background()
+	if err := db.update(func(tx *bolt.Tx) error {
+		return saveLegacySpans(tx, benchmarkSpans(1))
+	}); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.view(func(tx *bolt.Tx) error {
+			_, err := legacyEpochSpans(ctx, tx, 0)
+			return err
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStore_EpochSpans_Chunks(b *testing.B) {
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	db := setupDB(b, cli.NewContext(&app, set, nil))
+	defer teardownDB(b, db)
+	if err := db.update(func(tx *bolt.Tx) error {
+		return saveSpans(tx, benchmarkSpans(1))
+	}); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.view(func(tx *bolt.Tx) error {
+			_, err := epochSpans(tx, 0)
+			return err
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"bytes"
 	"context"
 
 	"github.com/pkg/errors"
@@ -37,7 +38,7 @@ var (
 
 // This function defines a function which triggers upon a span map being
 // evicted from the cache. It allows us to persist the span map by the epoch value
-// to the database itself in the validatorsMinMaxSpanBucket.
+// to the database itself in the validatorsMinMaxSpanChunksBucket.
 func persistSpanMapsOnEviction(db *Store) func(key interface{}, value interface{}) {
 	// We use a closure here so we can access the database itself
 	// on the eviction of a span map from the cache. The function has the signature
@@ -52,16 +53,9 @@ func persistSpanMapsOnEviction(db *Store) func(key interface{}, value interface{
 				return errors.New("could not cast key and value into needed types")
 			}
 
-			bucket := tx.Bucket(validatorsMinMaxSpanBucket)
-			epochBucket, err := bucket.CreateBucketIfNotExists(bytesutil.Bytes8(epoch))
-			if err != nil {
+			if err := saveSpans(tx, map[uint64]map[uint64]types.Span{epoch: spanMap}); err != nil {
 				return err
 			}
-			for k, v := range spanMap {
-				if err = epochBucket.Put(bytesutil.Bytes8(k), marshalSpan(v)); err != nil {
-					return err
-				}
-			}
 			epochSpansCacheEvictions.Inc()
 			return nil
 		})
@@ -117,22 +111,8 @@ func (db *Store) EpochSpansMap(ctx context.Context, epoch uint64) (map[uint64]ty
 	var err error
 	var spanMap map[uint64]types.Span
 	err = db.view(func(tx *bolt.Tx) error {
-		b := tx.Bucket(validatorsMinMaxSpanBucket)
-		epochBucket := b.Bucket(bytesutil.Bytes8(epoch))
-		if epochBucket == nil {
-			return nil
-		}
-		keysLength := epochBucket.Stats().KeyN
-		spanMap = make(map[uint64]types.Span, keysLength)
-		return epochBucket.ForEach(func(k, v []byte) error {
-			key := bytesutil.FromBytes8(k)
-			value, err := unmarshalSpan(ctx, v)
-			if err != nil {
-				return err
-			}
-			spanMap[key] = value
-			return nil
-		})
+		spanMap, err = epochSpans(tx, epoch)
+		return err
 	})
 	if spanMap == nil {
 		spanMap = make(map[uint64]types.Span)
@@ -163,21 +143,11 @@ func (db *Store) EpochSpanByValidatorIndex(ctx context.Context, validatorIdx uin
 
 	var spans types.Span
 	err := db.view(func(tx *bolt.Tx) error {
-		b := tx.Bucket(validatorsMinMaxSpanBucket)
-		epochBucket := b.Bucket(bytesutil.Bytes8(epoch))
-		if epochBucket == nil {
-			return nil
-		}
-		key := bytesutil.Bytes8(validatorIdx)
-		v := epochBucket.Get(key)
-		if v == nil {
-			return nil
-		}
-		value, err := unmarshalSpan(ctx, v)
-		if err != nil {
+		chunk, err := readSpanChunk(tx.Bucket(validatorsMinMaxSpanChunksBucket), spanChunkKey(epoch, validatorIdx))
+		if err != nil || chunk == nil {
 			return err
 		}
-		spans = value
+		spans = chunk.span(validatorIdx, epoch)
 		return nil
 	})
 	return spans, err
@@ -185,6 +155,7 @@ func (db *Store) EpochSpanByValidatorIndex(ctx context.Context, validatorIdx uin
 
 // EpochsSpanByValidatorsIndices accepts validator indices and epoch and
 // returns all their previous corresponding spans for slashing detection epoch=> validator index => spammap.
+// The epochs are read chunk by chunk down from the max epoch until an epoch chunk without spans.
 // Returns empty map if no values exists and error on db error.
 func (db *Store) EpochsSpanByValidatorsIndices(ctx context.Context, validatorIndices []uint64, maxEpoch uint64) (map[uint64]map[uint64]types.Span, error) {
 	ctx, span := trace.StartSpan(ctx, "slasherDB.EpochsSpanByValidatorsIndices")
@@ -193,31 +164,42 @@ func (db *Store) EpochsSpanByValidatorsIndices(ctx context.Context, validatorInd
 	var err error
 	epochsSpanMap := make(map[uint64]map[uint64]types.Span)
 	err = db.view(func(tx *bolt.Tx) error {
-		b := tx.Bucket(validatorsMinMaxSpanBucket)
-		epoch := maxEpoch
-		epochBucket := b.Bucket(bytesutil.Bytes8(epoch))
-
-		for epochBucket != nil {
-			valSpans := make(map[uint64]types.Span, len(validatorIndices))
+		b := tx.Bucket(validatorsMinMaxSpanChunksBucket)
+		for epochChunk := maxEpoch / spanChunkEpochs; ; epochChunk-- {
+			prefix := bytesutil.Bytes8(epochChunk)
+			if k, _ := b.Cursor().Seek(prefix); k == nil || !bytes.HasPrefix(k, prefix) {
+				return nil
+			}
+			chunks := make(map[uint64]spanChunk)
 			for _, v := range validatorIndices {
-				enc := epochBucket.Get(bytesutil.Bytes8(v))
-				if enc == nil {
+				if _, ok := chunks[v/spanChunkValidators]; ok {
 					continue
 				}
-				value, err := unmarshalSpan(ctx, enc)
+				chunk, err := readSpanChunk(b, spanChunkKey(epochChunk*spanChunkEpochs, v))
 				if err != nil {
 					return err
 				}
-				valSpans[v] = value
+				chunks[v/spanChunkValidators] = chunk
 			}
-			epochsSpanMap[epoch] = valSpans
-			if epoch == 0 {
-				break
+			for epoch := epochChunk * spanChunkEpochs; epoch < (epochChunk+1)*spanChunkEpochs && epoch <= maxEpoch; epoch++ {
+				valSpans := make(map[uint64]types.Span, len(validatorIndices))
+				for _, v := range validatorIndices {
+					chunk := chunks[v/spanChunkValidators]
+					if chunk == nil {
+						continue
+					}
+					if span := chunk.span(v, epoch); span != (types.Span{}) {
+						valSpans[v] = span
+					}
+				}
+				if len(valSpans) > 0 {
+					epochsSpanMap[epoch] = valSpans
+				}
+			}
+			if epochChunk == 0 {
+				return nil
 			}
-			epoch--
-			epochBucket = b.Bucket(bytesutil.Bytes8(epoch))
 		}
-		return nil
 	})
 	return epochsSpanMap, err
 }
@@ -229,23 +211,9 @@ func (db *Store) SaveEpochsSpanByValidatorsIndices(ctx context.Context, epochsSp
 	ctx, span := trace.StartSpan(ctx, "slasherDB.SaveEpochsSpanByValidatorsIndices")
 	defer span.End()
 
-	err := db.update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(validatorsMinMaxSpanBucket)
-		for epoch, indicesSpanMaps := range epochsSpans {
-			epochBucket, err := b.CreateBucketIfNotExists(bytesutil.Bytes8(epoch))
-			if err != nil {
-				return err
-			}
-			for idx, v := range indicesSpanMaps {
-				enc := marshalSpan(v)
-				if err := epochBucket.Put(bytesutil.Bytes8(idx), enc); err != nil {
-					return err
-				}
-			}
-		}
-		return nil
+	return db.update(func(tx *bolt.Tx) error {
+		return saveSpans(tx, epochsSpans)
 	})
-	return err
 }
 
 // SaveValidatorEpochSpan accepts validator index epoch and spans returns.
@@ -272,14 +240,7 @@ func (db *Store) SaveValidatorEpochSpan(
 	}
 
 	return db.update(func(tx *bolt.Tx) error {
-		b := tx.Bucket(validatorsMinMaxSpanBucket)
-		epochBucket, err := b.CreateBucketIfNotExists(bytesutil.Bytes8(epoch))
-		if err != nil {
-			return err
-		}
-		key := bytesutil.Bytes8(validatorIdx)
-		value := marshalSpan(span)
-		return epochBucket.Put(key, value)
+		return saveSpans(tx, map[uint64]map[uint64]types.Span{epoch: {validatorIdx: span}})
 	})
 }
 
@@ -296,18 +257,7 @@ func (db *Store) SaveEpochSpansMap(ctx context.Context, epoch uint64, spanMap ma
 	}
 
 	return db.update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(validatorsMinMaxSpanBucket)
-		valBucket, err := bucket.CreateBucketIfNotExists(bytesutil.Bytes8(epoch))
-		if err != nil {
-			return err
-		}
-		for k, v := range spanMap {
-			err = valBucket.Put(bytesutil.Bytes8(k), marshalSpan(v))
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+		return saveSpans(tx, map[uint64]map[uint64]types.Span{epoch: spanMap})
 	})
 }
 
@@ -317,21 +267,22 @@ func (db *Store) EnableSpanCache(enable bool) {
 }
 
 // SaveCachedSpansMaps saves all span maps that are currently
-// in memory into the DB. if no span maps are in db or cache is disabled it returns nil.
+// in memory into the DB in a single batch, so that each span chunk is written once.
+// if no span maps are in db or cache is disabled it returns nil.
 func (db *Store) SaveCachedSpansMaps(ctx context.Context) error {
 	ctx, span := trace.StartSpan(ctx, "slasherDB.SaveCachedSpansMaps")
 	defer span.End()
 	if db.spanCacheEnabled {
-		db.EnableSpanCache(false)
-		defer db.EnableSpanCache(true)
+		epochsSpans := make(map[uint64]map[uint64]types.Span)
 		for epoch := lowestObservedEpoch; epoch <= highestObservedEpoch; epoch++ {
 			spanMap, ok := db.spanCache.Get(epoch)
 			if ok {
-				if err := db.SaveEpochSpansMap(ctx, epoch, spanMap); err != nil {
-					return errors.Wrap(err, "failed to save span maps from cache")
-				}
+				epochsSpans[epoch] = spanMap
 			}
 		}
+		if err := db.SaveEpochsSpanByValidatorsIndices(ctx, epochsSpans); err != nil {
+			return errors.Wrap(err, "failed to save span maps from cache")
+		}
 		// Reset the observed epochs after saving to the DB.
 		lowestObservedEpoch = params.BeaconConfig().FarFutureEpoch
 		highestObservedEpoch = 0
@@ -349,9 +300,7 @@ func (db *Store) DeleteEpochSpans(ctx context.Context, epoch uint64) error {
 		return nil
 	}
 	return db.update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(validatorsMinMaxSpanBucket)
-		key := bytesutil.Bytes8(epoch)
-		return bucket.DeleteBucket(key)
+		return deleteEpochSpans(tx, epoch)
 	})
 }
 
@@ -371,11 +320,7 @@ func (db *Store) DeleteValidatorSpanByEpoch(ctx context.Context, validatorIdx ui
 	}
 
 	return db.update(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket(validatorsMinMaxSpanBucket)
-		e := bytesutil.Bytes8(epoch)
-		epochBucket := bucket.Bucket(e)
-		v := bytesutil.Bytes8(validatorIdx)
-		return epochBucket.Delete(v)
+		return saveSpans(tx, map[uint64]map[uint64]types.Span{epoch: {validatorIdx: {}}})
 	})
 }
 
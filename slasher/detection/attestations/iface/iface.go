@@ -17,4 +17,5 @@ type SpanDetector interface {
 
 	// Write functions.
 	UpdateSpans(ctx context.Context, att *ethpb.IndexedAttestation) error
+	FlushSpans(ctx context.Context) error
 }
@@ -81,3 +81,8 @@ func (s *MockSpanDetector) DeleteValidatorSpansByEpoch(ctx context.Context, vali
 func (s *MockSpanDetector) UpdateSpans(ctx context.Context, att *ethpb.IndexedAttestation) error {
 	return nil
 }
+
+// FlushSpans is a mock for saving the updated spans.
+func (s *MockSpanDetector) FlushSpans(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,150 @@
+package attestations
+
+import (
+	"context"
+
+	db "github.com/prysmaticlabs/prysm/slasher/db"
+	"github.com/prysmaticlabs/prysm/slasher/detection/attestations/types"
+)
+
+// batchEpoch is the span map of an epoch read in full during a batch.
+type batchEpoch struct {
+	spans     map[uint64]types.Span
+	fromCache bool
+}
+
+// spanBatch accumulates the span updates of the attestations of an epoch, so the spans of
+// an epoch are read once per batch instead of once per attestation, and only the updated
+// spans are written back, in a single transaction, when the batch is flushed.
+type spanBatch struct {
+	slasherDB db.Database
+	// Span maps read in full, by epoch.
+	epochs map[uint64]*batchEpoch
+	// Spans read for single validators by the min span lookback, by epoch then validator
+	// index, for the epochs which are not read in full.
+	lookback map[uint64]map[uint64]types.Span
+	// Highest epoch up to which the lookback holds the spans of a validator.
+	lookbackEpochs map[uint64]uint64
+	// Validator indices of the spans updated in the batch, by epoch.
+	updated map[uint64]map[uint64]bool
+}
+
+func newSpanBatch(slasherDB db.Database) *spanBatch {
+	return &spanBatch{
+		slasherDB:      slasherDB,
+		epochs:         make(map[uint64]*batchEpoch),
+		lookback:       make(map[uint64]map[uint64]types.Span),
+		lookbackEpochs: make(map[uint64]uint64),
+		updated:        make(map[uint64]map[uint64]bool),
+	}
+}
+
+// epochSpans returns the span map of all validators at the epoch, reading it on first use.
+func (b *spanBatch) epochSpans(ctx context.Context, epoch uint64) (*batchEpoch, error) {
+	if e, ok := b.epochs[epoch]; ok {
+		return e, nil
+	}
+	spanMap, fromCache, err := b.slasherDB.EpochSpansMap(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	// Spans already updated through the lookback are more recent than the ones read.
+	for idx := range b.updated[epoch] {
+		spanMap[idx] = b.lookback[epoch][idx]
+	}
+	delete(b.lookback, epoch)
+	e := &batchEpoch{spans: spanMap, fromCache: fromCache}
+	b.epochs[epoch] = e
+	return e, nil
+}
+
+// validatorSpans returns the spans of the validators at the epoch. Unless the epoch is read in
+// full, the spans of the validators are read for all epochs up to the epoch at once.
+func (b *spanBatch) validatorSpans(ctx context.Context, epoch uint64, indices []uint64) (map[uint64]types.Span, error) {
+	if e, ok := b.epochs[epoch]; ok {
+		return e.spans, nil
+	}
+	var missing []uint64
+	for _, idx := range indices {
+		if maxEpoch, ok := b.lookbackEpochs[idx]; !ok || maxEpoch < epoch {
+			missing = append(missing, idx)
+		}
+	}
+	if len(missing) > 0 {
+		epochsSpans, err := b.slasherDB.EpochsSpanByValidatorsIndices(ctx, missing, epoch)
+		if err != nil {
+			return nil, err
+		}
+		for e, spanMap := range epochsSpans {
+			if _, ok := b.epochs[e]; ok {
+				continue
+			}
+			for _, idx := range missing {
+				span, ok := spanMap[idx]
+				if !ok {
+					continue
+				}
+				// Spans already held by the lookback may have been updated in the batch.
+				if maxEpoch, read := b.lookbackEpochs[idx]; read && e <= maxEpoch {
+					continue
+				}
+				if b.lookback[e] == nil {
+					b.lookback[e] = make(map[uint64]types.Span)
+				}
+				b.lookback[e][idx] = span
+			}
+		}
+		for _, idx := range missing {
+			b.lookbackEpochs[idx] = epoch
+		}
+	}
+	spanMap := b.lookback[epoch]
+	if spanMap == nil {
+		spanMap = make(map[uint64]types.Span)
+		b.lookback[epoch] = spanMap
+	}
+	return spanMap, nil
+}
+
+// setSpan updates the span of a validator at the epoch.
+func (b *spanBatch) setSpan(epoch uint64, idx uint64, span types.Span) {
+	if e, ok := b.epochs[epoch]; ok {
+		e.spans[idx] = span
+	} else {
+		if b.lookback[epoch] == nil {
+			b.lookback[epoch] = make(map[uint64]types.Span)
+		}
+		b.lookback[epoch][idx] = span
+	}
+	if b.updated[epoch] == nil {
+		b.updated[epoch] = make(map[uint64]bool)
+	}
+	b.updated[epoch][idx] = true
+}
+
+// flush saves the spans updated in the batch. Span maps read from the span cache are saved
+// back to it, the other updated spans are written to the database in a single transaction.
+func (b *spanBatch) flush(ctx context.Context) error {
+	epochsSpans := make(map[uint64]map[uint64]types.Span)
+	for epoch, indices := range b.updated {
+		if e, ok := b.epochs[epoch]; ok && e.fromCache {
+			if err := b.slasherDB.SaveEpochSpansMap(ctx, epoch, e.spans); err != nil {
+				return err
+			}
+			continue
+		}
+		spanMap := make(map[uint64]types.Span, len(indices))
+		for idx := range indices {
+			if e, ok := b.epochs[epoch]; ok {
+				spanMap[idx] = e.spans[idx]
+			} else {
+				spanMap[idx] = b.lookback[epoch][idx]
+			}
+		}
+		epochsSpans[epoch] = spanMap
+	}
+	if len(epochsSpans) == 0 {
+		return nil
+	}
+	return b.slasherDB.SaveEpochsSpanByValidatorsIndices(ctx, epochsSpans)
+}
@@ -3,6 +3,7 @@ package attestations
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -35,8 +36,16 @@ var _ = iface.SpanDetector(&SpanDetector{})
 // SpanDetector defines a struct which can detect slashable
 // attestation offenses by tracking validator min-max
 // spans from validators and attestation data roots.
+// Span updates are batched by target epoch: the spans updated by the
+// attestations of the latest target epoch are held in memory, and read
+// by detection, until attestations of a later target epoch are received
+// or the spans are flushed.
 type SpanDetector struct {
 	slasherDB db.Database
+	lock      sync.Mutex
+	batch     *spanBatch
+	// Latest target epoch of the attestations in the batch.
+	batchEpoch uint64
 }
 
 // NewSpanDetector creates a new instance of a struct tracking
@@ -67,11 +76,14 @@ func (s *SpanDetector) DetectSlashingsForAttestation(
 		)
 	}
 
-	spanMap, _, err := s.slasherDB.EpochSpansMap(ctx, sourceEpoch)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	batch := s.currentBatch()
+	sourceSpans, err := batch.epochSpans(ctx, sourceEpoch)
 	if err != nil {
 		return nil, err
 	}
-	targetSpanMap, _, err := s.slasherDB.EpochSpansMap(ctx, targetEpoch)
+	targetSpans, err := batch.epochSpans(ctx, targetEpoch)
 	if err != nil {
 		return nil, err
 	}
@@ -79,11 +91,11 @@ func (s *SpanDetector) DetectSlashingsForAttestation(
 	var detections []*types.DetectionResult
 	distance := uint16(targetEpoch - sourceEpoch)
 	for _, idx := range att.AttestingIndices {
-		span := spanMap[idx]
+		span := sourceSpans.spans[idx]
 		minSpan := span.MinSpan
 		if minSpan > 0 && minSpan < distance {
 			slashableEpoch := sourceEpoch + uint64(minSpan)
-			targetSpan, err := s.slasherDB.EpochSpanByValidatorIndex(ctx, idx, slashableEpoch)
+			slashableSpans, err := batch.epochSpans(ctx, slashableEpoch)
 			if err != nil {
 				return nil, err
 			}
@@ -91,7 +103,7 @@ func (s *SpanDetector) DetectSlashingsForAttestation(
 				ValidatorIndex: idx,
 				Kind:           types.SurroundVote,
 				SlashableEpoch: slashableEpoch,
-				SigBytes:       targetSpan.SigBytes,
+				SigBytes:       slashableSpans.spans[idx].SigBytes,
 			})
 			continue
 		}
@@ -99,7 +111,7 @@ func (s *SpanDetector) DetectSlashingsForAttestation(
 		maxSpan := span.MaxSpan
 		if maxSpan > distance {
 			slashableEpoch := sourceEpoch + uint64(maxSpan)
-			targetSpan, err := s.slasherDB.EpochSpanByValidatorIndex(ctx, idx, slashableEpoch)
+			slashableSpans, err := batch.epochSpans(ctx, slashableEpoch)
 			if err != nil {
 				return nil, err
 			}
@@ -107,12 +119,12 @@ func (s *SpanDetector) DetectSlashingsForAttestation(
 				ValidatorIndex: idx,
 				Kind:           types.SurroundVote,
 				SlashableEpoch: slashableEpoch,
-				SigBytes:       targetSpan.SigBytes,
+				SigBytes:       slashableSpans.spans[idx].SigBytes,
 			})
 			continue
 		}
 
-		targetSpan := targetSpanMap[idx]
+		targetSpan := targetSpans.spans[idx]
 		// Check if the validator has attested for this epoch or not.
 		if targetSpan.HasAttested {
 			detections = append(detections, &types.DetectionResult{
@@ -129,61 +141,100 @@ func (s *SpanDetector) DetectSlashingsForAttestation(
 }
 
 // UpdateSpans given an indexed attestation for all of its attesting indices.
+// The spans are saved when attestations of a later target epoch are received,
+// or when they are flushed.
 func (s *SpanDetector) UpdateSpans(ctx context.Context, att *ethpb.IndexedAttestation) error {
 	ctx, span := trace.StartSpan(ctx, "spanner.UpdateSpans")
 	defer span.End()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if att.Data.Target.Epoch > s.batchEpoch {
+		if err := s.flush(ctx); err != nil {
+			return err
+		}
+		s.batchEpoch = att.Data.Target.Epoch
+	}
+	batch := s.currentBatch()
 	// Save the signature for the received attestation so we can have more detail to find it in the DB.
-	if err := s.saveSigBytes(ctx, att); err != nil {
+	if err := s.saveSigBytes(ctx, batch, att); err != nil {
 		return err
 	}
 	// Update min and max spans.
-	if err := s.updateMinSpan(ctx, att); err != nil {
+	if err := s.updateMinSpan(ctx, batch, att); err != nil {
 		return err
 	}
-	if err := s.updateMaxSpan(ctx, att); err != nil {
+	if err := s.updateMaxSpan(ctx, batch, att); err != nil {
 		return err
 	}
 	return nil
 }
 
+// FlushSpans saves the spans updated by the attestations received since the last flush.
+func (s *SpanDetector) FlushSpans(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "spanner.FlushSpans")
+	defer span.End()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.flush(ctx)
+}
+
+// currentBatch returns the batch of span updates, starting one if needed.
+// The caller must hold the lock.
+func (s *SpanDetector) currentBatch() *spanBatch {
+	if s.batch == nil {
+		s.batch = newSpanBatch(s.slasherDB)
+	}
+	return s.batch
+}
+
+// flush saves the batch of span updates, the caller must hold the lock.
+func (s *SpanDetector) flush(ctx context.Context) error {
+	if s.batch == nil {
+		return nil
+	}
+	if err := s.batch.flush(ctx); err != nil {
+		return err
+	}
+	s.batch = nil
+	return nil
+}
+
 // saveSigBytes saves the first 2 bytes of the signature for the att we're updating the spans to.
 // Later used to help us find the violating attestation in the DB.
-func (s *SpanDetector) saveSigBytes(ctx context.Context, att *ethpb.IndexedAttestation) error {
+func (s *SpanDetector) saveSigBytes(ctx context.Context, batch *spanBatch, att *ethpb.IndexedAttestation) error {
 	ctx, traceSpan := trace.StartSpan(ctx, "spanner.saveSigBytes")
 	defer traceSpan.End()
 	target := att.Data.Target.Epoch
-	spanMap, _, err := s.slasherDB.EpochSpansMap(ctx, target)
+	targetSpans, err := batch.epochSpans(ctx, target)
 	if err != nil {
 		return err
 	}
 
-	// We loop through the indices, instead of constantly locking/unlocking the cache for equivalent accesses.
+	sigBytes := [2]byte{0, 0}
+	if len(att.Signature) > 1 {
+		sigBytes = [2]byte{att.Signature[0], att.Signature[1]}
+	}
 	for _, idx := range att.AttestingIndices {
-		span := spanMap[idx]
+		span := targetSpans.spans[idx]
 		// If the validator has already attested for this target epoch,
 		// then we do not need to update the values of the span sig bytes.
 		if span.HasAttested {
-			return nil
-		}
-
-		sigBytes := [2]byte{0, 0}
-		if len(att.Signature) > 1 {
-			sigBytes = [2]byte{att.Signature[0], att.Signature[1]}
+			continue
 		}
 		// Save the signature bytes into the span for this epoch.
-		spanMap[idx] = types.Span{
+		batch.setSpan(target, idx, types.Span{
 			MinSpan:     span.MinSpan,
 			MaxSpan:     span.MaxSpan,
 			HasAttested: true,
 			SigBytes:    sigBytes,
-		}
+		})
 	}
-	return s.slasherDB.SaveEpochSpansMap(ctx, target, spanMap)
+	return nil
 }
 
 // Updates a min span for a validator index given a source and target epoch
 // for an attestation produced by the validator. Used for catching surrounding votes.
-func (s *SpanDetector) updateMinSpan(ctx context.Context, att *ethpb.IndexedAttestation) error {
+func (s *SpanDetector) updateMinSpan(ctx context.Context, batch *spanBatch, att *ethpb.IndexedAttestation) error {
 	ctx, traceSpan := trace.StartSpan(ctx, "spanner.updateMinSpan")
 	defer traceSpan.End()
 	source := att.Data.Source.Epoch
@@ -195,32 +246,23 @@ func (s *SpanDetector) updateMinSpan(ctx context.Context, att *ethpb.IndexedAtte
 	copy(valIndices, att.AttestingIndices)
 	latestMinSpanDistanceObserved.Set(float64(att.Data.Target.Epoch - att.Data.Source.Epoch))
 
-	// the for loop tries to update min span using cache for as long as there
-	// is a relevant cached epoch. when there is no such epoch in cache batch
-	// db read and write is used.
-	spanMap := make(map[uint64]types.Span)
-	epochsSpansMap := make(map[uint64]map[uint64]types.Span)
-	epoch := source - 1
-	useCache := true
-	useDb := false
-	var err error
-	for ; epoch >= 0; epoch-- {
-		if useCache {
-			spanMap, useCache, err = s.slasherDB.EpochSpansMap(ctx, epoch)
-		}
-		// Should happen once when cache is exhausted.
-		if !useCache && !useDb {
-			epochsSpansMap, err = s.slasherDB.EpochsSpanByValidatorsIndices(ctx, valIndices, epoch)
-			useDb = true
-		}
-		if err != nil {
-			return err
-		}
-		if useDb {
-			spanMap = epochsSpansMap[epoch]
-			if spanMap == nil {
-				spanMap = make(map[uint64]types.Span)
-				epochsSpansMap[epoch] = spanMap
+	// The span maps of the epochs are used for as long as they are cached. Past the
+	// first epoch which is not, only the spans of the attesting validators are read.
+	useEpochSpans := true
+	for epoch := source - 1; ; epoch-- {
+		var spanMap map[uint64]types.Span
+		if useEpochSpans {
+			epochSpans, err := batch.epochSpans(ctx, epoch)
+			if err != nil {
+				return err
+			}
+			spanMap = epochSpans.spans
+			useEpochSpans = epochSpans.fromCache
+		} else {
+			var err error
+			spanMap, err = batch.validatorSpans(ctx, epoch, valIndices)
+			if err != nil {
+				return err
 			}
 		}
 
@@ -229,29 +271,17 @@ func (s *SpanDetector) updateMinSpan(ctx context.Context, att *ethpb.IndexedAtte
 			span := spanMap[idx]
 			newMinSpan := uint16(target - epoch)
 			if span.MinSpan == 0 || span.MinSpan > newMinSpan {
-				span = types.Span{
+				batch.setSpan(epoch, idx, types.Span{
 					MinSpan:     newMinSpan,
 					MaxSpan:     span.MaxSpan,
 					SigBytes:    span.SigBytes,
 					HasAttested: span.HasAttested,
-				}
-				spanMap[idx] = span
+				})
 				indices = append(indices, idx)
 			}
 		}
-		copy(valIndices, indices)
-		if useCache {
-			if err := s.slasherDB.SaveEpochSpansMap(ctx, epoch, spanMap); err != nil {
-				return err
-			}
-		}
-		if len(indices) == 0 || epoch == 0 {
-			if useDb {
-				// should happen once when finishing update to all epochs and all indices.
-				if err := s.slasherDB.SaveEpochsSpanByValidatorsIndices(ctx, epochsSpansMap); err != nil {
-					return err
-				}
-			}
+		valIndices = indices
+		if len(valIndices) == 0 || epoch == 0 {
 			break
 		}
 	}
@@ -260,7 +290,7 @@ func (s *SpanDetector) updateMinSpan(ctx context.Context, att *ethpb.IndexedAtte
 
 // Updates a max span for a validator index given a source and target epoch
 // for an attestation produced by the validator. Used for catching surrounded votes.
-func (s *SpanDetector) updateMaxSpan(ctx context.Context, att *ethpb.IndexedAttestation) error {
+func (s *SpanDetector) updateMaxSpan(ctx context.Context, batch *spanBatch, att *ethpb.IndexedAttestation) error {
 	ctx, traceSpan := trace.StartSpan(ctx, "spanner.updateMaxSpan")
 	defer traceSpan.End()
 	source := att.Data.Source.Epoch
@@ -269,29 +299,26 @@ func (s *SpanDetector) updateMaxSpan(ctx context.Context, att *ethpb.IndexedAtte
 	valIndices := make([]uint64, len(att.AttestingIndices))
 	copy(valIndices, att.AttestingIndices)
 	for epoch := source + 1; epoch < target; epoch++ {
-		spanMap, _, err := s.slasherDB.EpochSpansMap(ctx, epoch)
+		epochSpans, err := batch.epochSpans(ctx, epoch)
 		if err != nil {
 			return err
 		}
 		indices := valIndices[:0]
 		for _, idx := range valIndices {
-			span := spanMap[idx]
+			span := epochSpans.spans[idx]
 			newMaxSpan := uint16(target - epoch)
 			if newMaxSpan > span.MaxSpan {
-				span = types.Span{
+				batch.setSpan(epoch, idx, types.Span{
 					MinSpan:     span.MinSpan,
 					MaxSpan:     newMaxSpan,
 					SigBytes:    span.SigBytes,
 					HasAttested: span.HasAttested,
-				}
-				spanMap[idx] = span
+				})
 				indices = append(indices, idx)
 			}
 		}
-		if err := s.slasherDB.SaveEpochSpansMap(ctx, epoch, spanMap); err != nil {
-			return err
-		}
-		if len(indices) == 0 {
+		valIndices = indices
+		if len(valIndices) == 0 {
 			break
 		}
 	}
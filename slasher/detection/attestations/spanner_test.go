@@ -824,6 +824,9 @@ func TestNewSpanDetector_UpdateSpans(t *testing.T) {
 			if err := sd.UpdateSpans(ctx, tt.att); err != nil {
 				t.Fatal(err)
 			}
+			if err := sd.FlushSpans(ctx); err != nil {
+				t.Fatal(err)
+			}
 			for epoch := range tt.want {
 				sm, _, err := sd.slasherDB.EpochSpansMap(ctx, uint64(epoch))
 				if err != nil {
@@ -836,3 +839,114 @@ func TestNewSpanDetector_UpdateSpans(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanDetector_UpdateSpans_BatchedByTargetEpoch(t *testing.T) {
+	db := testDB.SetupSlasherDB(t, false)
+	defer testDB.TeardownSlasherDB(t, db)
+	ctx := context.Background()
+	sd := NewSpanDetector(db)
+
+	// The min spans of the first attestation are read and updated for the validator only,
+	// the second attestation reads its max span epochs in full.
+	if err := sd.UpdateSpans(ctx, indexedAttestation(3, 4, []uint64{0})); err != nil {
+		t.Fatal(err)
+	}
+	if err := sd.UpdateSpans(ctx, indexedAttestation(1, 4, []uint64{0})); err != nil {
+		t.Fatal(err)
+	}
+	spanMap, _, err := db.EpochSpansMap(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spanMap) != 0 {
+		t.Fatalf("Expected the spans of the batch not to be saved yet, received %v", spanMap)
+	}
+	// Detection reads the spans of the batch.
+	res, err := sd.DetectSlashingsForAttestation(ctx, indexedAttestation(3, 4, []uint64{0}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Kind != types.DoubleVote {
+		t.Fatalf("Expected a double vote, received %v", res)
+	}
+
+	// Attestations of a later target epoch save the batch.
+	if err := sd.UpdateSpans(ctx, indexedAttestation(4, 5, []uint64{1})); err != nil {
+		t.Fatal(err)
+	}
+	want := []types.Span{
+		{MinSpan: 4},
+		{MinSpan: 3},
+		{MinSpan: 2, MaxSpan: 2},
+		{MaxSpan: 1},
+		{SigBytes: [2]byte{1, 2}, HasAttested: true},
+	}
+	for epoch, span := range want {
+		got, err := db.EpochSpanByValidatorIndex(ctx, 0, uint64(epoch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != span {
+			t.Errorf("Wanted span %v at epoch %d, received %v", span, epoch, got)
+		}
+	}
+}
+
+const benchmarkValidators = 100000
+
+// benchmarkEpochAttestations returns the attestations of all validators for the epoch,
+// by committees of 128 validators.
+func benchmarkEpochAttestations(epoch uint64) []*ethpb.IndexedAttestation {
+	var atts []*ethpb.IndexedAttestation
+	for first := uint64(0); first < benchmarkValidators; first += 128 {
+		var indices []uint64
+		for idx := first; idx < first+128 && idx < benchmarkValidators; idx++ {
+			indices = append(indices, idx)
+		}
+		atts = append(atts, indexedAttestation(epoch, epoch+1, indices))
+	}
+	return atts
+}
+
+func benchmarkUpdateSpans(b *testing.B, flushEach bool) {
+	db := testDB.SetupSlasherDB(b, false)
+	defer testDB.TeardownSlasherDB(b, db)
+	ctx := context.Background()
+	sd := NewSpanDetector(db)
+	epochsAtts := make([][]*ethpb.IndexedAttestation, b.N)
+	for i := range epochsAtts {
+		epochsAtts[i] = benchmarkEpochAttestations(uint64(i + 1))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, att := range epochsAtts[i] {
+			if _, err := sd.DetectSlashingsForAttestation(ctx, att); err != nil {
+				b.Fatal(err)
+			}
+			if err := sd.UpdateSpans(ctx, att); err != nil {
+				b.Fatal(err)
+			}
+			if flushEach {
+				if err := sd.FlushSpans(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	if err := sd.FlushSpans(ctx); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// BenchmarkSpanDetector_UpdateSpans_PerEpoch runs detection and span updates on an epoch of
+// attestations of 100k validators, with the span updates batched per target epoch.
+func BenchmarkSpanDetector_UpdateSpans_PerEpoch(b *testing.B) {
+	benchmarkUpdateSpans(b, false)
+}
+
+// BenchmarkSpanDetector_UpdateSpans_PerAttestation saves the span updates after every attestation,
+// as the detector did before span updates were batched.
+func BenchmarkSpanDetector_UpdateSpans_PerAttestation(b *testing.B) {
+	benchmarkUpdateSpans(b, true)
+}
@@ -0,0 +1,78 @@
+package detection
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"go.opencensus.io/trace"
+)
+
+// ReplayResult lists the slashings found by replaying recorded chain data.
+type ReplayResult struct {
+	AttesterSlashings []*ethpb.AttesterSlashing
+	ProposerSlashings []*ethpb.ProposerSlashing
+}
+
+// Replay runs slashing detection on recorded block headers and indexed attestations, as the
+// service does on the objects received from a beacon node, and returns the slashings found
+// instead of submitting them. Block headers are replayed by slot and attestations by target
+// epoch, each one being saved to the slasher DB so the following ones are checked against it.
+func (ds *Service) Replay(
+	ctx context.Context,
+	headers []*ethpb.SignedBeaconBlockHeader,
+	atts []*ethpb.IndexedAttestation,
+) (*ReplayResult, error) {
+	ctx, span := trace.StartSpan(ctx, "detection.Replay")
+	defer span.End()
+	result := &ReplayResult{}
+
+	headers = append([]*ethpb.SignedBeaconBlockHeader{}, headers...)
+	sort.SliceStable(headers, func(i, j int) bool {
+		return headers[i].Header.Slot < headers[j].Header.Slot
+	})
+	for _, header := range headers {
+		slashing, err := ds.DetectDoubleProposals(ctx, header)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not detect double proposals at slot %d", header.Header.Slot)
+		}
+		if slashing != nil {
+			result.ProposerSlashings = append(result.ProposerSlashings, slashing)
+		}
+		if err := ds.slasherDB.SaveBlockHeader(ctx, header); err != nil {
+			return nil, errors.Wrap(err, "could not save block header")
+		}
+	}
+
+	atts = append([]*ethpb.IndexedAttestation{}, atts...)
+	sort.SliceStable(atts, func(i, j int) bool {
+		if atts[i].Data.Target.Epoch != atts[j].Data.Target.Epoch {
+			return atts[i].Data.Target.Epoch < atts[j].Data.Target.Epoch
+		}
+		return atts[i].Data.Source.Epoch < atts[j].Data.Source.Epoch
+	})
+	for _, att := range atts {
+		if err := ds.slasherDB.SaveIndexedAttestation(ctx, att); err != nil {
+			return nil, errors.Wrap(err, "could not save indexed attestation")
+		}
+		slashings, err := ds.DetectAttesterSlashings(ctx, att)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not detect attester slashings at target epoch %d", att.Data.Target.Epoch)
+		}
+		if len(slashings) > 0 {
+			result.AttesterSlashings = append(result.AttesterSlashings, slashings...)
+			continue
+		}
+		if err := ds.minMaxSpanDetector.UpdateSpans(ctx, att); err != nil {
+			return nil, errors.Wrap(err, "could not update spans")
+		}
+	}
+	if err := ds.minMaxSpanDetector.FlushSpans(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not flush spans")
+	}
+	if err := ds.slasherDB.SaveCachedSpansMaps(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not save span maps")
+	}
+	return result, nil
+}
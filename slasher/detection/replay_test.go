@@ -0,0 +1,68 @@
+package detection
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	testDB "github.com/prysmaticlabs/prysm/slasher/db/testing"
+	testDetect "github.com/prysmaticlabs/prysm/slasher/detection/testing"
+)
+
+func TestService_Replay(t *testing.T) {
+	db := testDB.SetupSlasherDB(t, false)
+	defer testDB.TeardownSlasherDB(t, db)
+	ctx := context.Background()
+	ds := NewDetectionService(ctx, &Config{SlasherDB: db})
+
+	blk1, err := testDetect.SignedBlockHeader(testDetect.StartSlot(0), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk2, err := testDetect.SignedBlockHeader(testDetect.StartSlot(0), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk3, err := testDetect.SignedBlockHeader(testDetect.StartSlot(1), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The surrounding attestation is recorded first, it is replayed after the one it surrounds.
+	atts := []*ethpb.IndexedAttestation{
+		{
+			AttestingIndices: []uint64{1, 3, 7},
+			Data: &ethpb.AttestationData{
+				Source: &ethpb.Checkpoint{Epoch: 7},
+				Target: &ethpb.Checkpoint{Epoch: 14},
+			},
+			Signature: bytesutil.PadTo([]byte{3, 4}, 96),
+		},
+		{
+			AttestingIndices: []uint64{3},
+			Data: &ethpb.AttestationData{
+				Source: &ethpb.Checkpoint{Epoch: 9},
+				Target: &ethpb.Checkpoint{Epoch: 13},
+			},
+			Signature: bytesutil.PadTo([]byte{1, 2}, 96),
+		},
+	}
+
+	result, err := ds.Replay(ctx, []*ethpb.SignedBeaconBlockHeader{blk1, blk3, blk2}, atts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ProposerSlashings) != 1 {
+		t.Fatalf("Wanted %d proposer slashing, received %d", 1, len(result.ProposerSlashings))
+	}
+	if !isDoublePropose(result.ProposerSlashings[0].Header_1, result.ProposerSlashings[0].Header_2) {
+		t.Error("Expected a double proposal slashing")
+	}
+	if len(result.AttesterSlashings) != 1 {
+		t.Fatalf("Wanted %d attester slashing, received %d", 1, len(result.AttesterSlashings))
+	}
+	slashing := result.AttesterSlashings[0]
+	if !isSurrounding(slashing.Attestation_1, slashing.Attestation_2) {
+		t.Errorf("Expected a surround vote slashing, received %v", slashing)
+	}
+}
@@ -3,6 +3,7 @@ package detection
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
@@ -67,7 +68,11 @@ func NewDetectionService(ctx context.Context, cfg *Config) *Service {
 func (ds *Service) Stop() error {
 	ds.cancel()
 	log.Info("Stopping service")
-	return nil
+	// Save the span updates batched by the span detector, along with the span cache.
+	if err := ds.minMaxSpanDetector.FlushSpans(context.Background()); err != nil {
+		return errors.Wrap(err, "could not flush spans")
+	}
+	return ds.slasherDB.SaveCachedSpansMaps(context.Background())
 }
 
 // Status returns an error if there exists an error in
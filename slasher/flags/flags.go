@@ -43,4 +43,10 @@ var (
 		Name:  "rebuild-span-maps",
 		Usage: "Rebuild span maps from indexed attestations in db",
 	}
+	// AdditionalBeaconRPCProviderFlag defines a flag for more beacon nodes to receive blocks and attestations from.
+	AdditionalBeaconRPCProviderFlag = &cli.StringSliceFlag{
+		Name: "additional-beacon-rpc-provider",
+		Usage: "Beacon node RPC provider endpoint to also receive blocks and attestations from, so that slashings are " +
+			"detected while one beacon node falls behind or restarts. This flag may be used multiple times.",
+	}
 )
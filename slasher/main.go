@@ -61,6 +61,7 @@ var appFlags = []cli.Flag{
 	flags.RebuildSpanMapsFlag,
 	flags.BeaconCertFlag,
 	flags.BeaconRPCProviderFlag,
+	flags.AdditionalBeaconRPCProviderFlag,
 }
 
 func init() {
@@ -73,6 +74,7 @@ func main() {
 	app.Usage = `launches an Ethereum Serenity slasher server that interacts with a beacon chain.`
 	app.Version = version.GetVersion()
 	app.Flags = appFlags
+	app.Commands = []*cli.Command{replayCommand}
 	app.Action = startSlasher
 	app.Before = func(ctx *cli.Context) error {
 		// Load any flags from file, if specified.
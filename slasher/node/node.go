@@ -184,6 +184,7 @@ func (s *SlasherNode) registerBeaconClientService(ctx *cli.Context) error {
 		BeaconCert:            beaconCert,
 		SlasherDB:             s.db,
 		BeaconProvider:        beaconProvider,
+		AdditionalProviders:   ctx.StringSlice(flags.AdditionalBeaconRPCProviderFlag.Name),
 		AttesterSlashingsFeed: s.attesterSlashingsFeed,
 		ProposerSlashingsFeed: s.proposerSlashingsFeed,
 	})
@@ -219,11 +220,13 @@ func (s *SlasherNode) registerRPCService(ctx *cli.Context) error {
 	cert := ctx.String(flags.CertFlag.Name)
 	key := ctx.String(flags.KeyFlag.Name)
 	rpcService := rpc.NewService(context.Background(), &rpc.Config{
-		Port:      port,
-		CertFlag:  cert,
-		KeyFlag:   key,
-		Detector:  detectionService,
-		SlasherDB: s.db,
+		Port:                  port,
+		CertFlag:              cert,
+		KeyFlag:               key,
+		Detector:              detectionService,
+		SlasherDB:             s.db,
+		AttesterSlashingsFeed: s.attesterSlashingsFeed,
+		ProposerSlashingsFeed: s.proposerSlashingsFeed,
 	})
 
 	return s.services.RegisterService(rpcService)
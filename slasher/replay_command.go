@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/slasher/db"
+	"github.com/prysmaticlabs/prysm/slasher/db/kv"
+	"github.com/prysmaticlabs/prysm/slasher/detection"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var replayAttestationsFlag = &cli.StringSliceFlag{
+	Name: "attestations",
+	Usage: "Files of indexed attestations to replay, as a JSON array if the file name ends in .json " +
+		"and as an SSZ list otherwise",
+}
+
+var replayBlockHeadersFlag = &cli.StringSliceFlag{
+	Name: "block-headers",
+	Usage: "Files of signed block headers to replay, as a JSON array if the file name ends in .json " +
+		"and as an SSZ list otherwise",
+}
+
+var replayCommand = &cli.Command{
+	Name:     "replay",
+	Category: "replay",
+	Usage: "Run slashing detection on recorded block headers and indexed attestations against a scratch " +
+		"slasher database, and print the slashings found as JSON",
+	Flags:  []cli.Flag{replayAttestationsFlag, replayBlockHeadersFlag},
+	Action: replay,
+}
+
+func replay(ctx *cli.Context) error {
+	log := logrus.WithField("prefix", "replay")
+	var headers []*ethpb.SignedBeaconBlockHeader
+	for _, fPath := range ctx.StringSlice(replayBlockHeadersFlag.Name) {
+		var fileHeaders []*ethpb.SignedBeaconBlockHeader
+		if err := readReplayFile(fPath, &fileHeaders, func() proto.Message {
+			h := &ethpb.SignedBeaconBlockHeader{}
+			fileHeaders = append(fileHeaders, h)
+			return h
+		}); err != nil {
+			return err
+		}
+		headers = append(headers, fileHeaders...)
+	}
+	var atts []*ethpb.IndexedAttestation
+	for _, fPath := range ctx.StringSlice(replayAttestationsFlag.Name) {
+		var fileAtts []*ethpb.IndexedAttestation
+		if err := readReplayFile(fPath, &fileAtts, func() proto.Message {
+			att := &ethpb.IndexedAttestation{}
+			fileAtts = append(fileAtts, att)
+			return att
+		}); err != nil {
+			return err
+		}
+		atts = append(atts, fileAtts...)
+	}
+	if len(headers) == 0 && len(atts) == 0 {
+		return fmt.Errorf("nothing to replay, set --%s or --%s", replayAttestationsFlag.Name, replayBlockHeadersFlag.Name)
+	}
+
+	dir, err := ioutil.TempDir("", "slasher-replay")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.WithError(err).Error("Could not remove scratch database")
+		}
+	}()
+	slasherDB, err := db.NewDB(dir, &kv.Config{})
+	if err != nil {
+		return errors.Wrap(err, "could not create scratch database")
+	}
+	defer func() {
+		if err := slasherDB.Close(); err != nil {
+			log.WithError(err).Error("Could not close scratch database")
+		}
+	}()
+
+	log.WithFields(logrus.Fields{
+		"blockHeaders": len(headers),
+		"attestations": len(atts),
+	}).Info("Replaying chain data")
+	ds := detection.NewDetectionService(context.Background(), &detection.Config{SlasherDB: slasherDB})
+	result, err := ds.Replay(context.Background(), headers, atts)
+	if err != nil {
+		return err
+	}
+
+	marshaler := &jsonpb.Marshaler{Indent: "  "}
+	for _, slashing := range result.ProposerSlashings {
+		if err := marshaler.Marshal(os.Stdout, slashing); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	for _, slashing := range result.AttesterSlashings {
+		if err := marshaler.Marshal(os.Stdout, slashing); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	log.WithFields(logrus.Fields{
+		"proposerSlashings": len(result.ProposerSlashings),
+		"attesterSlashings": len(result.AttesterSlashings),
+	}).Info("Completed replay")
+	return nil
+}
+
+// readReplayFile decodes the list of objects in the file, with newMsg appending an object to
+// the list for each element of a JSON array, and sszList receiving an SSZ list.
+func readReplayFile(fPath string, sszList interface{}, newMsg func() proto.Message) error {
+	rawFile, err := ioutil.ReadFile(fPath)
+	if err != nil {
+		return err
+	}
+	if strings.ToLower(filepath.Ext(fPath)) != ".json" {
+		return errors.Wrapf(ssz.Unmarshal(rawFile, sszList), "could not decode %s", fPath)
+	}
+	var elements []json.RawMessage
+	if err := json.Unmarshal(rawFile, &elements); err != nil {
+		return errors.Wrapf(err, "could not decode %s", fPath)
+	}
+	for i, element := range elements {
+		if err := jsonpb.Unmarshal(bytes.NewReader(element), newMsg()); err != nil {
+			return errors.Wrapf(err, "could not decode element %d of %s", i, fPath)
+		}
+	}
+	return nil
+}
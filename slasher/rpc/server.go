@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/slasher/db"
 	"github.com/prysmaticlabs/prysm/slasher/detection"
 	log "github.com/sirupsen/logrus"
@@ -17,9 +18,11 @@ import (
 // Server defines a server implementation of the gRPC Slasher service,
 // providing RPC endpoints for retrieving slashing proofs for malicious validators.
 type Server struct {
-	ctx       context.Context
-	detector  *detection.Service
-	slasherDB db.Database
+	ctx                   context.Context
+	detector              *detection.Service
+	slasherDB             db.Database
+	attesterSlashingsFeed *event.Feed
+	proposerSlashingsFeed *event.Feed
 }
 
 // IsSlashableAttestation returns an attester slashing if the attestation submitted
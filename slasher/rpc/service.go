@@ -10,6 +10,7 @@ import (
 	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/event"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
 	"github.com/prysmaticlabs/prysm/slasher/db"
 	"github.com/prysmaticlabs/prysm/slasher/detection"
@@ -23,27 +24,31 @@ import (
 // Service defines a server implementation of the gRPC Slasher service,
 // providing RPC endpoints for retrieving slashing proofs for malicious validators.
 type Service struct {
-	ctx             context.Context
-	cancel          context.CancelFunc
-	host            string
-	port            string
-	detector        *detection.Service
-	listener        net.Listener
-	grpcServer      *grpc.Server
-	slasherDB       db.Database
-	withCert        string
-	withKey         string
-	credentialError error
+	ctx                   context.Context
+	cancel                context.CancelFunc
+	host                  string
+	port                  string
+	detector              *detection.Service
+	listener              net.Listener
+	grpcServer            *grpc.Server
+	slasherDB             db.Database
+	attesterSlashingsFeed *event.Feed
+	proposerSlashingsFeed *event.Feed
+	withCert              string
+	withKey               string
+	credentialError       error
 }
 
 // Config options for the slasher node RPC server.
 type Config struct {
-	Host      string
-	Port      string
-	CertFlag  string
-	KeyFlag   string
-	Detector  *detection.Service
-	SlasherDB db.Database
+	Host                  string
+	Port                  string
+	CertFlag              string
+	KeyFlag               string
+	Detector              *detection.Service
+	SlasherDB             db.Database
+	AttesterSlashingsFeed *event.Feed
+	ProposerSlashingsFeed *event.Feed
 }
 
 // NewService instantiates a new RPC service instance that will
@@ -51,12 +56,14 @@ type Config struct {
 func NewService(ctx context.Context, cfg *Config) *Service {
 	ctx, cancel := context.WithCancel(ctx)
 	return &Service{
-		ctx:       ctx,
-		cancel:    cancel,
-		host:      cfg.Host,
-		port:      cfg.Port,
-		detector:  cfg.Detector,
-		slasherDB: cfg.SlasherDB,
+		ctx:                   ctx,
+		cancel:                cancel,
+		host:                  cfg.Host,
+		port:                  cfg.Port,
+		detector:              cfg.Detector,
+		slasherDB:             cfg.SlasherDB,
+		attesterSlashingsFeed: cfg.AttesterSlashingsFeed,
+		proposerSlashingsFeed: cfg.ProposerSlashingsFeed,
 	}
 }
 
@@ -103,9 +110,11 @@ func (s *Service) Start() {
 	s.grpcServer = grpc.NewServer(opts...)
 
 	slasherServer := &Server{
-		ctx:       s.ctx,
-		detector:  s.detector,
-		slasherDB: s.slasherDB,
+		ctx:                   s.ctx,
+		detector:              s.detector,
+		slasherDB:             s.slasherDB,
+		attesterSlashingsFeed: s.attesterSlashingsFeed,
+		proposerSlashingsFeed: s.proposerSlashingsFeed,
 	}
 	slashpb.RegisterSlasherServer(s.grpcServer, slasherServer)
 
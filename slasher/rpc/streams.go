@@ -0,0 +1,213 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/sliceutil"
+	"github.com/prysmaticlabs/prysm/slasher/db/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamQueueSize is the number of detected slashings buffered for each stream. A client which
+// falls further behind misses the slashings detected meanwhile, so it never blocks the detection
+// feeds, and can replay them from the database.
+const streamQueueSize = 256
+
+var droppedStreamSlashings = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "slasher_stream_dropped_slashings_total",
+	Help: "The # of detected slashings not sent to a stream subscriber falling behind",
+}, []string{"kind"})
+
+// slashingStatuses are the statuses of the slashings replayed to subscribers.
+var slashingStatuses = []types.SlashingStatus{types.Active, types.Included, types.Reverted}
+
+// StreamAttesterSlashings sends every attester slashing found by the detection service
+// over the stream, as soon as it is found. If requested, the attester slashings already
+// stored in the slasher database are replayed first, so a late subscriber may see a
+// slashing detected while it was subscribing twice.
+func (ss *Server) StreamAttesterSlashings(
+	req *slashpb.StreamSlashingsRequest, stream slashpb.Slasher_StreamAttesterSlashingsServer,
+) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	slashingsChannel := make(chan *ethpb.AttesterSlashing, 1)
+	sub := ss.attesterSlashingsFeed.Subscribe(slashingsChannel)
+	defer sub.Unsubscribe()
+	queue := make(chan *ethpb.AttesterSlashing, streamQueueSize)
+	go queueAttesterSlashings(ctx, slashingsChannel, queue, req.ValidatorIndices)
+
+	if req.Replay {
+		if err := ss.replayAttesterSlashings(ctx, req, stream); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case slashing := <-queue:
+			if err := stream.Send(slashing); err != nil {
+				return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+			}
+		case err := <-sub.Err():
+			return status.Errorf(codes.Aborted, "Subscriber closed: %v", err)
+		case <-ss.ctx.Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		}
+	}
+}
+
+// StreamProposerSlashings sends every proposer slashing found by the detection service
+// over the stream, as soon as it is found. If requested, the proposer slashings already
+// stored in the slasher database are replayed first.
+func (ss *Server) StreamProposerSlashings(
+	req *slashpb.StreamSlashingsRequest, stream slashpb.Slasher_StreamProposerSlashingsServer,
+) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	slashingsChannel := make(chan *ethpb.ProposerSlashing, 1)
+	sub := ss.proposerSlashingsFeed.Subscribe(slashingsChannel)
+	defer sub.Unsubscribe()
+	queue := make(chan *ethpb.ProposerSlashing, streamQueueSize)
+	go queueProposerSlashings(ctx, slashingsChannel, queue, req.ValidatorIndices)
+
+	if req.Replay {
+		if err := ss.replayProposerSlashings(ctx, req, stream); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case slashing := <-queue:
+			if err := stream.Send(slashing); err != nil {
+				return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+			}
+		case err := <-sub.Err():
+			return status.Errorf(codes.Aborted, "Subscriber closed: %v", err)
+		case <-ss.ctx.Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, "Context canceled")
+		}
+	}
+}
+
+// queueAttesterSlashings moves the attester slashings of the given validators received from the
+// detection feed to the queue of a stream, dropping them while the queue is full.
+func queueAttesterSlashings(
+	ctx context.Context, in <-chan *ethpb.AttesterSlashing, queue chan<- *ethpb.AttesterSlashing, indices []uint64,
+) {
+	for {
+		select {
+		case slashing := <-in:
+			if !attesterSlashingMatches(slashing, indices) {
+				continue
+			}
+			select {
+			case queue <- slashing:
+			default:
+				droppedStreamSlashings.WithLabelValues("attester").Inc()
+				log.WithField("targetEpoch", attesterSlashingEpoch(slashing)).Warn("Stream subscriber is falling behind, dropping attester slashing")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// queueProposerSlashings moves the proposer slashings of the given validators received from the
+// detection feed to the queue of a stream, dropping them while the queue is full.
+func queueProposerSlashings(
+	ctx context.Context, in <-chan *ethpb.ProposerSlashing, queue chan<- *ethpb.ProposerSlashing, indices []uint64,
+) {
+	for {
+		select {
+		case slashing := <-in:
+			if !proposerSlashingMatches(slashing, indices) {
+				continue
+			}
+			select {
+			case queue <- slashing:
+			default:
+				droppedStreamSlashings.WithLabelValues("proposer").Inc()
+				log.WithField("slot", slashing.Header_1.Header.Slot).Warn("Stream subscriber is falling behind, dropping proposer slashing")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ss *Server) replayAttesterSlashings(
+	ctx context.Context, req *slashpb.StreamSlashingsRequest, stream slashpb.Slasher_StreamAttesterSlashingsServer,
+) error {
+	for _, st := range slashingStatuses {
+		slashings, err := ss.slasherDB.AttesterSlashings(ctx, st)
+		if err != nil {
+			return status.Errorf(codes.Internal, "Could not retrieve %s attester slashings: %v", st, err)
+		}
+		for _, slashing := range slashings {
+			if attesterSlashingEpoch(slashing) < req.ReplayFromEpoch || !attesterSlashingMatches(slashing, req.ValidatorIndices) {
+				continue
+			}
+			if err := stream.Send(slashing); err != nil {
+				return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (ss *Server) replayProposerSlashings(
+	ctx context.Context, req *slashpb.StreamSlashingsRequest, stream slashpb.Slasher_StreamProposerSlashingsServer,
+) error {
+	for _, st := range slashingStatuses {
+		slashings, err := ss.slasherDB.ProposalSlashingsByStatus(ctx, st)
+		if err != nil {
+			return status.Errorf(codes.Internal, "Could not retrieve %s proposer slashings: %v", st, err)
+		}
+		for _, slashing := range slashings {
+			if helpers.SlotToEpoch(slashing.Header_1.Header.Slot) < req.ReplayFromEpoch || !proposerSlashingMatches(slashing, req.ValidatorIndices) {
+				continue
+			}
+			if err := stream.Send(slashing); err != nil {
+				return status.Errorf(codes.Unavailable, "Could not send over stream: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// attesterSlashingEpoch is the target epoch of the later attestation of the slashing.
+func attesterSlashingEpoch(slashing *ethpb.AttesterSlashing) uint64 {
+	epoch := slashing.Attestation_1.Data.Target.Epoch
+	if slashing.Attestation_2.Data.Target.Epoch > epoch {
+		epoch = slashing.Attestation_2.Data.Target.Epoch
+	}
+	return epoch
+}
+
+// attesterSlashingMatches returns true if any of the slashed validators of the slashing is one
+// of the given indices, or if no indices are given.
+func attesterSlashingMatches(slashing *ethpb.AttesterSlashing, indices []uint64) bool {
+	if len(indices) == 0 {
+		return true
+	}
+	slashed := sliceutil.IntersectionUint64(slashing.Attestation_1.AttestingIndices, slashing.Attestation_2.AttestingIndices)
+	return len(sliceutil.IntersectionUint64(slashed, indices)) > 0
+}
+
+// proposerSlashingMatches returns true if the slashed proposer is one of the given indices, or if
+// no indices are given.
+func proposerSlashingMatches(slashing *ethpb.ProposerSlashing, indices []uint64) bool {
+	if len(indices) == 0 {
+		return true
+	}
+	return sliceutil.IsInUint64(slashing.Header_1.Header.ProposerIndex, indices)
+}
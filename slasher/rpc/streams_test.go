@@ -0,0 +1,214 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/event"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	testDB "github.com/prysmaticlabs/prysm/slasher/db/testing"
+	"github.com/prysmaticlabs/prysm/slasher/db/types"
+	"google.golang.org/grpc"
+)
+
+type attesterSlashingsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *ethpb.AttesterSlashing
+}
+
+func (s *attesterSlashingsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *attesterSlashingsStream) Send(slashing *ethpb.AttesterSlashing) error {
+	s.sent <- slashing
+	return nil
+}
+
+type proposerSlashingsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *ethpb.ProposerSlashing
+}
+
+func (s *proposerSlashingsStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *proposerSlashingsStream) Send(slashing *ethpb.ProposerSlashing) error {
+	s.sent <- slashing
+	return nil
+}
+
+func attesterSlashing(targetEpoch uint64, indices ...uint64) *ethpb.AttesterSlashing {
+	att := func(source uint64) *ethpb.IndexedAttestation {
+		return &ethpb.IndexedAttestation{
+			AttestingIndices: indices,
+			Data: &ethpb.AttestationData{
+				BeaconBlockRoot: make([]byte, 32),
+				Source:          &ethpb.Checkpoint{Epoch: source, Root: make([]byte, 32)},
+				Target:          &ethpb.Checkpoint{Epoch: targetEpoch, Root: make([]byte, 32)},
+			},
+			Signature: bytesutil.PadTo([]byte{byte(source)}, 96),
+		}
+	}
+	return &ethpb.AttesterSlashing{Attestation_1: att(0), Attestation_2: att(1)}
+}
+
+func proposerSlashing(slot uint64, proposerIndex uint64) *ethpb.ProposerSlashing {
+	header := func(root byte) *ethpb.SignedBeaconBlockHeader {
+		return &ethpb.SignedBeaconBlockHeader{
+			Header: &ethpb.BeaconBlockHeader{
+				Slot:          slot,
+				ProposerIndex: proposerIndex,
+				StateRoot:     bytesutil.PadTo([]byte{root}, 32),
+			},
+			Signature: make([]byte, 96),
+		}
+	}
+	return &ethpb.ProposerSlashing{Header_1: header(1), Header_2: header(2)}
+}
+
+func TestServer_StreamAttesterSlashings_FiltersByValidatorIndex(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := new(event.Feed)
+	server := &Server{ctx: ctx, attesterSlashingsFeed: feed}
+	stream := &attesterSlashingsStream{ctx: ctx, sent: make(chan *ethpb.AttesterSlashing, 2)}
+
+	done := make(chan error)
+	go func() {
+		done <- server.StreamAttesterSlashings(&slashpb.StreamSlashingsRequest{ValidatorIndices: []uint64{2}}, stream)
+	}()
+	for feed.Send(attesterSlashing(3, 1)) == 0 {
+		// Wait for the stream to subscribe.
+		time.Sleep(10 * time.Millisecond)
+	}
+	expected := attesterSlashing(4, 1, 2)
+	feed.Send(expected)
+
+	select {
+	case slashing := <-stream.sent:
+		if slashing != expected {
+			t.Errorf("Expected the slashing of validator 2 only, received %v", slashing)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive the slashing of validator 2")
+	}
+	cancel()
+	if err := <-done; err == nil {
+		t.Error("Expected stream to end with an error once canceled")
+	}
+}
+
+func TestServer_StreamAttesterSlashings_ReplaysFromEpoch(t *testing.T) {
+	db := testDB.SetupSlasherDB(t, false)
+	defer testDB.TeardownSlasherDB(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	old := attesterSlashing(1, 1)
+	recent := attesterSlashing(5, 1)
+	if err := db.SaveAttesterSlashings(ctx, types.Active, []*ethpb.AttesterSlashing{old, recent}); err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{ctx: ctx, slasherDB: db, attesterSlashingsFeed: new(event.Feed)}
+	stream := &attesterSlashingsStream{ctx: ctx, sent: make(chan *ethpb.AttesterSlashing, 2)}
+	go func() {
+		if err := server.StreamAttesterSlashings(&slashpb.StreamSlashingsRequest{Replay: true, ReplayFromEpoch: 3}, stream); err == nil {
+			t.Error("Expected stream to end with an error once canceled")
+		}
+	}()
+
+	select {
+	case slashing := <-stream.sent:
+		if slashing.Attestation_1.Data.Target.Epoch != 5 {
+			t.Errorf("Expected the slashing at epoch 5 to be replayed, received %v", slashing)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive the replayed slashing")
+	}
+	select {
+	case slashing := <-stream.sent:
+		t.Errorf("Expected a single slashing to be replayed, received %v", slashing)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestServer_StreamProposerSlashings_ReplaysAndStreams(t *testing.T) {
+	db := testDB.SetupSlasherDB(t, false)
+	defer testDB.TeardownSlasherDB(t, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replayed := proposerSlashing(2*params.BeaconConfig().SlotsPerEpoch, 7)
+	if err := db.SaveProposerSlashing(ctx, types.Included, replayed); err != nil {
+		t.Fatal(err)
+	}
+	feed := new(event.Feed)
+	server := &Server{ctx: ctx, slasherDB: db, proposerSlashingsFeed: feed}
+	stream := &proposerSlashingsStream{ctx: ctx, sent: make(chan *ethpb.ProposerSlashing, 2)}
+	req := &slashpb.StreamSlashingsRequest{ValidatorIndices: []uint64{7}, Replay: true, ReplayFromEpoch: 2}
+	go func() {
+		if err := server.StreamProposerSlashings(req, stream); err == nil {
+			t.Error("Expected stream to end with an error once canceled")
+		}
+	}()
+
+	select {
+	case slashing := <-stream.sent:
+		if slashing.Header_1.Header.Slot != replayed.Header_1.Header.Slot {
+			t.Errorf("Expected the stored slashing to be replayed, received %v", slashing)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive the replayed slashing")
+	}
+
+	detected := proposerSlashing(3*params.BeaconConfig().SlotsPerEpoch, 7)
+	for feed.Send(detected) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case slashing := <-stream.sent:
+		if slashing != detected {
+			t.Errorf("Expected the detected slashing to be streamed, received %v", slashing)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Did not receive the detected slashing")
+	}
+}
+
+func TestServer_StreamProposerSlashings_SlowClientDoesNotBlockFeed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := new(event.Feed)
+	server := &Server{ctx: ctx, proposerSlashingsFeed: feed}
+	// Nothing reads the slashings sent over the stream, so the first send blocks.
+	stream := &proposerSlashingsStream{ctx: ctx, sent: make(chan *ethpb.ProposerSlashing)}
+	go func() {
+		if err := server.StreamProposerSlashings(&slashpb.StreamSlashingsRequest{}, stream); err == nil {
+			t.Error("Expected stream to end with an error once canceled")
+		}
+	}()
+	for feed.Send(proposerSlashing(0, 1)) == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sent := make(chan struct{})
+	go func() {
+		for i := uint64(1); i <= 2*streamQueueSize; i++ {
+			feed.Send(proposerSlashing(i, 1))
+		}
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Detection feed blocked on a slow stream subscriber")
+	}
+}
@@ -76,6 +76,7 @@ var appHelpFlagGroups = []flagGroup{
 			flags.RPCPort,
 			flags.RebuildSpanMapsFlag,
 			flags.BeaconRPCProviderFlag,
+			flags.AdditionalBeaconRPCProviderFlag,
 		},
 	},
 }
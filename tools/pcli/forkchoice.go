@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	ptypes "github.com/gogo/protobuf/types"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"google.golang.org/grpc"
+	"gopkg.in/urfave/cli.v2"
+)
+
+var (
+	rpcEndpoint   string
+	timestamp     uint64
+	listSnapshots bool
+)
+
+// forkChoiceCommand prints the fork choice store of a running beacon node as JSON, or one of
+// the snapshots the node keeps on disk.
+var forkChoiceCommand = &cli.Command{
+	Name:     "fork-choice",
+	Category: "debug",
+	Usage:    "Subcommand to dump the fork choice store of a beacon node as JSON",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "rpc-endpoint",
+			Usage:       "gRPC endpoint of the beacon node",
+			Value:       "localhost:4000",
+			Destination: &rpcEndpoint,
+		},
+		&cli.Uint64Flag{
+			Name:        "timestamp",
+			Usage:       "Unix time to dump the latest fork choice snapshot taken at or before, instead of the current store",
+			Destination: &timestamp,
+		},
+		&cli.BoolFlag{
+			Name:        "list-snapshots",
+			Usage:       "List the fork choice snapshots kept by the beacon node",
+			Destination: &listSnapshots,
+		},
+	},
+	Action: func(c *cli.Context) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		conn, err := grpc.DialContext(ctx, rpcEndpoint, grpc.WithInsecure(), grpc.WithBlock())
+		if err != nil {
+			return fmt.Errorf("could not connect to beacon node at %s: %v", rpcEndpoint, err)
+		}
+		defer func() {
+			if err := conn.Close(); err != nil {
+				fmt.Printf("Could not close connection: %v\n", err)
+			}
+		}()
+		client := rpcpb.NewDebugClient(conn)
+
+		var res proto.Message
+		switch {
+		case listSnapshots:
+			res, err = client.ListForkChoiceSnapshots(ctx, &ptypes.Empty{})
+		case timestamp != 0:
+			res, err = client.GetForkChoiceSnapshot(ctx, &rpcpb.ForkChoiceSnapshotRequest{Timestamp: timestamp})
+		default:
+			res, err = client.GetForkChoice(ctx, &ptypes.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+		out, err := (&jsonpb.Marshaler{EmitDefaults: true, Indent: "  "}).MarshalToString(res)
+		if err != nil {
+			return err
+		}
+		fmt.Println(out)
+		return nil
+	},
+}
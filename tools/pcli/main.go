@@ -132,6 +132,7 @@ func main() {
 			return nil
 		},
 	},
+		forkChoiceCommand,
 	}
 	if err := app.Run(os.Args); err != nil {
 		log.Error(err.Error())
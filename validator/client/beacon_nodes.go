@@ -0,0 +1,387 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// healthCheckTimeout bounds the requests checking the health of a beacon node.
+const healthCheckTimeout = 5 * time.Second
+
+// broadcastMethods are the RPCs sent through all the healthy beacon nodes when broadcasting
+// is enabled, so a block or an attestation is published even if a node fails to gossip it.
+var broadcastMethods = map[string]bool{
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/ProposeBlock":       true,
+	"/ethereum.eth.v1alpha1.BeaconNodeValidator/ProposeAttestation": true,
+}
+
+var (
+	beaconNodeHealthyGaugeVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "beacon_node_healthy",
+			Help:      "1 if the beacon node is synced and close to the best head slot, 0 otherwise.",
+		},
+		[]string{
+			// beacon node endpoint
+			"endpoint",
+		},
+	)
+	beaconNodeHeadSlotGaugeVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "beacon_node_head_slot",
+			Help:      "The head slot reported by the beacon node at the last health check.",
+		},
+		[]string{
+			// beacon node endpoint
+			"endpoint",
+		},
+	)
+	beaconNodeRequestsVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "beacon_node_requests",
+			Help:      "The number of requests served by each beacon node, by gRPC method.",
+		},
+		[]string{
+			// beacon node endpoint
+			"endpoint",
+			// gRPC method name
+			"method",
+		},
+	)
+)
+
+// beaconNode is a beacon node endpoint along with the health observed at its last check.
+type beaconNode struct {
+	endpoint     string
+	conn         *grpc.ClientConn
+	nodeClient   ethpb.NodeClient
+	beaconClient ethpb.BeaconChainClient
+	healthy      bool
+	headSlot     uint64
+	err          error
+	lastChecked  time.Time
+}
+
+// BeaconNodeStatus describes the health of a beacon node endpoint of the validator client.
+type BeaconNodeStatus struct {
+	Endpoint    string
+	Healthy     bool
+	HeadSlot    uint64
+	Err         error
+	LastChecked time.Time
+}
+
+// beaconNodes routes the requests of the validator client to the first healthy beacon node in
+// the order they are configured, and fails over to the next ones when a request fails.
+type beaconNodes struct {
+	nodes     []*beaconNode
+	lock      sync.RWMutex
+	broadcast bool
+}
+
+// servedByKey is the context key of the endpoints which served a request, for logging.
+type servedByKey struct{}
+
+// withServedBy returns a context recording the beacon nodes which serve the requests made
+// with it, and a function returning them once the requests are done.
+func withServedBy(ctx context.Context) (context.Context, func() string) {
+	endpoints := &[]string{}
+	return context.WithValue(ctx, servedByKey{}, endpoints), func() string {
+		return strings.Join(*endpoints, ",")
+	}
+}
+
+func recordServedBy(ctx context.Context, endpoints []string) {
+	if served, ok := ctx.Value(servedByKey{}).(*[]string); ok {
+		*served = append(*served, endpoints...)
+	}
+}
+
+// candidates returns the beacon nodes to try for a request, the healthy ones first. The
+// unhealthy ones are kept as a last resort as they may have recovered since their last check.
+func (b *beaconNodes) candidates() []*beaconNode {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	nodes := make([]*beaconNode, 0, len(b.nodes))
+	for _, node := range b.nodes {
+		if node.healthy {
+			nodes = append(nodes, node)
+		}
+	}
+	for _, node := range b.nodes {
+		if !node.healthy {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (b *beaconNodes) healthyNodes() []*beaconNode {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	var nodes []*beaconNode
+	for _, node := range b.nodes {
+		if node.healthy {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// markUnreachable flags a beacon node which could not be reached or timed out as unhealthy
+// until its next health check, so the following requests go to the other nodes first.
+func (b *beaconNodes) markUnreachable(node *beaconNode, err error) {
+	if code := status.Code(err); code != codes.Unavailable && code != codes.DeadlineExceeded {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if node.healthy {
+		log.WithError(err).WithField("endpoint", node.endpoint).Warn("Beacon node unreachable, failing over")
+		beaconNodeHealthyGaugeVec.WithLabelValues(node.endpoint).Set(0)
+	}
+	node.healthy = false
+	node.err = err
+}
+
+// unaryInterceptor sends the requests made on the routing connection to the beacon nodes in
+// order of health until one succeeds, or to all the healthy nodes for the broadcast methods.
+func (b *beaconNodes) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	_ *grpc.ClientConn,
+	_ grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if b.broadcast && broadcastMethods[method] {
+		if nodes := b.healthyNodes(); len(nodes) > 1 {
+			return b.broadcastRequest(ctx, nodes, method, req, reply, opts...)
+		}
+	}
+	var err error
+	for _, node := range b.candidates() {
+		err = node.conn.Invoke(ctx, method, req, reply, opts...)
+		if err == nil {
+			beaconNodeRequestsVec.WithLabelValues(node.endpoint, methodName(method)).Inc()
+			recordServedBy(ctx, []string{node.endpoint})
+			return nil
+		}
+		b.markUnreachable(node, err)
+		if ctx.Err() != nil {
+			return err
+		}
+		log.WithError(err).WithFields(logrus.Fields{
+			"endpoint": node.endpoint,
+			"method":   method,
+		}).Debug("Beacon node request failed, trying the next beacon node")
+	}
+	return err
+}
+
+// broadcastRequest sends the request to all the nodes at once, and succeeds if any of them
+// accepts it. The reply is the one of the first node in order to succeed.
+func (b *beaconNodes) broadcastRequest(
+	ctx context.Context,
+	nodes []*beaconNode,
+	method string,
+	req, reply interface{},
+	opts ...grpc.CallOption,
+) error {
+	msg, ok := reply.(proto.Message)
+	if !ok {
+		return errors.New("could not copy the reply of a broadcast request")
+	}
+	replies := make([]proto.Message, len(nodes))
+	for i := range nodes {
+		replies[i] = proto.Clone(msg)
+	}
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node *beaconNode) {
+			defer wg.Done()
+			errs[i] = node.conn.Invoke(ctx, method, req, replies[i], opts...)
+		}(i, node)
+	}
+	wg.Wait()
+
+	var served []string
+	var firstServed int
+	for i, node := range nodes {
+		if errs[i] != nil {
+			b.markUnreachable(node, errs[i])
+			log.WithError(errs[i]).WithFields(logrus.Fields{
+				"endpoint": node.endpoint,
+				"method":   method,
+			}).Debug("Could not broadcast request through beacon node")
+			continue
+		}
+		if len(served) == 0 {
+			firstServed = i
+		}
+		served = append(served, node.endpoint)
+		beaconNodeRequestsVec.WithLabelValues(node.endpoint, methodName(method)).Inc()
+	}
+	if len(served) == 0 {
+		return errs[0]
+	}
+	proto.Merge(msg, replies[firstServed])
+	recordServedBy(ctx, served)
+	return nil
+}
+
+// streamInterceptor opens the streams requested on the routing connection on the first beacon
+// node in order of health to accept them. An open stream is not moved to another node.
+func (b *beaconNodes) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	_ *grpc.ClientConn,
+	method string,
+	_ grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	var err error
+	for _, node := range b.candidates() {
+		var stream grpc.ClientStream
+		stream, err = node.conn.NewStream(ctx, desc, method, opts...)
+		if err == nil {
+			beaconNodeRequestsVec.WithLabelValues(node.endpoint, methodName(method)).Inc()
+			recordServedBy(ctx, []string{node.endpoint})
+			return stream, nil
+		}
+		b.markUnreachable(node, err)
+		if ctx.Err() != nil {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// checkHealth checks the sync status and the head slot of every beacon node. A node is healthy
+// if it is not syncing and its head is at most an epoch behind the best head of the nodes.
+func (b *beaconNodes) checkHealth(ctx context.Context) {
+	b.lock.RLock()
+	nodes := b.nodes
+	b.lock.RUnlock()
+
+	syncing := make([]bool, len(nodes))
+	headSlots := make([]uint64, len(nodes))
+	errs := make([]error, len(nodes))
+	var bestHeadSlot uint64
+	for i, node := range nodes {
+		syncing[i], headSlots[i], errs[i] = checkBeaconNode(ctx, node)
+		if errs[i] == nil && !syncing[i] && headSlots[i] > bestHeadSlot {
+			bestHeadSlot = headSlots[i]
+		}
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for i, node := range nodes {
+		err := errs[i]
+		if err == nil && syncing[i] {
+			err = errors.New("beacon node is syncing")
+		}
+		if err == nil && headSlots[i]+params.BeaconConfig().SlotsPerEpoch < bestHeadSlot {
+			err = errors.Errorf("head slot %d is behind the best head slot %d", headSlots[i], bestHeadSlot)
+		}
+		healthy := err == nil
+		if healthy && !node.healthy {
+			log.WithField("endpoint", node.endpoint).Info("Beacon node is healthy")
+		} else if !healthy && (node.healthy || node.lastChecked.IsZero()) {
+			log.WithError(err).WithField("endpoint", node.endpoint).Warn("Beacon node is unhealthy")
+		}
+		node.healthy = healthy
+		node.headSlot = headSlots[i]
+		node.err = err
+		node.lastChecked = roughtime.Now()
+		beaconNodeHeadSlotGaugeVec.WithLabelValues(node.endpoint).Set(float64(headSlots[i]))
+		if healthy {
+			beaconNodeHealthyGaugeVec.WithLabelValues(node.endpoint).Set(1)
+		} else {
+			beaconNodeHealthyGaugeVec.WithLabelValues(node.endpoint).Set(0)
+		}
+	}
+}
+
+func checkBeaconNode(ctx context.Context, node *beaconNode) (bool, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	syncStatus, err := node.nodeClient.GetSyncStatus(ctx, &ptypes.Empty{})
+	if err != nil {
+		return false, 0, errors.Wrap(err, "could not get sync status")
+	}
+	head, err := node.beaconClient.GetChainHead(ctx, &ptypes.Empty{})
+	if err != nil {
+		return false, 0, errors.Wrap(err, "could not get chain head")
+	}
+	return syncStatus.Syncing, head.HeadSlot, nil
+}
+
+// run checks the health of the beacon nodes periodically until the context is canceled.
+func (b *beaconNodes) run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.checkHealth(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (b *beaconNodes) statuses() []BeaconNodeStatus {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	statuses := make([]BeaconNodeStatus, len(b.nodes))
+	for i, node := range b.nodes {
+		statuses[i] = BeaconNodeStatus{
+			Endpoint:    node.endpoint,
+			Healthy:     node.healthy,
+			HeadSlot:    node.headSlot,
+			Err:         node.err,
+			LastChecked: node.lastChecked,
+		}
+	}
+	return statuses
+}
+
+func (b *beaconNodes) close() error {
+	var errs []string
+	for _, node := range b.nodes {
+		if err := node.conn.Close(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "could not close connection to %s", node.endpoint).Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// methodName returns the name of a gRPC method without its service.
+func methodName(method string) string {
+	return method[strings.LastIndex(method, "/")+1:]
+}
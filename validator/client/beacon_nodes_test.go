@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeBeaconNode serves the genesis and attestation RPCs of a beacon node, the other RPCs panic.
+type fakeBeaconNode struct {
+	ethpb.NodeServer
+	ethpb.BeaconNodeValidatorServer
+	attestations int32
+}
+
+func (f *fakeBeaconNode) GetGenesis(_ context.Context, _ *ptypes.Empty) (*ethpb.Genesis, error) {
+	return &ethpb.Genesis{DepositContractAddress: []byte{1}}, nil
+}
+
+func (f *fakeBeaconNode) ProposeAttestation(_ context.Context, _ *ethpb.Attestation) (*ethpb.AttestResponse, error) {
+	atomic.AddInt32(&f.attestations, 1)
+	return &ethpb.AttestResponse{AttestationDataRoot: []byte{2}}, nil
+}
+
+// startFakeBeaconNode serves a fake beacon node on a local port and returns its endpoint.
+func startFakeBeaconNode(t *testing.T, node *fakeBeaconNode) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	ethpb.RegisterNodeServer(server, node)
+	ethpb.RegisterBeaconNodeValidatorServer(server, node)
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			t.Log(err)
+		}
+	}()
+	return lis.Addr().String(), server.Stop
+}
+
+// dialBeaconNodes dials the endpoints and returns their routing connection.
+func dialBeaconNodes(t *testing.T, b *beaconNodes, endpoints ...string) *grpc.ClientConn {
+	for _, endpoint := range endpoints {
+		conn, err := grpc.Dial(endpoint, grpc.WithInsecure())
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.nodes = append(b.nodes, &beaconNode{endpoint: endpoint, conn: conn, healthy: true})
+	}
+	conn, err := grpc.Dial(endpoints[0],
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(b.unaryInterceptor),
+		grpc.WithStreamInterceptor(b.streamInterceptor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestBeaconNodes_CheckHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	b := &beaconNodes{}
+	for _, tt := range []struct {
+		syncing  bool
+		headSlot uint64
+	}{
+		{syncing: true, headSlot: 100},
+		{headSlot: 100},
+		{headSlot: 10},
+	} {
+		nodeClient := mock.NewMockNodeClient(ctrl)
+		nodeClient.EXPECT().GetSyncStatus(gomock.Any(), gomock.Any()).Return(&ethpb.SyncStatus{Syncing: tt.syncing}, nil)
+		beaconClient := mock.NewMockBeaconChainClient(ctrl)
+		beaconClient.EXPECT().GetChainHead(gomock.Any(), gomock.Any()).Return(&ethpb.ChainHead{HeadSlot: tt.headSlot}, nil)
+		b.nodes = append(b.nodes, &beaconNode{
+			endpoint:     string('a' + rune(len(b.nodes))),
+			nodeClient:   nodeClient,
+			beaconClient: beaconClient,
+		})
+	}
+
+	b.checkHealth(context.Background())
+	statuses := b.statuses()
+	if statuses[0].Healthy || statuses[0].Err == nil {
+		t.Error("Expected the syncing beacon node to be unhealthy")
+	}
+	if !statuses[1].Healthy {
+		t.Errorf("Expected the synced beacon node to be healthy, received error %v", statuses[1].Err)
+	}
+	if statuses[2].Healthy || statuses[2].Err == nil {
+		t.Error("Expected the beacon node behind the best head to be unhealthy")
+	}
+	candidates := b.candidates()
+	if candidates[0].endpoint != "b" || candidates[1].endpoint != "a" || candidates[2].endpoint != "c" {
+		t.Errorf("Wanted the healthy beacon node first then the others in order, received %s, %s, %s",
+			candidates[0].endpoint, candidates[1].endpoint, candidates[2].endpoint)
+	}
+}
+
+func TestBeaconNodes_FailsOver(t *testing.T) {
+	downEndpoint, stop := startFakeBeaconNode(t, &fakeBeaconNode{})
+	stop()
+	upEndpoint, stop := startFakeBeaconNode(t, &fakeBeaconNode{})
+	defer stop()
+	b := &beaconNodes{}
+	conn := dialBeaconNodes(t, b, downEndpoint, upEndpoint)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+		if err := b.close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	ctx, servedBy := withServedBy(context.Background())
+	genesis, err := ethpb.NewNodeClient(conn).GetGenesis(ctx, &ptypes.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(genesis.DepositContractAddress) != 1 {
+		t.Errorf("Unexpected genesis %v", genesis)
+	}
+	if servedBy() != upEndpoint {
+		t.Errorf("Wanted the request served by %s, received %s", upEndpoint, servedBy())
+	}
+	if b.nodes[0].healthy {
+		t.Error("Expected the unreachable beacon node to be marked unhealthy")
+	}
+}
+
+func TestBeaconNodes_Broadcast(t *testing.T) {
+	node1, node2 := &fakeBeaconNode{}, &fakeBeaconNode{}
+	endpoint1, stop1 := startFakeBeaconNode(t, node1)
+	defer stop1()
+	endpoint2, stop2 := startFakeBeaconNode(t, node2)
+	defer stop2()
+	b := &beaconNodes{broadcast: true}
+	conn := dialBeaconNodes(t, b, endpoint1, endpoint2)
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+		if err := b.close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	ctx, servedBy := withServedBy(context.Background())
+	res, err := ethpb.NewBeaconNodeValidatorClient(conn).ProposeAttestation(ctx, &ethpb.Attestation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.AttestationDataRoot) != 1 {
+		t.Errorf("Unexpected attestation response %v", res)
+	}
+	attestations1, attestations2 := atomic.LoadInt32(&node1.attestations), atomic.LoadInt32(&node2.attestations)
+	if attestations1 != 1 || attestations2 != 1 {
+		t.Errorf("Wanted the attestation sent through both beacon nodes, received %d and %d", attestations1, attestations2)
+	}
+	if want := endpoint1 + "," + endpoint2; servedBy() != want {
+		t.Errorf("Wanted the request served by %s, received %s", want, servedBy())
+	}
+}
+
+func TestBeaconNodes_MarkUnreachable(t *testing.T) {
+	for _, tt := range []struct {
+		code    codes.Code
+		healthy bool
+	}{
+		{code: codes.Unavailable},
+		{code: codes.DeadlineExceeded},
+		{code: codes.NotFound, healthy: true},
+		{code: codes.Canceled, healthy: true},
+	} {
+		node := &beaconNode{endpoint: "localhost:4000", healthy: true}
+		b := &beaconNodes{nodes: []*beaconNode{node}}
+		b.markUnreachable(node, status.Error(tt.code, "request failed"))
+		if node.healthy != tt.healthy {
+			t.Errorf("Wanted healthy %v after a %v error, received %v", tt.healthy, tt.code, node.healthy)
+		}
+	}
+}
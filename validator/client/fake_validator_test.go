@@ -10,6 +10,7 @@ var _ = Validator(&fakeValidator{})
 type fakeValidator struct {
 	DoneCalled                       bool
 	WaitForActivationCalled          bool
+	DetectDoppelgangersCalled        bool
 	WaitForChainStartCalled          bool
 	WaitForSyncCalled                bool
 	WaitForSyncedCalled              bool
@@ -28,6 +29,7 @@ type fakeValidator struct {
 	NextSlotRet                      <-chan uint64
 	PublicKey                        string
 	UpdateDutiesRet                  error
+	DetectDoppelgangersRet           error
 	RolesAtRet                       []validatorRole
 }
 
@@ -45,6 +47,11 @@ func (fv *fakeValidator) WaitForActivation(_ context.Context) error {
 	return nil
 }
 
+func (fv *fakeValidator) DetectDoppelgangers(_ context.Context) error {
+	fv.DetectDoppelgangersCalled = true
+	return fv.DetectDoppelgangersRet
+}
+
 func (fv *fakeValidator) WaitForSync(_ context.Context) error {
 	fv.WaitForSyncCalled = true
 	return nil
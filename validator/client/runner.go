@@ -22,6 +22,7 @@ type Validator interface {
 	WaitForSync(ctx context.Context) error
 	WaitForSynced(ctx context.Context) error
 	WaitForActivation(ctx context.Context) error
+	DetectDoppelgangers(ctx context.Context) error
 	CanonicalHeadSlot(ctx context.Context) (uint64, error)
 	NextSlot() <-chan uint64
 	SlotDeadline(slot uint64) time.Time
@@ -36,16 +37,18 @@ type Validator interface {
 }
 
 // Run the main validator routine. This routine exits if the context is
-// canceled.
+// canceled, or returns a DoppelgangerError if all the keys of the validator
+// are found active elsewhere, or any of them when the detection fails closed.
 //
 // Order of operations:
 // 1 - Initialize validator data
 // 2 - Wait for validator activation
-// 3 - Wait for the next slot start
-// 4 - Update assignments
-// 5 - Determine role at current slot
-// 6 - Perform assigned role, if any
-func run(ctx context.Context, v Validator) {
+// 3 - Detect doppelgangers, if enabled
+// 4 - Wait for the next slot start
+// 5 - Update assignments
+// 6 - Determine role at current slot
+// 7 - Perform assigned role, if any
+func run(ctx context.Context, v Validator) error {
 	defer v.Done()
 	if featureconfig.Get().WaitForSynced {
 		if err := v.WaitForSynced(ctx); err != nil {
@@ -62,11 +65,20 @@ func run(ctx context.Context, v Validator) {
 	if err := v.WaitForActivation(ctx); err != nil {
 		log.Fatalf("Could not wait for validator activation: %v", err)
 	}
+	if err := v.DetectDoppelgangers(ctx); err != nil {
+		if _, ok := err.(*DoppelgangerError); ok {
+			return err
+		}
+		log.Fatalf("Could not detect doppelgangers: %v", err)
+	}
 	headSlot, err := v.CanonicalHeadSlot(ctx)
 	if err != nil {
 		log.Fatalf("Could not get current canonical head slot: %v", err)
 	}
 	if err := v.UpdateDuties(ctx, headSlot); err != nil {
+		if _, ok := err.(*DoppelgangerError); ok {
+			return err
+		}
 		handleAssignmentError(err, headSlot)
 	}
 	for {
@@ -75,7 +87,7 @@ func run(ctx context.Context, v Validator) {
 		select {
 		case <-ctx.Done():
 			log.Info("Context canceled, stopping validator")
-			return // Exit if context is canceled.
+			return nil // Exit if context is canceled.
 		case slot := <-v.NextSlot():
 			span.AddAttributes(trace.Int64Attribute("slot", int64(slot)))
 			deadline := v.SlotDeadline(slot)
@@ -90,6 +102,11 @@ func run(ctx context.Context, v Validator) {
 			// Keep trying to update assignments if they are nil or if we are past an
 			// epoch transition in the beacon node's state.
 			if err := v.UpdateDuties(ctx, slot); err != nil {
+				if _, ok := err.(*DoppelgangerError); ok {
+					cancel()
+					span.End()
+					return err
+				}
 				handleAssignmentError(err, slot)
 				cancel()
 				span.End()
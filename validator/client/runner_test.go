@@ -54,6 +54,39 @@ func TestCancelledContext_WaitsForActivation(t *testing.T) {
 	}
 }
 
+func TestCancelledContext_DetectsDoppelgangers(t *testing.T) {
+	v := &fakeValidator{}
+	run(cancelledContext(), v)
+	if !v.DetectDoppelgangersCalled {
+		t.Error("Expected DetectDoppelgangers() to be called")
+	}
+}
+
+func TestRun_ReturnsDoppelgangerError(t *testing.T) {
+	v := &fakeValidator{DetectDoppelgangersRet: &DoppelgangerError{PublicKeys: [][48]byte{{1}}}}
+	err := run(context.Background(), v)
+	if _, ok := err.(*DoppelgangerError); !ok {
+		t.Errorf("Wanted a doppelganger error, received %v", err)
+	}
+	if !v.DoneCalled {
+		t.Error("Expected Done() to be called")
+	}
+	if v.CanonicalHeadSlotCalled {
+		t.Error("Expected the validator not to start signing")
+	}
+}
+
+func TestRun_ReturnsDoppelgangerErrorFromDuties(t *testing.T) {
+	v := &fakeValidator{UpdateDutiesRet: &DoppelgangerError{PublicKeys: [][48]byte{{1}}}}
+	err := run(context.Background(), v)
+	if _, ok := err.(*DoppelgangerError); !ok {
+		t.Errorf("Wanted a doppelganger error, received %v", err)
+	}
+	if !v.DoneCalled {
+		t.Error("Expected Done() to be called")
+	}
+}
+
 func TestUpdateDuties_NextSlot(t *testing.T) {
 	v := &fakeValidator{}
 	ctx, cancel := context.WithCancel(context.Background())
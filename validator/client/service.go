@@ -2,7 +2,9 @@ package client
 
 import (
 	"context"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/dgraph-io/ristretto"
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -14,6 +16,7 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -31,25 +34,35 @@ var log = logrus.WithField("prefix", "validator")
 // ValidatorService represents a service to manage the validator client
 // routine.
 type ValidatorService struct {
-	ctx                  context.Context
-	cancel               context.CancelFunc
-	validator            Validator
-	graffiti             []byte
-	conn                 *grpc.ClientConn
-	endpoint             string
-	withCert             string
-	dataDir              string
-	keyManager           keymanager.KeyManager
-	logValidatorBalances bool
-	emitAccountMetrics   bool
-	maxCallRecvMsgSize   int
-	grpcRetries          uint
-	grpcHeaders          []string
+	ctx                    context.Context
+	cancel                 context.CancelFunc
+	validator              Validator
+	graffiti               []byte
+	conn                   *grpc.ClientConn
+	endpoint               string
+	fallbackEndpoints      []string
+	broadcast              bool
+	beaconNodes            *beaconNodes
+	withCert               string
+	dataDir                string
+	keyManager             keymanager.KeyManager
+	logValidatorBalances   bool
+	emitAccountMetrics     bool
+	maxCallRecvMsgSize     int
+	grpcRetries            uint
+	grpcHeaders            []string
+	watchKeys              bool
+	reloadKeys             chan struct{}
+	doppelgangerEpochs     uint64
+	doppelgangerFailClosed bool
+	runErr                 chan error
 }
 
 // Config for the validator service.
 type Config struct {
 	Endpoint                   string
+	FallbackEndpoints          []string
+	Broadcast                  bool
 	DataDir                    string
 	CertFlag                   string
 	GraffitiFlag               string
@@ -59,6 +72,9 @@ type Config struct {
 	GrpcMaxCallRecvMsgSizeFlag int
 	GrpcRetriesFlag            uint
 	GrpcHeadersFlag            string
+	WatchKeys                  bool
+	DoppelgangerEpochs         uint64
+	DoppelgangerFailClosed     bool
 }
 
 // NewValidatorService creates a new validator service for the service
@@ -66,18 +82,25 @@ type Config struct {
 func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	return &ValidatorService{
-		ctx:                  ctx,
-		cancel:               cancel,
-		endpoint:             cfg.Endpoint,
-		withCert:             cfg.CertFlag,
-		dataDir:              cfg.DataDir,
-		graffiti:             []byte(cfg.GraffitiFlag),
-		keyManager:           cfg.KeyManager,
-		logValidatorBalances: cfg.LogValidatorBalances,
-		emitAccountMetrics:   cfg.EmitAccountMetrics,
-		maxCallRecvMsgSize:   cfg.GrpcMaxCallRecvMsgSizeFlag,
-		grpcRetries:          cfg.GrpcRetriesFlag,
-		grpcHeaders:          strings.Split(cfg.GrpcHeadersFlag, ","),
+		ctx:                    ctx,
+		cancel:                 cancel,
+		endpoint:               cfg.Endpoint,
+		fallbackEndpoints:      cfg.FallbackEndpoints,
+		broadcast:              cfg.Broadcast,
+		withCert:               cfg.CertFlag,
+		dataDir:                cfg.DataDir,
+		graffiti:               []byte(cfg.GraffitiFlag),
+		keyManager:             cfg.KeyManager,
+		logValidatorBalances:   cfg.LogValidatorBalances,
+		emitAccountMetrics:     cfg.EmitAccountMetrics,
+		maxCallRecvMsgSize:     cfg.GrpcMaxCallRecvMsgSizeFlag,
+		grpcRetries:            cfg.GrpcRetriesFlag,
+		grpcHeaders:            strings.Split(cfg.GrpcHeadersFlag, ","),
+		watchKeys:              cfg.WatchKeys,
+		reloadKeys:             make(chan struct{}, 1),
+		doppelgangerEpochs:     cfg.DoppelgangerEpochs,
+		doppelgangerFailClosed: cfg.DoppelgangerFailClosed,
+		runErr:                 make(chan error, 1),
 	}, nil
 }
 
@@ -117,11 +140,18 @@ func (v *ValidatorService) Start() {
 		}
 	}
 
+	// With several beacon nodes, a failed request fails over to the next node instead of
+	// being retried on the same one.
+	endpoints := append([]string{v.endpoint}, v.fallbackEndpoints...)
+	grpcRetries := v.grpcRetries
+	if len(endpoints) > 1 {
+		grpcRetries = 0
+	}
 	opts := []grpc.DialOption{
 		dialOpt,
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
-			grpc_retry.WithMax(v.grpcRetries),
+			grpc_retry.WithMax(grpcRetries),
 			grpc.Header(&md),
 		),
 		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
@@ -137,12 +167,37 @@ func (v *ValidatorService) Start() {
 			logDebugRequestInfoUnaryInterceptor,
 		)),
 	}
-	conn, err := grpc.DialContext(v.ctx, v.endpoint, opts...)
+	// Each beacon node has its own connection. The clients of the validator use a routing
+	// connection which sends every request to the beacon node connections by health.
+	v.beaconNodes = &beaconNodes{broadcast: v.broadcast}
+	for _, endpoint := range endpoints {
+		nodeConn, err := grpc.DialContext(v.ctx, endpoint, opts...)
+		if err != nil {
+			log.Errorf("Could not dial endpoint: %s, %v", endpoint, err)
+			if err := v.beaconNodes.close(); err != nil {
+				log.WithError(err).Error("Could not close beacon node connections")
+			}
+			return
+		}
+		v.beaconNodes.nodes = append(v.beaconNodes.nodes, &beaconNode{
+			endpoint:     endpoint,
+			conn:         nodeConn,
+			nodeClient:   ethpb.NewNodeClient(nodeConn),
+			beaconClient: ethpb.NewBeaconChainClient(nodeConn),
+		})
+	}
+	conn, err := grpc.DialContext(v.ctx, v.endpoint,
+		dialOpt,
+		grpc.WithUnaryInterceptor(v.beaconNodes.unaryInterceptor),
+		grpc.WithStreamInterceptor(v.beaconNodes.streamInterceptor),
+	)
 	if err != nil {
 		log.Errorf("Could not dial endpoint: %s, %v", v.endpoint, err)
 		return
 	}
 	log.Debug("Successfully started gRPC connection")
+	v.beaconNodes.checkHealth(v.ctx)
+	go v.beaconNodes.run(v.ctx, time.Duration(params.BeaconConfig().SecondsPerSlot)*time.Second)
 
 	pubkeys, err := v.keyManager.FetchValidatingKeys()
 	if err != nil {
@@ -175,6 +230,7 @@ func (v *ValidatorService) Start() {
 		db:                             valDB,
 		validatorClient:                ethpb.NewBeaconNodeValidatorClient(v.conn),
 		beaconClient:                   ethpb.NewBeaconChainClient(v.conn),
+		forkClient:                     rpcpb.NewForkInfoClient(v.conn),
 		node:                           ethpb.NewNodeClient(v.conn),
 		keyManager:                     v.keyManager,
 		graffiti:                       v.graffiti,
@@ -184,16 +240,53 @@ func (v *ValidatorService) Start() {
 		attLogs:                        make(map[[32]byte]*attSubmitted),
 		domainDataCache:                cache,
 		aggregatedSlotCommitteeIDCache: aggregatedSlotCommitteeIDCache,
+		reloadKeys:                     v.reloadKeys,
+		doppelgangerEpochs:             v.doppelgangerEpochs,
+		doppelgangerFailClosed:         v.doppelgangerFailClosed,
+	}
+	if v.watchKeys {
+		if watchable, ok := v.keyManager.(keymanager.WatchableKeyManager); ok {
+			interval := time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+			go watchKeysPath(v.ctx, watchable.KeysPath(), interval, v.ReloadKeys)
+		} else {
+			log.Warn("Keymanager does not store its keys in a directory, not watching for key changes")
+		}
 	}
-	go run(v.ctx, v.validator)
+	go func() {
+		if err := run(v.ctx, v.validator); err != nil {
+			v.runErr <- err
+		}
+	}()
+}
+
+// Err returns a channel receiving the error stopping the validator routine, such as a
+// DoppelgangerError. The validator client is expected to shut down when it receives one.
+func (v *ValidatorService) Err() <-chan error {
+	return v.runErr
 }
 
 // Stop the validator service.
 func (v *ValidatorService) Stop() error {
 	v.cancel()
 	log.Info("Stopping service")
+	if closer, ok := v.keyManager.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.WithError(err).Error("Could not close keymanager")
+		}
+	}
+	var errs []string
+	if v.beaconNodes != nil {
+		if err := v.beaconNodes.close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
 	if v.conn != nil {
-		return v.conn.Close()
+		if err := v.conn.Close(); err != nil {
+			errs = append(errs, errors.Wrap(err, "could not close routing connection").Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
 	}
 	return nil
 }
@@ -205,9 +298,21 @@ func (v *ValidatorService) Status() error {
 	if v.conn == nil {
 		return errors.New("no connection to beacon RPC")
 	}
+	if len(v.beaconNodes.healthyNodes()) == 0 {
+		return errors.New("no healthy beacon node")
+	}
 	return nil
 }
 
+// BeaconNodeStatuses returns the health of the beacon nodes of the validator client, in the
+// order they are used.
+func (v *ValidatorService) BeaconNodeStatuses() []BeaconNodeStatus {
+	if v.beaconNodes == nil {
+		return nil
+	}
+	return v.beaconNodes.statuses()
+}
+
 // signObject signs a generic object, with protection if available.
 func (v *validator) signObject(pubKey [48]byte, object interface{}, domain []byte) (*bls.Signature, error) {
 	if protectingKeymanager, supported := v.keyManager.(keymanager.ProtectingKeyManager); supported {
@@ -21,6 +21,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	rpcpb "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/hashutil"
@@ -46,8 +47,11 @@ type validator struct {
 	ticker                             *slotutil.SlotTicker
 	db                                 *db.Store
 	duties                             *ethpb.DutiesResponse
+	dutiesLock                         sync.RWMutex
 	validatorClient                    ethpb.BeaconNodeValidatorClient
 	beaconClient                       ethpb.BeaconChainClient
+	forkClient                         rpcpb.ForkInfoClient
+	genesisValidatorsRoot              []byte
 	graffiti                           []byte
 	node                               ethpb.NodeClient
 	keyManager                         keymanager.KeyManager
@@ -60,6 +64,15 @@ type validator struct {
 	domainDataCache                    *ristretto.Cache
 	aggregatedSlotCommitteeIDCache     *lru.Cache
 	aggregatedSlotCommitteeIDCacheLock sync.Mutex
+	validatingKeys                     map[[48]byte]bool
+	pendingKeys                        map[[48]byte]uint64
+	disabledKeys                       map[[48]byte]bool
+	keysLock                           sync.RWMutex
+	reloadKeys                         chan struct{}
+	doppelgangerEpochs                 uint64
+	doppelgangerFailClosed             bool
+	checkingPendingKeys                bool
+	doppelgangerErr                    *DoppelgangerError
 }
 
 var validatorStatusesGaugeVec = promauto.NewGaugeVec(
@@ -108,6 +121,9 @@ func (v *validator) WaitForChainStart(ctx context.Context) error {
 		v.genesisTime = chainStartRes.GenesisTime
 		break
 	}
+	if err := v.updateForkInfo(ctx); err != nil {
+		return err
+	}
 	// Once the ChainStart log is received, we update the genesis time of the validator client
 	// and begin a slot ticker used to track the current slot the beacon node is in.
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
@@ -173,6 +189,9 @@ func (v *validator) WaitForSynced(ctx context.Context) error {
 		v.genesisTime = syncedRes.GenesisTime
 		break
 	}
+	if err := v.updateForkInfo(ctx); err != nil {
+		return err
+	}
 	// Once the Synced log is received, we update the genesis time of the validator client
 	// and begin a slot ticker used to track the current slot the beacon node is in.
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
@@ -180,6 +199,28 @@ func (v *validator) WaitForSynced(ctx context.Context) error {
 	return nil
 }
 
+// updateForkInfo passes the fork of the chain head and the genesis validators root to a keymanager
+// which signs with them. It is called at every epoch start, so the keymanager follows forks.
+func (v *validator) updateForkInfo(ctx context.Context) error {
+	forkAwareKeymanager, supported := v.keyManager.(keymanager.ForkAwareKeyManager)
+	if !supported {
+		return nil
+	}
+	if v.genesisValidatorsRoot == nil {
+		genesis, err := v.node.GetGenesis(ctx, &ptypes.Empty{})
+		if err != nil {
+			return errors.Wrap(err, "could not get genesis info")
+		}
+		v.genesisValidatorsRoot = genesis.GenesisValidatorsRoot
+	}
+	fork, err := v.forkClient.GetHeadFork(ctx, &ptypes.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "could not get head fork")
+	}
+	forkAwareKeymanager.SetForkInfo(fork, v.genesisValidatorsRoot)
+	return nil
+}
+
 // WaitForActivation checks whether the validator pubkey is in the active
 // validator set. If not, this operation will block until an activation message is
 // received.
@@ -311,10 +352,13 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 	ctx, span := trace.StartSpan(ctx, "validator.UpdateAssignments")
 	defer span.End()
 
-	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	validatingKeys, err := v.updateValidatingKeys(ctx)
 	if err != nil {
 		return err
 	}
+	if err := v.updateForkInfo(ctx); err != nil {
+		log.WithError(err).Warn("Could not update fork info, signing with the previous fork")
+	}
 	req := &ethpb.DutiesRequest{
 		Epoch:      slot / params.BeaconConfig().SlotsPerEpoch,
 		PublicKeys: bytesutil.FromBytes48Array(validatingKeys),
@@ -323,12 +367,16 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 	// If duties is nil it means we have had no prior duties and just started up.
 	resp, err := v.validatorClient.GetDuties(ctx, req)
 	if err != nil {
+		v.dutiesLock.Lock()
 		v.duties = nil // Clear assignments so we know to retry the request.
+		v.dutiesLock.Unlock()
 		log.Error(err)
 		return err
 	}
 
+	v.dutiesLock.Lock()
 	v.duties = resp
+	v.dutiesLock.Unlock()
 	v.logDuties(slot, v.duties.Duties)
 	subscribeSlots := make([]uint64, 0, len(validatingKeys))
 	subscribeCommitteeIDs := make([]uint64, 0, len(validatingKeys))
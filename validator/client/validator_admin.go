@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+// ErrNoDuties is returned by the admin accessors of the validator service before the
+// validator has fetched its first duties from the beacon node.
+var ErrNoDuties = errors.New("validator has not fetched its duties yet")
+
+// ValidatingKeys returns the public keys the validator client currently validates with. Before
+// the validator loads its keys, these are the keys of the keymanager.
+func (v *ValidatorService) ValidatingKeys() ([][48]byte, error) {
+	if val, ok := v.validator.(*validator); ok {
+		if keys, loaded := val.signingKeysSnapshot(); loaded {
+			return keys, nil
+		}
+	}
+	return v.keyManager.FetchValidatingKeys()
+}
+
+// Duties returns a copy of the duties of the current epoch along with the current slot.
+func (v *ValidatorService) Duties() (*ethpb.DutiesResponse, uint64, error) {
+	val, ok := v.validator.(*validator)
+	if !ok {
+		return nil, 0, ErrNoDuties
+	}
+	val.dutiesLock.RLock()
+	defer val.dutiesLock.RUnlock()
+	// The genesis time is always known once duties have been fetched.
+	if val.duties == nil {
+		return nil, 0, ErrNoDuties
+	}
+	slot := slotutil.SlotsSinceGenesis(time.Unix(int64(val.genesisTime), 0))
+	return proto.Clone(val.duties).(*ethpb.DutiesResponse), slot, nil
+}
+
+// ProtectionHistory returns the slashing protection history recorded for a validating key.
+func (v *ValidatorService) ProtectionHistory(ctx context.Context, pubKey [48]byte) (*db.InterchangeHistory, error) {
+	val, ok := v.validator.(*validator)
+	if !ok || val.db == nil {
+		return nil, errors.New("validator database is not opened yet")
+	}
+	return val.db.ProtectionHistory(ctx, pubKey[:])
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+func TestValidatorService_Duties(t *testing.T) {
+	val := &validator{}
+	v := &ValidatorService{validator: val}
+	if _, _, err := v.Duties(); err != ErrNoDuties {
+		t.Errorf("Wanted %v, received %v", ErrNoDuties, err)
+	}
+
+	val.duties = &ethpb.DutiesResponse{
+		Duties: []*ethpb.DutiesResponse_Duty{{PublicKey: []byte{1}, ValidatorIndex: 5}},
+	}
+	duties, _, err := v.Duties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	duties.Duties[0].ValidatorIndex = 6
+	if val.duties.Duties[0].ValidatorIndex != 5 {
+		t.Error("Wanted the duties of the validator to be copied")
+	}
+}
+
+func TestValidatorService_ValidatingKeys(t *testing.T) {
+	keys, err := testKeyManager.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	val := &validator{}
+	v := &ValidatorService{validator: val, keyManager: testKeyManager}
+
+	// The keys of the keymanager are returned until the validator loads them.
+	loaded, err := v.ValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(keys) {
+		t.Errorf("Wanted %d keys, received %d", len(keys), len(loaded))
+	}
+
+	pending := [48]byte{1}
+	disabled := [48]byte{2}
+	val.validatingKeys = map[[48]byte]bool{keys[0]: true, pending: true, disabled: true}
+	val.pendingKeys = map[[48]byte]uint64{pending: 0}
+	val.disabledKeys = map[[48]byte]bool{disabled: true}
+	signing, err := v.ValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signing) != 1 || signing[0] != keys[0] {
+		t.Errorf("Wanted the signing key only, received %v", signing)
+	}
+}
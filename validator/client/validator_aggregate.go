@@ -10,9 +10,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"go.opencensus.io/trace"
 )
 
@@ -165,6 +167,13 @@ func (v *validator) aggregateAndProofSig(ctx context.Context, pubKey [48]byte, a
 	if err != nil {
 		return nil, err
 	}
+	if dutySigningKeymanager, supported := v.keyManager.(keymanager.DutySigningKeyManager); supported {
+		sig, err := dutySigningKeymanager.SignAggregateAndProof(pubKey, bytesutil.ToBytes32(d.SignatureDomain), agg)
+		if err != nil {
+			return nil, err
+		}
+		return sig.Marshal(), nil
+	}
 	signedRoot, err := helpers.ComputeSigningRoot(agg, d.SignatureDomain)
 	if err != nil {
 		return nil, err
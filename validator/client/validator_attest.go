@@ -139,7 +139,8 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 		Signature:       sig,
 	}
 
-	attResp, err := v.validatorClient.ProposeAttestation(ctx, attestation)
+	submitCtx, servedBy := withServedBy(ctx)
+	attResp, err := v.validatorClient.ProposeAttestation(submitCtx, attestation)
 	if err != nil {
 		log.WithError(err).Error("Could not submit attestation to beacon node")
 		if v.emitAccountMetrics {
@@ -159,7 +160,7 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 		}
 	}
 
-	if err := v.saveAttesterIndexToData(data, duty.ValidatorIndex); err != nil {
+	if err := v.saveAttesterIndexToData(data, duty.ValidatorIndex, servedBy()); err != nil {
 		log.WithError(err).Error("Could not save validator index for logging")
 		if v.emitAccountMetrics {
 			validatorAttestFailVec.WithLabelValues(fmtKey).Inc()
@@ -224,7 +225,7 @@ func (v *validator) signAtt(ctx context.Context, pubKey [48]byte, data *ethpb.At
 
 // For logging, this saves the last submitted attester index to its attestation data. The purpose of this
 // is to enhance attesting logs to be readable when multiple validator keys ran in a single client.
-func (v *validator) saveAttesterIndexToData(data *ethpb.AttestationData, index uint64) error {
+func (v *validator) saveAttesterIndexToData(data *ethpb.AttestationData, index uint64, beaconNode string) error {
 	v.attLogsLock.Lock()
 	defer v.attLogsLock.Unlock()
 
@@ -234,9 +235,17 @@ func (v *validator) saveAttesterIndexToData(data *ethpb.AttestationData, index u
 	}
 
 	if v.attLogs[h] == nil {
-		v.attLogs[h] = &attSubmitted{data, []uint64{}, []uint64{}}
+		v.attLogs[h] = &attSubmitted{data, []uint64{}, []uint64{}, []string{}}
 	}
-	v.attLogs[h] = &attSubmitted{data, append(v.attLogs[h].attesterIndices, index), []uint64{}}
+	beaconNodes := v.attLogs[h].beaconNodes
+	seen := beaconNode == ""
+	for _, node := range beaconNodes {
+		seen = seen || node == beaconNode
+	}
+	if !seen {
+		beaconNodes = append(beaconNodes, beaconNode)
+	}
+	v.attLogs[h] = &attSubmitted{data, append(v.attLogs[h].attesterIndices, index), []uint64{}, beaconNodes}
 
 	return nil
 }
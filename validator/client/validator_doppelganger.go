@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DoppelgangerExitCode is the exit code of the validator client when it finds its keys active
+// elsewhere during doppelganger detection.
+const DoppelgangerExitCode = 3
+
+var doppelgangerKeysGauge = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: "validator",
+	Name:      "doppelganger_keys",
+	Help:      "Number of validator keys found active elsewhere, which the validator client does not sign with.",
+})
+
+// DoppelgangerError is returned when validator keys are found attesting or proposing elsewhere.
+type DoppelgangerError struct {
+	PublicKeys [][48]byte
+}
+
+func (e *DoppelgangerError) Error() string {
+	keys := make([]string, len(e.PublicKeys))
+	for i, key := range e.PublicKeys {
+		keys[i] = fmt.Sprintf("%#x", bytesutil.Trunc(key[:]))
+	}
+	return fmt.Sprintf("validator keys already active elsewhere: %s", strings.Join(keys, ", "))
+}
+
+// DetectDoppelgangers watches the chain for the configured number of epochs before the validator
+// starts signing, and stops the validator from signing with any of its keys which attested or
+// proposed in that time. It returns a DoppelgangerError if all of its keys did, or if any did when
+// the detection fails closed. Attestations are
+// seen once they are included in a block, and only those targeting an epoch after the current one
+// are counted, so duties signed by a previous run of this client do not trigger the detection.
+func (v *validator) DetectDoppelgangers(ctx context.Context) error {
+	if v.doppelgangerEpochs == 0 {
+		return nil
+	}
+	ctx, span := trace.StartSpan(ctx, "validator.DetectDoppelgangers")
+	defer span.End()
+
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return errors.Wrap(err, "could not fetch validating keys")
+	}
+	keysByIndex, err := v.keysByIndex(ctx, validatingKeys)
+	if err != nil {
+		return err
+	}
+	if len(keysByIndex) == 0 {
+		return nil
+	}
+
+	startEpoch := slotutil.EpochsSinceGenesis(time.Unix(int64(v.genesisTime), 0))
+	log.WithFields(logrus.Fields{
+		"epochs":     v.doppelgangerEpochs,
+		"validators": len(keysByIndex),
+	}).Info("Waiting for doppelganger detection before signing")
+
+	found := make(map[uint64]bool)
+	for epoch := startEpoch + 1; epoch <= startEpoch+v.doppelgangerEpochs; epoch++ {
+		// Wait for the end of the epoch so its blocks are available from the beacon node.
+		epochEnd := slotutil.SlotStartTime(v.genesisTime, (epoch+1)*params.BeaconConfig().SlotsPerEpoch)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(roughtime.Until(epochEnd)):
+		}
+
+		if err := v.checkEpochForDoppelgangers(ctx, epoch, startEpoch, keysByIndex, found); err != nil {
+			return err
+		}
+		log.WithFields(logrus.Fields{
+			"epoch":         epoch,
+			"doppelgangers": len(found),
+		}).Debug("Checked epoch for doppelgangers")
+	}
+	if len(found) == 0 {
+		log.Info("No doppelgangers detected, starting to sign")
+		return nil
+	}
+
+	doppelgangers := &DoppelgangerError{}
+	for index := range found {
+		doppelgangers.PublicKeys = append(doppelgangers.PublicKeys, keysByIndex[index])
+	}
+	if v.doppelgangerFailClosed || len(doppelgangers.PublicKeys) == len(validatingKeys) {
+		return doppelgangers
+	}
+	v.disableKeys(doppelgangers.PublicKeys)
+	return nil
+}
+
+// checkPendingKeysInBackground runs the doppelganger detection of the pending keys in its own
+// routine, so the requests it makes to the beacon node do not delay the duties of the validating
+// keys. Only one detection runs at a time.
+func (v *validator) checkPendingKeysInBackground(ctx context.Context, currentEpoch uint64) {
+	v.keysLock.Lock()
+	if v.checkingPendingKeys {
+		v.keysLock.Unlock()
+		return
+	}
+	v.checkingPendingKeys = true
+	v.keysLock.Unlock()
+
+	go func() {
+		defer func() {
+			v.keysLock.Lock()
+			v.checkingPendingKeys = false
+			v.keysLock.Unlock()
+		}()
+		if err := v.checkPendingKeys(ctx, currentEpoch); err != nil {
+			log.WithError(err).Error("Could not run doppelganger detection for new keys, retrying next epoch")
+		}
+	}()
+}
+
+// checkPendingKeys runs the doppelganger detection of the keys loaded after the validator started,
+// once they have been watched for the configured number of epochs. The keys which were not seen
+// active elsewhere start validating, and the others are disabled. When the detection fails closed,
+// the keys found active elsewhere are also recorded as a DoppelgangerError stopping the validator.
+func (v *validator) checkPendingKeys(ctx context.Context, currentEpoch uint64) error {
+	keysByStartEpoch := make(map[uint64][][48]byte)
+	v.keysLock.RLock()
+	for key, startEpoch := range v.pendingKeys {
+		if currentEpoch > startEpoch+v.doppelgangerEpochs {
+			keysByStartEpoch[startEpoch] = append(keysByStartEpoch[startEpoch], key)
+		}
+	}
+	v.keysLock.RUnlock()
+	for startEpoch, keys := range keysByStartEpoch {
+		keysByIndex, err := v.keysByIndex(ctx, keys)
+		if err != nil {
+			return err
+		}
+		found := make(map[uint64]bool)
+		if len(keysByIndex) > 0 {
+			for epoch := startEpoch + 1; epoch <= startEpoch+v.doppelgangerEpochs; epoch++ {
+				if err := v.checkEpochForDoppelgangers(ctx, epoch, startEpoch, keysByIndex, found); err != nil {
+					return err
+				}
+			}
+		}
+		var doppelgangers [][48]byte
+		disabled := make(map[[48]byte]bool, len(found))
+		for index := range found {
+			doppelgangers = append(doppelgangers, keysByIndex[index])
+			disabled[keysByIndex[index]] = true
+		}
+		v.disableKeys(doppelgangers)
+		v.keysLock.Lock()
+		for _, key := range keys {
+			delete(v.pendingKeys, key)
+		}
+		if v.doppelgangerFailClosed && len(doppelgangers) > 0 {
+			if v.doppelgangerErr == nil {
+				v.doppelgangerErr = &DoppelgangerError{}
+			}
+			v.doppelgangerErr.PublicKeys = append(v.doppelgangerErr.PublicKeys, doppelgangers...)
+		}
+		v.keysLock.Unlock()
+		for _, key := range keys {
+			if !disabled[key] {
+				log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(key[:]))).Info("Started validating for public key")
+			}
+		}
+	}
+	return nil
+}
+
+// disableKeys stops the validator from signing with keys found active elsewhere, until it is restarted.
+func (v *validator) disableKeys(keys [][48]byte) {
+	if len(keys) == 0 {
+		return
+	}
+	v.keysLock.Lock()
+	defer v.keysLock.Unlock()
+	if v.disabledKeys == nil {
+		v.disabledKeys = make(map[[48]byte]bool)
+	}
+	for _, key := range keys {
+		v.disabledKeys[key] = true
+		doppelgangerKeysGauge.Set(float64(len(v.disabledKeys)))
+		log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(key[:]))).Error(
+			"Validator key active elsewhere, not signing with it until the other validator client is stopped and this one restarted",
+		)
+	}
+}
+
+// keysByIndex returns the given keys by validator index, leaving out the keys without one.
+func (v *validator) keysByIndex(ctx context.Context, keys [][48]byte) (map[uint64][48]byte, error) {
+	keysByIndex := make(map[uint64][48]byte, len(keys))
+	for _, key := range keys {
+		res, err := v.validatorClient.ValidatorIndex(ctx, &ethpb.ValidatorIndexRequest{PublicKey: key[:]})
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get validator index for public key %#x", bytesutil.Trunc(key[:]))
+		}
+		keysByIndex[res.Index] = key
+	}
+	return keysByIndex, nil
+}
+
+// checkEpochForDoppelgangers adds to found the indices of the keys attesting or proposing in the
+// blocks of the epoch, counting only the attestations targeting an epoch after startEpoch.
+func (v *validator) checkEpochForDoppelgangers(
+	ctx context.Context,
+	epoch uint64,
+	startEpoch uint64,
+	keysByIndex map[uint64][48]byte,
+	found map[uint64]bool,
+) error {
+	attesters, err := v.epochAttesters(ctx, epoch, startEpoch)
+	if err != nil {
+		return err
+	}
+	proposers, err := v.epochProposers(ctx, epoch)
+	if err != nil {
+		return err
+	}
+	for _, index := range append(attesters, proposers...) {
+		if _, ok := keysByIndex[index]; ok {
+			found[index] = true
+		}
+	}
+	return nil
+}
+
+// epochAttesters returns the validator indices attesting in the blocks of the epoch to a target
+// after the given epoch.
+func (v *validator) epochAttesters(ctx context.Context, epoch uint64, afterEpoch uint64) ([]uint64, error) {
+	var indices []uint64
+	res := &ethpb.ListIndexedAttestationsResponse{}
+	var err error
+	received := 0
+	for {
+		res, err = v.beaconClient.ListIndexedAttestations(ctx, &ethpb.ListIndexedAttestationsRequest{
+			QueryFilter: &ethpb.ListIndexedAttestationsRequest_Epoch{
+				Epoch: epoch,
+			},
+			PageSize:  int32(params.BeaconConfig().DefaultPageSize),
+			PageToken: res.NextPageToken,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not request indexed attestations for epoch: %d", epoch)
+		}
+		for _, att := range res.IndexedAttestations {
+			if att.Data.Target.Epoch > afterEpoch {
+				indices = append(indices, att.AttestingIndices...)
+			}
+		}
+		received += len(res.IndexedAttestations)
+		if res.NextPageToken == "" || res.TotalSize == 0 || received == int(res.TotalSize) {
+			break
+		}
+	}
+	return indices, nil
+}
+
+// epochProposers returns the validator indices proposing the blocks of the epoch.
+func (v *validator) epochProposers(ctx context.Context, epoch uint64) ([]uint64, error) {
+	var indices []uint64
+	res := &ethpb.ListBlocksResponse{}
+	var err error
+	received := 0
+	for {
+		res, err = v.beaconClient.ListBlocks(ctx, &ethpb.ListBlocksRequest{
+			QueryFilter: &ethpb.ListBlocksRequest_Epoch{
+				Epoch: epoch,
+			},
+			PageSize:  int32(params.BeaconConfig().DefaultPageSize),
+			PageToken: res.NextPageToken,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not request blocks for epoch: %d", epoch)
+		}
+		for _, container := range res.BlockContainers {
+			indices = append(indices, container.Block.Block.ProposerIndex)
+		}
+		received += len(res.BlockContainers)
+		if res.NextPageToken == "" || res.TotalSize == 0 || received == int(res.TotalSize) {
+			break
+		}
+	}
+	return indices, nil
+}
@@ -0,0 +1,344 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/internal"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+func TestDetectDoppelgangers_Disabled(t *testing.T) {
+	v := validator{keyManager: testKeyManager}
+	if err := v.DetectDoppelgangers(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectDoppelgangers(t *testing.T) {
+	cfg := *params.BeaconConfig()
+	cfg.SecondsPerSlot = 1
+	cfg.SlotsPerEpoch = 1
+	params.OverrideBeaconConfig(&cfg)
+	defer params.OverrideBeaconConfig(params.MainnetConfig())
+
+	tests := []struct {
+		name         string
+		attestations []*ethpb.IndexedAttestation
+		proposer     uint64
+		doppelganger bool
+	}{
+		{
+			name: "no doppelganger",
+			attestations: []*ethpb.IndexedAttestation{
+				{AttestingIndices: []uint64{1, 2}, Data: &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 1 << 40}}},
+			},
+			proposer: 1,
+		},
+		{
+			name: "attestation before detection",
+			attestations: []*ethpb.IndexedAttestation{
+				{AttestingIndices: []uint64{5}, Data: &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 0}}},
+			},
+			proposer: 1,
+		},
+		{
+			name: "attesting doppelganger",
+			attestations: []*ethpb.IndexedAttestation{
+				{AttestingIndices: []uint64{4, 5}, Data: &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 1 << 40}}},
+			},
+			proposer:     1,
+			doppelganger: true,
+		},
+		{
+			name:         "proposing doppelganger",
+			proposer:     5,
+			doppelganger: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			validatorClient := internal.NewMockBeaconNodeValidatorClient(ctrl)
+			beaconClient := mock.NewMockBeaconChainClient(ctrl)
+			v := validator{
+				genesisTime:        uint64(time.Now().Unix()) - 10,
+				keyManager:         testKeyManager,
+				validatorClient:    validatorClient,
+				beaconClient:       beaconClient,
+				doppelgangerEpochs: 1,
+			}
+
+			validatorClient.EXPECT().ValidatorIndex(
+				gomock.Any(),
+				gomock.Any(),
+			).Return(&ethpb.ValidatorIndexResponse{Index: 5}, nil)
+			beaconClient.EXPECT().ListIndexedAttestations(
+				gomock.Any(),
+				gomock.Any(),
+			).Return(&ethpb.ListIndexedAttestationsResponse{
+				IndexedAttestations: tt.attestations,
+				TotalSize:           int32(len(tt.attestations)),
+			}, nil)
+			beaconClient.EXPECT().ListBlocks(
+				gomock.Any(),
+				gomock.Any(),
+			).Return(&ethpb.ListBlocksResponse{
+				BlockContainers: []*ethpb.BeaconBlockContainer{
+					{Block: &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{ProposerIndex: tt.proposer}}},
+				},
+				TotalSize: 1,
+			}, nil)
+
+			err := v.DetectDoppelgangers(context.Background())
+			if !tt.doppelganger {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			doppelgangers, ok := err.(*DoppelgangerError)
+			if !ok {
+				t.Fatalf("Wanted a doppelganger error, received %v", err)
+			}
+			keys, err := testKeyManager.FetchValidatingKeys()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(doppelgangers.PublicKeys) != 1 || doppelgangers.PublicKeys[0] != keys[0] {
+				t.Errorf("Wanted the validating key reported, received %v", doppelgangers.PublicKeys)
+			}
+		})
+	}
+}
+
+func TestDetectDoppelgangers_DisablesAffectedKeys(t *testing.T) {
+	cfg := *params.BeaconConfig()
+	cfg.SecondsPerSlot = 1
+	cfg.SlotsPerEpoch = 1
+	params.OverrideBeaconConfig(&cfg)
+	defer params.OverrideBeaconConfig(params.MainnetConfig())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	validatorClient := internal.NewMockBeaconNodeValidatorClient(ctrl)
+	beaconClient := mock.NewMockBeaconChainClient(ctrl)
+	km := keymanager.NewDirect([]*bls.SecretKey{bls.RandKey(), bls.RandKey()})
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := validator{
+		genesisTime:        uint64(time.Now().Unix()) - 10,
+		keyManager:         km,
+		validatorClient:    validatorClient,
+		beaconClient:       beaconClient,
+		doppelgangerEpochs: 1,
+	}
+
+	validatorClient.EXPECT().ValidatorIndex(
+		gomock.Any(),
+		&ethpb.ValidatorIndexRequest{PublicKey: keys[0][:]},
+	).Return(&ethpb.ValidatorIndexResponse{Index: 5}, nil)
+	validatorClient.EXPECT().ValidatorIndex(
+		gomock.Any(),
+		&ethpb.ValidatorIndexRequest{PublicKey: keys[1][:]},
+	).Return(&ethpb.ValidatorIndexResponse{Index: 6}, nil)
+	beaconClient.EXPECT().ListIndexedAttestations(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListIndexedAttestationsResponse{
+		IndexedAttestations: []*ethpb.IndexedAttestation{
+			{AttestingIndices: []uint64{5}, Data: &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 1 << 40}}},
+		},
+		TotalSize: 1,
+	}, nil)
+	beaconClient.EXPECT().ListBlocks(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListBlocksResponse{}, nil)
+
+	if err := v.DetectDoppelgangers(context.Background()); err != nil {
+		t.Fatalf("Wanted no error with keys left to sign with, received %v", err)
+	}
+	signing := v.signingKeys(keys)
+	if len(signing) != 1 || signing[0] != keys[1] {
+		t.Errorf("Wanted to sign with the unaffected key only, received %v", signing)
+	}
+}
+
+func TestCheckPendingKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	validatorClient := internal.NewMockBeaconNodeValidatorClient(ctrl)
+	beaconClient := mock.NewMockBeaconChainClient(ctrl)
+	km := keymanager.NewDirect([]*bls.SecretKey{bls.RandKey(), bls.RandKey()})
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := validator{
+		validatorClient:    validatorClient,
+		beaconClient:       beaconClient,
+		doppelgangerEpochs: 1,
+		pendingKeys:        map[[48]byte]uint64{keys[0]: 3, keys[1]: 3},
+	}
+	ctx := context.Background()
+
+	// The keys are not checked before the end of the detection epochs.
+	if err := v.checkPendingKeys(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	if signing := v.signingKeys(keys); len(signing) != 0 {
+		t.Errorf("Wanted no key to sign with during detection, received %v", signing)
+	}
+
+	validatorClient.EXPECT().ValidatorIndex(
+		gomock.Any(),
+		&ethpb.ValidatorIndexRequest{PublicKey: keys[0][:]},
+	).Return(&ethpb.ValidatorIndexResponse{Index: 5}, nil)
+	validatorClient.EXPECT().ValidatorIndex(
+		gomock.Any(),
+		&ethpb.ValidatorIndexRequest{PublicKey: keys[1][:]},
+	).Return(&ethpb.ValidatorIndexResponse{Index: 6}, nil)
+	beaconClient.EXPECT().ListIndexedAttestations(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListIndexedAttestationsResponse{}, nil)
+	beaconClient.EXPECT().ListBlocks(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListBlocksResponse{
+		BlockContainers: []*ethpb.BeaconBlockContainer{
+			{Block: &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{ProposerIndex: 5}}},
+		},
+		TotalSize: 1,
+	}, nil)
+
+	if err := v.checkPendingKeys(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.pendingKeys) != 0 {
+		t.Errorf("Wanted no pending key left, received %d", len(v.pendingKeys))
+	}
+	signing := v.signingKeys(keys)
+	if len(signing) != 1 || signing[0] != keys[1] {
+		t.Errorf("Wanted to sign with the key not found elsewhere only, received %v", signing)
+	}
+}
+
+func TestDetectDoppelgangers_FailClosed(t *testing.T) {
+	cfg := *params.BeaconConfig()
+	cfg.SecondsPerSlot = 1
+	cfg.SlotsPerEpoch = 1
+	params.OverrideBeaconConfig(&cfg)
+	defer params.OverrideBeaconConfig(params.MainnetConfig())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	validatorClient := internal.NewMockBeaconNodeValidatorClient(ctrl)
+	beaconClient := mock.NewMockBeaconChainClient(ctrl)
+	km := keymanager.NewDirect([]*bls.SecretKey{bls.RandKey(), bls.RandKey()})
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := validator{
+		genesisTime:            uint64(time.Now().Unix()) - 10,
+		keyManager:             km,
+		validatorClient:        validatorClient,
+		beaconClient:           beaconClient,
+		doppelgangerEpochs:     1,
+		doppelgangerFailClosed: true,
+	}
+
+	validatorClient.EXPECT().ValidatorIndex(
+		gomock.Any(),
+		&ethpb.ValidatorIndexRequest{PublicKey: keys[0][:]},
+	).Return(&ethpb.ValidatorIndexResponse{Index: 5}, nil)
+	validatorClient.EXPECT().ValidatorIndex(
+		gomock.Any(),
+		&ethpb.ValidatorIndexRequest{PublicKey: keys[1][:]},
+	).Return(&ethpb.ValidatorIndexResponse{Index: 6}, nil)
+	beaconClient.EXPECT().ListIndexedAttestations(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListIndexedAttestationsResponse{}, nil)
+	beaconClient.EXPECT().ListBlocks(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListBlocksResponse{
+		BlockContainers: []*ethpb.BeaconBlockContainer{
+			{Block: &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{ProposerIndex: 6}}},
+		},
+		TotalSize: 1,
+	}, nil)
+
+	err = v.DetectDoppelgangers(context.Background())
+	doppelgangers, ok := err.(*DoppelgangerError)
+	if !ok {
+		t.Fatalf("Wanted a doppelganger error with a key active elsewhere, received %v", err)
+	}
+	if len(doppelgangers.PublicKeys) != 1 || doppelgangers.PublicKeys[0] != keys[1] {
+		t.Errorf("Wanted the affected key reported, received %v", doppelgangers.PublicKeys)
+	}
+}
+
+func TestCheckPendingKeys_FailClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	validatorClient := internal.NewMockBeaconNodeValidatorClient(ctrl)
+	beaconClient := mock.NewMockBeaconChainClient(ctrl)
+	km := keymanager.NewDirect([]*bls.SecretKey{bls.RandKey()})
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := validator{
+		keyManager:             km,
+		validatorClient:        validatorClient,
+		beaconClient:           beaconClient,
+		doppelgangerEpochs:     1,
+		doppelgangerFailClosed: true,
+		pendingKeys:            map[[48]byte]uint64{keys[0]: 3},
+	}
+	ctx := context.Background()
+
+	validatorClient.EXPECT().ValidatorIndex(
+		gomock.Any(),
+		&ethpb.ValidatorIndexRequest{PublicKey: keys[0][:]},
+	).Return(&ethpb.ValidatorIndexResponse{Index: 5}, nil)
+	beaconClient.EXPECT().ListIndexedAttestations(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListIndexedAttestationsResponse{
+		IndexedAttestations: []*ethpb.IndexedAttestation{
+			{AttestingIndices: []uint64{5}, Data: &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 4}}},
+		},
+		TotalSize: 1,
+	}, nil)
+	beaconClient.EXPECT().ListBlocks(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.ListBlocksResponse{}, nil)
+
+	if err := v.checkPendingKeys(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	// The next key update stops the validator.
+	_, err = v.updateValidatingKeys(ctx)
+	doppelgangers, ok := err.(*DoppelgangerError)
+	if !ok {
+		t.Fatalf("Wanted a doppelganger error, received %v", err)
+	}
+	if len(doppelgangers.PublicKeys) != 1 || doppelgangers.PublicKeys[0] != keys[0] {
+		t.Errorf("Wanted the affected key reported, received %v", doppelgangers.PublicKeys)
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+// ReloadKeys requests the validating keys to be reloaded from the keymanager. The new set
+// of keys is picked up at the next epoch boundary, when duties are requested again.
+func (v *ValidatorService) ReloadKeys() {
+	select {
+	case v.reloadKeys <- struct{}{}:
+	default:
+		// A reload is already pending.
+	}
+}
+
+// updateValidatingKeys fetches the keys to validate with for the upcoming epoch, reloading them
+// from the keymanager first if a reload was requested. Keys which are no longer validated keep
+// their slashing protection history in the database, so it is still enforced if they come back.
+// When doppelganger detection is enabled, new keys only start validating once they pass it, and
+// keys found active elsewhere are left out, or a DoppelgangerError is returned if the detection
+// fails closed.
+func (v *validator) updateValidatingKeys(ctx context.Context) ([][48]byte, error) {
+	v.keysLock.RLock()
+	doppelgangerErr := v.doppelgangerErr
+	v.keysLock.RUnlock()
+	if doppelgangerErr != nil {
+		return nil, doppelgangerErr
+	}
+
+	select {
+	case <-v.reloadKeys:
+		if refreshable, ok := v.keyManager.(keymanager.RefreshableKeyManager); ok {
+			if err := refreshable.RefreshValidatingKeys(); err != nil {
+				log.WithError(err).Error("Could not reload validating keys, keeping current keys")
+			}
+		} else {
+			log.Warn("Keymanager does not support reloading validating keys")
+		}
+	default:
+	}
+
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return nil, err
+	}
+	newKeys := make(map[[48]byte]bool, len(validatingKeys))
+	for _, key := range validatingKeys {
+		newKeys[key] = true
+	}
+	// The validating keys are only changed by the validator routine, which reads them without
+	// lock. They are changed under lock as the admin service reads them from other routines. The
+	// pending and disabled keys are always accessed under lock, as the doppelganger detection of
+	// new keys updates them from its own routine.
+	if v.validatingKeys == nil {
+		v.keysLock.Lock()
+		defer v.keysLock.Unlock()
+		v.validatingKeys = newKeys
+		return v.signingKeys(validatingKeys), nil
+	}
+
+	var added [][48]byte
+	for key := range newKeys {
+		if !v.validatingKeys[key] {
+			added = append(added, key)
+		}
+	}
+	if len(added) > 0 {
+		if err := v.db.UpdatePublicKeysBuckets(added); err != nil {
+			return nil, err
+		}
+	}
+	currentEpoch := slotutil.EpochsSinceGenesis(time.Unix(int64(v.genesisTime), 0))
+	v.keysLock.Lock()
+	for _, key := range added {
+		if v.doppelgangerEpochs == 0 {
+			log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(key[:]))).Info("Started validating for public key")
+			continue
+		}
+		if v.pendingKeys == nil {
+			v.pendingKeys = make(map[[48]byte]uint64)
+		}
+		v.pendingKeys[key] = currentEpoch
+		log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(key[:]))).Info("Waiting for doppelganger detection before validating for public key")
+	}
+	for key := range v.validatingKeys {
+		if !newKeys[key] {
+			delete(v.pendingKeys, key)
+			log.WithField("pubKey", fmt.Sprintf("%#x", bytesutil.Trunc(key[:]))).Info("Stopped validating for public key")
+		}
+	}
+	v.validatingKeys = newKeys
+	hasPendingKeys := len(v.pendingKeys) > 0
+	v.keysLock.Unlock()
+	if hasPendingKeys {
+		v.checkPendingKeysInBackground(ctx, currentEpoch)
+	}
+
+	v.keysLock.RLock()
+	defer v.keysLock.RUnlock()
+	return v.signingKeys(validatingKeys), nil
+}
+
+// signingKeys leaves out the keys waiting for doppelganger detection and the keys found active
+// elsewhere. It must be called with the keys lock held.
+func (v *validator) signingKeys(keys [][48]byte) [][48]byte {
+	signing := make([][48]byte, 0, len(keys))
+	for _, key := range keys {
+		if _, pending := v.pendingKeys[key]; pending || v.disabledKeys[key] {
+			continue
+		}
+		signing = append(signing, key)
+	}
+	return signing
+}
+
+// signingKeysSnapshot returns the keys the validator currently signs with, or false if the
+// validator has not loaded its keys yet. It is safe to call from any routine.
+func (v *validator) signingKeysSnapshot() ([][48]byte, bool) {
+	v.keysLock.RLock()
+	defer v.keysLock.RUnlock()
+	if v.validatingKeys == nil {
+		return nil, false
+	}
+	keys := make([][48]byte, 0, len(v.validatingKeys))
+	for key := range v.validatingKeys {
+		keys = append(keys, key)
+	}
+	keys = v.signingKeys(keys)
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	return keys, true
+}
+
+// watchKeysPath polls the directory at path and calls reload whenever its contents change.
+func watchKeysPath(ctx context.Context, path string, interval time.Duration, reload func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last, err := dirFingerprint(path)
+	if err != nil {
+		log.WithError(err).Error("Could not read keys directory, not watching it for changes")
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := dirFingerprint(path)
+			if err != nil {
+				log.WithError(err).Warn("Could not read keys directory")
+				continue
+			}
+			if current != last {
+				log.WithField("path", path).Info("Keys directory changed, reloading keys at the next epoch")
+				last = current
+				reload()
+			}
+		}
+	}
+}
+
+// dirFingerprint summarizes the names, sizes and modification times of the files in a directory.
+func dirFingerprint(path string) (string, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&b, "%s:%d:%d;", f.Name(), f.Size(), f.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+type refreshableKeyManager struct {
+	*keymanager.Direct
+	next *keymanager.Direct
+}
+
+func (km *refreshableKeyManager) RefreshValidatingKeys() error {
+	km.Direct = km.next
+	return nil
+}
+
+func TestUpdateValidatingKeys_ReloadsOnRequest(t *testing.T) {
+	hook := logTest.NewGlobal()
+	sk1 := bls.RandKey()
+	sk2 := bls.RandKey()
+	pubKey1 := bytesutil.ToBytes48(sk1.PublicKey().Marshal())
+	pubKey2 := bytesutil.ToBytes48(sk2.PublicKey().Marshal())
+	km := &refreshableKeyManager{
+		Direct: keymanager.NewDirect([]*bls.SecretKey{sk1}),
+		next:   keymanager.NewDirect([]*bls.SecretKey{sk2}),
+	}
+	valDB := db.SetupDB(t, [][48]byte{pubKey1})
+	defer db.TeardownDB(t, valDB)
+	v := &validator{
+		db:         valDB,
+		keyManager: km,
+		reloadKeys: make(chan struct{}, 1),
+	}
+	ctx := context.Background()
+
+	history, err := valDB.AttestationHistory(ctx, pubKey1[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	history.TargetToSource[1] = 0
+	history.LatestEpochWritten = 1
+	if err := valDB.SaveAttestationHistory(ctx, pubKey1[:], history); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := v.updateValidatingKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != pubKey1 {
+		t.Fatalf("Expected to validate with the initial key only, received %v", keys)
+	}
+
+	v.reloadKeys <- struct{}{}
+	keys, err = v.updateValidatingKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != pubKey2 {
+		t.Fatalf("Expected to validate with the reloaded key only, received %v", keys)
+	}
+	testutil.AssertLogsContain(t, hook, "Started validating for public key")
+	testutil.AssertLogsContain(t, hook, "Stopped validating for public key")
+
+	// The added key must be able to record proposals.
+	if _, err := valDB.ProposalHistoryForEpoch(ctx, pubKey2[:], 0); err != nil {
+		t.Errorf("Expected proposal history of added key to be initialized: %v", err)
+	}
+	// The removed key must keep its attestation history.
+	history, err = valDB.AttestationHistory(ctx, pubKey1[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.LatestEpochWritten != 1 || history.TargetToSource[1] != 0 {
+		t.Errorf("Expected attestation history of removed key to be kept, received %v", history)
+	}
+}
+
+func TestUpdateValidatingKeys_NoReloadWithoutRequest(t *testing.T) {
+	sk1 := bls.RandKey()
+	pubKey1 := bytesutil.ToBytes48(sk1.PublicKey().Marshal())
+	km := &refreshableKeyManager{
+		Direct: keymanager.NewDirect([]*bls.SecretKey{sk1}),
+		next:   keymanager.NewDirect([]*bls.SecretKey{bls.RandKey()}),
+	}
+	v := &validator{
+		keyManager: km,
+		reloadKeys: make(chan struct{}, 1),
+	}
+
+	for i := 0; i < 2; i++ {
+		keys, err := v.updateValidatingKeys(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0] != pubKey1 {
+			t.Fatalf("Expected keys to be unchanged, received %v", keys)
+		}
+	}
+}
+
+func TestWatchKeysPath_ReloadsOnChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watchkeys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan struct{}, 1)
+	go watchKeysPath(ctx, dir, 10*time.Millisecond, func() {
+		reloaded <- struct{}{}
+	})
+	// Give the watcher time to record the initial contents of the directory.
+	time.Sleep(50 * time.Millisecond)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "newkey"), []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("Expected keys to be reloaded after the directory changed")
+	}
+}
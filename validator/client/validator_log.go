@@ -12,6 +12,7 @@ type attSubmitted struct {
 	data              *ethpb.AttestationData
 	attesterIndices   []uint64
 	aggregatorIndices []uint64
+	beaconNodes       []string
 }
 
 func (v *validator) LogAttestationsSubmitted() {
@@ -29,6 +30,7 @@ func (v *validator) LogAttestationsSubmitted() {
 			"TargetRoot":        fmt.Sprintf("%#x", bytesutil.Trunc(attLog.data.Target.Root)),
 			"AttesterIndices":   attLog.attesterIndices,
 			"AggregatorIndices": attLog.aggregatorIndices,
+			"BeaconNodes":       attLog.beaconNodes,
 		}).Info("Submitted new attestations")
 	}
 
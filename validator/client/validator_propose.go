@@ -122,7 +122,8 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	}
 
 	// Propose and broadcast block via beacon node
-	blkResp, err := v.validatorClient.ProposeBlock(ctx, blk)
+	proposeCtx, servedBy := withServedBy(ctx)
+	blkResp, err := v.validatorClient.ProposeBlock(proposeCtx, blk)
 	if err != nil {
 		log.WithError(err).Error("Failed to propose block")
 		if v.emitAccountMetrics {
@@ -158,6 +159,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 		"blockRoot":       blkRoot,
 		"numAttestations": len(b.Body.Attestations),
 		"numDeposits":     len(b.Body.Deposits),
+		"beaconNode":      servedBy(),
 	}).Info("Submitted new block")
 }
 
@@ -173,7 +175,12 @@ func (v *validator) signRandaoReveal(ctx context.Context, pubKey [48]byte, epoch
 		return nil, errors.Wrap(err, "could not get domain data")
 	}
 
-	randaoReveal, err := v.signObject(pubKey, epoch, domain.SignatureDomain)
+	var randaoReveal *bls.Signature
+	if dutySigningKeymanager, supported := v.keyManager.(keymanager.DutySigningKeyManager); supported {
+		randaoReveal, err = dutySigningKeymanager.SignRandaoReveal(pubKey, bytesutil.ToBytes32(domain.SignatureDomain), epoch)
+	} else {
+		randaoReveal, err = v.signObject(pubKey, epoch, domain.SignatureDomain)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "could not sign reveal")
 	}
@@ -12,6 +12,7 @@ import (
 	ptypes "github.com/gogo/protobuf/types"
 	"github.com/golang/mock/gomock"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/mock"
@@ -21,6 +22,7 @@ import (
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/sirupsen/logrus"
 	logTest "github.com/sirupsen/logrus/hooks/test"
+	"google.golang.org/grpc"
 )
 
 func init() {
@@ -557,6 +559,60 @@ func TestWaitSync_Syncing(t *testing.T) {
 	}
 }
 
+// forkAwareKeyManager records the fork info set by the validator.
+type forkAwareKeyManager struct {
+	keymanager.KeyManager
+	fork                  *pb.Fork
+	genesisValidatorsRoot []byte
+}
+
+func (km *forkAwareKeyManager) SetForkInfo(fork *pb.Fork, genesisValidatorsRoot []byte) {
+	km.fork = fork
+	km.genesisValidatorsRoot = genesisValidatorsRoot
+}
+
+// fakeForkClient serves a fixed head fork.
+type fakeForkClient struct {
+	fork *pb.Fork
+}
+
+func (f *fakeForkClient) GetHeadFork(_ context.Context, _ *ptypes.Empty, _ ...grpc.CallOption) (*pb.Fork, error) {
+	return f.fork, nil
+}
+
+func TestUpdateForkInfo_SetsHeadFork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	nodeClient := mock.NewMockNodeClient(ctrl)
+	km := &forkAwareKeyManager{KeyManager: testKeyManager}
+	forkClient := &fakeForkClient{fork: &pb.Fork{CurrentVersion: []byte{0, 0, 0, 1}, Epoch: 5}}
+	v := validator{
+		keyManager: km,
+		node:       nodeClient,
+		forkClient: forkClient,
+	}
+	nodeClient.EXPECT().GetGenesis(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.Genesis{GenesisValidatorsRoot: []byte{'a'}}, nil).Times(1)
+
+	if err := v.updateForkInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if km.fork.Epoch != 5 || string(km.genesisValidatorsRoot) != "a" {
+		t.Errorf("Unexpected fork info %v, %#x", km.fork, km.genesisValidatorsRoot)
+	}
+
+	// The fork is fetched again after a fork, the genesis validators root is kept.
+	forkClient.fork = &pb.Fork{PreviousVersion: []byte{0, 0, 0, 1}, CurrentVersion: []byte{0, 0, 0, 2}, Epoch: 10}
+	if err := v.updateForkInfo(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if km.fork.Epoch != 10 {
+		t.Errorf("Wanted the fork at epoch %d, received %v", 10, km.fork)
+	}
+}
+
 func TestUpdateDuties_DoesNothingWhenNotEpochStart_AlreadyExistingAssignments(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,346 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-bitfield"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/wealdtech/go-bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// InterchangeFormatVersion is the version of the slashing protection interchange
+// format written by ExportSlashingProtection and accepted by ImportSlashingProtection.
+const InterchangeFormatVersion = "1"
+
+// Interchange is the JSON document used to move a validator's slashing protection
+// history between machines.
+type Interchange struct {
+	Version string                `json:"version"`
+	Data    []*InterchangeHistory `json:"data"`
+}
+
+// InterchangeHistory is the slashing protection history of a single validator public key.
+type InterchangeHistory struct {
+	PublicKey          string                    `json:"pubkey"`
+	LatestEpochWritten uint64                    `json:"latest_epoch_written"`
+	Proposals          []*InterchangeProposal    `json:"signed_blocks"`
+	Attestations       []*InterchangeAttestation `json:"signed_attestations"`
+}
+
+// InterchangeProposal records a slot at which the validator has signed a block.
+type InterchangeProposal struct {
+	Slot uint64 `json:"slot"`
+}
+
+// InterchangeAttestation records the source and target epochs of an attestation signed by the validator.
+type InterchangeAttestation struct {
+	SourceEpoch uint64 `json:"source_epoch"`
+	TargetEpoch uint64 `json:"target_epoch"`
+}
+
+// ExportSlashingProtection writes the proposal and attestation history of every
+// public key in the database to w as an interchange JSON document.
+func (db *Store) ExportSlashingProtection(ctx context.Context, w io.Writer) error {
+	ctx, span := trace.StartSpan(ctx, "Validator.ExportSlashingProtection")
+	defer span.End()
+
+	histories := make(map[string]*InterchangeHistory)
+	historyFor := func(pubKey []byte) *InterchangeHistory {
+		key := fmt.Sprintf("%#x", pubKey)
+		if h, ok := histories[key]; ok {
+			return h
+		}
+		h := &InterchangeHistory{
+			PublicKey:    key,
+			Proposals:    make([]*InterchangeProposal, 0),
+			Attestations: make([]*InterchangeAttestation, 0),
+		}
+		histories[key] = h
+		return h
+	}
+
+	err := db.view(func(tx *bolt.Tx) error {
+		proposalsBucket := tx.Bucket(historicProposalsBucket)
+		if err := proposalsBucket.ForEach(func(pubKey []byte, v []byte) error {
+			// Only nested buckets are expected here, one per public key.
+			if v != nil {
+				return nil
+			}
+			h := historyFor(pubKey)
+			proposals, err := proposalsFromBucket(proposalsBucket.Bucket(pubKey))
+			if err != nil {
+				return err
+			}
+			h.Proposals = append(h.Proposals, proposals...)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		return tx.Bucket(historicAttestationsBucket).ForEach(func(pubKey []byte, enc []byte) error {
+			history, err := unmarshalAttestationHistory(enc)
+			if err != nil {
+				return err
+			}
+			h := historyFor(pubKey)
+			h.LatestEpochWritten = history.LatestEpochWritten
+			h.Attestations = append(h.Attestations, attestationsFromHistory(history)...)
+			return nil
+		})
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not read slashing protection history")
+	}
+
+	interchange := &Interchange{
+		Version: InterchangeFormatVersion,
+		Data:    make([]*InterchangeHistory, 0, len(histories)),
+	}
+	for _, h := range histories {
+		sort.Slice(h.Proposals, func(i, j int) bool {
+			return h.Proposals[i].Slot < h.Proposals[j].Slot
+		})
+		interchange.Data = append(interchange.Data, h)
+	}
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return interchange.Data[i].PublicKey < interchange.Data[j].PublicKey
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(interchange)
+}
+
+// ProtectionHistory returns the proposal and attestation history stored for a single public key,
+// with proposals sorted by slot.
+func (db *Store) ProtectionHistory(ctx context.Context, pubKey []byte) (*InterchangeHistory, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.ProtectionHistory")
+	defer span.End()
+
+	h := &InterchangeHistory{
+		PublicKey:    fmt.Sprintf("%#x", pubKey),
+		Proposals:    make([]*InterchangeProposal, 0),
+		Attestations: make([]*InterchangeAttestation, 0),
+	}
+	err := db.view(func(tx *bolt.Tx) error {
+		if valBucket := tx.Bucket(historicProposalsBucket).Bucket(pubKey); valBucket != nil {
+			proposals, err := proposalsFromBucket(valBucket)
+			if err != nil {
+				return err
+			}
+			h.Proposals = proposals
+		}
+		enc := tx.Bucket(historicAttestationsBucket).Get(pubKey)
+		if enc == nil {
+			return nil
+		}
+		history, err := unmarshalAttestationHistory(enc)
+		if err != nil {
+			return err
+		}
+		h.LatestEpochWritten = history.LatestEpochWritten
+		h.Attestations = attestationsFromHistory(history)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read slashing protection history")
+	}
+	sort.Slice(h.Proposals, func(i, j int) bool {
+		return h.Proposals[i].Slot < h.Proposals[j].Slot
+	})
+	return h, nil
+}
+
+// ImportSlashingProtection reads an interchange JSON document from r and merges it
+// into the history already stored in the database. Proposals are merged as a union
+// and attestations keep every recorded vote. The import is rejected as a whole if it
+// is malformed or conflicts with the stored history, as accepting it would weaken
+// the validator's protection.
+func (db *Store) ImportSlashingProtection(ctx context.Context, r io.Reader) error {
+	ctx, span := trace.StartSpan(ctx, "Validator.ImportSlashingProtection")
+	defer span.End()
+
+	interchange := &Interchange{}
+	if err := json.NewDecoder(r).Decode(interchange); err != nil {
+		return errors.Wrap(err, "could not decode slashing protection interchange")
+	}
+	if interchange.Version != InterchangeFormatVersion {
+		return fmt.Errorf("unsupported interchange format version %q, expected %q", interchange.Version, InterchangeFormatVersion)
+	}
+
+	return db.update(func(tx *bolt.Tx) error {
+		for _, h := range interchange.Data {
+			pubKey, err := hex.DecodeString(strings.TrimPrefix(h.PublicKey, "0x"))
+			if err != nil || len(pubKey) != 48 {
+				return fmt.Errorf("invalid public key %q in interchange", h.PublicKey)
+			}
+			if err := importProposals(tx, pubKey, h.Proposals); err != nil {
+				return errors.Wrapf(err, "could not import proposals for public key %#x", pubKey)
+			}
+			if err := importAttestations(tx, pubKey, h); err != nil {
+				return errors.Wrapf(err, "could not import attestations for public key %#x", pubKey)
+			}
+		}
+		return nil
+	})
+}
+
+func importProposals(tx *bolt.Tx, pubKey []byte, proposals []*InterchangeProposal) error {
+	valBucket, err := tx.Bucket(historicProposalsBucket).CreateBucketIfNotExists(pubKey)
+	if err != nil {
+		return err
+	}
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	newestEpoch := uint64(0)
+	for _, p := range proposals {
+		epoch := p.Slot / slotsPerEpoch
+		slotBits := bitfield.NewBitlist(slotsPerEpoch)
+		if enc := valBucket.Get(bytesutil.Bytes8(epoch)); len(enc) > 0 {
+			copy(slotBits, enc)
+		}
+		slotBits.SetBitAt(p.Slot%slotsPerEpoch, true)
+		if err := valBucket.Put(bytesutil.Bytes8(epoch), slotBits); err != nil {
+			return err
+		}
+		if epoch > newestEpoch {
+			newestEpoch = epoch
+		}
+	}
+	return pruneProposalHistory(valBucket, newestEpoch)
+}
+
+func importAttestations(tx *bolt.Tx, pubKey []byte, h *InterchangeHistory) error {
+	bucket := tx.Bucket(historicAttestationsBucket)
+	existing := newAttestationHistory()
+	if enc := bucket.Get(pubKey); enc != nil {
+		var err error
+		existing, err = unmarshalAttestationHistory(enc)
+		if err != nil {
+			return err
+		}
+	}
+
+	targetToSource := make(map[uint64]uint64)
+	for _, att := range attestationsFromHistory(existing) {
+		targetToSource[att.TargetEpoch] = att.SourceEpoch
+	}
+	for _, att := range h.Attestations {
+		if att.SourceEpoch > att.TargetEpoch {
+			return fmt.Errorf("source epoch %d is greater than target epoch %d", att.SourceEpoch, att.TargetEpoch)
+		}
+		if source, ok := targetToSource[att.TargetEpoch]; ok && source != att.SourceEpoch {
+			return fmt.Errorf(
+				"conflicting attestations for target epoch %d with source epochs %d and %d",
+				att.TargetEpoch,
+				source,
+				att.SourceEpoch,
+			)
+		}
+		targetToSource[att.TargetEpoch] = att.SourceEpoch
+	}
+
+	latestEpochWritten := existing.LatestEpochWritten
+	if h.LatestEpochWritten > latestEpochWritten {
+		latestEpochWritten = h.LatestEpochWritten
+	}
+	targets := make([]uint64, 0, len(targetToSource))
+	for target := range targetToSource {
+		targets = append(targets, target)
+		if target > latestEpochWritten {
+			latestEpochWritten = target
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i] < targets[j]
+	})
+
+	merged := newAttestationHistory()
+	for _, target := range targets {
+		merged = markTargetEpoch(merged, targetToSource[target], target)
+	}
+	if merged.LatestEpochWritten < latestEpochWritten {
+		merged = markTargetEpoch(merged, params.BeaconConfig().FarFutureEpoch, latestEpochWritten)
+	}
+
+	enc, err := proto.Marshal(merged)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode attestation history")
+	}
+	return bucket.Put(pubKey, enc)
+}
+
+// proposalsFromBucket lists the proposals recorded in the proposal history bucket of a public key.
+func proposalsFromBucket(valBucket *bolt.Bucket) ([]*InterchangeProposal, error) {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	proposals := make([]*InterchangeProposal, 0)
+	err := valBucket.ForEach(func(k []byte, slotBits []byte) error {
+		epoch := binary.LittleEndian.Uint64(k)
+		bits := bitfield.Bitlist(slotBits)
+		for i := uint64(0); i < slotsPerEpoch && i < bits.Len(); i++ {
+			if bits.BitAt(i) {
+				proposals = append(proposals, &InterchangeProposal{Slot: epoch*slotsPerEpoch + i})
+			}
+		}
+		return nil
+	})
+	return proposals, err
+}
+
+// attestationsFromHistory lists the attestations recorded in the history which are still
+// within the weak subjectivity period of its latest written epoch.
+func attestationsFromHistory(history *slashpb.AttestationHistory) []*InterchangeAttestation {
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	oldest := uint64(0)
+	if history.LatestEpochWritten > wsPeriod {
+		oldest = history.LatestEpochWritten - wsPeriod
+	}
+	atts := make([]*InterchangeAttestation, 0)
+	for target := oldest; target <= history.LatestEpochWritten; target++ {
+		source, ok := history.TargetToSource[target%wsPeriod]
+		if !ok || source == farFuture {
+			continue
+		}
+		atts = append(atts, &InterchangeAttestation{
+			SourceEpoch: source,
+			TargetEpoch: target,
+		})
+	}
+	return atts
+}
+
+func newAttestationHistory() *slashpb.AttestationHistory {
+	newMap := make(map[uint64]uint64)
+	newMap[0] = params.BeaconConfig().FarFutureEpoch
+	return &slashpb.AttestationHistory{
+		TargetToSource: newMap,
+	}
+}
+
+// markTargetEpoch records sourceEpoch for targetEpoch in the history, clearing any epochs
+// skipped since the latest written epoch, in the same manner as the validator client does when
+// signing an attestation.
+func markTargetEpoch(history *slashpb.AttestationHistory, sourceEpoch uint64, targetEpoch uint64) *slashpb.AttestationHistory {
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	if targetEpoch > history.LatestEpochWritten {
+		maxToWrite := history.LatestEpochWritten + wsPeriod
+		for i := history.LatestEpochWritten + 1; i < targetEpoch && i <= maxToWrite; i++ {
+			history.TargetToSource[i%wsPeriod] = params.BeaconConfig().FarFutureEpoch
+		}
+		history.LatestEpochWritten = targetEpoch
+	}
+	history.TargetToSource[targetEpoch%wsPeriod] = sourceEpoch
+	return history
+}
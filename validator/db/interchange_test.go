@@ -0,0 +1,199 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestExportImportSlashingProtection_RoundTrip(t *testing.T) {
+	pubKey := [48]byte{1}
+	source := SetupDB(t, [][48]byte{pubKey})
+	defer TeardownDB(t, source)
+	ctx := context.Background()
+
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(3, true)
+	if err := source.SaveProposalHistoryForEpoch(ctx, pubKey[:], 2, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	history := newAttestationHistory()
+	history = markTargetEpoch(history, 1, 2)
+	history = markTargetEpoch(history, 2, 3)
+	if err := source.SaveAttestationHistory(ctx, pubKey[:], history); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := source.ExportSlashingProtection(ctx, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	target := SetupDB(t, [][48]byte{})
+	defer TeardownDB(t, target)
+	if err := target.ImportSlashingProtection(ctx, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := target.ProposalHistoryForEpoch(ctx, pubKey[:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !imported.BitAt(3) {
+		t.Error("Expected imported proposal history to contain slot 3 of epoch 2")
+	}
+	importedAtts, err := target.AttestationHistory(ctx, pubKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if importedAtts.LatestEpochWritten != 3 {
+		t.Errorf("Expected latest epoch written to be 3, received %d", importedAtts.LatestEpochWritten)
+	}
+	if importedAtts.TargetToSource[2] != 1 || importedAtts.TargetToSource[3] != 2 {
+		t.Errorf("Unexpected imported attestation history %v", importedAtts.TargetToSource)
+	}
+	if importedAtts.TargetToSource[1] != params.BeaconConfig().FarFutureEpoch {
+		t.Errorf("Expected target epoch 1 to not be marked as attested for, received %d", importedAtts.TargetToSource[1])
+	}
+}
+
+func TestImportSlashingProtection_MergesWithExisting(t *testing.T) {
+	pubKey := [48]byte{2}
+	db := SetupDB(t, [][48]byte{pubKey})
+	defer TeardownDB(t, db)
+	ctx := context.Background()
+
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(1, true)
+	if err := db.SaveProposalHistoryForEpoch(ctx, pubKey[:], 0, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	history := markTargetEpoch(newAttestationHistory(), 4, 5)
+	if err := db.SaveAttestationHistory(ctx, pubKey[:], history); err != nil {
+		t.Fatal(err)
+	}
+
+	interchange := fmt.Sprintf(`{
+		"version": "%s",
+		"data": [{
+			"pubkey": "%#x",
+			"latest_epoch_written": 3,
+			"signed_blocks": [{"slot": 2}],
+			"signed_attestations": [{"source_epoch": 2, "target_epoch": 3}]
+		}]
+	}`, InterchangeFormatVersion, pubKey)
+	if err := db.ImportSlashingProtection(ctx, strings.NewReader(interchange)); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := db.ProposalHistoryForEpoch(ctx, pubKey[:], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !merged.BitAt(1) || !merged.BitAt(2) {
+		t.Errorf("Expected slots 1 and 2 to be marked as proposed, received %#x", merged)
+	}
+	mergedAtts, err := db.AttestationHistory(ctx, pubKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mergedAtts.LatestEpochWritten != 5 {
+		t.Errorf("Expected latest epoch written to stay at 5, received %d", mergedAtts.LatestEpochWritten)
+	}
+	if mergedAtts.TargetToSource[3] != 2 || mergedAtts.TargetToSource[5] != 4 {
+		t.Errorf("Unexpected merged attestation history %v", mergedAtts.TargetToSource)
+	}
+	if mergedAtts.TargetToSource[4] != params.BeaconConfig().FarFutureEpoch {
+		t.Errorf("Expected target epoch 4 to not be marked as attested for, received %d", mergedAtts.TargetToSource[4])
+	}
+}
+
+func TestImportSlashingProtection_RejectsConflictingAttestation(t *testing.T) {
+	pubKey := [48]byte{3}
+	db := SetupDB(t, [][48]byte{pubKey})
+	defer TeardownDB(t, db)
+	ctx := context.Background()
+
+	history := markTargetEpoch(newAttestationHistory(), 4, 5)
+	if err := db.SaveAttestationHistory(ctx, pubKey[:], history); err != nil {
+		t.Fatal(err)
+	}
+
+	interchange := fmt.Sprintf(`{
+		"version": "%s",
+		"data": [{
+			"pubkey": "%#x",
+			"latest_epoch_written": 5,
+			"signed_blocks": [],
+			"signed_attestations": [{"source_epoch": 3, "target_epoch": 5}]
+		}]
+	}`, InterchangeFormatVersion, pubKey)
+	err := db.ImportSlashingProtection(ctx, strings.NewReader(interchange))
+	if err == nil || !strings.Contains(err.Error(), "conflicting attestations") {
+		t.Fatalf("Expected conflicting attestations error, received %v", err)
+	}
+
+	stored, err := db.AttestationHistory(ctx, pubKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.TargetToSource[5] != 4 {
+		t.Errorf("Expected stored history to be left untouched, received %v", stored.TargetToSource)
+	}
+}
+
+func TestImportSlashingProtection_RejectsUnknownVersion(t *testing.T) {
+	db := SetupDB(t, [][48]byte{})
+	defer TeardownDB(t, db)
+
+	err := db.ImportSlashingProtection(context.Background(), strings.NewReader(`{"version": "0", "data": []}`))
+	if err == nil || !strings.Contains(err.Error(), "unsupported interchange format version") {
+		t.Fatalf("Expected unsupported version error, received %v", err)
+	}
+}
+
+func TestProtectionHistory(t *testing.T) {
+	pubKey := [48]byte{4}
+	db := SetupDB(t, [][48]byte{pubKey})
+	defer TeardownDB(t, db)
+	ctx := context.Background()
+
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(2, true)
+	if err := db.SaveProposalHistoryForEpoch(ctx, pubKey[:], 1, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	history := markTargetEpoch(newAttestationHistory(), 0, 1)
+	history = markTargetEpoch(history, 1, 3)
+	if err := db.SaveAttestationHistory(ctx, pubKey[:], history); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := db.ProtectionHistory(ctx, pubKey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSlot := params.BeaconConfig().SlotsPerEpoch + 2
+	if len(h.Proposals) != 1 || h.Proposals[0].Slot != wantSlot {
+		t.Errorf("Expected a single proposal at slot %d, received %v", wantSlot, h.Proposals)
+	}
+	if h.LatestEpochWritten != 3 {
+		t.Errorf("Expected latest epoch written to be 3, received %d", h.LatestEpochWritten)
+	}
+	if len(h.Attestations) != 2 || h.Attestations[1].SourceEpoch != 1 || h.Attestations[1].TargetEpoch != 3 {
+		t.Errorf("Unexpected attestations %v", h.Attestations)
+	}
+
+	empty, err := db.ProtectionHistory(ctx, []byte{5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty.Proposals) != 0 || len(empty.Attestations) != 0 {
+		t.Errorf("Expected no history for unknown key, received %v", empty)
+	}
+}
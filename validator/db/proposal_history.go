@@ -92,6 +92,12 @@ func pruneProposalHistory(valBucket *bolt.Bucket, newestEpoch uint64) error {
 	return nil
 }
 
+// UpdatePublicKeysBuckets creates the proposal history buckets of public keys which
+// were not known when the database was opened.
+func (db *Store) UpdatePublicKeysBuckets(pubKeys [][48]byte) error {
+	return db.initializeSubBuckets(pubKeys)
+}
+
 func (db *Store) initializeSubBuckets(pubKeys [][48]byte) error {
 	return db.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(historicProposalsBucket)
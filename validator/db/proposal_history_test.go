@@ -45,6 +45,24 @@ func TestProposalHistoryForEpoch_NilDB(t *testing.T) {
 	}
 }
 
+func TestUpdatePublicKeysBuckets_InitializesNewPubKeys(t *testing.T) {
+	valPubkey := [48]byte{4, 5, 6}
+	db := SetupDB(t, [][48]byte{})
+	defer TeardownDB(t, db)
+
+	if err := db.UpdatePublicKeysBuckets([][48]byte{valPubkey}); err != nil {
+		t.Fatal(err)
+	}
+	slotBits, err := db.ProposalHistoryForEpoch(context.Background(), valPubkey[:], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	if !bytes.Equal(slotBits.Bytes(), cleanBits.Bytes()) {
+		t.Fatalf("Expected proposal history slot bits to be empty, received %v", slotBits.Bytes())
+	}
+}
+
 func TestSaveProposalHistoryForEpoch_OK(t *testing.T) {
 	pubkey := [48]byte{3}
 	db := SetupDB(t, [][48]byte{pubkey})
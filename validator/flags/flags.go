@@ -10,6 +10,34 @@ var (
 		Name:  "enable-account-metrics",
 		Usage: "Enable prometheus metrics for validator accounts",
 	}
+	// AdminRPCFlag enables the admin gRPC service and its JSON gateway.
+	AdminRPCFlag = &cli.BoolFlag{
+		Name:  "enable-admin-rpc",
+		Usage: "Serve the validator admin API, listing keys, duties and slashing protection history",
+	}
+	// AdminRPCHostFlag defines the host on which the admin gRPC service and its gateway listen.
+	AdminRPCHostFlag = &cli.StringFlag{
+		Name:  "admin-rpc-host",
+		Usage: "Host on which the admin RPC server should listen",
+		Value: "127.0.0.1",
+	}
+	// AdminRPCPortFlag defines the port of the admin gRPC service.
+	AdminRPCPortFlag = &cli.IntFlag{
+		Name:  "admin-rpc-port",
+		Usage: "Port on which the admin RPC server should listen",
+		Value: 7000,
+	}
+	// AdminRPCTokenFileFlag defines the file holding the token admin API requests must authenticate with.
+	AdminRPCTokenFileFlag = &cli.StringFlag{
+		Name:  "admin-rpc-token-file",
+		Usage: "Path to a file holding the token admin API requests must send as an \"Authorization: Bearer <token>\" header. Required when the admin API listens on a non-loopback host",
+	}
+	// AdminGatewayPortFlag defines the port of the JSON gateway of the admin gRPC service.
+	AdminGatewayPortFlag = &cli.IntFlag{
+		Name:  "admin-grpc-gateway-port",
+		Usage: "Port on which the admin JSON-HTTP gateway should listen, 0 to disable it",
+		Value: 7500,
+	}
 	// BeaconRPCProviderFlag defines a beacon node RPC endpoint.
 	BeaconRPCProviderFlag = &cli.StringFlag{
 		Name:  "beacon-rpc-provider",
@@ -51,7 +79,7 @@ var (
 	// KeyManager specifies the key manager to use.
 	KeyManager = &cli.StringFlag{
 		Name:  "keymanager",
-		Usage: "The keymanger to use (unencrypted, interop, keystore, wallet)",
+		Usage: "The keymanger to use (unencrypted, interop, keystore, wallet, remote, remote-http)",
 		Value: "",
 	}
 	// KeyManagerOpts specifies the key manager options.
@@ -81,6 +109,17 @@ var (
 		Name:  "password",
 		Usage: "String value of the password for your validator private keys",
 	}
+	// SlashingProtectionFileFlag defines the path of a slashing protection interchange JSON file
+	// to export the validator's history to or import it from.
+	SlashingProtectionFileFlag = &cli.StringFlag{
+		Name:  "slashing-protection-file",
+		Usage: "Path to a slashing protection interchange JSON file",
+	}
+	// WatchKeysFlag enables reloading the validating keys when the keymanager's key directory changes.
+	WatchKeysFlag = &cli.BoolFlag{
+		Name:  "watch-keys",
+		Usage: "Watch the keymanager's key directory and pick up added or removed keys at the next epoch",
+	}
 	// UnencryptedKeysFlag specifies a file path of a JSON file of unencrypted validator keys as an
 	// alternative from launching the validator client from decrypting a keystore directory.
 	UnencryptedKeysFlag = &cli.StringFlag{
@@ -88,4 +127,31 @@ var (
 		Usage: "Filepath to a JSON file of unencrypted validator keys for easier launching of the validator client",
 		Value: "",
 	}
+	// FallbackBeaconRPCProviderFlag defines beacon node RPC endpoints to fail over to, in order of preference.
+	FallbackBeaconRPCProviderFlag = &cli.StringSliceFlag{
+		Name: "fallback-beacon-rpc-provider",
+		Usage: "Beacon node RPC provider endpoints used, in the given order, when the beacon-rpc-provider " +
+			"is syncing, behind or unreachable",
+	}
+	// BroadcastBeaconNodesFlag sends blocks and attestations through all the healthy beacon nodes.
+	BroadcastBeaconNodesFlag = &cli.BoolFlag{
+		Name:  "broadcast-beacon-nodes",
+		Usage: "Submit blocks and attestations through all the healthy beacon nodes instead of the first one",
+	}
+	// DoppelgangerEpochsFlag defines the number of epochs to watch the chain for the validator keys
+	// being active elsewhere before signing.
+	DoppelgangerEpochsFlag = &cli.Uint64Flag{
+		Name: "doppelganger-detection-epochs",
+		Usage: "Number of epochs to wait at startup, checking whether the validator keys are attesting or " +
+			"proposing elsewhere, before signing. The validator client exits with code 3 if they all are, or only stops " +
+			"signing with the keys which are. 0 disables the detection",
+		Value: 0,
+	}
+	// DoppelgangerFailClosedFlag makes the validator client exit as soon as any of its keys is found
+	// active elsewhere.
+	DoppelgangerFailClosedFlag = &cli.BoolFlag{
+		Name: "doppelganger-fail-closed",
+		Usage: "Exit with code 3 when any validator key is found attesting or proposing elsewhere by the " +
+			"doppelganger detection, including keys loaded after startup, instead of only not signing with it",
+	}
 )
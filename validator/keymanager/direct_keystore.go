@@ -11,9 +11,13 @@ import (
 	"golang.org/x/crypto/ssh/terminal"
 )
 
+var _ = WatchableKeyManager(&Keystore{})
+
 // Keystore is a key manager that loads keys from a standard keystore.
 type Keystore struct {
 	*Direct
+	path       string
+	passphrase string
 }
 
 type keystoreOpts struct {
@@ -72,21 +76,36 @@ func NewKeystore(input string) (KeyManager, string, error) {
 		}
 	}
 
-	keyMap, err := accounts.DecryptKeysFromKeystore(opts.Path, opts.Passphrase)
-	if err != nil {
+	km := &Keystore{
+		Direct:     &Direct{},
+		path:       opts.Path,
+		passphrase: opts.Passphrase,
+	}
+	if err := km.RefreshValidatingKeys(); err != nil {
 		return nil, keystoreOptsHelp, err
 	}
+	return km, "", nil
+}
 
-	km := &Unencrypted{
-		Direct: &Direct{
-			publicKeys: make(map[[48]byte]*bls.PublicKey),
-			secretKeys: make(map[[48]byte]*bls.SecretKey),
-		},
+// RefreshValidatingKeys decrypts the keys found in the keystore directory again, replacing
+// the keys held by the key manager.
+func (km *Keystore) RefreshValidatingKeys() error {
+	keyMap, err := accounts.DecryptKeysFromKeystore(km.path, km.passphrase)
+	if err != nil {
+		return err
 	}
+	publicKeys := make(map[[48]byte]*bls.PublicKey, len(keyMap))
+	secretKeys := make(map[[48]byte]*bls.SecretKey, len(keyMap))
 	for _, key := range keyMap {
 		pubKey := bytesutil.ToBytes48(key.PublicKey.Marshal())
-		km.publicKeys[pubKey] = key.PublicKey
-		km.secretKeys[pubKey] = key.SecretKey
+		publicKeys[pubKey] = key.PublicKey
+		secretKeys[pubKey] = key.SecretKey
 	}
-	return km, "", nil
+	km.setKeys(publicKeys, secretKeys)
+	return nil
+}
+
+// KeysPath returns the keystore directory holding the key manager's keys.
+func (km *Keystore) KeysPath() string {
+	return km.path
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bls"
 )
 
@@ -37,3 +38,35 @@ type ProtectingKeyManager interface {
 	// SignAttestation signs an attestation for the validator to broadcast.
 	SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error)
 }
+
+// RefreshableKeyManager is a keymanager whose set of validating keys can change while the validator client runs.
+type RefreshableKeyManager interface {
+	// RefreshValidatingKeys reloads the list of validating keys from the keymanager's source.
+	RefreshValidatingKeys() error
+}
+
+// WatchableKeyManager is a refreshable keymanager whose keys are stored in a directory
+// that can be watched for changes.
+type WatchableKeyManager interface {
+	RefreshableKeyManager
+	// KeysPath returns the directory holding the keymanager's keys.
+	KeysPath() string
+}
+
+// DutySigningKeyManager provides typed signing for the validator duties not covered by ProtectingKeyManager.
+type DutySigningKeyManager interface {
+	// SignAggregateAndProof signs an aggregate and proof for the validator to broadcast.
+	SignAggregateAndProof(pubKey [48]byte, domain [32]byte, data *ethpb.AggregateAttestationAndProof) (*bls.Signature, error)
+
+	// SignRandaoReveal signs the randao reveal of an epoch for the validator to broadcast.
+	SignRandaoReveal(pubKey [48]byte, domain [32]byte, epoch uint64) (*bls.Signature, error)
+
+	// SignVoluntaryExit signs a voluntary exit for the validator to broadcast.
+	SignVoluntaryExit(pubKey [48]byte, domain [32]byte, data *ethpb.VoluntaryExit) (*bls.Signature, error)
+}
+
+// ForkAwareKeyManager is a keymanager that needs the chain's fork and genesis validators root to sign.
+type ForkAwareKeyManager interface {
+	// SetForkInfo sets the fork and genesis validators root of the chain being validated.
+	SetForkInfo(fork *pb.Fork, genesisValidatorsRoot []byte)
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"io/ioutil"
+	"sync"
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -16,10 +17,13 @@ import (
 	"google.golang.org/grpc/credentials"
 )
 
+var _ = RefreshableKeyManager(&Remote{})
+
 // Remote is a key manager that accesses a remote wallet daemon.
 type Remote struct {
 	paths               []string
 	conn                *grpc.ClientConn
+	accountsLock        sync.RWMutex
 	accounts            map[[48]byte]*accountInfo
 	signClientInitiator func(*grpc.ClientConn)
 }
@@ -83,32 +87,9 @@ func NewRemoteWallet(input string) (KeyManager, string, error) {
 	if opts.Certificates == nil {
 		return nil, remoteOptsHelp, errors.New("certificates are required")
 	}
-	if opts.Certificates.ClientCert == "" {
-		return nil, remoteOptsHelp, errors.New("client certificate is required")
-	}
-	if opts.Certificates.ClientKey == "" {
-		return nil, remoteOptsHelp, errors.New("client key is required")
-	}
-	clientPair, err := tls.LoadX509KeyPair(opts.Certificates.ClientCert, opts.Certificates.ClientKey)
+	tlsCfg, err := clientTLSConfig(opts.Certificates)
 	if err != nil {
-		return nil, remoteOptsHelp, errors.Wrap(err, "failed to obtain client's certificate and/or key")
-	}
-
-	// Load the CA for the server certificate if present.
-	cp := x509.NewCertPool()
-	if opts.Certificates.CACert != "" {
-		serverCA, err := ioutil.ReadFile(opts.Certificates.CACert)
-		if err != nil {
-			return nil, remoteOptsHelp, errors.Wrap(err, "failed to obtain server's CA certificate")
-		}
-		if !cp.AppendCertsFromPEM(serverCA) {
-			return nil, remoteOptsHelp, errors.Wrap(err, "failed to add server's CA certificate to pool")
-		}
-	}
-
-	tlsCfg := &tls.Config{
-		Certificates: []tls.Certificate{clientPair},
-		RootCAs:      cp,
+		return nil, remoteOptsHelp, err
 	}
 	clientCreds := credentials.NewTLS(tlsCfg)
 
@@ -135,8 +116,42 @@ func NewRemoteWallet(input string) (KeyManager, string, error) {
 	return km, remoteOptsHelp, nil
 }
 
+// clientTLSConfig builds a TLS configuration presenting the client certificate, and trusting
+// the server's certificate authority if one is provided.
+func clientTLSConfig(opts *remoteCertificateOpts) (*tls.Config, error) {
+	if opts.ClientCert == "" {
+		return nil, errors.New("client certificate is required")
+	}
+	if opts.ClientKey == "" {
+		return nil, errors.New("client key is required")
+	}
+	clientPair, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain client's certificate and/or key")
+	}
+
+	// Load the CA for the server certificate if present.
+	cp := x509.NewCertPool()
+	if opts.CACert != "" {
+		serverCA, err := ioutil.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain server's CA certificate")
+		}
+		if !cp.AppendCertsFromPEM(serverCA) {
+			return nil, errors.New("failed to add server's CA certificate to pool")
+		}
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{clientPair},
+		RootCAs:      cp,
+	}, nil
+}
+
 // FetchValidatingKeys fetches the list of public keys that should be used to validate with.
 func (km *Remote) FetchValidatingKeys() ([][48]byte, error) {
+	km.accountsLock.RLock()
+	defer km.accountsLock.RUnlock()
 	res := make([][48]byte, 0, len(km.accounts))
 	for _, accountInfo := range km.accounts {
 		res = append(res, bytesutil.ToBytes48(accountInfo.PubKey))
@@ -151,7 +166,7 @@ func (km *Remote) Sign(pubKey [48]byte, root [32]byte) (*bls.Signature, error) {
 
 // SignGeneric signs a generic message for the validator to broadcast.
 func (km *Remote) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (*bls.Signature, error) {
-	accountInfo, exists := km.accounts[pubKey]
+	accountInfo, exists := km.account(pubKey)
 	if !exists {
 		return nil, ErrNoSuchKey
 	}
@@ -177,7 +192,7 @@ func (km *Remote) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (
 
 // SignProposal signs a block proposal for the validator to broadcast.
 func (km *Remote) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (*bls.Signature, error) {
-	accountInfo, exists := km.accounts[pubKey]
+	accountInfo, exists := km.account(pubKey)
 	if !exists {
 		return nil, ErrNoSuchKey
 	}
@@ -208,7 +223,7 @@ func (km *Remote) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.Bea
 
 // SignAttestation signs an attestation for the validator to broadcast.
 func (km *Remote) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error) {
-	accountInfo, exists := km.accounts[pubKey]
+	accountInfo, exists := km.account(pubKey)
 	if !exists {
 		return nil, ErrNoSuchKey
 	}
@@ -252,7 +267,7 @@ func (km *Remote) RefreshValidatingKeys() error {
 	}
 	accountsResp, err := listerClient.ListAccounts(context.Background(), listAccountsReq)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	accounts := make(map[[48]byte]*accountInfo, len(accountsResp.Accounts))
 	for _, account := range accountsResp.Accounts {
@@ -262,6 +277,17 @@ func (km *Remote) RefreshValidatingKeys() error {
 		}
 		accounts[bytesutil.ToBytes48(account.PubKey)] = account
 	}
+	km.accountsLock.Lock()
 	km.accounts = accounts
+	km.accountsLock.Unlock()
 	return nil
 }
+
+// account returns the account of the given public key. The lock is not held while signing, so a
+// refresh of the accounts does not wait on the remote signer.
+func (km *Remote) account(pubKey [48]byte) (*accountInfo, bool) {
+	km.accountsLock.RLock()
+	defer km.accountsLock.RUnlock()
+	accountInfo, exists := km.accounts[pubKey]
+	return accountInfo, exists
+}
@@ -0,0 +1,348 @@
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+var _ = ProtectingKeyManager(&RemoteHTTP{})
+var _ = DutySigningKeyManager(&RemoteHTTP{})
+var _ = ForkAwareKeyManager(&RemoteHTTP{})
+var _ = RefreshableKeyManager(&RemoteHTTP{})
+
+// RemoteHTTP is a key manager that posts typed signing requests to a remote HTTP signer.
+type RemoteHTTP struct {
+	url                   string
+	client                *http.Client
+	timeout               time.Duration
+	keysLock              sync.RWMutex
+	keys                  map[[48]byte]bool
+	forkLock              sync.RWMutex
+	fork                  *pb.Fork
+	genesisValidatorsRoot []byte
+	done                  chan struct{}
+	closeOnce             sync.Once
+}
+
+type remoteHTTPOpts struct {
+	URL             string                 `json:"url"`
+	Timeout         string                 `json:"timeout"`
+	RefreshInterval string                 `json:"refresh_interval"`
+	Certificates    *remoteCertificateOpts `json:"certificates"`
+}
+
+var remoteHTTPOptsHelp = `The remote-http key manager sends signing requests to an HTTP signer.  The options are:
+  - url This is the base URL of the signer.  Public keys are listed with a GET
+    request to <url>/keys and signatures are requested with a POST to <url>/sign.
+  - timeout This is the maximum duration of a request to the signer, for
+    example "2s".  Defaults to 5s.
+  - refresh_interval This is how often the list of public keys is refreshed
+    from the signer, for example "1m".  If not supplied the list is only
+    fetched at startup.
+  - certificates This provides paths to certificates for mutual TLS:
+    - ca_cert This is the path to the server's certificate authority certificate file
+    - client_cert This is the path to the client's certificate file
+    - client_key This is the path to the client's key file
+
+An sample keymanager options file (with annotations; these should be removed if
+using this as a template) is:
+
+  {
+    "url":              "https://signer.example.com:9000", // Send requests to the signer at signer.example.com
+    "timeout":          "2s",                              // Give up on a request after 2 seconds
+    "refresh_interval": "1m",                              // Refresh the list of public keys every minute
+    "certificates": {
+      "ca_cert": "/home/eth2/certs/ca.crt"         // Certificate file for the CA that signed the server's certificate
+      "client_cert": "/home/eth2/certs/client.crt" // Certificate file for this client
+      "client_key": "/home/eth2/certs/client.key"  // Key file for this client
+    }
+  }`
+
+// Signing request types understood by the remote HTTP signer.
+const (
+	SignTypeBlockHeader       = "block_header"
+	SignTypeAttestationData   = "attestation_data"
+	SignTypeAggregateAndProof = "aggregate_and_proof"
+	SignTypeRandaoReveal      = "randao_reveal"
+	SignTypeVoluntaryExit     = "voluntary_exit"
+	SignTypeGeneric           = "generic"
+)
+
+const defaultRemoteHTTPTimeout = 5 * time.Second
+
+// NewRemoteHTTP creates a key manager populated with the keys listed by a remote HTTP signer.
+func NewRemoteHTTP(input string) (KeyManager, string, error) {
+	opts := &remoteHTTPOpts{}
+	if err := json.Unmarshal([]byte(input), opts); err != nil {
+		return nil, remoteHTTPOptsHelp, err
+	}
+	if opts.URL == "" {
+		return nil, remoteHTTPOptsHelp, errors.New("url is required")
+	}
+
+	timeout := defaultRemoteHTTPTimeout
+	if opts.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(opts.Timeout)
+		if err != nil {
+			return nil, remoteHTTPOptsHelp, errors.Wrap(err, "invalid timeout")
+		}
+	}
+	var refreshInterval time.Duration
+	if opts.RefreshInterval != "" {
+		var err error
+		refreshInterval, err = time.ParseDuration(opts.RefreshInterval)
+		if err != nil {
+			return nil, remoteHTTPOptsHelp, errors.Wrap(err, "invalid refresh interval")
+		}
+	}
+
+	transport := &http.Transport{}
+	if opts.Certificates != nil {
+		tlsCfg, err := clientTLSConfig(opts.Certificates)
+		if err != nil {
+			return nil, remoteHTTPOptsHelp, err
+		}
+		transport.TLSClientConfig = tlsCfg
+	}
+
+	km := NewRemoteHTTPWithClient(opts.URL, &http.Client{Transport: transport}, timeout)
+	if err := km.RefreshValidatingKeys(); err != nil {
+		return nil, remoteHTTPOptsHelp, errors.Wrap(err, "failed to fetch keys from remote signer")
+	}
+	if refreshInterval > 0 {
+		go km.refreshKeysLoop(refreshInterval)
+	}
+
+	return km, remoteHTTPOptsHelp, nil
+}
+
+// NewRemoteHTTPWithClient creates a remote HTTP key manager using the given HTTP client.
+// The list of validating keys is empty until RefreshValidatingKeys is called.
+func NewRemoteHTTPWithClient(url string, client *http.Client, timeout time.Duration) *RemoteHTTP {
+	return &RemoteHTTP{
+		url:     strings.TrimSuffix(url, "/"),
+		client:  client,
+		timeout: timeout,
+		keys:    make(map[[48]byte]bool),
+		done:    make(chan struct{}),
+	}
+}
+
+// Close stops refreshing the list of validating keys.
+func (km *RemoteHTTP) Close() error {
+	km.closeOnce.Do(func() {
+		close(km.done)
+	})
+	return nil
+}
+
+// SetForkInfo sets the fork and genesis validators root that are sent along with every signing request.
+func (km *RemoteHTTP) SetForkInfo(fork *pb.Fork, genesisValidatorsRoot []byte) {
+	km.forkLock.Lock()
+	defer km.forkLock.Unlock()
+	km.fork = fork
+	km.genesisValidatorsRoot = genesisValidatorsRoot
+}
+
+// FetchValidatingKeys fetches the list of public keys that should be used to validate with.
+func (km *RemoteHTTP) FetchValidatingKeys() ([][48]byte, error) {
+	km.keysLock.RLock()
+	defer km.keysLock.RUnlock()
+	res := make([][48]byte, 0, len(km.keys))
+	for key := range km.keys {
+		res = append(res, key)
+	}
+	return res, nil
+}
+
+// RefreshValidatingKeys refreshes the list of validating keys from the remote signer.
+func (km *RemoteHTTP) RefreshValidatingKeys() error {
+	ctx, cancel := context.WithTimeout(context.Background(), km.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, km.url+"/keys", nil)
+	if err != nil {
+		return err
+	}
+	resp := &remoteHTTPKeysResponse{}
+	if err := km.do(req.WithContext(ctx), resp); err != nil {
+		return err
+	}
+	keys := make(map[[48]byte]bool, len(resp.Keys))
+	for _, key := range resp.Keys {
+		if len(key) != 48 {
+			return fmt.Errorf("invalid public key %#x returned by remote signer", []byte(key))
+		}
+		keys[bytesutil.ToBytes48(key)] = true
+	}
+	km.keysLock.Lock()
+	km.keys = keys
+	km.keysLock.Unlock()
+	return nil
+}
+
+func (km *RemoteHTTP) refreshKeysLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := km.RefreshValidatingKeys(); err != nil {
+				log.WithError(err).Warn("Could not refresh keys from remote signer")
+			}
+		case <-km.done:
+			return
+		}
+	}
+}
+
+// Sign without protection is not supported by remote keymanagers.
+func (km *RemoteHTTP) Sign(pubKey [48]byte, root [32]byte) (*bls.Signature, error) {
+	return nil, errors.New("remote keymanager does not support unprotected signing")
+}
+
+// SignGeneric signs a generic root for the validator to broadcast.
+func (km *RemoteHTTP) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (*bls.Signature, error) {
+	signingRoot, err := ssz.HashTreeRoot(&pb.SigningRoot{ObjectRoot: root[:], Domain: domain[:]})
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, SignTypeGeneric, domain, signingRoot, &remoteHTTPGeneric{ObjectRoot: root[:]})
+}
+
+// SignProposal signs a block proposal for the validator to broadcast.
+func (km *RemoteHTTP) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, SignTypeBlockHeader, domain, signingRoot, blockHeaderToHTTP(data))
+}
+
+// SignAttestation signs an attestation for the validator to broadcast.
+func (km *RemoteHTTP) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, SignTypeAttestationData, domain, signingRoot, attestationDataToHTTP(data))
+}
+
+// SignAggregateAndProof signs an aggregate and proof for the validator to broadcast.
+func (km *RemoteHTTP) SignAggregateAndProof(pubKey [48]byte, domain [32]byte, data *ethpb.AggregateAttestationAndProof) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, SignTypeAggregateAndProof, domain, signingRoot, aggregateAndProofToHTTP(data))
+}
+
+// SignRandaoReveal signs the randao reveal of an epoch for the validator to broadcast.
+func (km *RemoteHTTP) SignRandaoReveal(pubKey [48]byte, domain [32]byte, epoch uint64) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(epoch, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, SignTypeRandaoReveal, domain, signingRoot, &remoteHTTPRandaoReveal{Epoch: epoch})
+}
+
+// SignVoluntaryExit signs a voluntary exit for the validator to broadcast.
+func (km *RemoteHTTP) SignVoluntaryExit(pubKey [48]byte, domain [32]byte, data *ethpb.VoluntaryExit) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, SignTypeVoluntaryExit, domain, signingRoot, &remoteHTTPVoluntaryExit{
+		Epoch:          data.Epoch,
+		ValidatorIndex: data.ValidatorIndex,
+	})
+}
+
+func (km *RemoteHTTP) sign(pubKey [48]byte, signType string, domain [32]byte, signingRoot [32]byte, data interface{}) (*bls.Signature, error) {
+	km.keysLock.RLock()
+	exists := km.keys[pubKey]
+	km.keysLock.RUnlock()
+	if !exists {
+		return nil, ErrNoSuchKey
+	}
+
+	encData, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode signing data")
+	}
+	signReq := &remoteHTTPSignRequest{
+		Type:        signType,
+		PublicKey:   pubKey[:],
+		Domain:      domain[:],
+		SigningRoot: signingRoot[:],
+		Data:        encData,
+	}
+	km.forkLock.RLock()
+	if km.fork != nil {
+		signReq.Fork = &remoteHTTPFork{
+			PreviousVersion: km.fork.PreviousVersion,
+			CurrentVersion:  km.fork.CurrentVersion,
+			Epoch:           km.fork.Epoch,
+		}
+	}
+	signReq.GenesisValidatorsRoot = km.genesisValidatorsRoot
+	km.forkLock.RUnlock()
+
+	body, err := json.Marshal(signReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode signing request")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), km.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, km.url+"/sign", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp := &remoteHTTPSignResponse{}
+	if err := km.do(req.WithContext(ctx), resp); err != nil {
+		return nil, err
+	}
+	return bls.SignatureFromBytes(resp.Signature)
+}
+
+// do sends the request to the remote signer and decodes its JSON response into res.
+func (km *RemoteHTTP) do(req *http.Request, res interface{}) error {
+	resp, err := km.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "request to remote signer failed")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	switch {
+	case resp.StatusCode == http.StatusForbidden:
+		return ErrDenied
+	case resp.StatusCode == http.StatusNotFound && req.Method == http.MethodPost:
+		return ErrNoSuchKey
+	case resp.StatusCode != http.StatusOK:
+		msg, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return ErrCannotSign
+		}
+		return errors.Wrapf(ErrCannotSign, "remote signer returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
@@ -0,0 +1,159 @@
+package keymanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+// inProcessSigner is an in-process stand-in for a remote HTTP signer, to be served with
+// net/http/httptest. It holds its secret keys in memory, and only signs a request
+// if its signing root matches the typed data and domain in the request.
+type inProcessSigner struct {
+	lock        sync.Mutex
+	secretKeys  map[[48]byte]*bls.SecretKey
+	lastRequest *remoteHTTPSignRequest
+}
+
+// newInProcessSigner creates a stand-in remote signer holding the given secret keys.
+func newInProcessSigner(sks []*bls.SecretKey) *inProcessSigner {
+	s := &inProcessSigner{
+		secretKeys: make(map[[48]byte]*bls.SecretKey),
+	}
+	for _, sk := range sks {
+		s.AddKey(sk)
+	}
+	return s
+}
+
+// AddKey adds a secret key to the signer.
+func (s *inProcessSigner) AddKey(sk *bls.SecretKey) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.secretKeys[bytesutil.ToBytes48(sk.PublicKey().Marshal())] = sk
+}
+
+// RemoveKey removes the secret key of the given public key from the signer.
+func (s *inProcessSigner) RemoveKey(pubKey [48]byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.secretKeys, pubKey)
+}
+
+// LastRequest returns the last signing request received by the signer.
+func (s *inProcessSigner) LastRequest() *remoteHTTPSignRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lastRequest
+}
+
+// ServeHTTP handles key listing and signing requests.
+func (s *inProcessSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/keys":
+		s.serveKeys(w)
+	case r.Method == http.MethodPost && r.URL.Path == "/sign":
+		s.serveSign(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *inProcessSigner) serveKeys(w http.ResponseWriter) {
+	s.lock.Lock()
+	resp := &remoteHTTPKeysResponse{Keys: make([]hexBytes, 0, len(s.secretKeys))}
+	for pubKey := range s.secretKeys {
+		key := pubKey
+		resp.Keys = append(resp.Keys, key[:])
+	}
+	s.lock.Unlock()
+	writeJSON(w, resp)
+}
+
+func (s *inProcessSigner) serveSign(w http.ResponseWriter, r *http.Request) {
+	req := &remoteHTTPSignRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.lock.Lock()
+	s.lastRequest = req
+	sk, ok := s.secretKeys[bytesutil.ToBytes48(req.PublicKey)]
+	s.lock.Unlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("no such key %#x", []byte(req.PublicKey)), http.StatusNotFound)
+		return
+	}
+
+	root, err := signingRootOf(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if root != bytesutil.ToBytes32(req.SigningRoot) {
+		http.Error(w, "signing root does not match request data", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, &remoteHTTPSignResponse{Signature: sk.Sign(root[:]).Marshal()})
+}
+
+// signingRootOf recomputes the signing root of a request from its typed data.
+func signingRootOf(req *remoteHTTPSignRequest) ([32]byte, error) {
+	domain := req.Domain
+	switch req.Type {
+	case SignTypeBlockHeader:
+		data := &remoteHTTPBlockHeader{}
+		if err := json.Unmarshal(req.Data, data); err != nil {
+			return [32]byte{}, err
+		}
+		return helpers.ComputeSigningRoot(data.toProto(), domain)
+	case SignTypeAttestationData:
+		data := &remoteHTTPAttestationData{}
+		if err := json.Unmarshal(req.Data, data); err != nil {
+			return [32]byte{}, err
+		}
+		return helpers.ComputeSigningRoot(data.toProto(), domain)
+	case SignTypeAggregateAndProof:
+		data := &remoteHTTPAggregateAndProof{}
+		if err := json.Unmarshal(req.Data, data); err != nil {
+			return [32]byte{}, err
+		}
+		return helpers.ComputeSigningRoot(data.toProto(), domain)
+	case SignTypeRandaoReveal:
+		data := &remoteHTTPRandaoReveal{}
+		if err := json.Unmarshal(req.Data, data); err != nil {
+			return [32]byte{}, err
+		}
+		return helpers.ComputeSigningRoot(data.Epoch, domain)
+	case SignTypeVoluntaryExit:
+		data := &remoteHTTPVoluntaryExit{}
+		if err := json.Unmarshal(req.Data, data); err != nil {
+			return [32]byte{}, err
+		}
+		return helpers.ComputeSigningRoot(&ethpb.VoluntaryExit{Epoch: data.Epoch, ValidatorIndex: data.ValidatorIndex}, domain)
+	case SignTypeGeneric:
+		data := &remoteHTTPGeneric{}
+		if err := json.Unmarshal(req.Data, data); err != nil {
+			return [32]byte{}, err
+		}
+		return ssz.HashTreeRoot(&pb.SigningRoot{ObjectRoot: data.ObjectRoot, Domain: domain})
+	default:
+		return [32]byte{}, fmt.Errorf("unknown signing request type %q", req.Type)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, res interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
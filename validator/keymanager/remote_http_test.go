@@ -0,0 +1,221 @@
+package keymanager
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+func setupRemoteHTTP(t *testing.T, sks []*bls.SecretKey) (*RemoteHTTP, *inProcessSigner, func()) {
+	signer := newInProcessSigner(sks)
+	srv := httptest.NewServer(signer)
+	km := NewRemoteHTTPWithClient(srv.URL, srv.Client(), time.Second)
+	if err := km.RefreshValidatingKeys(); err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	return km, signer, srv.Close
+}
+
+func TestRemoteHTTP_SignsEveryDuty(t *testing.T) {
+	sk := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	km, _, cleanup := setupRemoteHTTP(t, []*bls.SecretKey{sk})
+	defer cleanup()
+
+	domain := [32]byte{1, 2, 3}
+	attData := &ethpb.AttestationData{
+		Slot:            5,
+		CommitteeIndex:  1,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Epoch: 0, Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+	}
+	header := &ethpb.BeaconBlockHeader{
+		Slot:       10,
+		ParentRoot: make([]byte, 32),
+		StateRoot:  make([]byte, 32),
+		BodyRoot:   make([]byte, 32),
+	}
+	aggregate := &ethpb.AggregateAttestationAndProof{
+		AggregatorIndex: 3,
+		Aggregate: &ethpb.Attestation{
+			AggregationBits: bitfield.Bitlist{0b1101},
+			Data:            attData,
+			Signature:       make([]byte, 96),
+		},
+		SelectionProof: make([]byte, 96),
+	}
+	exit := &ethpb.VoluntaryExit{Epoch: 4, ValidatorIndex: 3}
+
+	tests := []struct {
+		name   string
+		object interface{}
+		sign   func() (*bls.Signature, error)
+	}{
+		{
+			name:   "block header",
+			object: header,
+			sign: func() (*bls.Signature, error) {
+				return km.SignProposal(pubKey, domain, header)
+			},
+		},
+		{
+			name:   "attestation data",
+			object: attData,
+			sign: func() (*bls.Signature, error) {
+				return km.SignAttestation(pubKey, domain, attData)
+			},
+		},
+		{
+			name:   "aggregate and proof",
+			object: aggregate,
+			sign: func() (*bls.Signature, error) {
+				return km.SignAggregateAndProof(pubKey, domain, aggregate)
+			},
+		},
+		{
+			name:   "randao reveal",
+			object: uint64(7),
+			sign: func() (*bls.Signature, error) {
+				return km.SignRandaoReveal(pubKey, domain, 7)
+			},
+		},
+		{
+			name:   "voluntary exit",
+			object: exit,
+			sign: func() (*bls.Signature, error) {
+				return km.SignVoluntaryExit(pubKey, domain, exit)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := tt.sign()
+			if err != nil {
+				t.Fatal(err)
+			}
+			root, err := helpers.ComputeSigningRoot(tt.object, domain[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !sig.Verify(root[:], sk.PublicKey()) {
+				t.Error("Signature did not verify")
+			}
+		})
+	}
+}
+
+func TestRemoteHTTP_SendsForkInfo(t *testing.T) {
+	sk := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	km, signer, cleanup := setupRemoteHTTP(t, []*bls.SecretKey{sk})
+	defer cleanup()
+
+	genesisValidatorsRoot := bytesutil.PadTo([]byte("root"), 32)
+	km.SetForkInfo(&pb.Fork{
+		PreviousVersion: []byte{0, 0, 0, 1},
+		CurrentVersion:  []byte{0, 0, 0, 2},
+		Epoch:           3,
+	}, genesisValidatorsRoot)
+	if _, err := km.SignRandaoReveal(pubKey, [32]byte{}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	req := signer.LastRequest()
+	if req.Fork == nil || req.Fork.Epoch != 3 || string(req.Fork.CurrentVersion) != string([]byte{0, 0, 0, 2}) {
+		t.Errorf("Unexpected fork in signing request: %v", req.Fork)
+	}
+	if string(req.GenesisValidatorsRoot) != string(genesisValidatorsRoot) {
+		t.Errorf("Expected genesis validators root %#x, received %#x", genesisValidatorsRoot, []byte(req.GenesisValidatorsRoot))
+	}
+}
+
+func TestRemoteHTTP_RefreshValidatingKeys(t *testing.T) {
+	sk1 := bls.RandKey()
+	sk2 := bls.RandKey()
+	pubKey2 := bytesutil.ToBytes48(sk2.PublicKey().Marshal())
+	km, signer, cleanup := setupRemoteHTTP(t, []*bls.SecretKey{sk1})
+	defer cleanup()
+
+	if _, err := km.SignRandaoReveal(pubKey2, [32]byte{}, 1); err != ErrNoSuchKey {
+		t.Fatalf("Expected %v, received %v", ErrNoSuchKey, err)
+	}
+
+	signer.AddKey(sk2)
+	if err := km.RefreshValidatingKeys(); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys after refresh, received %d", len(keys))
+	}
+	if _, err := km.SignRandaoReveal(pubKey2, [32]byte{}, 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoteHTTP_Timeout(t *testing.T) {
+	sk := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	signer := newInProcessSigner([]*bls.SecretKey{sk})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sign" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		signer.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	km := NewRemoteHTTPWithClient(srv.URL, srv.Client(), 50*time.Millisecond)
+	if err := km.RefreshValidatingKeys(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := km.SignRandaoReveal(pubKey, [32]byte{}, 1); err == nil {
+		t.Error("Expected signing request to time out")
+	}
+}
+
+func TestNewRemoteHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "Bad JSON",
+			input: "{",
+			err:   "unexpected end of JSON input",
+		},
+		{
+			name:  "Missing URL",
+			input: `{}`,
+			err:   "url is required",
+		},
+		{
+			name:  "Bad timeout",
+			input: `{"url":"http://localhost:1","timeout":"soon"}`,
+			err:   "invalid timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewRemoteHTTP(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error %q, received %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package keymanager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// hexBytes is a byte slice encoded in JSON as a 0x-prefixed hex string.
+type hexBytes []byte
+
+// MarshalJSON encodes the bytes as a 0x-prefixed hex string.
+func (b hexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%#x", []byte(b)))
+}
+
+// UnmarshalJSON decodes a 0x-prefixed hex string.
+func (b *hexBytes) UnmarshalJSON(input []byte) error {
+	var str string
+	if err := json.Unmarshal(input, &str); err != nil {
+		return err
+	}
+	dec, err := hex.DecodeString(strings.TrimPrefix(str, "0x"))
+	if err != nil {
+		return err
+	}
+	*b = dec
+	return nil
+}
+
+type remoteHTTPKeysResponse struct {
+	Keys []hexBytes `json:"keys"`
+}
+
+type remoteHTTPSignRequest struct {
+	Type                  string          `json:"type"`
+	PublicKey             hexBytes        `json:"pubkey"`
+	Domain                hexBytes        `json:"domain"`
+	Fork                  *remoteHTTPFork `json:"fork,omitempty"`
+	GenesisValidatorsRoot hexBytes        `json:"genesis_validators_root,omitempty"`
+	SigningRoot           hexBytes        `json:"signing_root"`
+	Data                  json.RawMessage `json:"data"`
+}
+
+type remoteHTTPSignResponse struct {
+	Signature hexBytes `json:"signature"`
+}
+
+type remoteHTTPFork struct {
+	PreviousVersion hexBytes `json:"previous_version"`
+	CurrentVersion  hexBytes `json:"current_version"`
+	Epoch           uint64   `json:"epoch"`
+}
+
+type remoteHTTPGeneric struct {
+	ObjectRoot hexBytes `json:"object_root"`
+}
+
+type remoteHTTPRandaoReveal struct {
+	Epoch uint64 `json:"epoch"`
+}
+
+type remoteHTTPVoluntaryExit struct {
+	Epoch          uint64 `json:"epoch"`
+	ValidatorIndex uint64 `json:"validator_index"`
+}
+
+type remoteHTTPBlockHeader struct {
+	Slot       uint64   `json:"slot"`
+	ParentRoot hexBytes `json:"parent_root"`
+	StateRoot  hexBytes `json:"state_root"`
+	BodyRoot   hexBytes `json:"body_root"`
+}
+
+type remoteHTTPCheckpoint struct {
+	Epoch uint64   `json:"epoch"`
+	Root  hexBytes `json:"root"`
+}
+
+type remoteHTTPAttestationData struct {
+	Slot            uint64                `json:"slot"`
+	CommitteeIndex  uint64                `json:"index"`
+	BeaconBlockRoot hexBytes              `json:"beacon_block_root"`
+	Source          *remoteHTTPCheckpoint `json:"source"`
+	Target          *remoteHTTPCheckpoint `json:"target"`
+}
+
+type remoteHTTPAttestation struct {
+	AggregationBits hexBytes                   `json:"aggregation_bits"`
+	Data            *remoteHTTPAttestationData `json:"data"`
+	Signature       hexBytes                   `json:"signature"`
+}
+
+type remoteHTTPAggregateAndProof struct {
+	AggregatorIndex uint64                 `json:"aggregator_index"`
+	Aggregate       *remoteHTTPAttestation `json:"aggregate"`
+	SelectionProof  hexBytes               `json:"selection_proof"`
+}
+
+func blockHeaderToHTTP(h *ethpb.BeaconBlockHeader) *remoteHTTPBlockHeader {
+	return &remoteHTTPBlockHeader{
+		Slot:       h.Slot,
+		ParentRoot: h.ParentRoot,
+		StateRoot:  h.StateRoot,
+		BodyRoot:   h.BodyRoot,
+	}
+}
+
+func (h *remoteHTTPBlockHeader) toProto() *ethpb.BeaconBlockHeader {
+	return &ethpb.BeaconBlockHeader{
+		Slot:       h.Slot,
+		ParentRoot: h.ParentRoot,
+		StateRoot:  h.StateRoot,
+		BodyRoot:   h.BodyRoot,
+	}
+}
+
+func attestationDataToHTTP(d *ethpb.AttestationData) *remoteHTTPAttestationData {
+	return &remoteHTTPAttestationData{
+		Slot:            d.Slot,
+		CommitteeIndex:  d.CommitteeIndex,
+		BeaconBlockRoot: d.BeaconBlockRoot,
+		Source: &remoteHTTPCheckpoint{
+			Epoch: d.Source.Epoch,
+			Root:  d.Source.Root,
+		},
+		Target: &remoteHTTPCheckpoint{
+			Epoch: d.Target.Epoch,
+			Root:  d.Target.Root,
+		},
+	}
+}
+
+func (d *remoteHTTPAttestationData) toProto() *ethpb.AttestationData {
+	data := &ethpb.AttestationData{
+		Slot:            d.Slot,
+		CommitteeIndex:  d.CommitteeIndex,
+		BeaconBlockRoot: d.BeaconBlockRoot,
+		Source:          &ethpb.Checkpoint{},
+		Target:          &ethpb.Checkpoint{},
+	}
+	if d.Source != nil {
+		data.Source = &ethpb.Checkpoint{Epoch: d.Source.Epoch, Root: d.Source.Root}
+	}
+	if d.Target != nil {
+		data.Target = &ethpb.Checkpoint{Epoch: d.Target.Epoch, Root: d.Target.Root}
+	}
+	return data
+}
+
+func aggregateAndProofToHTTP(a *ethpb.AggregateAttestationAndProof) *remoteHTTPAggregateAndProof {
+	return &remoteHTTPAggregateAndProof{
+		AggregatorIndex: a.AggregatorIndex,
+		Aggregate: &remoteHTTPAttestation{
+			AggregationBits: hexBytes(a.Aggregate.AggregationBits),
+			Data:            attestationDataToHTTP(a.Aggregate.Data),
+			Signature:       a.Aggregate.Signature,
+		},
+		SelectionProof: a.SelectionProof,
+	}
+}
+
+func (a *remoteHTTPAggregateAndProof) toProto() *ethpb.AggregateAttestationAndProof {
+	aggregate := &ethpb.Attestation{Data: &ethpb.AttestationData{}}
+	if a.Aggregate != nil {
+		aggregate.AggregationBits = bitfield.Bitlist(a.Aggregate.AggregationBits)
+		aggregate.Signature = a.Aggregate.Signature
+		if a.Aggregate.Data != nil {
+			aggregate.Data = a.Aggregate.Data.toProto()
+		}
+	}
+	return &ethpb.AggregateAttestationAndProof{
+		AggregatorIndex: a.AggregatorIndex,
+		Aggregate:       aggregate,
+		SelectionProof:  a.SelectionProof,
+	}
+}
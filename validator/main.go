@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
 	runtimeDebug "runtime/debug"
 
 	joonix "github.com/joonix/log"
+	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
@@ -14,6 +16,8 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/node"
 	"github.com/sirupsen/logrus"
@@ -30,12 +34,89 @@ func startNode(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	validatorClient.Start()
+	if err := validatorClient.Start(); err != nil {
+		if _, ok := err.(*client.DoppelgangerError); ok {
+			log.WithError(err).Error("Refusing to sign with keys active elsewhere, stop the other validator client before restarting")
+			os.Exit(client.DoppelgangerExitCode)
+		}
+		return err
+	}
+	return nil
+}
+
+func openValidatorDB(ctx *cli.Context) (*db.Store, string, error) {
+	dataDir := ctx.String(cmd.DataDirFlag.Name)
+	if dataDir == "" {
+		dataDir = cmd.DefaultDataDir()
+	}
+	filePath := ctx.String(flags.SlashingProtectionFileFlag.Name)
+	if filePath == "" {
+		return nil, "", fmt.Errorf("%s is required", flags.SlashingProtectionFileFlag.Name)
+	}
+	valDB, err := db.NewKVStore(dataDir, nil)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "could not open DB in dir %s", dataDir)
+	}
+	return valDB, filePath, nil
+}
+
+func exportSlashingProtection(ctx *cli.Context) error {
+	valDB, filePath, err := openValidatorDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := valDB.Close(); err != nil {
+			log.WithError(err).Error("Failed to close validator DB")
+		}
+	}()
+
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "could not create file %s", filePath)
+	}
+	if err := valDB.ExportSlashingProtection(context.Background(), f); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "could not export slashing protection history")
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	log.WithField("file", filePath).Info("Exported slashing protection history")
+	return nil
+}
+
+func importSlashingProtection(ctx *cli.Context) error {
+	valDB, filePath, err := openValidatorDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := valDB.Close(); err != nil {
+			log.WithError(err).Error("Failed to close validator DB")
+		}
+	}()
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "could not open file %s", filePath)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Failed to close slashing protection file")
+		}
+	}()
+	if err := valDB.ImportSlashingProtection(context.Background(), f); err != nil {
+		return errors.Wrap(err, "could not import slashing protection history")
+	}
+	log.WithField("file", filePath).Info("Imported slashing protection history")
 	return nil
 }
 
 var appFlags = []cli.Flag{
 	flags.BeaconRPCProviderFlag,
+	flags.FallbackBeaconRPCProviderFlag,
+	flags.BroadcastBeaconNodesFlag,
 	flags.CertFlag,
 	flags.GraffitiFlag,
 	flags.KeystorePathFlag,
@@ -50,6 +131,14 @@ var appFlags = []cli.Flag{
 	flags.KeyManager,
 	flags.KeyManagerOpts,
 	flags.AccountMetricsFlag,
+	flags.WatchKeysFlag,
+	flags.DoppelgangerEpochsFlag,
+	flags.DoppelgangerFailClosedFlag,
+	flags.AdminRPCFlag,
+	flags.AdminRPCHostFlag,
+	flags.AdminRPCPortFlag,
+	flags.AdminRPCTokenFileFlag,
+	flags.AdminGatewayPortFlag,
 	cmd.VerbosityFlag,
 	cmd.DataDirFlag,
 	cmd.ClearDB,
@@ -135,6 +224,37 @@ contract in order to activate the validator client`,
 				},
 			},
 		},
+		{
+			Name:     "db",
+			Category: "db",
+			Usage:    "defines commands for managing the validator client's slashing protection database",
+			Subcommands: []*cli.Command{
+				{
+					Name: "export",
+					Description: `exports the proposal and attestation history of every key in the validator's
+slashing protection database to an interchange JSON file`,
+					Flags: []cli.Flag{
+						cmd.DataDirFlag,
+						flags.SlashingProtectionFileFlag,
+					},
+					Action: func(ctx *cli.Context) error {
+						return exportSlashingProtection(ctx)
+					},
+				},
+				{
+					Name: "import",
+					Description: `imports the proposal and attestation history from an interchange JSON file into the
+validator's slashing protection database, merging it with the history already stored`,
+					Flags: []cli.Flag{
+						cmd.DataDirFlag,
+						flags.SlashingProtectionFileFlag,
+					},
+					Action: func(ctx *cli.Context) error {
+						return importSlashingProtection(ctx)
+					},
+				},
+			},
+		},
 	}
 	app.Flags = appFlags
 
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/signal"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/rpc"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/urfave/cli.v2"
 )
@@ -111,11 +113,18 @@ func NewValidatorClient(ctx *cli.Context) (*ValidatorClient, error) {
 		return nil, err
 	}
 
+	if ctx.Bool(flags.AdminRPCFlag.Name) {
+		if err := ValidatorClient.registerAdminRPCService(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	return ValidatorClient, nil
 }
 
-// Start every service in the validator client.
-func (s *ValidatorClient) Start() {
+// Start every service in the validator client. It returns once the validator client is
+// stopped, with the error of the validator routine if it stopped the client.
+func (s *ValidatorClient) Start() error {
 	s.lock.Lock()
 
 	log.WithFields(logrus.Fields{
@@ -125,6 +134,11 @@ func (s *ValidatorClient) Start() {
 	s.services.StartAll()
 
 	stop := s.stop
+	var validatorService *client.ValidatorService
+	if err := s.services.FetchService(&validatorService); err != nil {
+		s.lock.Unlock()
+		return err
+	}
 	s.lock.Unlock()
 
 	go func() {
@@ -144,8 +158,15 @@ func (s *ValidatorClient) Start() {
 		panic("Panic closing the sharding validator")
 	}()
 
-	// Wait for stop channel to be closed.
-	<-stop
+	// Wait for stop channel to be closed, or for the validator routine to fail.
+	select {
+	case <-stop:
+		return nil
+	case err := <-validatorService.Err():
+		log.WithError(err).Error("Validator routine stopped, shutting down")
+		s.Close()
+		return err
+	}
 }
 
 // Close handles graceful shutdown of the system.
@@ -179,6 +200,8 @@ func (s *ValidatorClient) registerClientService(ctx *cli.Context, keyManager key
 	grpcRetries := ctx.Uint(flags.GrpcRetriesFlag.Name)
 	v, err := client.NewValidatorService(context.Background(), &client.Config{
 		Endpoint:                   endpoint,
+		FallbackEndpoints:          ctx.StringSlice(flags.FallbackBeaconRPCProviderFlag.Name),
+		Broadcast:                  ctx.Bool(flags.BroadcastBeaconNodesFlag.Name),
 		DataDir:                    dataDir,
 		KeyManager:                 keyManager,
 		LogValidatorBalances:       logValidatorBalances,
@@ -188,6 +211,9 @@ func (s *ValidatorClient) registerClientService(ctx *cli.Context, keyManager key
 		GrpcMaxCallRecvMsgSizeFlag: maxCallRecvMsgSize,
 		GrpcRetriesFlag:            grpcRetries,
 		GrpcHeadersFlag:            ctx.String(flags.GrpcHeadersFlag.Name),
+		WatchKeys:                  ctx.Bool(flags.WatchKeysFlag.Name),
+		DoppelgangerEpochs:         ctx.Uint64(flags.DoppelgangerEpochsFlag.Name),
+		DoppelgangerFailClosed:     ctx.Bool(flags.DoppelgangerFailClosedFlag.Name),
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not initialize client service")
@@ -195,6 +221,64 @@ func (s *ValidatorClient) registerClientService(ctx *cli.Context, keyManager key
 	return s.services.RegisterService(v)
 }
 
+func (s *ValidatorClient) registerAdminRPCService(ctx *cli.Context) error {
+	var validatorService *client.ValidatorService
+	if err := s.services.FetchService(&validatorService); err != nil {
+		return err
+	}
+	host := ctx.String(flags.AdminRPCHostFlag.Name)
+	port := ctx.Int(flags.AdminRPCPortFlag.Name)
+	var token string
+	if tokenFile := ctx.String(flags.AdminRPCTokenFileFlag.Name); tokenFile != "" {
+		enc, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return errors.Wrap(err, "could not read admin RPC token file")
+		}
+		token = strings.TrimSpace(string(enc))
+		if token == "" {
+			return fmt.Errorf("admin RPC token file %s is empty", tokenFile)
+		}
+	}
+	// The admin API can reload the validating keys, so it is only served without authentication
+	// to local clients.
+	if token == "" && !isLoopback(host) {
+		return fmt.Errorf(
+			"refusing to serve the admin API on non-loopback host %s without --%s",
+			host,
+			flags.AdminRPCTokenFileFlag.Name,
+		)
+	}
+	server := rpc.NewService(context.Background(), &rpc.Config{
+		Host:      host,
+		Port:      fmt.Sprintf("%d", port),
+		Token:     token,
+		Validator: validatorService,
+	})
+	if err := s.services.RegisterService(server); err != nil {
+		return err
+	}
+
+	gatewayPort := ctx.Int(flags.AdminGatewayPortFlag.Name)
+	if gatewayPort == 0 {
+		return nil
+	}
+	gateway := rpc.NewGateway(
+		context.Background(),
+		fmt.Sprintf("%s:%d", host, port),
+		fmt.Sprintf("%s:%d", host, gatewayPort),
+	)
+	return s.services.RegisterService(gateway)
+}
+
+// isLoopback returns true if the host only accepts connections from the local machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // selectKeyManager selects the key manager depending on the options provided by the user.
 func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 	manager := strings.ToLower(ctx.String(flags.KeyManager.Name))
@@ -250,6 +334,8 @@ func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 		km, help, err = keymanager.NewWallet(opts)
 	case "remote":
 		km, help, err = keymanager.NewRemoteWallet(opts)
+	case "remote-http":
+		km, help, err = keymanager.NewRemoteHTTP(opts)
 	default:
 		return nil, fmt.Errorf("unknown keymanager %q", manager)
 	}
@@ -0,0 +1,45 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	tests := []struct {
+		name     string
+		token    string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "no token configured", wantCode: codes.OK},
+		{name: "missing metadata", token: "secret", wantCode: codes.Unauthenticated},
+		{name: "missing header", token: "secret", md: metadata.Pairs("foo", "bar"), wantCode: codes.Unauthenticated},
+		{name: "wrong token", token: "secret", md: metadata.Pairs("authorization", "Bearer wrong"), wantCode: codes.Unauthenticated},
+		{name: "wrong scheme", token: "secret", md: metadata.Pairs("authorization", "Basic secret"), wantCode: codes.Unauthenticated},
+		{name: "valid token", token: "secret", md: metadata.Pairs("authorization", "Bearer secret"), wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			res, err := authUnaryInterceptor(tt.token)(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Expected code %v, received %v", tt.wantCode, err)
+			}
+			if tt.wantCode == codes.OK && res != "ok" {
+				t.Errorf("Expected the handler to be called, received %v", res)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	adminpb "github.com/prysmaticlabs/prysm/proto/validator/admin"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server defines a server implementation of the validator admin gRPC service.
+type Server struct {
+	ctx       context.Context
+	validator ValidatorStateFetcher
+}
+
+// ListKeys lists the validating keys of the validator client along with the status
+// reported for them by the beacon node in the latest duties.
+func (as *Server) ListKeys(ctx context.Context, _ *empty.Empty) (*adminpb.ListKeysResponse, error) {
+	ctx, span := trace.StartSpan(ctx, "admin.ListKeys")
+	defer span.End()
+
+	keys, err := as.validator.ValidatingKeys()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not fetch validating keys: %v", err)
+	}
+	duties, slot, err := as.validator.Duties()
+	if err != nil && err != client.ErrNoDuties {
+		return nil, status.Errorf(codes.Internal, "Could not fetch duties: %v", err)
+	}
+	dutyByKey := make(map[[48]byte]*ethpb.DutiesResponse_Duty)
+	if duties != nil {
+		for _, duty := range duties.Duties {
+			if duty == nil {
+				continue
+			}
+			dutyByKey[bytesutil.ToBytes48(duty.PublicKey)] = duty
+		}
+	}
+
+	res := &adminpb.ListKeysResponse{
+		Keys: make([]*adminpb.ListKeysResponse_Key, 0, len(keys)),
+		Slot: slot,
+	}
+	for _, pubKey := range keys {
+		key := &adminpb.ListKeysResponse_Key{
+			PublicKey: bytesutil.SafeCopyBytes(pubKey[:]),
+			Status:    ethpb.ValidatorStatus_UNKNOWN_STATUS.String(),
+		}
+		if duty, ok := dutyByKey[pubKey]; ok {
+			key.ValidatorIndex = duty.ValidatorIndex
+			key.Status = duty.Status.String()
+		}
+		res.Keys = append(res.Keys, key)
+	}
+	return res, nil
+}
+
+// ListDuties lists the roles of each validating key for the remaining slots of the current epoch,
+// as assigned by the duties the validator last fetched. Keys without a role at a slot are left out.
+// Aggregation is not reported, as selecting an aggregator requires signing a selection proof.
+func (as *Server) ListDuties(ctx context.Context, _ *empty.Empty) (*adminpb.ListDutiesResponse, error) {
+	ctx, span := trace.StartSpan(ctx, "admin.ListDuties")
+	defer span.End()
+
+	dutiesRes, currentSlot, err := as.validator.Duties()
+	if err == client.ErrNoDuties {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not fetch duties: %v", err)
+	}
+
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	epoch := currentSlot / slotsPerEpoch
+	res := &adminpb.ListDutiesResponse{
+		Duties: make([]*adminpb.ListDutiesResponse_Duty, 0),
+		Epoch:  epoch,
+	}
+	for slot := currentSlot; slot < (epoch+1)*slotsPerEpoch; slot++ {
+		duties := make([]*adminpb.ListDutiesResponse_Duty, 0)
+		for _, duty := range dutiesRes.Duties {
+			if duty == nil {
+				continue
+			}
+			roles := rolesAt(duty, slot)
+			if len(roles) == 0 {
+				continue
+			}
+			duties = append(duties, &adminpb.ListDutiesResponse_Duty{
+				PublicKey: bytesutil.SafeCopyBytes(duty.PublicKey),
+				Slot:      slot,
+				Roles:     roles,
+			})
+		}
+		sort.Slice(duties, func(i, j int) bool {
+			return string(duties[i].PublicKey) < string(duties[j].PublicKey)
+		})
+		res.Duties = append(res.Duties, duties...)
+	}
+	return res, nil
+}
+
+// rolesAt returns the names of the roles a duty assigns at the given slot.
+func rolesAt(duty *ethpb.DutiesResponse_Duty, slot uint64) []string {
+	var roles []string
+	for _, proposerSlot := range duty.ProposerSlots {
+		if proposerSlot != 0 && proposerSlot == slot {
+			roles = append(roles, "PROPOSER")
+			break
+		}
+	}
+	if duty.AttesterSlot == slot {
+		roles = append(roles, "ATTESTER")
+	}
+	return roles
+}
+
+// GetProtectionHistory returns the slashing protection history recorded for a validating key,
+// along with the last block and attestation it signed.
+func (as *Server) GetProtectionHistory(ctx context.Context, req *adminpb.ProtectionHistoryRequest) (*adminpb.ProtectionHistory, error) {
+	ctx, span := trace.StartSpan(ctx, "admin.GetProtectionHistory")
+	defer span.End()
+
+	if len(req.PublicKey) != 48 {
+		return nil, status.Errorf(codes.InvalidArgument, "Expected a 48 byte public key, received %d bytes", len(req.PublicKey))
+	}
+	history, err := as.validator.ProtectionHistory(ctx, bytesutil.ToBytes48(req.PublicKey))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not read protection history: %v", err)
+	}
+
+	res := &adminpb.ProtectionHistory{
+		PublicKey:          req.PublicKey,
+		ProposalSlots:      make([]uint64, 0, len(history.Proposals)),
+		Attestations:       make([]*adminpb.ProtectionHistory_Attestation, 0, len(history.Attestations)),
+		LatestEpochWritten: history.LatestEpochWritten,
+	}
+	for _, p := range history.Proposals {
+		res.ProposalSlots = append(res.ProposalSlots, p.Slot)
+		if p.Slot > res.LastProposalSlot {
+			res.LastProposalSlot = p.Slot
+		}
+	}
+	for _, att := range history.Attestations {
+		res.Attestations = append(res.Attestations, &adminpb.ProtectionHistory_Attestation{
+			SourceEpoch: att.SourceEpoch,
+			TargetEpoch: att.TargetEpoch,
+		})
+	}
+	if len(res.Attestations) > 0 {
+		res.LastAttestation = res.Attestations[len(res.Attestations)-1]
+	}
+	return res, nil
+}
+
+// ReloadKeys requests the validating keys to be reloaded from the keymanager at the next epoch.
+func (as *Server) ReloadKeys(ctx context.Context, _ *empty.Empty) (*empty.Empty, error) {
+	as.validator.ReloadKeys()
+	return &empty.Empty{}, nil
+}
@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	adminpb "github.com/prysmaticlabs/prysm/proto/validator/admin"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockValidator struct {
+	keys      [][48]byte
+	duties    *ethpb.DutiesResponse
+	slot      uint64
+	histories map[[48]byte]*db.InterchangeHistory
+	reloaded  bool
+}
+
+func (m *mockValidator) ValidatingKeys() ([][48]byte, error) {
+	return m.keys, nil
+}
+
+func (m *mockValidator) Duties() (*ethpb.DutiesResponse, uint64, error) {
+	if m.duties == nil {
+		return nil, 0, client.ErrNoDuties
+	}
+	return m.duties, m.slot, nil
+}
+
+func (m *mockValidator) ProtectionHistory(_ context.Context, pubKey [48]byte) (*db.InterchangeHistory, error) {
+	return m.histories[pubKey], nil
+}
+
+func (m *mockValidator) ReloadKeys() {
+	m.reloaded = true
+}
+
+func TestListKeys_StatusFromDuties(t *testing.T) {
+	active := [48]byte{1}
+	unknown := [48]byte{2}
+	as := &Server{validator: &mockValidator{
+		keys: [][48]byte{active, unknown},
+		duties: &ethpb.DutiesResponse{
+			Duties: []*ethpb.DutiesResponse_Duty{
+				{PublicKey: active[:], ValidatorIndex: 5, Status: ethpb.ValidatorStatus_ACTIVE},
+			},
+		},
+		slot: 10,
+	}}
+
+	res, err := as.ListKeys(context.Background(), &empty.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Keys) != 2 || res.Slot != 10 {
+		t.Fatalf("Unexpected response %v", res)
+	}
+	if res.Keys[0].Status != "ACTIVE" || res.Keys[0].ValidatorIndex != 5 {
+		t.Errorf("Unexpected key %v", res.Keys[0])
+	}
+	if res.Keys[1].Status != "UNKNOWN_STATUS" {
+		t.Errorf("Expected key without duties to have an unknown status, received %s", res.Keys[1].Status)
+	}
+}
+
+func TestListKeys_NoDutiesYet(t *testing.T) {
+	as := &Server{validator: &mockValidator{keys: [][48]byte{{1}}}}
+	res, err := as.ListKeys(context.Background(), &empty.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Keys) != 1 || res.Keys[0].Status != "UNKNOWN_STATUS" {
+		t.Errorf("Unexpected response %v", res)
+	}
+}
+
+func TestListDuties_RemainingSlotsOfEpoch(t *testing.T) {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	proposer := [48]byte{1}
+	attester := [48]byte{2}
+	currentSlot := slotsPerEpoch + 1
+	as := &Server{validator: &mockValidator{
+		duties: &ethpb.DutiesResponse{
+			Duties: []*ethpb.DutiesResponse_Duty{
+				{
+					PublicKey: attester[:],
+					// Attestations of past epochs must not be listed.
+					AttesterSlot: 2*slotsPerEpoch - 1,
+				},
+				{
+					PublicKey: proposer[:],
+					// Proposals of past slots must not be listed.
+					ProposerSlots: []uint64{slotsPerEpoch, currentSlot},
+					AttesterSlot:  currentSlot,
+				},
+			},
+		},
+		slot: currentSlot,
+	}}
+
+	res, err := as.ListDuties(context.Background(), &empty.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Epoch != 1 {
+		t.Errorf("Expected epoch 1, received %d", res.Epoch)
+	}
+	if len(res.Duties) != 2 {
+		t.Fatalf("Expected 2 duties, received %v", res.Duties)
+	}
+	if res.Duties[0].Slot != currentSlot || len(res.Duties[0].Roles) != 2 || res.Duties[0].Roles[0] != "PROPOSER" {
+		t.Errorf("Unexpected duty %v", res.Duties[0])
+	}
+	if res.Duties[1].Slot != 2*slotsPerEpoch-1 || len(res.Duties[1].Roles) != 1 || res.Duties[1].Roles[0] != "ATTESTER" {
+		t.Errorf("Unexpected duty %v", res.Duties[1])
+	}
+}
+
+func TestListDuties_NoDutiesYet(t *testing.T) {
+	as := &Server{validator: &mockValidator{}}
+	_, err := as.ListDuties(context.Background(), &empty.Empty{})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected unavailable error, received %v", err)
+	}
+}
+
+func TestGetProtectionHistory(t *testing.T) {
+	pubKey := [48]byte{3}
+	as := &Server{validator: &mockValidator{
+		histories: map[[48]byte]*db.InterchangeHistory{
+			pubKey: {
+				LatestEpochWritten: 4,
+				Proposals:          []*db.InterchangeProposal{{Slot: 3}, {Slot: 40}},
+				Attestations: []*db.InterchangeAttestation{
+					{SourceEpoch: 1, TargetEpoch: 2},
+					{SourceEpoch: 2, TargetEpoch: 4},
+				},
+			},
+		},
+	}}
+
+	res, err := as.GetProtectionHistory(context.Background(), &adminpb.ProtectionHistoryRequest{PublicKey: pubKey[:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.LastProposalSlot != 40 || len(res.ProposalSlots) != 2 {
+		t.Errorf("Unexpected proposals %v, last %d", res.ProposalSlots, res.LastProposalSlot)
+	}
+	if res.LastAttestation == nil || res.LastAttestation.SourceEpoch != 2 || res.LastAttestation.TargetEpoch != 4 {
+		t.Errorf("Unexpected last attestation %v", res.LastAttestation)
+	}
+	if res.LatestEpochWritten != 4 {
+		t.Errorf("Expected latest epoch written 4, received %d", res.LatestEpochWritten)
+	}
+
+	_, err = as.GetProtectionHistory(context.Background(), &adminpb.ProtectionHistoryRequest{PublicKey: []byte{1}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected invalid argument error, received %v", err)
+	}
+}
+
+func TestReloadKeys(t *testing.T) {
+	m := &mockValidator{}
+	as := &Server{validator: m}
+	if _, err := as.ReloadKeys(context.Background(), &empty.Empty{}); err != nil {
+		t.Fatal(err)
+	}
+	if !m.reloaded {
+		t.Error("Expected keys to be reloaded")
+	}
+}
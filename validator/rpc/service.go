@@ -0,0 +1,137 @@
+// Package rpc defines the local admin gRPC service of the validator client, along with a
+// gateway serving it as JSON over HTTP.
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	grpc_opentracing "github.com/grpc-ecosystem/go-grpc-middleware/tracing/opentracing"
+	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	adminpb "github.com/prysmaticlabs/prysm/proto/validator/admin"
+	"github.com/prysmaticlabs/prysm/shared/traceutil"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/plugin/ocgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+var log = logrus.WithField("prefix", "rpc")
+
+// ValidatorStateFetcher exposes the state of a running validator client to the admin service.
+type ValidatorStateFetcher interface {
+	ValidatingKeys() ([][48]byte, error)
+	Duties() (*ethpb.DutiesResponse, uint64, error)
+	ProtectionHistory(ctx context.Context, pubKey [48]byte) (*db.InterchangeHistory, error)
+	ReloadKeys()
+}
+
+// Service defines a server implementation of the validator admin gRPC service,
+// providing RPC endpoints to inspect the keys, duties and slashing protection
+// history of the validator client.
+type Service struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	host       string
+	port       string
+	token      string
+	validator  ValidatorStateFetcher
+	listener   net.Listener
+	grpcServer *grpc.Server
+}
+
+// Config options for the validator admin RPC server.
+type Config struct {
+	Host      string
+	Port      string
+	Token     string // Bearer token requests must authenticate with, if set.
+	Validator ValidatorStateFetcher
+}
+
+// NewService instantiates a new RPC service instance that will
+// be registered into a running validator client.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Service{
+		ctx:       ctx,
+		cancel:    cancel,
+		host:      cfg.Host,
+		port:      cfg.Port,
+		token:     cfg.Token,
+		validator: cfg.Validator,
+	}
+}
+
+// Start the gRPC service.
+func (s *Service) Start() {
+	address := fmt.Sprintf("%s:%s", s.host, s.port)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("Could not listen to port in Start() %s: %v", address, err)
+	}
+	s.listener = lis
+	log.WithField("address", address).Info("Admin RPC-API listening on port")
+
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
+		grpc.StreamInterceptor(middleware.ChainStreamServer(
+			recovery.StreamServerInterceptor(
+				recovery.WithRecoveryHandlerContext(traceutil.RecoveryHandlerFunc),
+			),
+			grpc_prometheus.StreamServerInterceptor,
+			grpc_opentracing.StreamServerInterceptor(),
+			authStreamInterceptor(s.token),
+		)),
+		grpc.UnaryInterceptor(middleware.ChainUnaryServer(
+			recovery.UnaryServerInterceptor(
+				recovery.WithRecoveryHandlerContext(traceutil.RecoveryHandlerFunc),
+			),
+			grpc_prometheus.UnaryServerInterceptor,
+			grpc_opentracing.UnaryServerInterceptor(),
+			authUnaryInterceptor(s.token),
+		)),
+	}
+	grpc_prometheus.EnableHandlingTimeHistogram()
+	s.grpcServer = grpc.NewServer(opts...)
+
+	adminServer := &Server{
+		ctx:       s.ctx,
+		validator: s.validator,
+	}
+	adminpb.RegisterValidatorAdminServer(s.grpcServer, adminServer)
+
+	// Register reflection service on gRPC server.
+	reflection.Register(s.grpcServer)
+
+	go func() {
+		if s.listener == nil {
+			return
+		}
+		if err := s.grpcServer.Serve(s.listener); err != nil {
+			log.Errorf("Could not serve gRPC: %v", err)
+		}
+	}()
+}
+
+// Stop the service.
+func (s *Service) Stop() error {
+	s.cancel()
+	if s.listener != nil {
+		s.grpcServer.GracefulStop()
+		log.Debug("Initiated graceful stop of gRPC server")
+	}
+	return nil
+}
+
+// Status returns nil if the service is listening.
+func (s *Service) Status() error {
+	if s.listener == nil {
+		return fmt.Errorf("admin RPC server is not listening on %s:%s", s.host, s.port)
+	}
+	return nil
+}
@@ -73,6 +73,8 @@ var appHelpFlagGroups = []flagGroup{
 		Name: "validator",
 		Flags: []cli.Flag{
 			flags.BeaconRPCProviderFlag,
+			flags.FallbackBeaconRPCProviderFlag,
+			flags.BroadcastBeaconNodesFlag,
 			flags.CertFlag,
 			flags.KeyManager,
 			flags.KeyManagerOpts,
@@ -85,6 +87,14 @@ var appHelpFlagGroups = []flagGroup{
 			flags.GrpcRetriesFlag,
 			flags.GrpcHeadersFlag,
 			flags.AccountMetricsFlag,
+			flags.WatchKeysFlag,
+			flags.DoppelgangerEpochsFlag,
+			flags.DoppelgangerFailClosedFlag,
+			flags.AdminRPCFlag,
+			flags.AdminRPCHostFlag,
+			flags.AdminRPCPortFlag,
+			flags.AdminRPCTokenFileFlag,
+			flags.AdminGatewayPortFlag,
 		},
 	},
 	{